@@ -0,0 +1,73 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/sirupsen/logrus"
+	"github.com/tnosaj/sar-training/backend/internal/adapters/sqlite"
+	"github.com/tnosaj/sar-training/backend/internal/application/observations"
+	"github.com/tnosaj/sar-training/backend/internal/infra/config"
+	logx "github.com/tnosaj/sar-training/backend/internal/infra/log"
+)
+
+// importWeather runs "server import-weather [flags] FILE..." against the
+// database in DB_PATH and prints one import report per file.
+func importWeather(args []string) error {
+	fs := flag.NewFlagSet("import-weather", flag.ContinueOnError)
+	format := fs.String("format", "", "csv or metar (default: by file extension)")
+	location := fs.String("location", "", "location the observations belong to (default: station id)")
+	reference := fs.String("reference", "", "month, date or time METAR reports fall into (default: now)")
+	maxGap := fs.Duration("max-gap", observations.DefaultMaxGap, "largest gap between session start and observation")
+	overwrite := fs.Bool("overwrite", false, "replace conditions already recorded on sessions")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() == 0 {
+		return fmt.Errorf("usage: import-weather [flags] FILE...")
+	}
+	logx.Std.SetLevel(logrus.InfoLevel)
+
+	db, err := sqlite.Open(config.DBPath())
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+	if err := sqlite.ApplyMigrations(db); err != nil {
+		return err
+	}
+	svc := observations.NewService(sqlite.NewWeatherRepo(db.DB), sqlite.NewSessionsRepo(db.DB))
+
+	cmd := observations.ImportCommand{MaxGap: *maxGap, Overwrite: *overwrite}
+	if *location != "" {
+		cmd.Location = location
+	}
+	if *reference != "" {
+		t, err := observations.ParseReference(*reference)
+		if err != nil {
+			return fmt.Errorf("invalid -reference: %w", err)
+		}
+		cmd.Reference = &t
+	}
+	for _, name := range fs.Args() {
+		f, err := os.Open(name)
+		if err != nil {
+			return err
+		}
+		cmd.Data, cmd.Format = f, *format
+		if cmd.Format == "" {
+			cmd.Format = observations.DetectFormat(name)
+		}
+		res, err := svc.Import(context.Background(), cmd)
+		f.Close()
+		if err != nil {
+			return fmt.Errorf("%s: %w", name, err)
+		}
+		out, _ := json.Marshal(res)
+		fmt.Printf("%s: %s\n", name, out)
+	}
+	return nil
+}
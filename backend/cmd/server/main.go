@@ -2,23 +2,47 @@ package main
 
 import (
 	"context"
+	"fmt"
 	"net/http"
+	"os"
 	"time"
 
 	"github.com/sirupsen/logrus"
 	"github.com/tnosaj/sar-training/backend/internal/adapters/httpapi"
 	"github.com/tnosaj/sar-training/backend/internal/adapters/sqlite"
+	"github.com/tnosaj/sar-training/backend/internal/application/alerts"
 	"github.com/tnosaj/sar-training/backend/internal/application/behaviors"
+	"github.com/tnosaj/sar-training/backend/internal/application/curriculum"
+	"github.com/tnosaj/sar-training/backend/internal/application/disciplines"
 	"github.com/tnosaj/sar-training/backend/internal/application/dogs"
+	"github.com/tnosaj/sar-training/backend/internal/application/exams"
 	"github.com/tnosaj/sar-training/backend/internal/application/exercises"
+	"github.com/tnosaj/sar-training/backend/internal/application/figurants"
+	"github.com/tnosaj/sar-training/backend/internal/application/healthlog"
+	"github.com/tnosaj/sar-training/backend/internal/application/locations"
+	"github.com/tnosaj/sar-training/backend/internal/application/missions"
+	"github.com/tnosaj/sar-training/backend/internal/application/observations"
+	"github.com/tnosaj/sar-training/backend/internal/application/pairings"
+	"github.com/tnosaj/sar-training/backend/internal/application/protocols"
+	"github.com/tnosaj/sar-training/backend/internal/application/qualifications"
 	"github.com/tnosaj/sar-training/backend/internal/application/sessions"
 	"github.com/tnosaj/sar-training/backend/internal/application/skills"
+	"github.com/tnosaj/sar-training/backend/internal/application/tracks"
 	"github.com/tnosaj/sar-training/backend/internal/application/users"
+	"github.com/tnosaj/sar-training/backend/internal/domain/alert"
 	"github.com/tnosaj/sar-training/backend/internal/infra/config"
 	logx "github.com/tnosaj/sar-training/backend/internal/infra/log"
 )
 
 func main() {
+	if len(os.Args) > 1 && os.Args[1] == "import-weather" {
+		if err := importWeather(os.Args[2:]); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		return
+	}
+
 	cfg := config.Load()
 
 	lvl, err := logrus.ParseLevel(cfg.LogLevel)
@@ -44,14 +68,40 @@ func main() {
 	dgRepo := sqlite.NewDogsRepo(db.DB)
 	snRepo := sqlite.NewSessionsRepo(db.DB)
 	usrRepo := sqlite.NewUsersRepo(db.DB)
+	alRepo := sqlite.NewAlertsRepo(db.DB)
+	exmRepo := sqlite.NewExamsRepo(db.DB)
+	qlRepo := sqlite.NewQualificationsRepo(db.DB)
+	prRepo := sqlite.NewPairingsRepo(db.DB)
+	hlRepo := sqlite.NewHealthRepo(db.DB)
+	wxRepo := sqlite.NewWeatherRepo(db.DB)
+	lcRepo := sqlite.NewLocationsRepo(db.DB)
+	tkRepo := sqlite.NewTracksRepo(db.DB)
+	dcRepo := sqlite.NewDisciplinesRepo(db.DB)
+	fgRepo := sqlite.NewFigurantsRepo(db.DB)
+	msRepo := sqlite.NewMissionsRepo(db.DB)
+	pcRepo := sqlite.NewProtocolsRepo(db.DB)
+	mtRepo := sqlite.NewMasteryRepo(db.DB)
 
 	// services
-	skSvc := skills.NewService(skRepo)
+	skSvc := skills.NewService(skRepo, dcRepo)
 	bhSvc := behaviors.NewService(bhRepo)
 	exSvc := exercises.NewService(exRepo)
 	dgSvc := dogs.NewService(dgRepo)
-	snSvc := sessions.NewService(snRepo)
+	snSvc := sessions.NewService(snRepo, prRepo, usrRepo, hlRepo, lcRepo, dcRepo, exRepo)
 	usrSvs := users.NewService(usrRepo)
+	alSvc := alerts.NewService(alRepo, alert.DefaultThresholds())
+	exmSvc := exams.NewService(exmRepo, dgRepo)
+	qlSvc := qualifications.NewService(qlRepo, dcRepo)
+	prSvc := pairings.NewService(prRepo, dgRepo, usrRepo)
+	hlSvc := healthlog.NewService(hlRepo, dgRepo)
+	wxSvc := observations.NewService(wxRepo, snRepo)
+	lcSvc := locations.NewService(lcRepo)
+	tkSvc := tracks.NewService(tkRepo, snRepo, lcRepo)
+	dcSvc := disciplines.NewService(dcRepo)
+	fgSvc := figurants.NewService(fgRepo, snRepo, prRepo, usrRepo)
+	msSvc := missions.NewService(msRepo, lcRepo)
+	pcSvc := protocols.NewService(pcRepo, snRepo, dgRepo, hlRepo)
+	cuSvc := curriculum.NewService(mtRepo, bhRepo, dgRepo)
 
 	// handlers
 	skH := httpapi.NewSkillsHandler(skSvc)
@@ -60,8 +110,26 @@ func main() {
 	dgH := httpapi.NewDogsHandler(dgSvc)
 	snH := httpapi.NewSessionsHandler(snSvc)
 	usH := httpapi.NewUsersHandler(usrSvs, []byte(cfg.Secret))
+	alH := httpapi.NewAlertsHandler(alSvc)
+	exmH := httpapi.NewExamsHandler(exmSvc)
+	qlH := httpapi.NewQualificationsHandler(qlSvc)
+	prH := httpapi.NewPairingsHandler(prSvc)
+	hlH := httpapi.NewHealthHandler(hlSvc)
+	wxH := httpapi.NewWeatherHandler(wxSvc)
+	lcH := httpapi.NewLocationsHandler(lcSvc)
+	tkH := httpapi.NewTracksHandler(tkSvc)
+	dcH := httpapi.NewDisciplinesHandler(dcSvc)
+	fgH := httpapi.NewFigurantsHandler(fgSvc)
+	msH := httpapi.NewMissionsHandler(msSvc)
+	pcH := httpapi.NewProtocolsHandler(pcSvc)
+	cuH := httpapi.NewCurriculumHandler(cuSvc)
+
+	// background jobs
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go alSvc.Run(ctx, cfg.AlertInterval)
 
-	r := httpapi.NewRouter(health(db), skH, bhH, exH, dgH, snH, usH)
+	r := httpapi.NewRouter(health(db), skH, bhH, exH, dgH, snH, usH, alH, exmH, qlH, prH, hlH, wxH, lcH, tkH, dcH, fgH, msH, pcH, cuH)
 
 	addr := ":" + cfg.Port
 	logx.Std.Infof("listening on %s", addr)
@@ -0,0 +1,92 @@
+package httpapi
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/tnosaj/sar-training/backend/internal/application/alerts"
+	"github.com/tnosaj/sar-training/backend/internal/domain/common"
+	logx "github.com/tnosaj/sar-training/backend/internal/infra/log"
+)
+
+type AlertsHandler struct{ svc *alerts.Service }
+
+func NewAlertsHandler(s *alerts.Service) *AlertsHandler {
+	logx.Std.Trace("starting alerts handler")
+	return &AlertsHandler{svc: s}
+}
+
+// GET /alerts?dog_id=&behavior_id=&status=
+func (h *AlertsHandler) List(w http.ResponseWriter, r *http.Request) {
+	var q alerts.ListAlertsQuery
+	if v := r.URL.Query().Get("dog_id"); v != "" {
+		id, err := strconv.ParseInt(v, 10, 64)
+		if err != nil {
+			writeError(w, 400, "invalid dog id")
+			return
+		}
+		q.DogID = &id
+	}
+	if v := r.URL.Query().Get("behavior_id"); v != "" {
+		id, err := strconv.ParseInt(v, 10, 64)
+		if err != nil {
+			writeError(w, 400, "invalid behavior id")
+			return
+		}
+		q.BehaviorID = &id
+	}
+	if v := r.URL.Query().Get("status"); v != "" {
+		q.Status = &v
+	}
+	items, err := h.svc.List(r.Context(), q)
+	if err != nil {
+		if err == common.ErrValidation {
+			writeError(w, 400, "invalid status")
+			return
+		}
+		writeError(w, 500, err.Error())
+		return
+	}
+	writeJSON(w, 200, items)
+}
+
+// POST /alerts/analyze runs the trend analysis now instead of waiting for
+// the background job.
+func (h *AlertsHandler) Analyze(w http.ResponseWriter, r *http.Request) {
+	n, err := h.svc.Analyze(r.Context())
+	if err != nil {
+		writeError(w, 500, err.Error())
+		return
+	}
+	writeJSON(w, 200, map[string]any{"created": n})
+}
+
+func (h *AlertsHandler) Acknowledge(w http.ResponseWriter, r *http.Request) {
+	id, _ := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	res, err := h.svc.Acknowledge(r.Context(), alerts.AcknowledgeAlertCommand{ID: id, UserID: currentUserID(r)})
+	h.writeTransition(w, res, err)
+}
+
+func (h *AlertsHandler) Dismiss(w http.ResponseWriter, r *http.Request) {
+	id, _ := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	res, err := h.svc.Dismiss(r.Context(), alerts.DismissAlertCommand{ID: id, UserID: currentUserID(r)})
+	h.writeTransition(w, res, err)
+}
+
+func (h *AlertsHandler) writeTransition(w http.ResponseWriter, res any, err error) {
+	if err != nil {
+		switch err {
+		case common.ErrValidation:
+			writeError(w, 400, "invalid input")
+		case common.ErrNotFound:
+			writeError(w, 404, "not found")
+		case common.ErrConflict:
+			writeError(w, 409, "alert already dismissed")
+		default:
+			writeError(w, 500, err.Error())
+		}
+		return
+	}
+	writeJSON(w, 200, res)
+}
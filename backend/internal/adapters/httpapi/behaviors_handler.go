@@ -5,7 +5,9 @@ import (
 	"net/http"
 	"strconv"
 
+	"github.com/go-chi/chi/v5"
 	"github.com/tnosaj/sar-training/backend/internal/application/behaviors"
+	"github.com/tnosaj/sar-training/backend/internal/domain/common"
 	logx "github.com/tnosaj/sar-training/backend/internal/infra/log"
 )
 
@@ -16,19 +18,30 @@ func NewBehaviorsHandler(s *behaviors.Service) *BehaviorsHandler {
 	return &BehaviorsHandler{svc: s}
 }
 
+// GET /behaviors?skill_id=&exercise_id=
 func (h *BehaviorsHandler) List(w http.ResponseWriter, r *http.Request) {
-	q := r.URL.Query().Get("skill_id")
-	var sid *int64
-	if q != "" {
-		v, _ := strconv.ParseInt(q, 10, 64)
-		sid = &v
+	var q behaviors.ListBehaviorsQuery
+	for _, p := range []struct {
+		name string
+		out  **int64
+	}{{"skill_id", &q.SkillID}, {"exercise_id", &q.ExerciseID}} {
+		if v := r.URL.Query().Get(p.name); v != "" {
+			id, err := strconv.ParseInt(v, 10, 64)
+			if err != nil {
+				writeError(w, 400, "invalid "+p.name)
+				return
+			}
+			*p.out = &id
+		}
 	}
-	items, err := h.svc.List(r.Context(), behaviors.ListBehaviorsQuery{SkillID: sid})
-	if err != nil {
-		writeError(w, 500, err.Error())
-		return
-	}
-	writeJSON(w, 200, items)
+	items, err := h.svc.List(r.Context(), q)
+	h.write(w, 200, items, err)
+}
+
+func (h *BehaviorsHandler) Get(w http.ResponseWriter, r *http.Request) {
+	id, _ := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	res, err := h.svc.Get(r.Context(), id)
+	h.write(w, 200, res, err)
 }
 
 func (h *BehaviorsHandler) Create(w http.ResponseWriter, r *http.Request) {
@@ -38,9 +51,64 @@ func (h *BehaviorsHandler) Create(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 	res, err := h.svc.Create(r.Context(), cmd)
+	h.write(w, 201, res, err)
+}
+
+func (h *BehaviorsHandler) Update(w http.ResponseWriter, r *http.Request) {
+	id, _ := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	var cmd behaviors.UpdateBehaviorCommand
+	if err := json.NewDecoder(r.Body).Decode(&cmd); err != nil {
+		writeError(w, 400, "invalid json")
+		return
+	}
+	cmd.ID = id
+	res, err := h.svc.Update(r.Context(), cmd)
+	h.write(w, 200, res, err)
+}
+
+func (h *BehaviorsHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	id, _ := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err := h.svc.Delete(r.Context(), id); err != nil {
+		h.write(w, 0, nil, err)
+		return
+	}
+	w.WriteHeader(204)
+}
+
+// GET /behaviors/free-text
+func (h *BehaviorsHandler) FreeTexts(w http.ResponseWriter, r *http.Request) {
+	items, err := h.svc.FreeTexts(r.Context())
+	h.write(w, 200, items, err)
+}
+
+// POST /behaviors/promote
+func (h *BehaviorsHandler) Promote(w http.ResponseWriter, r *http.Request) {
+	var cmd behaviors.PromoteCommand
+	if err := json.NewDecoder(r.Body).Decode(&cmd); err != nil {
+		writeError(w, 400, "invalid json")
+		return
+	}
+	res, err := h.svc.Promote(r.Context(), cmd)
+	if err == common.ErrNotFound {
+		writeError(w, 404, "no unlinked rounds with that free text")
+		return
+	}
+	h.write(w, 200, res, err)
+}
+
+func (h *BehaviorsHandler) write(w http.ResponseWriter, code int, res any, err error) {
 	if err != nil {
-		writeError(w, 400, err.Error())
+		switch err {
+		case common.ErrValidation:
+			writeError(w, 400, "invalid input")
+		case common.ErrNotFound:
+			writeError(w, 404, "not found")
+		case common.ErrConflict:
+			writeError(w, 409, "behavior is used by rounds or exam protocols")
+		default:
+			writeError(w, 500, err.Error())
+		}
 		return
 	}
-	writeJSON(w, 201, res)
+	writeJSON(w, code, res)
 }
@@ -0,0 +1,111 @@
+package httpapi
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/tnosaj/sar-training/backend/internal/application/curriculum"
+	"github.com/tnosaj/sar-training/backend/internal/domain/common"
+	logx "github.com/tnosaj/sar-training/backend/internal/infra/log"
+)
+
+type CurriculumHandler struct{ svc *curriculum.Service }
+
+func NewCurriculumHandler(s *curriculum.Service) *CurriculumHandler {
+	logx.Std.Trace("starting curriculum handler")
+	return &CurriculumHandler{svc: s}
+}
+
+// GET /behaviors/{id}/prerequisites
+func (h *CurriculumHandler) Prerequisites(w http.ResponseWriter, r *http.Request) {
+	id, _ := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	res, err := h.svc.Prerequisites(r.Context(), id)
+	h.write(w, 200, res, err)
+}
+
+// POST /behaviors/{id}/prerequisites
+func (h *CurriculumHandler) AddPrerequisite(w http.ResponseWriter, r *http.Request) {
+	var cmd curriculum.AddPrerequisiteCommand
+	if err := json.NewDecoder(r.Body).Decode(&cmd); err != nil {
+		writeError(w, 400, "invalid json")
+		return
+	}
+	cmd.BehaviorID, _ = strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	res, err := h.svc.AddPrerequisite(r.Context(), cmd)
+	h.write(w, 201, res, err)
+}
+
+// DELETE /behaviors/{id}/prerequisites/{requiresId}
+func (h *CurriculumHandler) RemovePrerequisite(w http.ResponseWriter, r *http.Request) {
+	id, _ := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	requiresID, _ := strconv.ParseInt(chi.URLParam(r, "requiresId"), 10, 64)
+	if err := h.svc.RemovePrerequisite(r.Context(), id, requiresID); err != nil {
+		h.write(w, 0, nil, err)
+		return
+	}
+	w.WriteHeader(204)
+}
+
+// GET /behaviors/{id}/mastery
+func (h *CurriculumHandler) Criteria(w http.ResponseWriter, r *http.Request) {
+	id, _ := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	res, err := h.svc.Criteria(r.Context(), id)
+	h.write(w, 200, res, err)
+}
+
+// PUT /behaviors/{id}/mastery
+func (h *CurriculumHandler) SetCriteria(w http.ResponseWriter, r *http.Request) {
+	var cmd curriculum.SetCriteriaCommand
+	if err := json.NewDecoder(r.Body).Decode(&cmd); err != nil {
+		writeError(w, 400, "invalid json")
+		return
+	}
+	cmd.BehaviorID, _ = strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	res, err := h.svc.SetCriteria(r.Context(), cmd)
+	h.write(w, 200, res, err)
+}
+
+// DELETE /behaviors/{id}/mastery
+func (h *CurriculumHandler) DeleteCriteria(w http.ResponseWriter, r *http.Request) {
+	id, _ := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err := h.svc.DeleteCriteria(r.Context(), id); err != nil {
+		h.write(w, 0, nil, err)
+		return
+	}
+	w.WriteHeader(204)
+}
+
+// GET /dogs/{id}/curriculum?skill_id=
+func (h *CurriculumHandler) Dog(w http.ResponseWriter, r *http.Request) {
+	q := curriculum.CurriculumQuery{}
+	q.DogID, _ = strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if v := r.URL.Query().Get("skill_id"); v != "" {
+		id, err := strconv.ParseInt(v, 10, 64)
+		if err != nil {
+			writeError(w, 400, "invalid skill_id")
+			return
+		}
+		q.SkillID = &id
+	}
+	res, err := h.svc.Curriculum(r.Context(), q)
+	h.write(w, 200, res, err)
+}
+
+func (h *CurriculumHandler) write(w http.ResponseWriter, code int, res any, err error) {
+	if err != nil {
+		switch err {
+		case common.ErrValidation:
+			writeError(w, 400, "invalid input")
+		case common.ErrNotFound:
+			writeError(w, 404, "not found")
+		case common.ErrConflict:
+			writeError(w, 409, "prerequisite would create a cycle")
+		default:
+			writeError(w, 500, err.Error())
+		}
+		return
+	}
+	writeJSON(w, code, res)
+}
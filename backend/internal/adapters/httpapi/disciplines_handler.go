@@ -0,0 +1,82 @@
+package httpapi
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/tnosaj/sar-training/backend/internal/application/disciplines"
+	"github.com/tnosaj/sar-training/backend/internal/domain/common"
+	logx "github.com/tnosaj/sar-training/backend/internal/infra/log"
+)
+
+type DisciplinesHandler struct{ svc *disciplines.Service }
+
+func NewDisciplinesHandler(s *disciplines.Service) *DisciplinesHandler {
+	logx.Std.Trace("starting disciplines handler")
+	return &DisciplinesHandler{svc: s}
+}
+
+func (h *DisciplinesHandler) List(w http.ResponseWriter, r *http.Request) {
+	items, err := h.svc.List(r.Context())
+	if err != nil {
+		writeError(w, 500, err.Error())
+		return
+	}
+	writeJSON(w, 200, items)
+}
+
+func (h *DisciplinesHandler) Create(w http.ResponseWriter, r *http.Request) {
+	var cmd disciplines.CreateDisciplineCommand
+	if err := json.NewDecoder(r.Body).Decode(&cmd); err != nil {
+		writeError(w, 400, "invalid json")
+		return
+	}
+	res, err := h.svc.Create(r.Context(), cmd)
+	h.write(w, 201, res, err)
+}
+
+func (h *DisciplinesHandler) Get(w http.ResponseWriter, r *http.Request) {
+	id, _ := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	res, err := h.svc.Get(r.Context(), id)
+	h.write(w, 200, res, err)
+}
+
+func (h *DisciplinesHandler) Update(w http.ResponseWriter, r *http.Request) {
+	id, _ := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	var cmd disciplines.UpdateDisciplineCommand
+	if err := json.NewDecoder(r.Body).Decode(&cmd); err != nil {
+		writeError(w, 400, "invalid json")
+		return
+	}
+	cmd.ID = id
+	res, err := h.svc.Update(r.Context(), cmd)
+	h.write(w, 200, res, err)
+}
+
+func (h *DisciplinesHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	id, _ := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err := h.svc.Delete(r.Context(), id); err != nil {
+		h.write(w, 0, nil, err)
+		return
+	}
+	w.WriteHeader(204)
+}
+
+func (h *DisciplinesHandler) write(w http.ResponseWriter, code int, res any, err error) {
+	if err != nil {
+		switch err {
+		case common.ErrValidation:
+			writeError(w, 400, "invalid input")
+		case common.ErrNotFound:
+			writeError(w, 404, "not found")
+		case common.ErrConflict:
+			writeError(w, 409, "discipline name already exists")
+		default:
+			writeError(w, 500, err.Error())
+		}
+		return
+	}
+	writeJSON(w, code, res)
+}
@@ -2,15 +2,20 @@ package httpapi
 
 import (
 	"encoding/json"
+	"io"
 	"net/http"
 	"strconv"
 
 	"github.com/go-chi/chi/v5"
 	"github.com/tnosaj/sar-training/backend/internal/application/dogs"
 	"github.com/tnosaj/sar-training/backend/internal/domain/common"
+	"github.com/tnosaj/sar-training/backend/internal/domain/dog"
 	logx "github.com/tnosaj/sar-training/backend/internal/infra/log"
 )
 
+// maxPhotoSize caps uploaded dog photos.
+const maxPhotoSize = 5 << 20
+
 type DogsHandler struct{ svc *dogs.Service }
 
 func NewDogsHandler(s *dogs.Service) *DogsHandler {
@@ -18,15 +23,50 @@ func NewDogsHandler(s *dogs.Service) *DogsHandler {
 	return &DogsHandler{svc: s}
 }
 
+// GET /dogs?status=&include_archived=
 func (h *DogsHandler) List(w http.ResponseWriter, r *http.Request) {
-	items, err := h.svc.List(r.Context())
+	var q dogs.ListDogsQuery
+	if v := r.URL.Query().Get("status"); v != "" {
+		q.Status = &v
+	}
+	q.IncludeArchived = r.URL.Query().Get("include_archived") == "true"
+	if r.URL.Query().Get("mine") == "true" {
+		uid := currentUserID(r)
+		q.HandlerUserID = &uid
+	} else if v := r.URL.Query().Get("handler_id"); v != "" {
+		uid, err := strconv.ParseInt(v, 10, 64)
+		if err != nil {
+			writeError(w, 400, "invalid handler_id")
+			return
+		}
+		q.HandlerUserID = &uid
+	}
+	items, err := h.svc.List(r.Context(), q)
 	if err != nil {
+		if err == common.ErrValidation {
+			writeError(w, 400, "invalid status")
+			return
+		}
 		writeError(w, 500, err.Error())
 		return
 	}
 	writeJSON(w, 200, items)
 }
 
+func (h *DogsHandler) Get(w http.ResponseWriter, r *http.Request) {
+	id, _ := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	res, err := h.svc.Get(r.Context(), id)
+	if err != nil {
+		if err == common.ErrNotFound {
+			writeError(w, 404, "not found")
+			return
+		}
+		writeError(w, 500, err.Error())
+		return
+	}
+	writeJSON(w, 200, res)
+}
+
 func (h *DogsHandler) Create(w http.ResponseWriter, r *http.Request) {
 	var cmd dogs.CreateDogCommand
 	if err := json.NewDecoder(r.Body).Decode(&cmd); err != nil {
@@ -36,7 +76,11 @@ func (h *DogsHandler) Create(w http.ResponseWriter, r *http.Request) {
 	res, err := h.svc.Create(r.Context(), cmd)
 	if err != nil {
 		if err == common.ErrValidation {
-			writeError(w, 400, "missing name")
+			writeError(w, 400, "invalid input")
+			return
+		}
+		if err == common.ErrConflict {
+			writeError(w, 409, "microchip exists")
 			return
 		}
 		writeError(w, 500, err.Error())
@@ -63,6 +107,10 @@ func (h *DogsHandler) Update(w http.ResponseWriter, r *http.Request) {
 			writeError(w, 404, "not found")
 			return
 		}
+		if err == common.ErrConflict {
+			writeError(w, 409, "microchip exists")
+			return
+		}
 		writeError(w, 500, err.Error())
 		return
 	}
@@ -83,8 +131,134 @@ func (h *DogsHandler) Delete(w http.ResponseWriter, r *http.Request) {
 			writeError(w, 404, "not found")
 			return
 		}
+		if err == common.ErrConflict {
+			writeError(w, 409, "dog has history; archive or purge it instead")
+			return
+		}
 		writeError(w, 500, err.Error())
 		return
 	}
 	writeJSON(w, 200, "ok")
 }
+
+func (h *DogsHandler) Archive(w http.ResponseWriter, r *http.Request) {
+	id, _ := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	res, err := h.svc.Archive(r.Context(), id)
+	if err != nil {
+		if err == common.ErrNotFound {
+			writeError(w, 404, "not found")
+			return
+		}
+		writeError(w, 500, err.Error())
+		return
+	}
+	writeJSON(w, 200, res)
+}
+
+func (h *DogsHandler) Unarchive(w http.ResponseWriter, r *http.Request) {
+	id, _ := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	res, err := h.svc.Unarchive(r.Context(), id)
+	if err != nil {
+		if err == common.ErrNotFound {
+			writeError(w, 404, "not found")
+			return
+		}
+		writeError(w, 500, err.Error())
+		return
+	}
+	writeJSON(w, 200, res)
+}
+
+// POST /dogs/{id}/purge?dry_run=true
+func (h *DogsHandler) Purge(w http.ResponseWriter, r *http.Request) {
+	id, _ := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	cmd := dogs.PurgeDogCommand{ID: id, DryRun: r.URL.Query().Get("dry_run") == "true"}
+	res, err := h.svc.Purge(r.Context(), cmd)
+	if err != nil {
+		if err == common.ErrValidation {
+			writeError(w, 400, "invalid input")
+			return
+		}
+		if err == common.ErrNotFound {
+			writeError(w, 404, "not found")
+			return
+		}
+		writeError(w, 500, err.Error())
+		return
+	}
+	writeJSON(w, 200, res)
+}
+
+// PUT /dogs/{id}/photo (multipart form, field "file")
+func (h *DogsHandler) SetPhoto(w http.ResponseWriter, r *http.Request) {
+	id, _ := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	r.Body = http.MaxBytesReader(w, r.Body, maxPhotoSize+1<<20)
+	if err := r.ParseMultipartForm(maxPhotoSize); err != nil {
+		writeError(w, 400, "invalid multipart form")
+		return
+	}
+	f, hdr, err := r.FormFile("file")
+	if err != nil {
+		writeError(w, 400, "missing file")
+		return
+	}
+	defer f.Close()
+	data, err := io.ReadAll(io.LimitReader(f, maxPhotoSize+1))
+	if err != nil {
+		writeError(w, 400, "invalid file")
+		return
+	}
+	if len(data) > maxPhotoSize {
+		writeError(w, 413, "file too large")
+		return
+	}
+	err = h.svc.SetPhoto(r.Context(), dogs.SetPhotoCommand{DogID: id, ContentType: hdr.Header.Get("Content-Type"), Data: data})
+	if err != nil {
+		if err == common.ErrValidation {
+			writeError(w, 400, "jpeg, png, gif or webp image required")
+			return
+		}
+		if err == common.ErrNotFound {
+			writeError(w, 404, "not found")
+			return
+		}
+		writeError(w, 500, err.Error())
+		return
+	}
+	w.WriteHeader(204)
+}
+
+func (h *DogsHandler) GetPhoto(w http.ResponseWriter, r *http.Request) {
+	id, _ := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	p, err := h.svc.GetPhoto(r.Context(), id)
+	if err != nil {
+		if err == common.ErrNotFound {
+			writeError(w, 404, "not found")
+			return
+		}
+		writeError(w, 500, err.Error())
+		return
+	}
+	ct := p.ContentType
+	if !dog.PhotoTypes[ct] {
+		ct = "application/octet-stream"
+	}
+	w.Header().Set("Content-Type", ct)
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	w.Header().Set("Content-Length", strconv.Itoa(len(p.Data)))
+	w.WriteHeader(200)
+	_, _ = w.Write(p.Data)
+}
+
+func (h *DogsHandler) DeletePhoto(w http.ResponseWriter, r *http.Request) {
+	id, _ := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err := h.svc.DeletePhoto(r.Context(), id); err != nil {
+		if err == common.ErrNotFound {
+			writeError(w, 404, "not found")
+			return
+		}
+		writeError(w, 500, err.Error())
+		return
+	}
+	w.WriteHeader(204)
+}
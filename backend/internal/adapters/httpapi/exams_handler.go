@@ -0,0 +1,133 @@
+package httpapi
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/tnosaj/sar-training/backend/internal/application/exams"
+	"github.com/tnosaj/sar-training/backend/internal/domain/common"
+	logx "github.com/tnosaj/sar-training/backend/internal/infra/log"
+)
+
+type ExamsHandler struct{ svc *exams.Service }
+
+func NewExamsHandler(s *exams.Service) *ExamsHandler {
+	logx.Std.Trace("starting exams handler")
+	return &ExamsHandler{svc: s}
+}
+
+func (h *ExamsHandler) List(w http.ResponseWriter, r *http.Request) {
+	items, err := h.svc.List(r.Context())
+	if err != nil {
+		writeError(w, 500, err.Error())
+		return
+	}
+	writeJSON(w, 200, items)
+}
+
+func (h *ExamsHandler) Get(w http.ResponseWriter, r *http.Request) {
+	id, _ := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	res, err := h.svc.Get(r.Context(), id)
+	if err != nil {
+		if err == common.ErrNotFound {
+			writeError(w, 404, "not found")
+			return
+		}
+		writeError(w, 500, err.Error())
+		return
+	}
+	writeJSON(w, 200, res)
+}
+
+func (h *ExamsHandler) Create(w http.ResponseWriter, r *http.Request) {
+	var cmd exams.CreateExamCommand
+	if err := json.NewDecoder(r.Body).Decode(&cmd); err != nil {
+		writeError(w, 400, "invalid json")
+		return
+	}
+	res, err := h.svc.Create(r.Context(), cmd)
+	if err != nil {
+		if err == common.ErrValidation {
+			writeError(w, 400, "invalid input")
+			return
+		}
+		if err == common.ErrConflict {
+			writeError(w, 409, "name exists")
+			return
+		}
+		writeError(w, 500, err.Error())
+		return
+	}
+	writeJSON(w, 201, res)
+}
+
+func (h *ExamsHandler) Update(w http.ResponseWriter, r *http.Request) {
+	id, _ := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	var cmd exams.UpdateExamCommand
+	if err := json.NewDecoder(r.Body).Decode(&cmd); err != nil {
+		writeError(w, 400, "invalid json")
+		return
+	}
+	cmd.ID = id
+	res, err := h.svc.Update(r.Context(), cmd)
+	if err != nil {
+		if err == common.ErrValidation {
+			writeError(w, 400, "invalid input")
+			return
+		}
+		if err == common.ErrNotFound {
+			writeError(w, 404, "not found")
+			return
+		}
+		if err == common.ErrConflict {
+			writeError(w, 409, "name exists")
+			return
+		}
+		writeError(w, 500, err.Error())
+		return
+	}
+	writeJSON(w, 200, res)
+}
+
+func (h *ExamsHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	id, _ := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err := h.svc.Delete(r.Context(), id); err != nil {
+		if err == common.ErrNotFound {
+			writeError(w, 404, "not found")
+			return
+		}
+		writeError(w, 500, err.Error())
+		return
+	}
+	w.WriteHeader(204)
+}
+
+// GET /dogs/{id}/readiness/{examId}
+func (h *ExamsHandler) Readiness(w http.ResponseWriter, r *http.Request) {
+	dogID, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		writeError(w, 400, "invalid dog id")
+		return
+	}
+	examID, err := strconv.ParseInt(chi.URLParam(r, "examId"), 10, 64)
+	if err != nil {
+		writeError(w, 400, "invalid exam id")
+		return
+	}
+	res, err := h.svc.Readiness(r.Context(), exams.ReadinessQuery{DogID: dogID, ExamID: examID})
+	if err != nil {
+		if err == common.ErrValidation {
+			writeError(w, 400, "invalid input")
+			return
+		}
+		if err == common.ErrNotFound {
+			writeError(w, 404, "not found")
+			return
+		}
+		writeError(w, 500, err.Error())
+		return
+	}
+	writeJSON(w, 200, res)
+}
@@ -3,7 +3,9 @@ package httpapi
 import (
 	"encoding/json"
 	"net/http"
+	"strconv"
 
+	"github.com/go-chi/chi/v5"
 	"github.com/tnosaj/sar-training/backend/internal/application/exercises"
 	"github.com/tnosaj/sar-training/backend/internal/domain/common"
 	logx "github.com/tnosaj/sar-training/backend/internal/infra/log"
@@ -16,13 +18,25 @@ func NewExercisesHandler(s *exercises.Service) *ExercisesHandler {
 	return &ExercisesHandler{svc: s}
 }
 
+// GET /exercises?behavior_id=
 func (h *ExercisesHandler) List(w http.ResponseWriter, r *http.Request) {
-	items, err := h.svc.List(r.Context())
-	if err != nil {
-		writeError(w, 500, err.Error())
-		return
+	var q exercises.ListExercisesQuery
+	if v := r.URL.Query().Get("behavior_id"); v != "" {
+		id, err := strconv.ParseInt(v, 10, 64)
+		if err != nil {
+			writeError(w, 400, "invalid behavior_id")
+			return
+		}
+		q.BehaviorID = &id
 	}
-	writeJSON(w, 200, items)
+	items, err := h.svc.List(r.Context(), q)
+	h.write(w, 200, items, err, "")
+}
+
+func (h *ExercisesHandler) Get(w http.ResponseWriter, r *http.Request) {
+	id, _ := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	res, err := h.svc.Get(r.Context(), id)
+	h.write(w, 200, res, err, "")
 }
 
 func (h *ExercisesHandler) Create(w http.ResponseWriter, r *http.Request) {
@@ -32,15 +46,48 @@ func (h *ExercisesHandler) Create(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 	res, err := h.svc.Create(r.Context(), cmd)
-	if err != nil {
-		if err == common.ErrValidation {
-			writeError(w, 400, "missing name")
-			return
-		}
-		writeError(w, 500, err.Error())
+	h.write(w, 201, res, err, "name exists")
+}
+
+func (h *ExercisesHandler) Update(w http.ResponseWriter, r *http.Request) {
+	id, _ := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	var cmd exercises.UpdateExerciseCommand
+	if err := json.NewDecoder(r.Body).Decode(&cmd); err != nil {
+		writeError(w, 400, "invalid json")
 		return
 	}
-	writeJSON(w, 201, res)
+	cmd.ID = id
+	res, err := h.svc.Update(r.Context(), cmd)
+	h.write(w, 200, res, err, "name exists")
+}
+
+func (h *ExercisesHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	id, _ := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err := h.svc.Delete(r.Context(), id); err != nil {
+		h.write(w, 0, nil, err, "exercise is used by rounds or exam protocols")
+		return
+	}
+	w.WriteHeader(204)
+}
+
+// GET /behavior-exercises?behavior_id=&exercise_id=
+func (h *ExercisesHandler) ListLinks(w http.ResponseWriter, r *http.Request) {
+	var q exercises.ListLinksQuery
+	for _, p := range []struct {
+		name string
+		out  **int64
+	}{{"behavior_id", &q.BehaviorID}, {"exercise_id", &q.ExerciseID}} {
+		if v := r.URL.Query().Get(p.name); v != "" {
+			id, err := strconv.ParseInt(v, 10, 64)
+			if err != nil {
+				writeError(w, 400, "invalid "+p.name)
+				return
+			}
+			*p.out = &id
+		}
+	}
+	items, err := h.svc.Links(r.Context(), q)
+	h.write(w, 200, items, err, "")
 }
 
 func (h *ExercisesHandler) LinkBehaviorExercise(w http.ResponseWriter, r *http.Request) {
@@ -49,9 +96,108 @@ func (h *ExercisesHandler) LinkBehaviorExercise(w http.ResponseWriter, r *http.R
 		writeError(w, 400, "invalid json")
 		return
 	}
-	if err := h.svc.LinkBehavior(r.Context(), cmd); err != nil {
-		writeError(w, 400, err.Error())
+	res, err := h.svc.LinkBehavior(r.Context(), cmd)
+	h.write(w, 200, res, err, "")
+}
+
+// DELETE /behavior-exercises/{behaviorId}/{exerciseId}
+func (h *ExercisesHandler) UnlinkBehaviorExercise(w http.ResponseWriter, r *http.Request) {
+	behaviorID, _ := strconv.ParseInt(chi.URLParam(r, "behaviorId"), 10, 64)
+	exerciseID, _ := strconv.ParseInt(chi.URLParam(r, "exerciseId"), 10, 64)
+	if err := h.svc.UnlinkBehavior(r.Context(), behaviorID, exerciseID); err != nil {
+		h.write(w, 0, nil, err, "")
+		return
+	}
+	w.WriteHeader(204)
+}
+
+func (h *ExercisesHandler) write(w http.ResponseWriter, code int, res any, err error, conflict string) {
+	if err != nil {
+		switch err {
+		case common.ErrValidation:
+			writeError(w, 400, "invalid input")
+		case common.ErrNotFound:
+			writeError(w, 404, "not found")
+		case common.ErrConflict:
+			writeError(w, 409, conflict)
+		default:
+			writeError(w, 500, err.Error())
+		}
+		return
+	}
+	writeJSON(w, code, res)
+}
+
+// GET /exercises/{id}/rubric
+func (h *ExercisesHandler) GetRubric(w http.ResponseWriter, r *http.Request) {
+	id, _ := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	res, err := h.svc.Rubric(r.Context(), id)
+	h.writeRubric(w, res, err)
+}
+
+// PUT /exercises/{id}/rubric
+func (h *ExercisesHandler) SetRubric(w http.ResponseWriter, r *http.Request) {
+	id, _ := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	var cmd exercises.SetRubricCommand
+	if err := json.NewDecoder(r.Body).Decode(&cmd); err != nil {
+		writeError(w, 400, "invalid json")
+		return
+	}
+	cmd.ExerciseID = id
+	res, err := h.svc.SetRubric(r.Context(), cmd)
+	h.writeRubric(w, res, err)
+}
+
+func (h *ExercisesHandler) writeRubric(w http.ResponseWriter, res any, err error) {
+	if err != nil {
+		switch err {
+		case common.ErrValidation:
+			writeError(w, 400, "invalid criteria")
+		case common.ErrNotFound:
+			writeError(w, 404, "exercise not found")
+		case common.ErrConflict:
+			writeError(w, 409, "criterion name taken, or criterion already scored on rounds")
+		default:
+			writeError(w, 500, err.Error())
+		}
+		return
+	}
+	writeJSON(w, 200, res)
+}
+
+// GET /exercises/{id}/difficulty
+func (h *ExercisesHandler) GetDifficulty(w http.ResponseWriter, r *http.Request) {
+	id, _ := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	res, err := h.svc.Difficulty(r.Context(), id)
+	h.writeDifficulty(w, res, err)
+}
+
+// PUT /exercises/{id}/difficulty
+func (h *ExercisesHandler) SetDifficulty(w http.ResponseWriter, r *http.Request) {
+	id, _ := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	var cmd exercises.SetDifficultyCommand
+	if err := json.NewDecoder(r.Body).Decode(&cmd); err != nil {
+		writeError(w, 400, "invalid json")
+		return
+	}
+	cmd.ExerciseID = id
+	res, err := h.svc.SetDifficulty(r.Context(), cmd)
+	h.writeDifficulty(w, res, err)
+}
+
+func (h *ExercisesHandler) writeDifficulty(w http.ResponseWriter, res any, err error) {
+	if err != nil {
+		switch err {
+		case common.ErrValidation:
+			writeError(w, 400, "invalid parameters or levels")
+		case common.ErrNotFound:
+			writeError(w, 404, "exercise not found")
+		case common.ErrConflict:
+			writeError(w, 409, "a level rounds were trained at would be renumbered, renamed or removed")
+		default:
+			writeError(w, 500, err.Error())
+		}
 		return
 	}
-	writeJSON(w, 200, map[string]any{"ok": true})
+	writeJSON(w, 200, res)
 }
@@ -0,0 +1,223 @@
+package httpapi
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/tnosaj/sar-training/backend/internal/application/figurants"
+	"github.com/tnosaj/sar-training/backend/internal/domain/common"
+	logx "github.com/tnosaj/sar-training/backend/internal/infra/log"
+)
+
+type FigurantsHandler struct{ svc *figurants.Service }
+
+func NewFigurantsHandler(s *figurants.Service) *FigurantsHandler {
+	logx.Std.Trace("starting figurants handler")
+	return &FigurantsHandler{svc: s}
+}
+
+// GET /figurants?active=true&available_on=YYYY-MM-DD
+func (h *FigurantsHandler) List(w http.ResponseWriter, r *http.Request) {
+	q := figurants.ListFigurantsQuery{ActiveOnly: r.URL.Query().Get("active") == "true"}
+	if v := r.URL.Query().Get("available_on"); v != "" {
+		q.AvailableOn = &v
+	}
+	items, err := h.svc.List(r.Context(), q)
+	h.write(w, 200, items, err)
+}
+
+func (h *FigurantsHandler) Create(w http.ResponseWriter, r *http.Request) {
+	var cmd figurants.CreateFigurantCommand
+	if err := json.NewDecoder(r.Body).Decode(&cmd); err != nil {
+		writeError(w, 400, "invalid json")
+		return
+	}
+	res, err := h.svc.Create(r.Context(), cmd)
+	h.write(w, 201, res, err)
+}
+
+func (h *FigurantsHandler) Get(w http.ResponseWriter, r *http.Request) {
+	id, _ := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	res, err := h.svc.Get(r.Context(), id)
+	h.write(w, 200, res, err)
+}
+
+func (h *FigurantsHandler) Update(w http.ResponseWriter, r *http.Request) {
+	id, _ := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	var cmd figurants.UpdateFigurantCommand
+	if err := json.NewDecoder(r.Body).Decode(&cmd); err != nil {
+		writeError(w, 400, "invalid json")
+		return
+	}
+	cmd.ID = id
+	res, err := h.svc.Update(r.Context(), cmd)
+	h.write(w, 200, res, err)
+}
+
+func (h *FigurantsHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	id, _ := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err := h.svc.Delete(r.Context(), id); err != nil {
+		h.write(w, 0, nil, err)
+		return
+	}
+	w.WriteHeader(204)
+}
+
+func (h *FigurantsHandler) ListAvailability(w http.ResponseWriter, r *http.Request) {
+	id, _ := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	items, err := h.svc.ListAvailability(r.Context(), id)
+	h.write(w, 200, items, err)
+}
+
+func (h *FigurantsHandler) AddAvailability(w http.ResponseWriter, r *http.Request) {
+	id, _ := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	var cmd figurants.AddAvailabilityCommand
+	if err := json.NewDecoder(r.Body).Decode(&cmd); err != nil {
+		writeError(w, 400, "invalid json")
+		return
+	}
+	cmd.FigurantID = id
+	res, err := h.svc.AddAvailability(r.Context(), cmd)
+	h.write(w, 201, res, err)
+}
+
+func (h *FigurantsHandler) DeleteAvailability(w http.ResponseWriter, r *http.Request) {
+	id, _ := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	availabilityID, _ := strconv.ParseInt(chi.URLParam(r, "availabilityId"), 10, 64)
+	if err := h.svc.DeleteAvailability(r.Context(), id, availabilityID); err != nil {
+		h.write(w, 0, nil, err)
+		return
+	}
+	w.WriteHeader(204)
+}
+
+// GET /figurants/{id}/history?dog_id=
+func (h *FigurantsHandler) History(w http.ResponseWriter, r *http.Request) {
+	id, _ := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	var dogID *int64
+	if v := r.URL.Query().Get("dog_id"); v != "" {
+		d, err := strconv.ParseInt(v, 10, 64)
+		if err != nil {
+			writeError(w, 400, "invalid dog id")
+			return
+		}
+		dogID = &d
+	}
+	items, err := h.svc.History(r.Context(), id, dogID)
+	h.write(w, 200, items, err)
+}
+
+func (h *FigurantsHandler) ListBySession(w http.ResponseWriter, r *http.Request) {
+	id, _ := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	items, err := h.svc.SessionFigurants(r.Context(), id)
+	h.write(w, 200, items, err)
+}
+
+// POST /sessions/{id}/figurants {"figurant_id":1}
+func (h *FigurantsHandler) AssignSession(w http.ResponseWriter, r *http.Request) {
+	id, _ := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	var body struct {
+		FigurantID int64 `json:"figurant_id"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		writeError(w, 400, "invalid json")
+		return
+	}
+	if err := h.svc.AssignSession(r.Context(), id, body.FigurantID); err != nil {
+		h.write(w, 0, nil, err)
+		return
+	}
+	w.WriteHeader(204)
+}
+
+func (h *FigurantsHandler) UnassignSession(w http.ResponseWriter, r *http.Request) {
+	id, _ := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	figurantID, _ := strconv.ParseInt(chi.URLParam(r, "figurantId"), 10, 64)
+	if err := h.svc.UnassignSession(r.Context(), id, figurantID); err != nil {
+		h.write(w, 0, nil, err)
+		return
+	}
+	w.WriteHeader(204)
+}
+
+// GET /sessions/{id}/figurants/suggestions?dog_id=&window_days=
+func (h *FigurantsHandler) Suggest(w http.ResponseWriter, r *http.Request) {
+	id, _ := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	q := figurants.SuggestFigurantsQuery{SessionID: id}
+	var err error
+	if q.DogID, err = strconv.ParseInt(r.URL.Query().Get("dog_id"), 10, 64); err != nil {
+		writeError(w, 400, "invalid dog id")
+		return
+	}
+	if q.WindowDays, err = windowDays(r); err != nil {
+		writeError(w, 400, "invalid window_days")
+		return
+	}
+	items, err := h.svc.Suggest(r.Context(), q)
+	h.write(w, 200, items, err)
+}
+
+// GET /sessions/{id}/rounds/{roundId}/figurants?window_days=
+func (h *FigurantsHandler) ListByRound(w http.ResponseWriter, r *http.Request) {
+	id, _ := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	roundID, _ := strconv.ParseInt(chi.URLParam(r, "roundId"), 10, 64)
+	days, err := windowDays(r)
+	if err != nil {
+		writeError(w, 400, "invalid window_days")
+		return
+	}
+	items, err := h.svc.RoundFigurants(r.Context(), id, roundID, days)
+	h.write(w, 200, items, err)
+}
+
+// PUT /sessions/{id}/rounds/{roundId}/figurants {"figurant_ids":[1,2]}
+func (h *FigurantsHandler) SetRoundFigurants(w http.ResponseWriter, r *http.Request) {
+	var cmd figurants.SetRoundFigurantsCommand
+	if err := json.NewDecoder(r.Body).Decode(&cmd); err != nil {
+		writeError(w, 400, "invalid json")
+		return
+	}
+	cmd.SessionID, _ = strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	cmd.RoundID, _ = strconv.ParseInt(chi.URLParam(r, "roundId"), 10, 64)
+	cmd.UserID = currentUserID(r)
+	var err error
+	if cmd.WindowDays, err = windowDays(r); err != nil {
+		writeError(w, 400, "invalid window_days")
+		return
+	}
+	items, err := h.svc.SetRoundFigurants(r.Context(), cmd)
+	h.write(w, 200, items, err)
+}
+
+func windowDays(r *http.Request) (*int, error) {
+	v := r.URL.Query().Get("window_days")
+	if v == "" {
+		return nil, nil
+	}
+	n, err := strconv.Atoi(v)
+	if err != nil {
+		return nil, err
+	}
+	return &n, nil
+}
+
+func (h *FigurantsHandler) write(w http.ResponseWriter, code int, res any, err error) {
+	if err != nil {
+		switch err {
+		case common.ErrValidation:
+			writeError(w, 400, "invalid input")
+		case common.ErrForbidden:
+			writeError(w, 403, "not a handler of this dog")
+		case common.ErrNotFound:
+			writeError(w, 404, "not found")
+		case common.ErrConflict:
+			writeError(w, 409, "figurant has round history; deactivate instead")
+		default:
+			writeError(w, 500, err.Error())
+		}
+		return
+	}
+	writeJSON(w, code, res)
+}
@@ -0,0 +1,134 @@
+package httpapi
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/tnosaj/sar-training/backend/internal/application/healthlog"
+	"github.com/tnosaj/sar-training/backend/internal/domain/common"
+	logx "github.com/tnosaj/sar-training/backend/internal/infra/log"
+)
+
+type HealthHandler struct{ svc *healthlog.Service }
+
+func NewHealthHandler(s *healthlog.Service) *HealthHandler {
+	logx.Std.Trace("starting health handler")
+	return &HealthHandler{svc: s}
+}
+
+// GET /dogs/{id}/health?kind=&from=&to=
+func (h *HealthHandler) List(w http.ResponseWriter, r *http.Request) {
+	id, _ := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	q := healthlog.ListEntriesQuery{DogID: id}
+	if v := r.URL.Query().Get("kind"); v != "" {
+		q.Kind = &v
+	}
+	if v := r.URL.Query().Get("from"); v != "" {
+		q.From = &v
+	}
+	if v := r.URL.Query().Get("to"); v != "" {
+		q.To = &v
+	}
+	items, err := h.svc.List(r.Context(), q)
+	if err != nil {
+		if err == common.ErrValidation {
+			writeError(w, 400, "invalid filter")
+			return
+		}
+		writeError(w, 500, err.Error())
+		return
+	}
+	writeJSON(w, 200, items)
+}
+
+func (h *HealthHandler) Create(w http.ResponseWriter, r *http.Request) {
+	id, _ := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	var cmd healthlog.CreateEntryCommand
+	if err := json.NewDecoder(r.Body).Decode(&cmd); err != nil {
+		writeError(w, 400, "invalid json")
+		return
+	}
+	cmd.DogID = id
+	res, err := h.svc.Create(r.Context(), cmd)
+	h.write(w, 201, res, err)
+}
+
+func (h *HealthHandler) Get(w http.ResponseWriter, r *http.Request) {
+	id, _ := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	eid, _ := strconv.ParseInt(chi.URLParam(r, "entryId"), 10, 64)
+	res, err := h.svc.Get(r.Context(), id, eid)
+	h.write(w, 200, res, err)
+}
+
+func (h *HealthHandler) Update(w http.ResponseWriter, r *http.Request) {
+	id, _ := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	eid, _ := strconv.ParseInt(chi.URLParam(r, "entryId"), 10, 64)
+	var cmd healthlog.UpdateEntryCommand
+	if err := json.NewDecoder(r.Body).Decode(&cmd); err != nil {
+		writeError(w, 400, "invalid json")
+		return
+	}
+	cmd.ID = eid
+	cmd.DogID = id
+	res, err := h.svc.Update(r.Context(), cmd)
+	h.write(w, 200, res, err)
+}
+
+func (h *HealthHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	id, _ := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	eid, _ := strconv.ParseInt(chi.URLParam(r, "entryId"), 10, 64)
+	if err := h.svc.Delete(r.Context(), id, eid); err != nil {
+		h.write(w, 0, nil, err)
+		return
+	}
+	w.WriteHeader(204)
+}
+
+// GET /dogs/{id}/fitness?date=2025-01-31
+func (h *HealthHandler) Fitness(w http.ResponseWriter, r *http.Request) {
+	id, _ := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	day := time.Now().UTC()
+	if v := r.URL.Query().Get("date"); v != "" {
+		t, err := common.ParseDate(v)
+		if err != nil {
+			writeError(w, 400, "invalid date")
+			return
+		}
+		day = t
+	}
+	res, err := h.svc.Fitness(r.Context(), id, day)
+	h.write(w, 200, res, err)
+}
+
+// GET /health/due?within_days=30
+func (h *HealthHandler) Due(w http.ResponseWriter, r *http.Request) {
+	within := 0
+	if v := r.URL.Query().Get("within_days"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil {
+			writeError(w, 400, "invalid within_days")
+			return
+		}
+		within = n
+	}
+	items, err := h.svc.Due(r.Context(), within)
+	h.write(w, 200, items, err)
+}
+
+func (h *HealthHandler) write(w http.ResponseWriter, code int, res any, err error) {
+	if err != nil {
+		switch err {
+		case common.ErrValidation:
+			writeError(w, 400, "invalid input")
+		case common.ErrNotFound:
+			writeError(w, 404, "not found")
+		default:
+			writeError(w, 500, err.Error())
+		}
+		return
+	}
+	writeJSON(w, code, res)
+}
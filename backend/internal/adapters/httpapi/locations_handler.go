@@ -0,0 +1,123 @@
+package httpapi
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/tnosaj/sar-training/backend/internal/application/locations"
+	"github.com/tnosaj/sar-training/backend/internal/domain/common"
+	logx "github.com/tnosaj/sar-training/backend/internal/infra/log"
+)
+
+type LocationsHandler struct{ svc *locations.Service }
+
+func NewLocationsHandler(s *locations.Service) *LocationsHandler {
+	logx.Std.Trace("starting locations handler")
+	return &LocationsHandler{svc: s}
+}
+
+func (h *LocationsHandler) List(w http.ResponseWriter, r *http.Request) {
+	items, err := h.svc.List(r.Context())
+	if err != nil {
+		writeError(w, 500, err.Error())
+		return
+	}
+	writeJSON(w, 200, items)
+}
+
+func (h *LocationsHandler) Create(w http.ResponseWriter, r *http.Request) {
+	var cmd locations.CreateLocationCommand
+	if err := json.NewDecoder(r.Body).Decode(&cmd); err != nil {
+		writeError(w, 400, "invalid json")
+		return
+	}
+	res, err := h.svc.Create(r.Context(), cmd)
+	h.write(w, 201, res, err)
+}
+
+func (h *LocationsHandler) Get(w http.ResponseWriter, r *http.Request) {
+	id, _ := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	res, err := h.svc.Get(r.Context(), id)
+	h.write(w, 200, res, err)
+}
+
+func (h *LocationsHandler) Update(w http.ResponseWriter, r *http.Request) {
+	id, _ := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	var cmd locations.UpdateLocationCommand
+	if err := json.NewDecoder(r.Body).Decode(&cmd); err != nil {
+		writeError(w, 400, "invalid json")
+		return
+	}
+	cmd.ID = id
+	res, err := h.svc.Update(r.Context(), cmd)
+	h.write(w, 200, res, err)
+}
+
+func (h *LocationsHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	id, _ := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err := h.svc.Delete(r.Context(), id); err != nil {
+		h.write(w, 0, nil, err)
+		return
+	}
+	w.WriteHeader(204)
+}
+
+// GET /locations/nearby?lat=47.1&lon=8.5&radius_km=10
+func (h *LocationsHandler) Nearby(w http.ResponseWriter, r *http.Request) {
+	var q locations.NearbyQuery
+	var err error
+	if q.Latitude, err = strconv.ParseFloat(r.URL.Query().Get("lat"), 64); err != nil {
+		writeError(w, 400, "invalid lat")
+		return
+	}
+	if q.Longitude, err = strconv.ParseFloat(r.URL.Query().Get("lon"), 64); err != nil {
+		writeError(w, 400, "invalid lon")
+		return
+	}
+	if v := r.URL.Query().Get("radius_km"); v != "" {
+		if q.RadiusKm, err = strconv.ParseFloat(v, 64); err != nil {
+			writeError(w, 400, "invalid radius_km")
+			return
+		}
+	}
+	res, err := h.svc.Nearby(r.Context(), q)
+	h.write(w, 200, res, err)
+}
+
+// POST /locations/{id}/merge {"source_ids":[2,3]}
+func (h *LocationsHandler) Merge(w http.ResponseWriter, r *http.Request) {
+	id, _ := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	var cmd locations.MergeLocationsCommand
+	if err := json.NewDecoder(r.Body).Decode(&cmd); err != nil {
+		writeError(w, 400, "invalid json")
+		return
+	}
+	cmd.TargetID = id
+	res, err := h.svc.Merge(r.Context(), cmd)
+	h.write(w, 200, res, err)
+}
+
+func (h *LocationsHandler) Stats(w http.ResponseWriter, r *http.Request) {
+	id, _ := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	res, err := h.svc.Stats(r.Context(), id)
+	h.write(w, 200, res, err)
+}
+
+func (h *LocationsHandler) write(w http.ResponseWriter, code int, res any, err error) {
+	if err != nil {
+		switch err {
+		case common.ErrValidation:
+			writeError(w, 400, "invalid input")
+		case common.ErrNotFound:
+			writeError(w, 404, "not found")
+		case common.ErrConflict:
+			writeError(w, 409, "location name already exists")
+		default:
+			writeError(w, 500, err.Error())
+		}
+		return
+	}
+	writeJSON(w, code, res)
+}
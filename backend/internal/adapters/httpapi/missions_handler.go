@@ -0,0 +1,130 @@
+package httpapi
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/tnosaj/sar-training/backend/internal/application/missions"
+	"github.com/tnosaj/sar-training/backend/internal/domain/common"
+	logx "github.com/tnosaj/sar-training/backend/internal/infra/log"
+)
+
+type MissionsHandler struct{ svc *missions.Service }
+
+func NewMissionsHandler(s *missions.Service) *MissionsHandler {
+	logx.Std.Trace("starting missions handler")
+	return &MissionsHandler{svc: s}
+}
+
+// GET /missions?dog_id=&handler_id=&outcome=&from=&to=
+func (h *MissionsHandler) List(w http.ResponseWriter, r *http.Request) {
+	q, ok := missionsQuery(w, r)
+	if !ok {
+		return
+	}
+	if v := r.URL.Query().Get("outcome"); v != "" {
+		q.Outcome = &v
+	}
+	items, err := h.svc.List(r.Context(), q)
+	h.write(w, 200, items, err)
+}
+
+// GET /dogs/{id}/missions
+func (h *MissionsHandler) ListByDog(w http.ResponseWriter, r *http.Request) {
+	id, _ := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	items, err := h.svc.List(r.Context(), missions.ListMissionsQuery{DogID: &id})
+	h.write(w, 200, items, err)
+}
+
+func (h *MissionsHandler) Create(w http.ResponseWriter, r *http.Request) {
+	var cmd missions.CreateMissionCommand
+	if err := json.NewDecoder(r.Body).Decode(&cmd); err != nil {
+		writeError(w, 400, "invalid json")
+		return
+	}
+	res, err := h.svc.Create(r.Context(), cmd)
+	h.write(w, 201, res, err)
+}
+
+func (h *MissionsHandler) Get(w http.ResponseWriter, r *http.Request) {
+	id, _ := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	res, err := h.svc.Get(r.Context(), id)
+	h.write(w, 200, res, err)
+}
+
+func (h *MissionsHandler) Update(w http.ResponseWriter, r *http.Request) {
+	id, _ := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	var cmd missions.UpdateMissionCommand
+	if err := json.NewDecoder(r.Body).Decode(&cmd); err != nil {
+		writeError(w, 400, "invalid json")
+		return
+	}
+	cmd.ID = id
+	res, err := h.svc.Update(r.Context(), cmd)
+	h.write(w, 200, res, err)
+}
+
+func (h *MissionsHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	id, _ := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err := h.svc.Delete(r.Context(), id); err != nil {
+		h.write(w, 0, nil, err)
+		return
+	}
+	w.WriteHeader(204)
+}
+
+// GET /analytics/deployments?dog_id=&handler_id=&from=&to=
+func (h *MissionsHandler) Deployments(w http.ResponseWriter, r *http.Request) {
+	q, ok := missionsQuery(w, r)
+	if !ok {
+		return
+	}
+	items, err := h.svc.Deployments(r.Context(), q)
+	h.write(w, 200, items, err)
+}
+
+func missionsQuery(w http.ResponseWriter, r *http.Request) (missions.ListMissionsQuery, bool) {
+	var q missions.ListMissionsQuery
+	if v := r.URL.Query().Get("dog_id"); v != "" {
+		id, err := strconv.ParseInt(v, 10, 64)
+		if err != nil {
+			writeError(w, 400, "invalid dog id")
+			return q, false
+		}
+		q.DogID = &id
+	}
+	if v := r.URL.Query().Get("handler_id"); v != "" {
+		id, err := strconv.ParseInt(v, 10, 64)
+		if err != nil {
+			writeError(w, 400, "invalid handler id")
+			return q, false
+		}
+		q.HandlerID = &id
+	}
+	if v := r.URL.Query().Get("from"); v != "" {
+		q.From = &v
+	}
+	if v := r.URL.Query().Get("to"); v != "" {
+		q.To = &v
+	}
+	return q, true
+}
+
+func (h *MissionsHandler) write(w http.ResponseWriter, code int, res any, err error) {
+	if err != nil {
+		switch err {
+		case common.ErrValidation:
+			writeError(w, 400, "invalid input")
+		case common.ErrNotFound:
+			writeError(w, 404, "not found")
+		case common.ErrConflict:
+			writeError(w, 409, "dog deployed twice on the mission")
+		default:
+			writeError(w, 500, err.Error())
+		}
+		return
+	}
+	writeJSON(w, code, res)
+}
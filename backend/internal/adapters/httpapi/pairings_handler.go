@@ -0,0 +1,110 @@
+package httpapi
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/tnosaj/sar-training/backend/internal/application/pairings"
+	"github.com/tnosaj/sar-training/backend/internal/domain/common"
+	logx "github.com/tnosaj/sar-training/backend/internal/infra/log"
+)
+
+type PairingsHandler struct{ svc *pairings.Service }
+
+func NewPairingsHandler(s *pairings.Service) *PairingsHandler {
+	logx.Std.Trace("starting pairings handler")
+	return &PairingsHandler{svc: s}
+}
+
+// GET /dogs/{id}/handlers?active_on=2025-01-31
+func (h *PairingsHandler) ListByDog(w http.ResponseWriter, r *http.Request) {
+	id, _ := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	q := pairings.ListPairingsQuery{DogID: &id}
+	if v := r.URL.Query().Get("active_on"); v != "" {
+		q.ActiveOn = &v
+	}
+	h.writeList(w, r, q)
+}
+
+// GET /users/me/dogs lists the current user's pairings.
+func (h *PairingsHandler) ListMine(w http.ResponseWriter, r *http.Request) {
+	uid := currentUserID(r)
+	q := pairings.ListPairingsQuery{UserID: &uid}
+	if v := r.URL.Query().Get("active_on"); v != "" {
+		q.ActiveOn = &v
+	}
+	h.writeList(w, r, q)
+}
+
+func (h *PairingsHandler) writeList(w http.ResponseWriter, r *http.Request, q pairings.ListPairingsQuery) {
+	items, err := h.svc.List(r.Context(), q)
+	if err != nil {
+		if err == common.ErrValidation {
+			writeError(w, 400, "invalid active_on")
+			return
+		}
+		writeError(w, 500, err.Error())
+		return
+	}
+	writeJSON(w, 200, items)
+}
+
+func (h *PairingsHandler) Create(w http.ResponseWriter, r *http.Request) {
+	id, _ := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	var cmd pairings.CreatePairingCommand
+	if err := json.NewDecoder(r.Body).Decode(&cmd); err != nil {
+		writeError(w, 400, "invalid json")
+		return
+	}
+	cmd.DogID = id
+	cmd.ActorID = currentUserID(r)
+	res, err := h.svc.Create(r.Context(), cmd)
+	h.write(w, 201, res, err)
+}
+
+func (h *PairingsHandler) Update(w http.ResponseWriter, r *http.Request) {
+	id, _ := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	pid, _ := strconv.ParseInt(chi.URLParam(r, "pairingId"), 10, 64)
+	var cmd pairings.UpdatePairingCommand
+	if err := json.NewDecoder(r.Body).Decode(&cmd); err != nil {
+		writeError(w, 400, "invalid json")
+		return
+	}
+	cmd.ID = pid
+	cmd.DogID = id
+	cmd.ActorID = currentUserID(r)
+	res, err := h.svc.Update(r.Context(), cmd)
+	h.write(w, 200, res, err)
+}
+
+func (h *PairingsHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	id, _ := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	pid, _ := strconv.ParseInt(chi.URLParam(r, "pairingId"), 10, 64)
+	err := h.svc.Delete(r.Context(), pairings.DeletePairingCommand{ID: pid, DogID: id, ActorID: currentUserID(r)})
+	if err == nil {
+		w.WriteHeader(204)
+		return
+	}
+	h.write(w, 0, nil, err)
+}
+
+func (h *PairingsHandler) write(w http.ResponseWriter, code int, res any, err error) {
+	if err != nil {
+		switch err {
+		case common.ErrValidation:
+			writeError(w, 400, "invalid input")
+		case common.ErrForbidden:
+			writeError(w, 403, "only admins and the primary handler can change pairings")
+		case common.ErrNotFound:
+			writeError(w, 404, "not found")
+		case common.ErrConflict:
+			writeError(w, 409, "pairing overlaps an existing one")
+		default:
+			writeError(w, 500, err.Error())
+		}
+		return
+	}
+	writeJSON(w, code, res)
+}
@@ -0,0 +1,162 @@
+package httpapi
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/tnosaj/sar-training/backend/internal/application/protocols"
+	"github.com/tnosaj/sar-training/backend/internal/domain/common"
+	logx "github.com/tnosaj/sar-training/backend/internal/infra/log"
+)
+
+type ProtocolsHandler struct{ svc *protocols.Service }
+
+func NewProtocolsHandler(s *protocols.Service) *ProtocolsHandler {
+	logx.Std.Trace("starting protocols handler")
+	return &ProtocolsHandler{svc: s}
+}
+
+func (h *ProtocolsHandler) List(w http.ResponseWriter, r *http.Request) {
+	items, err := h.svc.List(r.Context())
+	h.write(w, 200, items, err, "")
+}
+
+func (h *ProtocolsHandler) Get(w http.ResponseWriter, r *http.Request) {
+	id, _ := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	res, err := h.svc.Get(r.Context(), id)
+	h.write(w, 200, res, err, "")
+}
+
+func (h *ProtocolsHandler) Create(w http.ResponseWriter, r *http.Request) {
+	var cmd protocols.CreateProtocolCommand
+	if err := json.NewDecoder(r.Body).Decode(&cmd); err != nil {
+		writeError(w, 400, "invalid json")
+		return
+	}
+	res, err := h.svc.Create(r.Context(), cmd)
+	h.write(w, 201, res, err, "name exists")
+}
+
+func (h *ProtocolsHandler) Update(w http.ResponseWriter, r *http.Request) {
+	id, _ := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	var cmd protocols.UpdateProtocolCommand
+	if err := json.NewDecoder(r.Body).Decode(&cmd); err != nil {
+		writeError(w, 400, "invalid json")
+		return
+	}
+	cmd.ID = id
+	res, err := h.svc.Update(r.Context(), cmd)
+	h.write(w, 200, res, err, "name exists, or the tasks or pass ratio changed after runs started")
+}
+
+func (h *ProtocolsHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	id, _ := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err := h.svc.Delete(r.Context(), id); err != nil {
+		h.write(w, 0, nil, err, "protocol has exam runs")
+		return
+	}
+	w.WriteHeader(204)
+}
+
+// POST /protocols/{id}/runs
+func (h *ProtocolsHandler) StartRun(w http.ResponseWriter, r *http.Request) {
+	id, _ := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	var cmd protocols.StartRunCommand
+	if err := json.NewDecoder(r.Body).Decode(&cmd); err != nil {
+		writeError(w, 400, "invalid json")
+		return
+	}
+	cmd.ProtocolID = id
+	res, err := h.svc.StartRun(r.Context(), cmd)
+	h.write(w, 201, res, err, "dog not fit for training")
+}
+
+// GET /exam-runs?dog_id=&protocol_id=
+func (h *ProtocolsHandler) ListRuns(w http.ResponseWriter, r *http.Request) {
+	var q protocols.RunQuery
+	for _, p := range []struct {
+		name string
+		out  **int64
+	}{{"dog_id", &q.DogID}, {"protocol_id", &q.ProtocolID}} {
+		if v := r.URL.Query().Get(p.name); v != "" {
+			id, err := strconv.ParseInt(v, 10, 64)
+			if err != nil {
+				writeError(w, 400, "invalid "+p.name)
+				return
+			}
+			*p.out = &id
+		}
+	}
+	items, err := h.svc.ListRuns(r.Context(), q)
+	h.write(w, 200, items, err, "")
+}
+
+// GET /exam-runs/{id}
+func (h *ProtocolsHandler) Result(w http.ResponseWriter, r *http.Request) {
+	id, _ := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	res, err := h.svc.Result(r.Context(), id)
+	h.write(w, 200, res, err, "")
+}
+
+// PUT /exam-runs/{id}/tasks/{taskId}
+func (h *ProtocolsHandler) SetTaskRound(w http.ResponseWriter, r *http.Request) {
+	var cmd protocols.SetTaskRoundCommand
+	if err := json.NewDecoder(r.Body).Decode(&cmd); err != nil {
+		writeError(w, 400, "invalid json")
+		return
+	}
+	cmd.RunID, _ = strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	cmd.TaskID, _ = strconv.ParseInt(chi.URLParam(r, "taskId"), 10, 64)
+	res, err := h.svc.SetTaskRound(r.Context(), cmd)
+	h.write(w, 200, res, err, "run completed or round judged for another task")
+}
+
+// DELETE /exam-runs/{id}/tasks/{taskId}
+func (h *ProtocolsHandler) DeleteTaskRound(w http.ResponseWriter, r *http.Request) {
+	id, _ := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	taskID, _ := strconv.ParseInt(chi.URLParam(r, "taskId"), 10, 64)
+	if err := h.svc.DeleteTaskRound(r.Context(), id, taskID); err != nil {
+		h.write(w, 0, nil, err, "run completed")
+		return
+	}
+	w.WriteHeader(204)
+}
+
+// POST /exam-runs/{id}/complete
+func (h *ProtocolsHandler) Complete(w http.ResponseWriter, r *http.Request) {
+	id, _ := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	res, err := h.svc.Complete(r.Context(), id)
+	h.write(w, 200, res, err, "run completed")
+}
+
+// GET /exam-runs/{id}/sheet
+func (h *ProtocolsHandler) Sheet(w http.ResponseWriter, r *http.Request) {
+	id, _ := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	sheet, err := h.svc.Sheet(r.Context(), id)
+	if err != nil {
+		h.write(w, 0, nil, err, "")
+		return
+	}
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	w.WriteHeader(200)
+	w.Write(sheet)
+}
+
+func (h *ProtocolsHandler) write(w http.ResponseWriter, code int, res any, err error, conflict string) {
+	if err != nil {
+		switch err {
+		case common.ErrValidation:
+			writeError(w, 400, "invalid input")
+		case common.ErrNotFound:
+			writeError(w, 404, "not found")
+		case common.ErrConflict:
+			writeError(w, 409, conflict)
+		default:
+			writeError(w, 500, err.Error())
+		}
+		return
+	}
+	writeJSON(w, code, res)
+}
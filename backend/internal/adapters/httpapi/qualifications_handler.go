@@ -0,0 +1,275 @@
+package httpapi
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"mime"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/tnosaj/sar-training/backend/internal/application/qualifications"
+	"github.com/tnosaj/sar-training/backend/internal/domain/common"
+	"github.com/tnosaj/sar-training/backend/internal/domain/qualification"
+	logx "github.com/tnosaj/sar-training/backend/internal/infra/log"
+)
+
+// maxAttachmentSize caps uploaded certificate scans and similar documents.
+const maxAttachmentSize = 10 << 20
+
+type QualificationsHandler struct{ svc *qualifications.Service }
+
+func NewQualificationsHandler(s *qualifications.Service) *QualificationsHandler {
+	logx.Std.Trace("starting qualifications handler")
+	return &QualificationsHandler{svc: s}
+}
+
+// GET /qualifications?dog_id=&handler_id=&exam_type=&discipline_id=
+func (h *QualificationsHandler) List(w http.ResponseWriter, r *http.Request) {
+	var q qualifications.ListQualificationsQuery
+	if v := r.URL.Query().Get("dog_id"); v != "" {
+		id, err := strconv.ParseInt(v, 10, 64)
+		if err != nil {
+			writeError(w, 400, "invalid dog id")
+			return
+		}
+		q.DogID = &id
+	}
+	if v := r.URL.Query().Get("handler_id"); v != "" {
+		id, err := strconv.ParseInt(v, 10, 64)
+		if err != nil {
+			writeError(w, 400, "invalid handler id")
+			return
+		}
+		q.HandlerID = &id
+	}
+	if v := r.URL.Query().Get("exam_type"); v != "" {
+		q.ExamType = &v
+	}
+	if v := r.URL.Query().Get("discipline_id"); v != "" {
+		id, err := strconv.ParseInt(v, 10, 64)
+		if err != nil {
+			writeError(w, 400, "invalid discipline id")
+			return
+		}
+		q.DisciplineID = &id
+	}
+	items, err := h.svc.List(r.Context(), q)
+	if err != nil {
+		writeError(w, 500, err.Error())
+		return
+	}
+	writeJSON(w, 200, items)
+}
+
+func (h *QualificationsHandler) Get(w http.ResponseWriter, r *http.Request) {
+	id, _ := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	res, err := h.svc.Get(r.Context(), id)
+	if err != nil {
+		if err == common.ErrNotFound {
+			writeError(w, 404, "not found")
+			return
+		}
+		writeError(w, 500, err.Error())
+		return
+	}
+	writeJSON(w, 200, res)
+}
+
+func (h *QualificationsHandler) Create(w http.ResponseWriter, r *http.Request) {
+	var cmd qualifications.CreateQualificationCommand
+	if err := json.NewDecoder(r.Body).Decode(&cmd); err != nil {
+		writeError(w, 400, "invalid json")
+		return
+	}
+	res, err := h.svc.Create(r.Context(), cmd)
+	if err != nil {
+		if err == common.ErrValidation {
+			writeError(w, 400, "invalid input")
+			return
+		}
+		writeError(w, 500, err.Error())
+		return
+	}
+	writeJSON(w, 201, res)
+}
+
+func (h *QualificationsHandler) Update(w http.ResponseWriter, r *http.Request) {
+	id, _ := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	var cmd qualifications.UpdateQualificationCommand
+	if err := json.NewDecoder(r.Body).Decode(&cmd); err != nil {
+		writeError(w, 400, "invalid json")
+		return
+	}
+	cmd.ID = id
+	res, err := h.svc.Update(r.Context(), cmd)
+	if err != nil {
+		if err == common.ErrValidation {
+			writeError(w, 400, "invalid input")
+			return
+		}
+		if err == common.ErrNotFound {
+			writeError(w, 404, "not found")
+			return
+		}
+		writeError(w, 500, err.Error())
+		return
+	}
+	writeJSON(w, 200, res)
+}
+
+func (h *QualificationsHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	id, _ := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err := h.svc.Delete(r.Context(), id); err != nil {
+		if err == common.ErrNotFound {
+			writeError(w, 404, "not found")
+			return
+		}
+		writeError(w, 500, err.Error())
+		return
+	}
+	w.WriteHeader(204)
+}
+
+// GET /qualifications/reminders
+func (h *QualificationsHandler) Reminders(w http.ResponseWriter, r *http.Request) {
+	items, err := h.svc.Reminders(r.Context())
+	if err != nil {
+		writeError(w, 500, err.Error())
+		return
+	}
+	writeJSON(w, 200, items)
+}
+
+// GET /teams?deployable=true&discipline_id=
+func (h *QualificationsHandler) Teams(w http.ResponseWriter, r *http.Request) {
+	q := qualifications.ListTeamsQuery{DeployableOnly: r.URL.Query().Get("deployable") == "true"}
+	if v := r.URL.Query().Get("discipline_id"); v != "" {
+		id, err := strconv.ParseInt(v, 10, 64)
+		if err != nil {
+			writeError(w, 400, "invalid discipline id")
+			return
+		}
+		q.DisciplineID = &id
+	}
+	items, err := h.svc.Teams(r.Context(), q)
+	if err != nil {
+		writeError(w, 500, err.Error())
+		return
+	}
+	writeJSON(w, 200, items)
+}
+
+// GET /dogs/{id}/certifications
+func (h *QualificationsHandler) Certifications(w http.ResponseWriter, r *http.Request) {
+	id, _ := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	items, err := h.svc.Certifications(r.Context(), id)
+	if err != nil {
+		writeError(w, 500, err.Error())
+		return
+	}
+	writeJSON(w, 200, items)
+}
+
+// POST /qualifications/{id}/attachments (multipart form, field "file")
+func (h *QualificationsHandler) AddAttachment(w http.ResponseWriter, r *http.Request) {
+	id, _ := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	r.Body = http.MaxBytesReader(w, r.Body, maxAttachmentSize+1<<20)
+	if err := r.ParseMultipartForm(maxAttachmentSize); err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			writeError(w, 413, "file too large")
+			return
+		}
+		writeError(w, 400, "invalid multipart form")
+		return
+	}
+	f, hdr, err := r.FormFile("file")
+	if err != nil {
+		writeError(w, 400, "missing file")
+		return
+	}
+	defer f.Close()
+	data, err := io.ReadAll(io.LimitReader(f, maxAttachmentSize+1))
+	if err != nil {
+		writeError(w, 400, "invalid file")
+		return
+	}
+	if len(data) > maxAttachmentSize {
+		writeError(w, 413, "file too large")
+		return
+	}
+	res, err := h.svc.AddAttachment(r.Context(), qualifications.AddAttachmentCommand{
+		QualificationID: id, Filename: hdr.Filename, ContentType: hdr.Header.Get("Content-Type"), Data: data,
+	})
+	if err != nil {
+		if err == common.ErrValidation {
+			writeError(w, 400, "invalid input")
+			return
+		}
+		if err == common.ErrNotFound {
+			writeError(w, 404, "not found")
+			return
+		}
+		writeError(w, 500, err.Error())
+		return
+	}
+	writeJSON(w, 201, res)
+}
+
+func (h *QualificationsHandler) ListAttachments(w http.ResponseWriter, r *http.Request) {
+	id, _ := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	items, err := h.svc.ListAttachments(r.Context(), id)
+	if err != nil {
+		if err == common.ErrNotFound {
+			writeError(w, 404, "not found")
+			return
+		}
+		writeError(w, 500, err.Error())
+		return
+	}
+	writeJSON(w, 200, items)
+}
+
+func (h *QualificationsHandler) GetAttachment(w http.ResponseWriter, r *http.Request) {
+	id, _ := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	aid, _ := strconv.ParseInt(chi.URLParam(r, "attachmentId"), 10, 64)
+	a, err := h.svc.GetAttachment(r.Context(), id, aid)
+	if err != nil {
+		if err == common.ErrNotFound {
+			writeError(w, 404, "not found")
+			return
+		}
+		writeError(w, 500, err.Error())
+		return
+	}
+	ct := a.ContentType
+	if !qualification.AttachmentTypes[ct] {
+		ct = "application/octet-stream"
+	}
+	disposition := mime.FormatMediaType("attachment", map[string]string{"filename": a.Filename})
+	if disposition == "" {
+		disposition = "attachment"
+	}
+	w.Header().Set("Content-Type", ct)
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	w.Header().Set("Content-Length", strconv.FormatInt(a.Size, 10))
+	w.Header().Set("Content-Disposition", disposition)
+	w.WriteHeader(200)
+	_, _ = w.Write(a.Data)
+}
+
+func (h *QualificationsHandler) DeleteAttachment(w http.ResponseWriter, r *http.Request) {
+	id, _ := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	aid, _ := strconv.ParseInt(chi.URLParam(r, "attachmentId"), 10, 64)
+	if err := h.svc.DeleteAttachment(r.Context(), id, aid); err != nil {
+		if err == common.ErrNotFound {
+			writeError(w, 404, "not found")
+			return
+		}
+		writeError(w, 500, err.Error())
+		return
+	}
+	w.WriteHeader(204)
+}
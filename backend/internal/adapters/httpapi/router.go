@@ -15,6 +15,19 @@ func NewRouter(
 	dogs *DogsHandler,
 	sessions *SessionsHandler,
 	users *UsersHandler,
+	alerts *AlertsHandler,
+	exams *ExamsHandler,
+	qualifications *QualificationsHandler,
+	pairings *PairingsHandler,
+	healthLog *HealthHandler,
+	weather *WeatherHandler,
+	locations *LocationsHandler,
+	tracks *TracksHandler,
+	disciplines *DisciplinesHandler,
+	figurants *FigurantsHandler,
+	missions *MissionsHandler,
+	protocols *ProtocolsHandler,
+	curriculum *CurriculumHandler,
 ) http.Handler {
 	r := chi.NewRouter()
 	r.Use(middleware.Recoverer)
@@ -49,26 +62,75 @@ func NewRouter(
 			r.Delete("/{id}", skills.Delete)
 		})
 
+		protected.Route("/disciplines", func(r chi.Router) {
+			r.Get("/", disciplines.List)
+			r.Post("/", disciplines.Create)
+			r.Get("/{id}", disciplines.Get)
+			r.Put("/{id}", disciplines.Update)
+			r.Delete("/{id}", disciplines.Delete)
+		})
+
 		protected.Route("/behaviors", func(r chi.Router) {
 			r.Get("/", behaviors.List)
 			r.Post("/", behaviors.Create)
+			r.Get("/free-text", behaviors.FreeTexts)
+			r.Post("/promote", behaviors.Promote)
+			r.Get("/{id}", behaviors.Get)
+			r.Put("/{id}", behaviors.Update)
+			r.Delete("/{id}", behaviors.Delete)
+			r.Get("/{id}/prerequisites", curriculum.Prerequisites)
+			r.Post("/{id}/prerequisites", curriculum.AddPrerequisite)
+			r.Delete("/{id}/prerequisites/{requiresId}", curriculum.RemovePrerequisite)
+			r.Get("/{id}/mastery", curriculum.Criteria)
+			r.Put("/{id}/mastery", curriculum.SetCriteria)
+			r.Delete("/{id}/mastery", curriculum.DeleteCriteria)
 		})
 
 		protected.Route("/exercises", func(r chi.Router) {
 			r.Get("/", exercises.List)
 			r.Post("/", exercises.Create)
+			r.Get("/{id}", exercises.Get)
+			r.Put("/{id}", exercises.Update)
+			r.Delete("/{id}", exercises.Delete)
+			r.Get("/{id}/rubric", exercises.GetRubric)
+			r.Put("/{id}/rubric", exercises.SetRubric)
+			r.Get("/{id}/difficulty", exercises.GetDifficulty)
+			r.Put("/{id}/difficulty", exercises.SetDifficulty)
 		})
 		protected.Route("/behavior-exercises", func(r chi.Router) {
+			r.Get("/", exercises.ListLinks)
 			r.Post("/", exercises.LinkBehaviorExercise)
+			r.Delete("/{behaviorId}/{exerciseId}", exercises.UnlinkBehaviorExercise)
 		})
 
 		protected.Route("/dogs", func(r chi.Router) {
 			r.Get("/", dogs.List)
 			r.Post("/", dogs.Create)
+			r.Get("/{id}", dogs.Get)
 			r.Put("/{id}", dogs.Update)
 			r.Delete("/{id}", dogs.Delete)
+			r.Post("/{id}/archive", dogs.Archive)
+			r.Post("/{id}/unarchive", dogs.Unarchive)
+			r.With(users.adminRequired).Post("/{id}/purge", dogs.Purge)
+			r.Get("/{id}/photo", dogs.GetPhoto)
+			r.Put("/{id}/photo", dogs.SetPhoto)
+			r.Delete("/{id}/photo", dogs.DeletePhoto)
+			r.Get("/{id}/handlers", pairings.ListByDog)
+			r.Post("/{id}/handlers", pairings.Create)
+			r.Put("/{id}/handlers/{pairingId}", pairings.Update)
+			r.Delete("/{id}/handlers/{pairingId}", pairings.Delete)
+			r.Get("/{id}/health", healthLog.List)
+			r.Post("/{id}/health", healthLog.Create)
+			r.Get("/{id}/health/{entryId}", healthLog.Get)
+			r.Put("/{id}/health/{entryId}", healthLog.Update)
+			r.Delete("/{id}/health/{entryId}", healthLog.Delete)
+			r.Get("/{id}/fitness", healthLog.Fitness)
 			// rounds across sessions for a dog
 			r.Get("/{id}/rounds", sessions.ListRoundsByDog)
+			r.Get("/{id}/readiness/{examId}", exams.Readiness)
+			r.Get("/{id}/certifications", qualifications.Certifications)
+			r.Get("/{id}/missions", missions.ListByDog)
+			r.Get("/{id}/curriculum", curriculum.Dog)
 		})
 
 		protected.Route("/sessions", func(r chi.Router) {
@@ -80,6 +142,117 @@ func NewRouter(
 			r.Post("/{id}/dogs", sessions.AddDog)
 			r.Get("/{id}/rounds", sessions.ListRounds)
 			r.Post("/{id}/rounds", sessions.CreateRound)
+			r.Get("/{id}/rounds/{roundId}/area-search", sessions.GetAreaSearch)
+			r.Put("/{id}/rounds/{roundId}/area-search", sessions.SaveAreaSearch)
+			r.Delete("/{id}/rounds/{roundId}/area-search", sessions.DeleteAreaSearch)
+			r.Get("/{id}/rounds/{roundId}/trailing", sessions.GetTrailing)
+			r.Put("/{id}/rounds/{roundId}/trailing", sessions.SaveTrailing)
+			r.Delete("/{id}/rounds/{roundId}/trailing", sessions.DeleteTrailing)
+			r.Get("/{id}/rounds/{roundId}/rubble", sessions.GetRubble)
+			r.Put("/{id}/rounds/{roundId}/rubble", sessions.SaveRubble)
+			r.Delete("/{id}/rounds/{roundId}/rubble", sessions.DeleteRubble)
+			r.Get("/{id}/rounds/{roundId}/figurants", figurants.ListByRound)
+			r.Put("/{id}/rounds/{roundId}/figurants", figurants.SetRoundFigurants)
+			r.Get("/{id}/figurants", figurants.ListBySession)
+			r.Post("/{id}/figurants", figurants.AssignSession)
+			r.Get("/{id}/figurants/suggestions", figurants.Suggest)
+			r.Delete("/{id}/figurants/{figurantId}", figurants.UnassignSession)
+			r.Get("/{id}/tracks", tracks.List)
+			r.Post("/{id}/tracks", tracks.Upload)
+			r.Get("/{id}/tracks/geojson", tracks.Map)
+			r.Get("/{id}/tracks/{trackId}", tracks.Get)
+			r.Delete("/{id}/tracks/{trackId}", tracks.Delete)
+		})
+
+		protected.Route("/missions", func(r chi.Router) {
+			r.Get("/", missions.List)
+			r.Post("/", missions.Create)
+			r.Get("/{id}", missions.Get)
+			r.Put("/{id}", missions.Update)
+			r.Delete("/{id}", missions.Delete)
+		})
+
+		protected.Route("/alerts", func(r chi.Router) {
+			r.Get("/", alerts.List)
+			r.Post("/analyze", alerts.Analyze)
+			r.Post("/{id}/acknowledge", alerts.Acknowledge)
+			r.Post("/{id}/dismiss", alerts.Dismiss)
+		})
+
+		protected.Route("/exams", func(r chi.Router) {
+			r.Get("/", exams.List)
+			r.Post("/", exams.Create)
+			r.Get("/{id}", exams.Get)
+			r.Put("/{id}", exams.Update)
+			r.Delete("/{id}", exams.Delete)
+		})
+
+		protected.Route("/protocols", func(r chi.Router) {
+			r.Get("/", protocols.List)
+			r.Post("/", protocols.Create)
+			r.Get("/{id}", protocols.Get)
+			r.Put("/{id}", protocols.Update)
+			r.Delete("/{id}", protocols.Delete)
+			r.Post("/{id}/runs", protocols.StartRun)
+		})
+
+		protected.Route("/exam-runs", func(r chi.Router) {
+			r.Get("/", protocols.ListRuns)
+			r.Get("/{id}", protocols.Result)
+			r.Put("/{id}/tasks/{taskId}", protocols.SetTaskRound)
+			r.Delete("/{id}/tasks/{taskId}", protocols.DeleteTaskRound)
+			r.Post("/{id}/complete", protocols.Complete)
+			r.Get("/{id}/sheet", protocols.Sheet)
+		})
+
+		protected.Route("/qualifications", func(r chi.Router) {
+			r.Get("/", qualifications.List)
+			r.Post("/", qualifications.Create)
+			r.Get("/reminders", qualifications.Reminders)
+			r.Get("/{id}", qualifications.Get)
+			r.Put("/{id}", qualifications.Update)
+			r.Delete("/{id}", qualifications.Delete)
+			r.Get("/{id}/attachments", qualifications.ListAttachments)
+			r.Post("/{id}/attachments", qualifications.AddAttachment)
+			r.Get("/{id}/attachments/{attachmentId}", qualifications.GetAttachment)
+			r.Delete("/{id}/attachments/{attachmentId}", qualifications.DeleteAttachment)
+		})
+		protected.Get("/teams", qualifications.Teams)
+		protected.Get("/users/me/dogs", pairings.ListMine)
+		protected.Get("/health/due", healthLog.Due)
+		protected.Get("/catalogs/reinforcement", sessions.ReinforcementCatalog)
+		protected.Get("/analytics/conditions", sessions.ConditionStats)
+		protected.Get("/analytics/area-search", sessions.AreaSearchStats)
+		protected.Get("/analytics/trailing", sessions.TrailingStats)
+		protected.Get("/analytics/indications", sessions.IndicationStats)
+		protected.Get("/analytics/durations", sessions.DurationStats)
+		protected.Get("/analytics/difficulty", sessions.DifficultyProgress)
+		protected.Get("/analytics/reinforcement", sessions.ReinforcementStats)
+		protected.Get("/analytics/deployments", missions.Deployments)
+		protected.Route("/locations", func(r chi.Router) {
+			r.Get("/", locations.List)
+			r.Post("/", locations.Create)
+			r.Get("/nearby", locations.Nearby)
+			r.Get("/{id}", locations.Get)
+			r.Put("/{id}", locations.Update)
+			r.Delete("/{id}", locations.Delete)
+			r.Get("/{id}/stats", locations.Stats)
+			r.Post("/{id}/merge", locations.Merge)
+		})
+		protected.Route("/figurants", func(r chi.Router) {
+			r.Get("/", figurants.List)
+			r.Post("/", figurants.Create)
+			r.Get("/{id}", figurants.Get)
+			r.Put("/{id}", figurants.Update)
+			r.Delete("/{id}", figurants.Delete)
+			r.Get("/{id}/availability", figurants.ListAvailability)
+			r.Post("/{id}/availability", figurants.AddAvailability)
+			r.Delete("/{id}/availability/{availabilityId}", figurants.DeleteAvailability)
+			r.Get("/{id}/history", figurants.History)
+		})
+		protected.Route("/weather", func(r chi.Router) {
+			r.Get("/observations", weather.List)
+			r.Post("/import", weather.Import)
 		})
 	})
 
@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"net/http"
 	"strconv"
+	"strings"
 
 	"github.com/go-chi/chi/v5"
 	"github.com/tnosaj/sar-training/backend/internal/application/sessions"
@@ -35,6 +36,10 @@ func (h *SessionsHandler) Create(w http.ResponseWriter, r *http.Request) {
 	}
 	res, err := h.svc.Create(r.Context(), cmd)
 	if err != nil {
+		if err == common.ErrValidation {
+			writeError(w, 400, "invalid input")
+			return
+		}
 		writeError(w, 500, err.Error())
 		return
 	}
@@ -51,6 +56,10 @@ func (h *SessionsHandler) Update(w http.ResponseWriter, r *http.Request) {
 	cmd.SessionID = id
 	res, err := h.svc.Update(r.Context(), cmd)
 	if err != nil {
+		if err == common.ErrValidation {
+			writeError(w, 400, "invalid input")
+			return
+		}
 		writeError(w, 500, err.Error())
 		return
 	}
@@ -96,6 +105,14 @@ func (h *SessionsHandler) AddDog(w http.ResponseWriter, r *http.Request) {
 			writeError(w, 400, "invalid input")
 			return
 		}
+		if err == common.ErrNotFound {
+			writeError(w, 404, "session not found")
+			return
+		}
+		if err == common.ErrConflict {
+			writeError(w, 409, "dog is not fit for training")
+			return
+		}
 		writeError(w, 500, err.Error())
 		return
 	}
@@ -120,12 +137,21 @@ func (h *SessionsHandler) CreateRound(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 	cmd.SessionID = sid
+	cmd.UserID = currentUserID(r)
 	res, err := h.svc.CreateRound(r.Context(), cmd)
 	if err != nil {
 		if err == common.ErrValidation {
 			writeError(w, 400, "invalid input")
 			return
 		}
+		if err == common.ErrForbidden {
+			writeError(w, 403, "not a handler of this dog")
+			return
+		}
+		if err == common.ErrNotFound {
+			writeError(w, 404, "session not found")
+			return
+		}
 		writeError(w, 500, err.Error())
 		return
 	}
@@ -147,3 +173,382 @@ func (h *SessionsHandler) ListRoundsByDog(w http.ResponseWriter, r *http.Request
 	}
 	writeJSON(w, 200, items)
 }
+
+// GET /analytics/conditions?factor=wind_speed_kmh&cuts=5,15,30&dog_id=&behavior_id=&from=&to=
+func (h *SessionsHandler) ConditionStats(w http.ResponseWriter, r *http.Request) {
+	qs := r.URL.Query()
+	q := sessions.ConditionStatsQuery{Factor: qs.Get("factor")}
+	if v := qs.Get("cuts"); v != "" {
+		for _, p := range strings.Split(v, ",") {
+			c, err := strconv.ParseFloat(strings.TrimSpace(p), 64)
+			if err != nil {
+				writeError(w, 400, "invalid cuts")
+				return
+			}
+			q.Cuts = append(q.Cuts, c)
+		}
+	}
+	for _, p := range []struct {
+		name string
+		out  **int64
+	}{{"dog_id", &q.DogID}, {"behavior_id", &q.BehaviorID}} {
+		if v := qs.Get(p.name); v != "" {
+			id, err := strconv.ParseInt(v, 10, 64)
+			if err != nil {
+				writeError(w, 400, "invalid "+p.name)
+				return
+			}
+			*p.out = &id
+		}
+	}
+	if v := qs.Get("from"); v != "" {
+		q.From = &v
+	}
+	if v := qs.Get("to"); v != "" {
+		q.To = &v
+	}
+	res, err := h.svc.ConditionStats(r.Context(), q)
+	if err != nil {
+		if err == common.ErrValidation {
+			writeError(w, 400, "invalid factor, cuts or time range")
+			return
+		}
+		writeError(w, 500, err.Error())
+		return
+	}
+	writeJSON(w, 200, res)
+}
+
+// GET /sessions/{id}/rounds/{roundId}/area-search
+func (h *SessionsHandler) GetAreaSearch(w http.ResponseWriter, r *http.Request) {
+	sid, _ := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	rid, _ := strconv.ParseInt(chi.URLParam(r, "roundId"), 10, 64)
+	res, err := h.svc.GetAreaSearch(r.Context(), sid, rid)
+	h.writeRoundExtension(w, res, err)
+}
+
+// PUT /sessions/{id}/rounds/{roundId}/area-search
+func (h *SessionsHandler) SaveAreaSearch(w http.ResponseWriter, r *http.Request) {
+	var cmd sessions.SaveAreaSearchCommand
+	if err := json.NewDecoder(r.Body).Decode(&cmd); err != nil {
+		writeError(w, 400, "invalid json")
+		return
+	}
+	cmd.SessionID, _ = strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	cmd.RoundID, _ = strconv.ParseInt(chi.URLParam(r, "roundId"), 10, 64)
+	cmd.UserID = currentUserID(r)
+	res, err := h.svc.SaveAreaSearch(r.Context(), cmd)
+	h.writeRoundExtension(w, res, err)
+}
+
+func (h *SessionsHandler) DeleteAreaSearch(w http.ResponseWriter, r *http.Request) {
+	sid, _ := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	rid, _ := strconv.ParseInt(chi.URLParam(r, "roundId"), 10, 64)
+	if err := h.svc.DeleteAreaSearch(r.Context(), sid, rid, currentUserID(r)); err != nil {
+		h.writeRoundExtension(w, nil, err)
+		return
+	}
+	w.WriteHeader(204)
+}
+
+func (h *SessionsHandler) writeRoundExtension(w http.ResponseWriter, res any, err error) {
+	if err != nil {
+		switch err {
+		case common.ErrValidation:
+			writeError(w, 400, "invalid input")
+		case common.ErrForbidden:
+			writeError(w, 403, "not a handler of this dog")
+		case common.ErrNotFound:
+			writeError(w, 404, "not found")
+		default:
+			writeError(w, 500, err.Error())
+		}
+		return
+	}
+	writeJSON(w, 200, res)
+}
+
+// GET /analytics/area-search?dog_id=&location_id=&from=&to=
+func (h *SessionsHandler) AreaSearchStats(w http.ResponseWriter, r *http.Request) {
+	qs := r.URL.Query()
+	var q sessions.AreaSearchStatsQuery
+	for _, p := range []struct {
+		name string
+		out  **int64
+	}{{"dog_id", &q.DogID}, {"location_id", &q.LocationID}} {
+		if v := qs.Get(p.name); v != "" {
+			id, err := strconv.ParseInt(v, 10, 64)
+			if err != nil {
+				writeError(w, 400, "invalid "+p.name)
+				return
+			}
+			*p.out = &id
+		}
+	}
+	if v := qs.Get("from"); v != "" {
+		q.From = &v
+	}
+	if v := qs.Get("to"); v != "" {
+		q.To = &v
+	}
+	res, err := h.svc.AreaSearchStats(r.Context(), q)
+	if err != nil {
+		if err == common.ErrValidation {
+			writeError(w, 400, "invalid time range")
+			return
+		}
+		writeError(w, 500, err.Error())
+		return
+	}
+	writeJSON(w, 200, res)
+}
+
+// GET /analytics/indications?dog_id=&from=&to=
+func (h *SessionsHandler) IndicationStats(w http.ResponseWriter, r *http.Request) {
+	qs := r.URL.Query()
+	var q sessions.IndicationStatsQuery
+	if v := qs.Get("dog_id"); v != "" {
+		id, err := strconv.ParseInt(v, 10, 64)
+		if err != nil {
+			writeError(w, 400, "invalid dog_id")
+			return
+		}
+		q.DogID = &id
+	}
+	if v := qs.Get("from"); v != "" {
+		q.From = &v
+	}
+	if v := qs.Get("to"); v != "" {
+		q.To = &v
+	}
+	res, err := h.svc.IndicationStats(r.Context(), q)
+	if err != nil {
+		if err == common.ErrValidation {
+			writeError(w, 400, "invalid time range")
+			return
+		}
+		writeError(w, 500, err.Error())
+		return
+	}
+	writeJSON(w, 200, res)
+}
+
+// GET /catalogs/reinforcement
+func (h *SessionsHandler) ReinforcementCatalog(w http.ResponseWriter, r *http.Request) {
+	res, err := h.svc.ReinforcementCatalog(r.Context())
+	if err != nil {
+		writeError(w, 500, err.Error())
+		return
+	}
+	writeJSON(w, 200, res)
+}
+
+// GET /analytics/reinforcement?factor=reward|schedule|distraction|distraction_level&dog_id=&behavior_id=&from=&to=
+func (h *SessionsHandler) ReinforcementStats(w http.ResponseWriter, r *http.Request) {
+	qs := r.URL.Query()
+	q := sessions.ReinforcementStatsQuery{Factor: qs.Get("factor")}
+	for _, p := range []struct {
+		name string
+		out  **int64
+	}{{"dog_id", &q.DogID}, {"behavior_id", &q.BehaviorID}} {
+		if v := qs.Get(p.name); v != "" {
+			id, err := strconv.ParseInt(v, 10, 64)
+			if err != nil {
+				writeError(w, 400, "invalid "+p.name)
+				return
+			}
+			*p.out = &id
+		}
+	}
+	if v := qs.Get("from"); v != "" {
+		q.From = &v
+	}
+	if v := qs.Get("to"); v != "" {
+		q.To = &v
+	}
+	res, err := h.svc.ReinforcementStats(r.Context(), q)
+	if err != nil {
+		if err == common.ErrValidation {
+			writeError(w, 400, "invalid factor or time range")
+			return
+		}
+		writeError(w, 500, err.Error())
+		return
+	}
+	writeJSON(w, 200, res)
+}
+
+// GET /analytics/durations?group_by=behavior|exercise&dog_id=&behavior_id=&exercise_id=&from=&to=
+func (h *SessionsHandler) DurationStats(w http.ResponseWriter, r *http.Request) {
+	qs := r.URL.Query()
+	q := sessions.DurationStatsQuery{GroupBy: qs.Get("group_by")}
+	for _, p := range []struct {
+		name string
+		out  **int64
+	}{{"dog_id", &q.DogID}, {"behavior_id", &q.BehaviorID}, {"exercise_id", &q.ExerciseID}} {
+		if v := qs.Get(p.name); v != "" {
+			id, err := strconv.ParseInt(v, 10, 64)
+			if err != nil {
+				writeError(w, 400, "invalid "+p.name)
+				return
+			}
+			*p.out = &id
+		}
+	}
+	if v := qs.Get("from"); v != "" {
+		q.From = &v
+	}
+	if v := qs.Get("to"); v != "" {
+		q.To = &v
+	}
+	res, err := h.svc.DurationStats(r.Context(), q)
+	if err != nil {
+		if err == common.ErrValidation {
+			writeError(w, 400, "invalid group_by or time range")
+			return
+		}
+		writeError(w, 500, err.Error())
+		return
+	}
+	writeJSON(w, 200, res)
+}
+
+// GET /sessions/{id}/rounds/{roundId}/trailing
+func (h *SessionsHandler) GetTrailing(w http.ResponseWriter, r *http.Request) {
+	sid, _ := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	rid, _ := strconv.ParseInt(chi.URLParam(r, "roundId"), 10, 64)
+	res, err := h.svc.GetTrailing(r.Context(), sid, rid)
+	h.writeRoundExtension(w, res, err)
+}
+
+// PUT /sessions/{id}/rounds/{roundId}/trailing
+func (h *SessionsHandler) SaveTrailing(w http.ResponseWriter, r *http.Request) {
+	var cmd sessions.SaveTrailingCommand
+	if err := json.NewDecoder(r.Body).Decode(&cmd); err != nil {
+		writeError(w, 400, "invalid json")
+		return
+	}
+	cmd.SessionID, _ = strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	cmd.RoundID, _ = strconv.ParseInt(chi.URLParam(r, "roundId"), 10, 64)
+	cmd.UserID = currentUserID(r)
+	res, err := h.svc.SaveTrailing(r.Context(), cmd)
+	h.writeRoundExtension(w, res, err)
+}
+
+func (h *SessionsHandler) DeleteTrailing(w http.ResponseWriter, r *http.Request) {
+	sid, _ := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	rid, _ := strconv.ParseInt(chi.URLParam(r, "roundId"), 10, 64)
+	if err := h.svc.DeleteTrailing(r.Context(), sid, rid, currentUserID(r)); err != nil {
+		h.writeRoundExtension(w, nil, err)
+		return
+	}
+	w.WriteHeader(204)
+}
+
+// GET /sessions/{id}/rounds/{roundId}/rubble
+func (h *SessionsHandler) GetRubble(w http.ResponseWriter, r *http.Request) {
+	sid, _ := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	rid, _ := strconv.ParseInt(chi.URLParam(r, "roundId"), 10, 64)
+	res, err := h.svc.GetRubble(r.Context(), sid, rid)
+	h.writeRoundExtension(w, res, err)
+}
+
+// PUT /sessions/{id}/rounds/{roundId}/rubble
+func (h *SessionsHandler) SaveRubble(w http.ResponseWriter, r *http.Request) {
+	var cmd sessions.SaveRubbleCommand
+	if err := json.NewDecoder(r.Body).Decode(&cmd); err != nil {
+		writeError(w, 400, "invalid json")
+		return
+	}
+	cmd.SessionID, _ = strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	cmd.RoundID, _ = strconv.ParseInt(chi.URLParam(r, "roundId"), 10, 64)
+	cmd.UserID = currentUserID(r)
+	res, err := h.svc.SaveRubble(r.Context(), cmd)
+	h.writeRoundExtension(w, res, err)
+}
+
+func (h *SessionsHandler) DeleteRubble(w http.ResponseWriter, r *http.Request) {
+	sid, _ := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	rid, _ := strconv.ParseInt(chi.URLParam(r, "roundId"), 10, 64)
+	if err := h.svc.DeleteRubble(r.Context(), sid, rid, currentUserID(r)); err != nil {
+		h.writeRoundExtension(w, nil, err)
+		return
+	}
+	w.WriteHeader(204)
+}
+
+// GET /analytics/trailing?factor=trail_age_min&cuts=30,60,120&dog_id=&from=&to=
+func (h *SessionsHandler) TrailingStats(w http.ResponseWriter, r *http.Request) {
+	qs := r.URL.Query()
+	q := sessions.TrailingStatsQuery{Factor: qs.Get("factor")}
+	if v := qs.Get("cuts"); v != "" {
+		for _, p := range strings.Split(v, ",") {
+			c, err := strconv.ParseFloat(strings.TrimSpace(p), 64)
+			if err != nil {
+				writeError(w, 400, "invalid cuts")
+				return
+			}
+			q.Cuts = append(q.Cuts, c)
+		}
+	}
+	if v := qs.Get("dog_id"); v != "" {
+		id, err := strconv.ParseInt(v, 10, 64)
+		if err != nil {
+			writeError(w, 400, "invalid dog_id")
+			return
+		}
+		q.DogID = &id
+	}
+	if v := qs.Get("from"); v != "" {
+		q.From = &v
+	}
+	if v := qs.Get("to"); v != "" {
+		q.To = &v
+	}
+	res, err := h.svc.TrailingStats(r.Context(), q)
+	if err != nil {
+		if err == common.ErrValidation {
+			writeError(w, 400, "invalid factor, cuts or time range")
+			return
+		}
+		writeError(w, 500, err.Error())
+		return
+	}
+	writeJSON(w, 200, res)
+}
+
+// GET /analytics/difficulty?dog_id=&exercise_id=&bucket=week|month&from=&to=
+func (h *SessionsHandler) DifficultyProgress(w http.ResponseWriter, r *http.Request) {
+	qs := r.URL.Query()
+	q := sessions.DifficultyProgressQuery{Bucket: qs.Get("bucket")}
+	dogID, err := strconv.ParseInt(qs.Get("dog_id"), 10, 64)
+	if err != nil {
+		writeError(w, 400, "invalid dog_id")
+		return
+	}
+	q.DogID = dogID
+	if v := qs.Get("exercise_id"); v != "" {
+		id, err := strconv.ParseInt(v, 10, 64)
+		if err != nil {
+			writeError(w, 400, "invalid exercise_id")
+			return
+		}
+		q.ExerciseID = &id
+	}
+	if v := qs.Get("from"); v != "" {
+		q.From = &v
+	}
+	if v := qs.Get("to"); v != "" {
+		q.To = &v
+	}
+	res, err := h.svc.DifficultyProgress(r.Context(), q)
+	if err != nil {
+		if err == common.ErrValidation {
+			writeError(w, 400, "invalid bucket or time range")
+			return
+		}
+		writeError(w, 500, err.Error())
+		return
+	}
+	writeJSON(w, 200, res)
+}
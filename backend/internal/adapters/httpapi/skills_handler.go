@@ -18,8 +18,18 @@ func NewSkillsHandler(s *skills.Service) *SkillsHandler {
 	return &SkillsHandler{svc: s}
 }
 
+// GET /skills?discipline_id=
 func (h *SkillsHandler) List(w http.ResponseWriter, r *http.Request) {
-	items, err := h.svc.List(r.Context(), skills.ListSkillsQuery{})
+	var q skills.ListSkillsQuery
+	if v := r.URL.Query().Get("discipline_id"); v != "" {
+		id, err := strconv.ParseInt(v, 10, 64)
+		if err != nil {
+			writeError(w, 400, "invalid discipline id")
+			return
+		}
+		q.DisciplineID = &id
+	}
+	items, err := h.svc.List(r.Context(), q)
 	if err != nil {
 		writeError(w, 500, err.Error())
 		return
@@ -36,7 +46,7 @@ func (h *SkillsHandler) Create(w http.ResponseWriter, r *http.Request) {
 	res, err := h.svc.Create(r.Context(), cmd)
 	if err != nil {
 		if err == common.ErrValidation {
-			writeError(w, 400, "missing name")
+			writeError(w, 400, "missing name or unknown discipline")
 			return
 		}
 		if err == common.ErrConflict {
@@ -0,0 +1,147 @@
+package httpapi
+
+import (
+	"errors"
+	"math"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/tnosaj/sar-training/backend/internal/application/tracks"
+	"github.com/tnosaj/sar-training/backend/internal/domain/common"
+	logx "github.com/tnosaj/sar-training/backend/internal/infra/log"
+)
+
+// maxTrackFileSize caps uploaded GPX files.
+const maxTrackFileSize = 20 << 20
+
+type TracksHandler struct{ svc *tracks.Service }
+
+func NewTracksHandler(s *tracks.Service) *TracksHandler {
+	logx.Std.Trace("starting tracks handler")
+	return &TracksHandler{svc: s}
+}
+
+// POST /sessions/{id}/tracks (multipart: file, kind, round_id)
+func (h *TracksHandler) Upload(w http.ResponseWriter, r *http.Request) {
+	id, _ := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	r.Body = http.MaxBytesReader(w, r.Body, maxTrackFileSize+1<<20)
+	if err := r.ParseMultipartForm(maxTrackFileSize); err != nil {
+		writeError(w, 400, "invalid multipart form")
+		return
+	}
+	f, _, err := r.FormFile("file")
+	if err != nil {
+		writeError(w, 400, "missing file")
+		return
+	}
+	defer f.Close()
+	cmd := tracks.UploadTracksCommand{SessionID: id, Kind: r.FormValue("kind"), Data: f}
+	if v := r.FormValue("round_id"); v != "" {
+		rid, err := strconv.ParseInt(v, 10, 64)
+		if err != nil {
+			writeError(w, 400, "invalid round id")
+			return
+		}
+		cmd.RoundID = &rid
+	}
+	res, err := h.svc.Upload(r.Context(), cmd)
+	h.write(w, 201, res, err)
+}
+
+// GET /sessions/{id}/tracks?round_id=&sweep_width_m=
+func (h *TracksHandler) List(w http.ResponseWriter, r *http.Request) {
+	q, ok := listTracksQuery(w, r)
+	if !ok {
+		return
+	}
+	res, err := h.svc.List(r.Context(), q)
+	h.write(w, 200, res, err)
+}
+
+// GET /sessions/{id}/tracks/geojson?round_id=&sweep_width_m=
+func (h *TracksHandler) Map(w http.ResponseWriter, r *http.Request) {
+	q, ok := listTracksQuery(w, r)
+	if !ok {
+		return
+	}
+	res, err := h.svc.Map(r.Context(), q)
+	h.write(w, 200, res, err)
+}
+
+// GET /sessions/{id}/tracks/{trackId}?sweep_width_m=
+func (h *TracksHandler) Get(w http.ResponseWriter, r *http.Request) {
+	q := tracks.GetTrackQuery{}
+	q.SessionID, _ = strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	q.TrackID, _ = strconv.ParseInt(chi.URLParam(r, "trackId"), 10, 64)
+	if v := r.URL.Query().Get("sweep_width_m"); v != "" {
+		f, ok := parseSweepWidth(v)
+		if !ok {
+			writeError(w, 400, "invalid sweep_width_m")
+			return
+		}
+		q.SweepWidthM = f
+	}
+	res, err := h.svc.Get(r.Context(), q)
+	h.write(w, 200, res, err)
+}
+
+func (h *TracksHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	id, _ := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	tid, _ := strconv.ParseInt(chi.URLParam(r, "trackId"), 10, 64)
+	if err := h.svc.Delete(r.Context(), id, tid); err != nil {
+		h.write(w, 0, nil, err)
+		return
+	}
+	w.WriteHeader(204)
+}
+
+func listTracksQuery(w http.ResponseWriter, r *http.Request) (tracks.ListTracksQuery, bool) {
+	var q tracks.ListTracksQuery
+	q.SessionID, _ = strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if v := r.URL.Query().Get("round_id"); v != "" {
+		id, err := strconv.ParseInt(v, 10, 64)
+		if err != nil {
+			writeError(w, 400, "invalid round id")
+			return q, false
+		}
+		q.RoundID = &id
+	}
+	if v := r.URL.Query().Get("sweep_width_m"); v != "" {
+		f, ok := parseSweepWidth(v)
+		if !ok {
+			writeError(w, 400, "invalid sweep_width_m")
+			return q, false
+		}
+		q.SweepWidthM = f
+	}
+	return q, true
+}
+
+// parseSweepWidth accepts a finite, positive width in metres up to
+// tracks.MaxSweepWidthM.
+func parseSweepWidth(v string) (float64, bool) {
+	f, err := strconv.ParseFloat(v, 64)
+	if err != nil || math.IsNaN(f) || math.IsInf(f, 0) || f <= 0 || f > tracks.MaxSweepWidthM {
+		return 0, false
+	}
+	return f, true
+}
+
+func (h *TracksHandler) write(w http.ResponseWriter, code int, res any, err error) {
+	if err != nil {
+		var pe *tracks.ParseError
+		switch {
+		case errors.As(err, &pe):
+			writeError(w, 400, pe.Error())
+		case err == common.ErrValidation:
+			writeError(w, 400, "invalid input")
+		case err == common.ErrNotFound:
+			writeError(w, 404, "not found")
+		default:
+			writeError(w, 500, err.Error())
+		}
+		return
+	}
+	writeJSON(w, code, res)
+}
@@ -7,6 +7,7 @@ import (
 	"time"
 
 	jwt "github.com/golang-jwt/jwt/v5"
+	"github.com/tnosaj/sar-training/backend/internal/application/users"
 )
 
 const authCookieName = "auth"
@@ -32,6 +33,22 @@ func (a *UsersHandler) authRequired(next http.Handler) http.Handler {
 	})
 }
 
+// adminRequired must run after authRequired.
+func (a *UsersHandler) adminRequired(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		u, err := a.svc.GetUserByID(r.Context(), users.GetUserByIDCommand{ID: currentUserID(r)})
+		if err != nil {
+			writeError(w, http.StatusUnauthorized, "unauthorized")
+			return
+		}
+		if !u.IsAdmin {
+			writeError(w, http.StatusForbidden, "admin only")
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
 func (a *UsersHandler) signToken(userID int64, ttl time.Duration) (string, error) {
 	claims := jwt.MapClaims{
 		"sub": userID,
@@ -79,3 +96,10 @@ func clearAuthCookie(w http.ResponseWriter) {
 	c := &http.Cookie{Name: authCookieName, Value: "", Path: "/", HttpOnly: true, Secure: true, SameSite: http.SameSiteLaxMode, Expires: time.Unix(0, 0)}
 	http.SetCookie(w, c)
 }
+
+// currentUserID returns the id of the authenticated user, or 0 outside of
+// authRequired.
+func currentUserID(r *http.Request) int64 {
+	uid, _ := r.Context().Value(userIDKey).(int64)
+	return uid
+}
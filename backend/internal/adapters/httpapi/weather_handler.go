@@ -0,0 +1,97 @@
+package httpapi
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/tnosaj/sar-training/backend/internal/application/observations"
+	"github.com/tnosaj/sar-training/backend/internal/domain/common"
+	logx "github.com/tnosaj/sar-training/backend/internal/infra/log"
+)
+
+// maxWeatherFileSize caps uploaded weather station exports.
+const maxWeatherFileSize = 10 << 20
+
+type WeatherHandler struct{ svc *observations.Service }
+
+func NewWeatherHandler(s *observations.Service) *WeatherHandler {
+	logx.Std.Trace("starting weather handler")
+	return &WeatherHandler{svc: s}
+}
+
+// POST /weather/import (multipart: file, format, location, reference, max_gap, overwrite)
+func (h *WeatherHandler) Import(w http.ResponseWriter, r *http.Request) {
+	r.Body = http.MaxBytesReader(w, r.Body, maxWeatherFileSize+1<<20)
+	if err := r.ParseMultipartForm(maxWeatherFileSize); err != nil {
+		writeError(w, 400, "invalid multipart form")
+		return
+	}
+	f, hdr, err := r.FormFile("file")
+	if err != nil {
+		writeError(w, 400, "missing file")
+		return
+	}
+	defer f.Close()
+	cmd := observations.ImportCommand{Data: f, Format: r.FormValue("format"), Overwrite: r.FormValue("overwrite") == "true"}
+	if cmd.Format == "" {
+		cmd.Format = observations.DetectFormat(hdr.Filename)
+	}
+	if v := r.FormValue("location"); v != "" {
+		cmd.Location = &v
+	}
+	if v := r.FormValue("reference"); v != "" {
+		t, err := observations.ParseReference(v)
+		if err != nil {
+			writeError(w, 400, "invalid reference")
+			return
+		}
+		cmd.Reference = &t
+	}
+	if v := r.FormValue("max_gap"); v != "" {
+		d, err := time.ParseDuration(v)
+		if err != nil {
+			writeError(w, 400, "invalid max_gap")
+			return
+		}
+		cmd.MaxGap = d
+	}
+	res, err := h.svc.Import(r.Context(), cmd)
+	if err != nil {
+		var pe *observations.ParseError
+		switch {
+		case errors.As(err, &pe):
+			writeError(w, 400, pe.Error())
+		case err == common.ErrValidation:
+			writeError(w, 400, "invalid input")
+		default:
+			writeError(w, 500, err.Error())
+		}
+		return
+	}
+	writeJSON(w, 201, res)
+}
+
+// GET /weather/observations?location=&from=&to=
+func (h *WeatherHandler) List(w http.ResponseWriter, r *http.Request) {
+	var q observations.ListObservationsQuery
+	if v := r.URL.Query().Get("location"); v != "" {
+		q.Location = &v
+	}
+	if v := r.URL.Query().Get("from"); v != "" {
+		q.From = &v
+	}
+	if v := r.URL.Query().Get("to"); v != "" {
+		q.To = &v
+	}
+	items, err := h.svc.List(r.Context(), q)
+	if err != nil {
+		if err == common.ErrValidation {
+			writeError(w, 400, "invalid time range")
+			return
+		}
+		writeError(w, 500, err.Error())
+		return
+	}
+	writeJSON(w, 200, items)
+}
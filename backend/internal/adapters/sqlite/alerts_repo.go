@@ -0,0 +1,161 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/tnosaj/sar-training/backend/internal/domain/alert"
+	"github.com/tnosaj/sar-training/backend/internal/domain/common"
+	logx "github.com/tnosaj/sar-training/backend/internal/infra/log"
+)
+
+type AlertsRepo struct{ db *sql.DB }
+
+func NewAlertsRepo(db *sql.DB) *AlertsRepo {
+	logx.Std.Trace("starting alerts repo")
+	return &AlertsRepo{db: db}
+}
+
+const alertColumns = `id, dog_id, behavior_id, kind, metric, status, baseline, recent, slope, p_value, samples, message, window_start, window_end, created_at, updated_at, acknowledged_by, acknowledged_at`
+
+func (r *AlertsRepo) Create(ctx context.Context, a *alert.Alert) error {
+	res, err := r.db.ExecContext(ctx, `INSERT INTO alerts (dog_id, behavior_id, kind, metric, status, baseline, recent, slope, p_value, samples, message, window_start, window_end, created_at, updated_at) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		a.DogID, a.BehaviorID, a.Kind, a.Metric, a.Status, a.Baseline, a.Recent, a.Slope, a.PValue, a.Samples, a.Message,
+		a.WindowStart.Format(time.RFC3339), a.WindowEnd.Format(time.RFC3339), a.CreatedAt.Format(time.RFC3339), a.UpdatedAt.Format(time.RFC3339))
+	if err != nil {
+		return err
+	}
+	id, _ := res.LastInsertId()
+	a.ID = alert.AlertID(id)
+	return nil
+}
+
+func (r *AlertsRepo) Get(ctx context.Context, id alert.AlertID) (*alert.Alert, error) {
+	row := r.db.QueryRowContext(ctx, `SELECT `+alertColumns+` FROM alerts WHERE id=?`, id)
+	a, err := scanAlert(row)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, common.ErrNotFound
+	}
+	return a, err
+}
+
+func (r *AlertsRepo) List(ctx context.Context, f alert.Filter) ([]*alert.Alert, error) {
+	query := `SELECT ` + alertColumns + ` FROM alerts WHERE 1=1`
+	args := []any{}
+	if f.DogID != nil {
+		query += ` AND dog_id = ?`
+		args = append(args, *f.DogID)
+	}
+	if f.BehaviorID != nil {
+		query += ` AND behavior_id = ?`
+		args = append(args, *f.BehaviorID)
+	}
+	if f.Status != nil {
+		query += ` AND status = ?`
+		args = append(args, *f.Status)
+	}
+	query += ` ORDER BY id DESC`
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var out []*alert.Alert
+	for rows.Next() {
+		a, err := scanAlert(rows)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, a)
+	}
+	return out, rows.Err()
+}
+
+func (r *AlertsRepo) UpdateStatus(ctx context.Context, a *alert.Alert) error {
+	var ackAt *string
+	if a.AcknowledgedAt != nil {
+		v := a.AcknowledgedAt.Format(time.RFC3339)
+		ackAt = &v
+	}
+	res, err := r.db.ExecContext(ctx, `UPDATE alerts SET status=?, acknowledged_by=?, acknowledged_at=?, updated_at=? WHERE id=?`,
+		a.Status, a.AcknowledgedBy, ackAt, a.UpdatedAt.Format(time.RFC3339), a.ID)
+	if err != nil {
+		return err
+	}
+	rows, _ := res.RowsAffected()
+	if rows == 0 {
+		return common.ErrNotFound
+	}
+	return nil
+}
+
+func (r *AlertsRepo) Latest(ctx context.Context, dogID, behaviorID int64, kind alert.Kind, metric alert.Metric) (*alert.Alert, error) {
+	row := r.db.QueryRowContext(ctx, `SELECT `+alertColumns+` FROM alerts WHERE dog_id=? AND behavior_id=? AND kind=? AND metric=? ORDER BY id DESC LIMIT 1`,
+		dogID, behaviorID, kind, metric)
+	a, err := scanAlert(row)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, common.ErrNotFound
+	}
+	return a, err
+}
+
+// ListObservations returns the rounds started at or after since, ordered by
+// dog and behavior. Start times are compared after parsing, since session
+// times are free text; rounds whose time cannot be parsed are skipped.
+func (r *AlertsRepo) ListObservations(ctx context.Context, since time.Time) ([]alert.Observation, error) {
+	rows, err := r.db.QueryContext(ctx, `SELECT r.id, r.dog_id, r.planned_behavior_id, COALESCE(r.started_at, s.started_at), r.outcome, r.score
+		FROM rounds r JOIN sessions s ON s.id = r.session_id
+		ORDER BY r.dog_id, r.planned_behavior_id, r.round_number`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var out []alert.Observation
+	for rows.Next() {
+		var o alert.Observation
+		var id int64
+		var at string
+		var score sql.NullInt64
+		if err := rows.Scan(&id, &o.DogID, &o.BehaviorID, &at, &o.Outcome, &score); err != nil {
+			return nil, err
+		}
+		if o.At, err = parseRoundTime(at); err != nil {
+			logx.Std.Warnf("skipping round %d for alerts: unreadable start time %q", id, at)
+			continue
+		}
+		if o.At.Before(since) {
+			continue
+		}
+		if score.Valid {
+			v := int(score.Int64)
+			o.Score = &v
+		}
+		out = append(out, o)
+	}
+	return out, rows.Err()
+}
+
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+func scanAlert(row rowScanner) (*alert.Alert, error) {
+	var a alert.Alert
+	var ws, we, c, u string
+	var ackAt sql.NullString
+	if err := row.Scan(&a.ID, &a.DogID, &a.BehaviorID, &a.Kind, &a.Metric, &a.Status, &a.Baseline, &a.Recent, &a.Slope, &a.PValue,
+		&a.Samples, &a.Message, &ws, &we, &c, &u, &a.AcknowledgedBy, &ackAt); err != nil {
+		return nil, err
+	}
+	a.WindowStart, _ = time.Parse(time.RFC3339, ws)
+	a.WindowEnd, _ = time.Parse(time.RFC3339, we)
+	a.CreatedAt, _ = time.Parse(time.RFC3339, c)
+	a.UpdatedAt, _ = time.Parse(time.RFC3339, u)
+	if ackAt.Valid {
+		t, _ := time.Parse(time.RFC3339, ackAt.String)
+		a.AcknowledgedAt = &t
+	}
+	return &a, nil
+}
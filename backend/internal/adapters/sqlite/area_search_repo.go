@@ -0,0 +1,173 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/tnosaj/sar-training/backend/internal/domain/common"
+	"github.com/tnosaj/sar-training/backend/internal/domain/session"
+)
+
+// Area-search details live in their own tables keyed by round; these are
+// the SessionsRepo methods reading and writing them.
+
+type execer interface {
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+}
+
+func (r *SessionsRepo) SaveAreaSearch(ctx context.Context, roundID int64, a *session.AreaSearch) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	if err := saveAreaSearch(ctx, tx, roundID, a); err != nil {
+		if isForeignKeyViolation(err) {
+			return common.ErrNotFound
+		}
+		return err
+	}
+	return tx.Commit()
+}
+
+func (r *SessionsRepo) GetAreaSearch(ctx context.Context, roundID int64) (*session.AreaSearch, error) {
+	m, err := r.loadAreaSearches(ctx, `r.id = ?`, roundID)
+	if err != nil {
+		return nil, err
+	}
+	a, ok := m[roundID]
+	if !ok {
+		return nil, common.ErrNotFound
+	}
+	return a, nil
+}
+
+func (r *SessionsRepo) DeleteAreaSearch(ctx context.Context, roundID int64) error {
+	res, err := r.db.ExecContext(ctx, `DELETE FROM round_area_searches WHERE round_id=?`, roundID)
+	if err != nil {
+		return err
+	}
+	if rows, _ := res.RowsAffected(); rows == 0 {
+		return common.ErrNotFound
+	}
+	return nil
+}
+
+// ListAreaSearches returns the area searches of the rounds matching f,
+// oldest first. A round without its own start time uses its session's.
+func (r *SessionsRepo) ListAreaSearches(ctx context.Context, f session.AreaSearchFilter) ([]*session.AreaSearchRound, error) {
+	where := `1=1`
+	args := []any{}
+	if f.DogID != nil {
+		where += ` AND r.dog_id = ?`
+		args = append(args, *f.DogID)
+	}
+	if f.LocationID != nil {
+		where += ` AND s.location_id = ?`
+		args = append(args, *f.LocationID)
+	}
+	if f.From != nil {
+		where += ` AND COALESCE(r.started_at, s.started_at) >= ?`
+		args = append(args, f.From.Format(time.RFC3339))
+	}
+	if f.To != nil {
+		where += ` AND COALESCE(r.started_at, s.started_at) <= ?`
+		args = append(args, f.To.Format(time.RFC3339))
+	}
+	details, err := r.loadAreaSearches(ctx, where, args...)
+	if err != nil {
+		return nil, err
+	}
+	rows, err := r.db.QueryContext(ctx, `SELECT r.id, r.session_id, r.dog_id, s.location_id, COALESCE(r.started_at, s.started_at)
+		FROM round_area_searches a JOIN rounds r ON r.id = a.round_id JOIN sessions s ON s.id = r.session_id
+		WHERE `+where+` ORDER BY 5, r.id`, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var out []*session.AreaSearchRound
+	for rows.Next() {
+		var ar session.AreaSearchRound
+		if err := rows.Scan(&ar.RoundID, &ar.SessionID, &ar.DogID, &ar.LocationID, &ar.StartedAt); err != nil {
+			return nil, err
+		}
+		if a, ok := details[ar.RoundID]; ok {
+			ar.AreaSearch = *a
+		}
+		out = append(out, &ar)
+	}
+	return out, rows.Err()
+}
+
+func (r *SessionsRepo) loadAreaSearches(ctx context.Context, where string, args ...any) (map[int64]*session.AreaSearch, error) {
+	const from = ` JOIN rounds r ON r.id = a.round_id JOIN sessions s ON s.id = r.session_id WHERE `
+	rows, err := r.db.QueryContext(ctx, `SELECT a.round_id, a.sector_name, a.sector, a.false_alerts FROM round_area_searches a`+from+where, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	out := map[int64]*session.AreaSearch{}
+	for rows.Next() {
+		var id int64
+		var a session.AreaSearch
+		var sector sql.NullString
+		if err := rows.Scan(&id, &a.SectorName, &sector, &a.FalseAlerts); err != nil {
+			return nil, err
+		}
+		if a.Sector, err = decodeBoundary(sector); err != nil {
+			return nil, err
+		}
+		out[id] = &a
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	if len(out) == 0 {
+		return out, nil
+	}
+
+	prows, err := r.db.QueryContext(ctx, `SELECT a.round_id, a.id, a.label, a.latitude, a.longitude, a.found, a.time_to_find_s, a.notes
+		FROM hidden_persons a`+from+where+` ORDER BY a.id`, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer prows.Close()
+	for prows.Next() {
+		var id int64
+		var p session.HiddenPerson
+		var found int
+		if err := prows.Scan(&id, &p.ID, &p.Label, &p.Position.Lat, &p.Position.Lon, &found, &p.TimeToFindS, &p.Notes); err != nil {
+			return nil, err
+		}
+		p.Found = found != 0
+		if a, ok := out[id]; ok {
+			a.Persons = append(a.Persons, p)
+		}
+	}
+	return out, prows.Err()
+}
+
+func saveAreaSearch(ctx context.Context, tx execer, roundID int64, a *session.AreaSearch) error {
+	sector, err := encodeBoundary(a.Sector)
+	if err != nil {
+		return err
+	}
+	if _, err := tx.ExecContext(ctx, `DELETE FROM round_area_searches WHERE round_id=?`, roundID); err != nil {
+		return err
+	}
+	if _, err := tx.ExecContext(ctx, `INSERT INTO round_area_searches (round_id, sector_name, sector, false_alerts) VALUES (?, ?, ?, ?)`,
+		roundID, a.SectorName, sector, a.FalseAlerts); err != nil {
+		return err
+	}
+	for i := range a.Persons {
+		p := &a.Persons[i]
+		res, err := tx.ExecContext(ctx, `INSERT INTO hidden_persons (round_id, label, latitude, longitude, found, time_to_find_s, notes) VALUES (?, ?, ?, ?, ?, ?, ?)`,
+			roundID, p.Label, p.Position.Lat, p.Position.Lon, boolToInt(p.Found), p.TimeToFindS, p.Notes)
+		if err != nil {
+			return err
+		}
+		p.ID, _ = res.LastInsertId()
+	}
+	return nil
+}
@@ -3,9 +3,12 @@ package sqlite
 import (
 	"context"
 	"database/sql"
+	"errors"
+	"strings"
 	"time"
 
 	"github.com/tnosaj/sar-training/backend/internal/domain/behavior"
+	"github.com/tnosaj/sar-training/backend/internal/domain/common"
 	logx "github.com/tnosaj/sar-training/backend/internal/infra/log"
 )
 
@@ -17,9 +20,16 @@ func NewBehaviorsRepo(db *sql.DB) *BehaviorsRepo {
 }
 
 func (r *BehaviorsRepo) Create(ctx context.Context, b *behavior.Behavior) error {
-	res, err := r.db.ExecContext(ctx, `INSERT INTO behaviors (skill_id, name, description, created_at, updated_at) VALUES (?, ?, ?, ?, ?)`,
+	return insertBehavior(ctx, r.db, b)
+}
+
+func insertBehavior(ctx context.Context, ex execer, b *behavior.Behavior) error {
+	res, err := ex.ExecContext(ctx, `INSERT INTO behaviors (skill_id, name, description, created_at, updated_at) VALUES (?, ?, ?, ?, ?)`,
 		b.SkillID, b.Name, b.Description, b.CreatedAt.Format(time.RFC3339), b.UpdatedAt.Format(time.RFC3339))
 	if err != nil {
+		if isForeignKeyViolation(err) {
+			return common.ErrValidation
+		}
 		return err
 	}
 	id, _ := res.LastInsertId()
@@ -27,14 +37,20 @@ func (r *BehaviorsRepo) Create(ctx context.Context, b *behavior.Behavior) error
 	return nil
 }
 
-func (r *BehaviorsRepo) List(ctx context.Context, skillID *int64) ([]*behavior.Behavior, error) {
-	query := `SELECT id, skill_id, name, description, created_at, updated_at FROM behaviors`
+func (r *BehaviorsRepo) List(ctx context.Context, f behavior.ListFilter) ([]*behavior.Behavior, error) {
+	query := `SELECT b.id, b.skill_id, b.name, b.description, b.created_at, b.updated_at FROM behaviors b`
 	args := []any{}
-	if skillID != nil {
-		query += ` WHERE skill_id = ?`
-		args = append(args, *skillID)
+	order := `b.id DESC`
+	if f.ExerciseID != nil {
+		query += ` JOIN behavior_exercises be ON be.behavior_id = b.id AND be.exercise_id = ?`
+		args = append(args, *f.ExerciseID)
+		order = `be.strength DESC, b.id DESC`
 	}
-	query += ` ORDER BY id DESC`
+	if f.SkillID != nil {
+		query += ` WHERE b.skill_id = ?`
+		args = append(args, *f.SkillID)
+	}
+	query += ` ORDER BY ` + order
 	rows, err := r.db.QueryContext(ctx, query, args...)
 	if err != nil {
 		return nil, err
@@ -42,20 +58,54 @@ func (r *BehaviorsRepo) List(ctx context.Context, skillID *int64) ([]*behavior.B
 	defer rows.Close()
 	var out []*behavior.Behavior
 	for rows.Next() {
-		var b behavior.Behavior
-		var c, u string
-		if err := rows.Scan(&b.ID, &b.SkillID, &b.Name, &b.Description, &c, &u); err != nil {
+		b, err := scanBehavior(rows)
+		if err != nil {
 			return nil, err
 		}
-		b.CreatedAt, _ = time.Parse(time.RFC3339, c)
-		b.UpdatedAt, _ = time.Parse(time.RFC3339, u)
-		out = append(out, &b)
+		out = append(out, b)
 	}
 	return out, rows.Err()
 }
 
 func (r *BehaviorsRepo) Get(ctx context.Context, id behavior.BehaviorID) (*behavior.Behavior, error) {
 	row := r.db.QueryRowContext(ctx, `SELECT id, skill_id, name, description, created_at, updated_at FROM behaviors WHERE id=?`, id)
+	b, err := scanBehavior(row)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, common.ErrNotFound
+	}
+	return b, err
+}
+
+func (r *BehaviorsRepo) Update(ctx context.Context, b *behavior.Behavior) error {
+	res, err := r.db.ExecContext(ctx, `UPDATE behaviors SET skill_id=?, name=?, description=?, updated_at=? WHERE id=?`,
+		b.SkillID, b.Name, b.Description, b.UpdatedAt.Format(time.RFC3339), b.ID)
+	if err != nil {
+		if isForeignKeyViolation(err) {
+			return common.ErrValidation
+		}
+		return err
+	}
+	if rows, _ := res.RowsAffected(); rows == 0 {
+		return common.ErrNotFound
+	}
+	return nil
+}
+
+func (r *BehaviorsRepo) Delete(ctx context.Context, id behavior.BehaviorID) error {
+	res, err := r.db.ExecContext(ctx, `DELETE FROM behaviors WHERE id=?`, id)
+	if err != nil {
+		if isForeignKeyViolation(err) {
+			return common.ErrConflict
+		}
+		return err
+	}
+	if rows, _ := res.RowsAffected(); rows == 0 {
+		return common.ErrNotFound
+	}
+	return nil
+}
+
+func scanBehavior(row rowScanner) (*behavior.Behavior, error) {
 	var b behavior.Behavior
 	var c, u string
 	if err := row.Scan(&b.ID, &b.SkillID, &b.Name, &b.Description, &c, &u); err != nil {
@@ -65,3 +115,56 @@ func (r *BehaviorsRepo) Get(ctx context.Context, id behavior.BehaviorID) (*behav
 	b.UpdatedAt, _ = time.Parse(time.RFC3339, u)
 	return &b, nil
 }
+
+func (r *BehaviorsRepo) FreeTexts(ctx context.Context) ([]behavior.FreeText, error) {
+	rows, err := r.db.QueryContext(ctx, `SELECT MIN(trim(r.exhibited_free_text)), COUNT(*), COUNT(DISTINCT r.dog_id), MIN(s.started_at), MAX(s.started_at),
+			(SELECT MIN(b.id) FROM behaviors b WHERE lower(b.name) = lower(trim(r.exhibited_free_text)))
+		FROM rounds r JOIN sessions s ON s.id = r.session_id
+		WHERE r.exhibited_behavior_id IS NULL AND trim(COALESCE(r.exhibited_free_text, '')) <> ''
+		GROUP BY lower(trim(r.exhibited_free_text))
+		ORDER BY COUNT(*) DESC, 1`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var out []behavior.FreeText
+	for rows.Next() {
+		var f behavior.FreeText
+		if err := rows.Scan(&f.Text, &f.Rounds, &f.Dogs, &f.FirstSeen, &f.LastSeen, &f.MatchID); err != nil {
+			return nil, err
+		}
+		out = append(out, f)
+	}
+	return out, rows.Err()
+}
+
+func (r *BehaviorsRepo) Promote(ctx context.Context, texts []string, b *behavior.Behavior) (int64, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+	if b.ID == 0 {
+		if err := insertBehavior(ctx, tx, b); err != nil {
+			return 0, err
+		}
+	}
+	args := []any{b.ID}
+	for _, t := range texts {
+		args = append(args, t)
+	}
+	in := strings.TrimSuffix(strings.Repeat("lower(trim(?)), ", len(texts)), ", ")
+	res, err := tx.ExecContext(ctx, `UPDATE rounds SET exhibited_behavior_id=?
+		WHERE exhibited_behavior_id IS NULL AND lower(trim(exhibited_free_text)) IN (`+in+`)`, args...)
+	if err != nil {
+		if isForeignKeyViolation(err) {
+			return 0, common.ErrValidation
+		}
+		return 0, err
+	}
+	n, _ := res.RowsAffected()
+	if n == 0 {
+		return 0, common.ErrNotFound
+	}
+	return n, tx.Commit()
+}
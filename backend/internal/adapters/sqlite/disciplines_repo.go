@@ -0,0 +1,116 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/tnosaj/sar-training/backend/internal/domain/common"
+	"github.com/tnosaj/sar-training/backend/internal/domain/discipline"
+	logx "github.com/tnosaj/sar-training/backend/internal/infra/log"
+)
+
+type DisciplinesRepo struct{ db *sql.DB }
+
+func NewDisciplinesRepo(db *sql.DB) *DisciplinesRepo {
+	logx.Std.Trace("starting disciplines repo")
+	return &DisciplinesRepo{db: db}
+}
+
+const disciplineColumns = `d.id, d.name, d.description, d.round_extensions, d.created_at, d.updated_at`
+
+func (r *DisciplinesRepo) Create(ctx context.Context, d *discipline.Discipline) error {
+	res, err := r.db.ExecContext(ctx, `INSERT INTO disciplines (name, description, round_extensions, created_at, updated_at) VALUES (?, ?, ?, ?, ?)`,
+		d.Name, d.Description, joinExtensions(d.Extensions), d.CreatedAt.Format(time.RFC3339), d.UpdatedAt.Format(time.RFC3339))
+	if err != nil {
+		return mapConstraint(err)
+	}
+	id, _ := res.LastInsertId()
+	d.ID = discipline.DisciplineID(id)
+	return nil
+}
+
+func (r *DisciplinesRepo) Get(ctx context.Context, id discipline.DisciplineID) (*discipline.Discipline, error) {
+	row := r.db.QueryRowContext(ctx, `SELECT `+disciplineColumns+` FROM disciplines d WHERE d.id=?`, id)
+	d, err := scanDiscipline(row)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, common.ErrNotFound
+	}
+	return d, err
+}
+
+func (r *DisciplinesRepo) ForBehavior(ctx context.Context, behaviorID int64) (*discipline.Discipline, error) {
+	row := r.db.QueryRowContext(ctx, `SELECT `+disciplineColumns+` FROM disciplines d
+		JOIN skills s ON s.discipline_id = d.id JOIN behaviors b ON b.skill_id = s.id WHERE b.id=?`, behaviorID)
+	d, err := scanDiscipline(row)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, common.ErrNotFound
+	}
+	return d, err
+}
+
+func (r *DisciplinesRepo) List(ctx context.Context) ([]*discipline.Discipline, error) {
+	rows, err := r.db.QueryContext(ctx, `SELECT `+disciplineColumns+` FROM disciplines d ORDER BY d.name`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var out []*discipline.Discipline
+	for rows.Next() {
+		d, err := scanDiscipline(rows)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, d)
+	}
+	return out, rows.Err()
+}
+
+func (r *DisciplinesRepo) Update(ctx context.Context, d *discipline.Discipline) error {
+	res, err := r.db.ExecContext(ctx, `UPDATE disciplines SET name=?, description=?, round_extensions=?, updated_at=? WHERE id=?`,
+		d.Name, d.Description, joinExtensions(d.Extensions), d.UpdatedAt.Format(time.RFC3339), d.ID)
+	if err != nil {
+		return mapConstraint(err)
+	}
+	if rows, _ := res.RowsAffected(); rows == 0 {
+		return common.ErrNotFound
+	}
+	return nil
+}
+
+func (r *DisciplinesRepo) Delete(ctx context.Context, id discipline.DisciplineID) error {
+	res, err := r.db.ExecContext(ctx, `DELETE FROM disciplines WHERE id=?`, id)
+	if err != nil {
+		return err
+	}
+	if rows, _ := res.RowsAffected(); rows == 0 {
+		return common.ErrNotFound
+	}
+	return nil
+}
+
+func scanDiscipline(row rowScanner) (*discipline.Discipline, error) {
+	var d discipline.Discipline
+	var ext, created, updated string
+	if err := row.Scan(&d.ID, &d.Name, &d.Description, &ext, &created, &updated); err != nil {
+		return nil, err
+	}
+	for _, e := range strings.Split(ext, ",") {
+		if e != "" {
+			d.Extensions = append(d.Extensions, discipline.Extension(e))
+		}
+	}
+	d.CreatedAt, _ = time.Parse(time.RFC3339, created)
+	d.UpdatedAt, _ = time.Parse(time.RFC3339, updated)
+	return &d, nil
+}
+
+func joinExtensions(ext []discipline.Extension) string {
+	parts := make([]string, len(ext))
+	for i, e := range ext {
+		parts[i] = string(e)
+	}
+	return strings.Join(parts, ",")
+}
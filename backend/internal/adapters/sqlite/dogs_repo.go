@@ -3,6 +3,9 @@ package sqlite
 import (
 	"context"
 	"database/sql"
+	"encoding/json"
+	"errors"
+	"time"
 
 	"github.com/tnosaj/sar-training/backend/internal/domain/common"
 	"github.com/tnosaj/sar-training/backend/internal/domain/dog"
@@ -16,52 +19,283 @@ func NewDogsRepo(db *sql.DB) *DogsRepo {
 	return &DogsRepo{db: db}
 }
 
+const dogColumns = `d.id, d.name, d.callname, d.birthdate, d.breed, d.sex, d.microchip, d.registration_number, d.owner, d.handler_id, d.status, d.indication, d.attributes,
+	EXISTS (SELECT 1 FROM dog_photos p WHERE p.dog_id = d.id), d.archived_at`
+
+// dogHistory lists every table holding rows that belong to a dog, with a
+// query counting them. Rows are removed by Purge either explicitly (rounds
+// and mission teams, which restrict deletion, and the dog's mock-exam
+// sessions) or through ON DELETE CASCADE.
+var dogHistory = []struct{ table, count string }{
+	{"rounds", `SELECT COUNT(*) FROM rounds WHERE dog_id=?`},
+	{"session_dogs", `SELECT COUNT(*) FROM session_dogs WHERE dog_id=?`},
+	{"alerts", `SELECT COUNT(*) FROM alerts WHERE dog_id=?`},
+	{"qualifications", `SELECT COUNT(*) FROM qualifications WHERE dog_id=?`},
+	{"qualification_attachments", `SELECT COUNT(*) FROM qualification_attachments WHERE qualification_id IN (SELECT id FROM qualifications WHERE dog_id=?)`},
+	{"dog_photos", `SELECT COUNT(*) FROM dog_photos WHERE dog_id=?`},
+	{"dog_handlers", `SELECT COUNT(*) FROM dog_handlers WHERE dog_id=?`},
+	{"health_entries", `SELECT COUNT(*) FROM health_entries WHERE dog_id=?`},
+	{"tracks", `SELECT COUNT(*) FROM tracks WHERE round_id IN (SELECT id FROM rounds WHERE dog_id=?)`},
+	{"round_area_searches", `SELECT COUNT(*) FROM round_area_searches WHERE round_id IN (SELECT id FROM rounds WHERE dog_id=?)`},
+	{"hidden_persons", `SELECT COUNT(*) FROM hidden_persons WHERE round_id IN (SELECT id FROM rounds WHERE dog_id=?)`},
+	{"round_trailing", `SELECT COUNT(*) FROM round_trailing WHERE round_id IN (SELECT id FROM rounds WHERE dog_id=?)`},
+	{"round_distractions", `SELECT COUNT(*) FROM round_distractions WHERE round_id IN (SELECT id FROM rounds WHERE dog_id=?)`},
+	{"round_rubble", `SELECT COUNT(*) FROM round_rubble WHERE round_id IN (SELECT id FROM rounds WHERE dog_id=?)`},
+	{"round_figurants", `SELECT COUNT(*) FROM round_figurants WHERE round_id IN (SELECT id FROM rounds WHERE dog_id=?)`},
+	{"round_scores", `SELECT COUNT(*) FROM round_scores WHERE round_id IN (SELECT id FROM rounds WHERE dog_id=?)`},
+	{"round_parameters", `SELECT COUNT(*) FROM round_parameters WHERE round_id IN (SELECT id FROM rounds WHERE dog_id=?)`},
+	{"mission_teams", `SELECT COUNT(*) FROM mission_teams WHERE dog_id=?`},
+	{"exam_runs", `SELECT COUNT(*) FROM exam_runs WHERE dog_id=?`},
+	{"exam_run_rounds", `SELECT COUNT(*) FROM exam_run_rounds WHERE run_id IN (SELECT id FROM exam_runs WHERE dog_id=?)`},
+	{"exam_run_results", `SELECT COUNT(*) FROM exam_run_results WHERE run_id IN (SELECT id FROM exam_runs WHERE dog_id=?)`},
+	{"mock_exam_sessions", `SELECT COUNT(*) FROM sessions WHERE ` + mockExamSessionsOf},
+}
+
+// mockExamSessionsOf selects the sessions created for the dog's exam runs.
+const mockExamSessionsOf = `kind = 'mock_exam' AND id IN (SELECT session_id FROM exam_runs WHERE dog_id=?)`
+
 func (r *DogsRepo) Create(ctx context.Context, d *dog.Dog) error {
-	res, err := r.db.ExecContext(ctx, `INSERT INTO dogs (name, callname, birthdate) VALUES (?, ?, ?)`, d.Name, d.Callname, d.Birthdate)
+	attrs, err := encodeAttributes(d.Attributes)
 	if err != nil {
 		return err
 	}
+	res, err := r.db.ExecContext(ctx, `INSERT INTO dogs (name, callname, birthdate, breed, sex, microchip, registration_number, owner, handler_id, status, indication, attributes) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		d.Name, d.Callname, d.Birthdate, d.Breed, d.Sex, d.Microchip, d.RegistrationNumber, d.Owner, d.HandlerID, d.Status, d.Indication, attrs)
+	if err != nil {
+		return mapConstraint(err)
+	}
 	id, _ := res.LastInsertId()
 	d.ID = dog.DogID(id)
 	return nil
 }
 
 func (r *DogsRepo) Update(ctx context.Context, d *dog.Dog) error {
-	_, err := r.db.ExecContext(ctx, `UPDATE dogs set name=?, callname=?, birthdate=? WHERE id=?`, d.Name, *d.Callname, *d.Birthdate, d.ID)
-	return err
+	attrs, err := encodeAttributes(d.Attributes)
+	if err != nil {
+		return err
+	}
+	res, err := r.db.ExecContext(ctx, `UPDATE dogs set name=?, callname=?, birthdate=?, breed=?, sex=?, microchip=?, registration_number=?, owner=?, handler_id=?, status=?, indication=?, attributes=? WHERE id=?`,
+		d.Name, d.Callname, d.Birthdate, d.Breed, d.Sex, d.Microchip, d.RegistrationNumber, d.Owner, d.HandlerID, d.Status, d.Indication, attrs, d.ID)
+	if err != nil {
+		return mapConstraint(err)
+	}
+	if rows, _ := res.RowsAffected(); rows == 0 {
+		return common.ErrNotFound
+	}
+	return nil
 }
 
+// Delete removes a dog that has no history at all. Anything listed in
+// dogHistory makes it a conflict, so cascades never drop records silently;
+// Purge is the way to remove those.
 func (r *DogsRepo) Delete(ctx context.Context, id dog.DogID) error {
-	res, err := r.db.ExecContext(ctx, `DELETE from dogs WHERE id=?`, id)
-	rows, _ := res.RowsAffected()
-	if rows == 0 {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	h, err := dogHistoryCounts(ctx, tx, id)
+	if err != nil {
+		return err
+	}
+	for _, n := range h {
+		if n > 0 {
+			return common.ErrConflict
+		}
+	}
+	res, err := tx.ExecContext(ctx, `DELETE from dogs WHERE id=?`, id)
+	if err != nil {
+		if isForeignKeyViolation(err) {
+			return common.ErrConflict
+		}
+		return err
+	}
+	if rows, _ := res.RowsAffected(); rows == 0 {
 		return common.ErrNotFound
 	}
-	return err
+	return tx.Commit()
+}
+
+func (r *DogsRepo) SetArchived(ctx context.Context, id dog.DogID, at *time.Time) error {
+	var v *string
+	if at != nil {
+		s := at.Format(time.RFC3339)
+		v = &s
+	}
+	res, err := r.db.ExecContext(ctx, `UPDATE dogs SET archived_at=? WHERE id=?`, v, id)
+	if err != nil {
+		return err
+	}
+	if rows, _ := res.RowsAffected(); rows == 0 {
+		return common.ErrNotFound
+	}
+	return nil
 }
 
-func (r *DogsRepo) List(ctx context.Context) ([]*dog.Dog, error) {
-	rows, err := r.db.QueryContext(ctx, `SELECT id, name, callname, birthdate FROM dogs ORDER BY id DESC`)
+func (r *DogsRepo) Archive(ctx context.Context, id dog.DogID, at time.Time) error {
+	res, err := r.db.ExecContext(ctx, `UPDATE dogs SET status=?, archived_at=? WHERE id=?`, dog.StatusRetired, at.Format(time.RFC3339), id)
+	if err != nil {
+		return err
+	}
+	if rows, _ := res.RowsAffected(); rows == 0 {
+		return common.ErrNotFound
+	}
+	return nil
+}
+
+func (r *DogsRepo) History(ctx context.Context, id dog.DogID) (dog.History, error) {
+	return dogHistoryCounts(ctx, r.db, id)
+}
+
+func (r *DogsRepo) Purge(ctx context.Context, id dog.DogID) (dog.History, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+	h, err := dogHistoryCounts(ctx, tx, id)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := tx.ExecContext(ctx, `DELETE FROM rounds WHERE dog_id=?`, id); err != nil {
+		return nil, err
+	}
+	if _, err := tx.ExecContext(ctx, `DELETE FROM mission_teams WHERE dog_id=?`, id); err != nil {
+		return nil, err
+	}
+	if _, err := tx.ExecContext(ctx, `DELETE FROM sessions WHERE `+mockExamSessionsOf, id); err != nil {
+		return nil, err
+	}
+	res, err := tx.ExecContext(ctx, `DELETE FROM dogs WHERE id=?`, id)
+	if err != nil {
+		return nil, err
+	}
+	if rows, _ := res.RowsAffected(); rows == 0 {
+		return nil, common.ErrNotFound
+	}
+	return h, tx.Commit()
+}
+
+type queryer interface {
+	QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row
+}
+
+func dogHistoryCounts(ctx context.Context, q queryer, id dog.DogID) (dog.History, error) {
+	h := dog.History{}
+	for _, t := range dogHistory {
+		var n int64
+		if err := q.QueryRowContext(ctx, t.count, id).Scan(&n); err != nil {
+			return nil, err
+		}
+		h[t.table] = n
+	}
+	return h, nil
+}
+
+func (r *DogsRepo) List(ctx context.Context, f dog.Filter) ([]*dog.Dog, error) {
+	query := `SELECT ` + dogColumns + ` FROM dogs d WHERE 1=1`
+	args := []any{}
+	if f.Status != nil {
+		query += ` AND d.status = ?`
+		args = append(args, *f.Status)
+	}
+	if !f.IncludeArchived {
+		query += ` AND d.archived_at IS NULL`
+	}
+	if f.HandlerUserID != nil {
+		// dogs never paired fall back to the handler on their profile
+		query += ` AND (d.id IN (SELECT h.dog_id FROM dog_handlers h WHERE h.user_id = ? AND h.start_date <= date('now') AND (h.end_date IS NULL OR h.end_date >= date('now')))
+			OR (d.handler_id = ? AND NOT EXISTS (SELECT 1 FROM dog_handlers h WHERE h.dog_id = d.id)))`
+		args = append(args, *f.HandlerUserID, *f.HandlerUserID)
+	}
+	query += ` ORDER BY d.id DESC`
+	rows, err := r.db.QueryContext(ctx, query, args...)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
 	var out []*dog.Dog
 	for rows.Next() {
-		var d dog.Dog
-		if err := rows.Scan(&d.ID, &d.Name, &d.Callname, &d.Birthdate); err != nil {
+		d, err := scanDog(rows)
+		if err != nil {
 			return nil, err
 		}
-		out = append(out, &d)
+		out = append(out, d)
 	}
 	return out, rows.Err()
 }
 
 func (r *DogsRepo) Get(ctx context.Context, id dog.DogID) (*dog.Dog, error) {
-	row := r.db.QueryRowContext(ctx, `SELECT id, name, callname, birthdate FROM dogs WHERE id=?`, id)
+	row := r.db.QueryRowContext(ctx, `SELECT `+dogColumns+` FROM dogs d WHERE d.id=?`, id)
+	d, err := scanDog(row)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, common.ErrNotFound
+		}
+		return nil, err
+	}
+	return d, nil
+}
+
+func (r *DogsRepo) SetPhoto(ctx context.Context, p *dog.Photo) error {
+	_, err := r.db.ExecContext(ctx, `INSERT INTO dog_photos (dog_id, content_type, data, updated_at) VALUES (?, ?, ?, ?)
+		ON CONFLICT(dog_id) DO UPDATE SET content_type=excluded.content_type, data=excluded.data, updated_at=excluded.updated_at`,
+		p.DogID, p.ContentType, p.Data, p.UpdatedAt.Format(time.RFC3339))
+	return err
+}
+
+func (r *DogsRepo) GetPhoto(ctx context.Context, id dog.DogID) (*dog.Photo, error) {
+	row := r.db.QueryRowContext(ctx, `SELECT dog_id, content_type, data, updated_at FROM dog_photos WHERE dog_id=?`, id)
+	var p dog.Photo
+	var u string
+	if err := row.Scan(&p.DogID, &p.ContentType, &p.Data, &u); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, common.ErrNotFound
+		}
+		return nil, err
+	}
+	p.UpdatedAt, _ = time.Parse(time.RFC3339, u)
+	return &p, nil
+}
+
+func (r *DogsRepo) DeletePhoto(ctx context.Context, id dog.DogID) error {
+	res, err := r.db.ExecContext(ctx, `DELETE FROM dog_photos WHERE dog_id=?`, id)
+	if err != nil {
+		return err
+	}
+	if rows, _ := res.RowsAffected(); rows == 0 {
+		return common.ErrNotFound
+	}
+	return nil
+}
+
+func scanDog(row rowScanner) (*dog.Dog, error) {
 	var d dog.Dog
-	if err := row.Scan(&d.ID, &d.Name, &d.Callname, &d.Birthdate); err != nil {
+	var attrs, archived sql.NullString
+	if err := row.Scan(&d.ID, &d.Name, &d.Callname, &d.Birthdate, &d.Breed, &d.Sex, &d.Microchip, &d.RegistrationNumber,
+		&d.Owner, &d.HandlerID, &d.Status, &d.Indication, &attrs, &d.HasPhoto, &archived); err != nil {
 		return nil, err
 	}
+	if archived.Valid {
+		t, _ := time.Parse(time.RFC3339, archived.String)
+		d.ArchivedAt = &t
+	}
+	if attrs.Valid && attrs.String != "" {
+		if err := json.Unmarshal([]byte(attrs.String), &d.Attributes); err != nil {
+			return nil, err
+		}
+	}
 	return &d, nil
 }
+
+func encodeAttributes(attrs map[string]string) (*string, error) {
+	if len(attrs) == 0 {
+		return nil, nil
+	}
+	b, err := json.Marshal(attrs)
+	if err != nil {
+		return nil, err
+	}
+	v := string(b)
+	return &v, nil
+}
@@ -0,0 +1,31 @@
+package sqlite
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/mattn/go-sqlite3"
+	"github.com/tnosaj/sar-training/backend/internal/domain/common"
+)
+
+// mapConstraint translates SQLite unique constraint violations into
+// common.ErrConflict and passes every other error through.
+func mapConstraint(err error) error {
+	var se sqlite3.Error
+	if errors.As(err, &se) && se.ExtendedCode == sqlite3.ErrConstraintUnique {
+		return common.ErrConflict
+	}
+	return err
+}
+
+// isForeignKeyViolation reports whether err was caused by a foreign key
+// constraint. SQLite reports ON DELETE RESTRICT violations with the trigger
+// constraint code, so those are recognized by their message.
+func isForeignKeyViolation(err error) bool {
+	var se sqlite3.Error
+	if !errors.As(err, &se) {
+		return false
+	}
+	return se.ExtendedCode == sqlite3.ErrConstraintForeignKey ||
+		(se.ExtendedCode == sqlite3.ErrConstraintTrigger && strings.Contains(se.Error(), "FOREIGN KEY"))
+}
@@ -0,0 +1,187 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/tnosaj/sar-training/backend/internal/domain/common"
+	"github.com/tnosaj/sar-training/backend/internal/domain/exam"
+	logx "github.com/tnosaj/sar-training/backend/internal/infra/log"
+)
+
+type ExamsRepo struct{ db *sql.DB }
+
+func NewExamsRepo(db *sql.DB) *ExamsRepo {
+	logx.Std.Trace("starting exams repo")
+	return &ExamsRepo{db: db}
+}
+
+func (r *ExamsRepo) Create(ctx context.Context, e *exam.Exam) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	res, err := tx.ExecContext(ctx, `INSERT INTO exams (name, description, window_days, created_at, updated_at) VALUES (?, ?, ?, ?, ?)`,
+		e.Name, e.Description, e.WindowDays, e.CreatedAt.Format(time.RFC3339), e.UpdatedAt.Format(time.RFC3339))
+	if err != nil {
+		return mapConstraint(err)
+	}
+	id, _ := res.LastInsertId()
+	e.ID = exam.ExamID(id)
+	if err := insertRequirements(ctx, tx, e); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+func (r *ExamsRepo) Get(ctx context.Context, id exam.ExamID) (*exam.Exam, error) {
+	row := r.db.QueryRowContext(ctx, `SELECT id, name, description, window_days, created_at, updated_at FROM exams WHERE id=?`, id)
+	var e exam.Exam
+	var c, u string
+	if err := row.Scan(&e.ID, &e.Name, &e.Description, &e.WindowDays, &c, &u); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, common.ErrNotFound
+		}
+		return nil, err
+	}
+	e.CreatedAt, _ = time.Parse(time.RFC3339, c)
+	e.UpdatedAt, _ = time.Parse(time.RFC3339, u)
+	reqs, err := r.listRequirements(ctx, e.ID)
+	if err != nil {
+		return nil, err
+	}
+	e.Requirements = reqs
+	return &e, nil
+}
+
+func (r *ExamsRepo) List(ctx context.Context) ([]*exam.Exam, error) {
+	rows, err := r.db.QueryContext(ctx, `SELECT id, name, description, window_days, created_at, updated_at FROM exams ORDER BY name`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var out []*exam.Exam
+	for rows.Next() {
+		var e exam.Exam
+		var c, u string
+		if err := rows.Scan(&e.ID, &e.Name, &e.Description, &e.WindowDays, &c, &u); err != nil {
+			return nil, err
+		}
+		e.CreatedAt, _ = time.Parse(time.RFC3339, c)
+		e.UpdatedAt, _ = time.Parse(time.RFC3339, u)
+		out = append(out, &e)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	for _, e := range out {
+		if e.Requirements, err = r.listRequirements(ctx, e.ID); err != nil {
+			return nil, err
+		}
+	}
+	return out, nil
+}
+
+func (r *ExamsRepo) Update(ctx context.Context, e *exam.Exam) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	res, err := tx.ExecContext(ctx, `UPDATE exams SET name=?, description=?, window_days=?, updated_at=? WHERE id=?`,
+		e.Name, e.Description, e.WindowDays, e.UpdatedAt.Format(time.RFC3339), e.ID)
+	if err != nil {
+		return mapConstraint(err)
+	}
+	if rows, _ := res.RowsAffected(); rows == 0 {
+		return common.ErrNotFound
+	}
+	if _, err := tx.ExecContext(ctx, `DELETE FROM exam_requirements WHERE exam_id=?`, e.ID); err != nil {
+		return err
+	}
+	if err := insertRequirements(ctx, tx, e); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+func (r *ExamsRepo) Delete(ctx context.Context, id exam.ExamID) error {
+	res, err := r.db.ExecContext(ctx, `DELETE FROM exams WHERE id=?`, id)
+	if err != nil {
+		return err
+	}
+	a, _ := res.RowsAffected()
+	if a == 0 {
+		return common.ErrNotFound
+	}
+	return nil
+}
+
+func (r *ExamsRepo) ListResults(ctx context.Context, dogID int64, req exam.Requirement, since time.Time) ([]exam.RoundResult, error) {
+	query := `SELECT r.outcome, r.score FROM rounds r JOIN sessions s ON s.id = r.session_id
+		WHERE r.dog_id = ? AND COALESCE(r.started_at, s.started_at) >= ?`
+	args := []any{dogID, since.Format(time.RFC3339)}
+	if req.Missions {
+		query = `SELECT CASE WHEN t.found THEN 'success' ELSE 'fail' END, NULL FROM mission_teams t JOIN missions m ON m.id = t.mission_id
+			WHERE t.dog_id = ? AND m.started_at >= ? AND m.outcome <> 'cancelled'`
+	} else if req.BehaviorID != nil {
+		query += ` AND r.planned_behavior_id = ?`
+		args = append(args, *req.BehaviorID)
+	} else {
+		query += ` AND r.planned_behavior_id IN (SELECT id FROM behaviors WHERE skill_id = ?)`
+		args = append(args, *req.SkillID)
+	}
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var out []exam.RoundResult
+	for rows.Next() {
+		var rr exam.RoundResult
+		var score sql.NullInt64
+		if err := rows.Scan(&rr.Outcome, &score); err != nil {
+			return nil, err
+		}
+		if score.Valid {
+			v := int(score.Int64)
+			rr.Score = &v
+		}
+		out = append(out, rr)
+	}
+	return out, rows.Err()
+}
+
+func (r *ExamsRepo) listRequirements(ctx context.Context, examID exam.ExamID) ([]exam.Requirement, error) {
+	rows, err := r.db.QueryContext(ctx, `SELECT id, exam_id, behavior_id, skill_id, missions, min_success_rate, min_score, min_rounds, window_days, weight FROM exam_requirements WHERE exam_id=? ORDER BY id`, examID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var out []exam.Requirement
+	for rows.Next() {
+		var q exam.Requirement
+		if err := rows.Scan(&q.ID, &q.ExamID, &q.BehaviorID, &q.SkillID, &q.Missions, &q.MinSuccessRate, &q.MinScore, &q.MinRounds, &q.WindowDays, &q.Weight); err != nil {
+			return nil, err
+		}
+		out = append(out, q)
+	}
+	return out, rows.Err()
+}
+
+func insertRequirements(ctx context.Context, tx *sql.Tx, e *exam.Exam) error {
+	for i := range e.Requirements {
+		q := &e.Requirements[i]
+		q.ExamID = e.ID
+		res, err := tx.ExecContext(ctx, `INSERT INTO exam_requirements (exam_id, behavior_id, skill_id, missions, min_success_rate, min_score, min_rounds, window_days, weight) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+			e.ID, q.BehaviorID, q.SkillID, q.Missions, q.MinSuccessRate, q.MinScore, q.MinRounds, q.WindowDays, q.Weight)
+		if err != nil {
+			return err
+		}
+		q.ID, _ = res.LastInsertId()
+	}
+	return nil
+}
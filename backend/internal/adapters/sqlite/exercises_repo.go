@@ -3,8 +3,10 @@ package sqlite
 import (
 	"context"
 	"database/sql"
+	"errors"
 	"time"
 
+	"github.com/tnosaj/sar-training/backend/internal/domain/common"
 	"github.com/tnosaj/sar-training/backend/internal/domain/exercise"
 	logx "github.com/tnosaj/sar-training/backend/internal/infra/log"
 )
@@ -20,35 +22,74 @@ func (r *ExercisesRepo) Create(ctx context.Context, e *exercise.Exercise) error
 	res, err := r.db.ExecContext(ctx, `INSERT INTO exercises (name, description, created_at, updated_at) VALUES (?, ?, ?, ?)`,
 		e.Name, e.Description, e.CreatedAt.Format(time.RFC3339), e.UpdatedAt.Format(time.RFC3339))
 	if err != nil {
-		return err
+		return mapConstraint(err)
 	}
 	id, _ := res.LastInsertId()
 	e.ID = exercise.ExerciseID(id)
 	return nil
 }
 
-func (r *ExercisesRepo) List(ctx context.Context) ([]*exercise.Exercise, error) {
-	rows, err := r.db.QueryContext(ctx, `SELECT id, name, description, created_at, updated_at FROM exercises ORDER BY id DESC`)
+func (r *ExercisesRepo) List(ctx context.Context, f exercise.ListFilter) ([]*exercise.Exercise, error) {
+	query := `SELECT e.id, e.name, e.description, e.created_at, e.updated_at FROM exercises e`
+	args := []any{}
+	order := `e.id DESC`
+	if f.BehaviorID != nil {
+		query += ` JOIN behavior_exercises be ON be.exercise_id = e.id AND be.behavior_id = ?`
+		args = append(args, *f.BehaviorID)
+		order = `be.strength DESC, e.id DESC`
+	}
+	rows, err := r.db.QueryContext(ctx, query+` ORDER BY `+order, args...)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
 	var out []*exercise.Exercise
 	for rows.Next() {
-		var e exercise.Exercise
-		var c, u string
-		if err := rows.Scan(&e.ID, &e.Name, &e.Description, &c, &u); err != nil {
+		e, err := scanExercise(rows)
+		if err != nil {
 			return nil, err
 		}
-		e.CreatedAt, _ = time.Parse(time.RFC3339, c)
-		e.UpdatedAt, _ = time.Parse(time.RFC3339, u)
-		out = append(out, &e)
+		out = append(out, e)
 	}
 	return out, rows.Err()
 }
 
 func (r *ExercisesRepo) Get(ctx context.Context, id exercise.ExerciseID) (*exercise.Exercise, error) {
 	row := r.db.QueryRowContext(ctx, `SELECT id, name, description, created_at, updated_at FROM exercises WHERE id=?`, id)
+	e, err := scanExercise(row)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, common.ErrNotFound
+	}
+	return e, err
+}
+
+func (r *ExercisesRepo) Update(ctx context.Context, e *exercise.Exercise) error {
+	res, err := r.db.ExecContext(ctx, `UPDATE exercises SET name=?, description=?, updated_at=? WHERE id=?`,
+		e.Name, e.Description, e.UpdatedAt.Format(time.RFC3339), e.ID)
+	if err != nil {
+		return mapConstraint(err)
+	}
+	if rows, _ := res.RowsAffected(); rows == 0 {
+		return common.ErrNotFound
+	}
+	return nil
+}
+
+func (r *ExercisesRepo) Delete(ctx context.Context, id exercise.ExerciseID) error {
+	res, err := r.db.ExecContext(ctx, `DELETE FROM exercises WHERE id=?`, id)
+	if err != nil {
+		if isForeignKeyViolation(err) {
+			return common.ErrConflict
+		}
+		return err
+	}
+	if rows, _ := res.RowsAffected(); rows == 0 {
+		return common.ErrNotFound
+	}
+	return nil
+}
+
+func scanExercise(row rowScanner) (*exercise.Exercise, error) {
 	var e exercise.Exercise
 	var c, u string
 	if err := row.Scan(&e.ID, &e.Name, &e.Description, &c, &u); err != nil {
@@ -60,7 +101,255 @@ func (r *ExercisesRepo) Get(ctx context.Context, id exercise.ExerciseID) (*exerc
 }
 
 func (r *ExercisesRepo) LinkBehavior(ctx context.Context, behaviorID int64, exerciseID int64, strength int) error {
-	_, err := r.db.ExecContext(ctx, `INSERT OR REPLACE INTO behavior_exercises (behavior_id, exercise_id, strength) VALUES (?, ?, ?)`,
+	_, err := r.db.ExecContext(ctx, `INSERT INTO behavior_exercises (behavior_id, exercise_id, strength) VALUES (?, ?, ?)
+		ON CONFLICT(behavior_id, exercise_id) DO UPDATE SET strength=excluded.strength`,
 		behaviorID, exerciseID, strength)
+	if err != nil && isForeignKeyViolation(err) {
+		return common.ErrValidation
+	}
 	return err
 }
+
+func (r *ExercisesRepo) UnlinkBehavior(ctx context.Context, behaviorID int64, exerciseID int64) error {
+	res, err := r.db.ExecContext(ctx, `DELETE FROM behavior_exercises WHERE behavior_id=? AND exercise_id=?`, behaviorID, exerciseID)
+	if err != nil {
+		return err
+	}
+	if rows, _ := res.RowsAffected(); rows == 0 {
+		return common.ErrNotFound
+	}
+	return nil
+}
+
+func (r *ExercisesRepo) Links(ctx context.Context, f exercise.LinkFilter) ([]exercise.Link, error) {
+	query := `SELECT be.behavior_id, b.name, be.exercise_id, e.name, be.strength
+		FROM behavior_exercises be JOIN behaviors b ON b.id = be.behavior_id JOIN exercises e ON e.id = be.exercise_id WHERE 1=1`
+	args := []any{}
+	if f.BehaviorID != nil {
+		query += ` AND be.behavior_id = ?`
+		args = append(args, *f.BehaviorID)
+	}
+	if f.ExerciseID != nil {
+		query += ` AND be.exercise_id = ?`
+		args = append(args, *f.ExerciseID)
+	}
+	query += ` ORDER BY b.name, be.strength DESC, e.name`
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var out []exercise.Link
+	for rows.Next() {
+		var l exercise.Link
+		if err := rows.Scan(&l.BehaviorID, &l.BehaviorName, &l.ExerciseID, &l.ExerciseName, &l.Strength); err != nil {
+			return nil, err
+		}
+		out = append(out, l)
+	}
+	return out, rows.Err()
+}
+
+func (r *ExercisesRepo) Rubric(ctx context.Context, id exercise.ExerciseID) (exercise.Rubric, error) {
+	rows, err := r.db.QueryContext(ctx, `SELECT id, exercise_id, name, description, weight, max_points, position
+		FROM rubric_criteria WHERE exercise_id=? ORDER BY position, id`, id)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var out exercise.Rubric
+	for rows.Next() {
+		var c exercise.Criterion
+		if err := rows.Scan(&c.ID, &c.ExerciseID, &c.Name, &c.Description, &c.Weight, &c.MaxPoints, &c.Position); err != nil {
+			return nil, err
+		}
+		out = append(out, &c)
+	}
+	return out, rows.Err()
+}
+
+func (r *ExercisesRepo) SetRubric(ctx context.Context, id exercise.ExerciseID, rb exercise.Rubric) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	rows, err := tx.QueryContext(ctx, `SELECT id FROM rubric_criteria WHERE exercise_id=?`, id)
+	if err != nil {
+		return err
+	}
+	existing := map[exercise.CriterionID]bool{}
+	for rows.Next() {
+		var cid exercise.CriterionID
+		if err := rows.Scan(&cid); err != nil {
+			rows.Close()
+			return err
+		}
+		existing[cid] = true
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return err
+	}
+	kept := map[exercise.CriterionID]bool{}
+	for _, c := range rb {
+		if c.ID != 0 {
+			if !existing[c.ID] {
+				return common.ErrValidation
+			}
+			kept[c.ID] = true
+		}
+	}
+	for cid := range existing {
+		if kept[cid] {
+			continue
+		}
+		if _, err := tx.ExecContext(ctx, `DELETE FROM rubric_criteria WHERE id=?`, cid); err != nil {
+			if isForeignKeyViolation(err) {
+				return common.ErrConflict
+			}
+			return err
+		}
+	}
+	for _, c := range rb {
+		c.ExerciseID = id
+		if c.ID != 0 {
+			// the weight and points scale of a scored criterion are fixed;
+			// rounds store the weighted score computed from them
+			res, err := tx.ExecContext(ctx, `UPDATE rubric_criteria SET name=?, description=?, weight=?, max_points=?, position=?
+				WHERE id=? AND ((weight=? AND max_points=?) OR NOT EXISTS (SELECT 1 FROM round_scores WHERE criterion_id=rubric_criteria.id))`,
+				c.Name, c.Description, c.Weight, c.MaxPoints, c.Position, c.ID, c.Weight, c.MaxPoints)
+			if err != nil {
+				return mapConstraint(err)
+			}
+			if rows, _ := res.RowsAffected(); rows == 0 {
+				return common.ErrConflict
+			}
+			continue
+		}
+		res, err := tx.ExecContext(ctx, `INSERT INTO rubric_criteria (exercise_id, name, description, weight, max_points, position) VALUES (?, ?, ?, ?, ?, ?)`,
+			id, c.Name, c.Description, c.Weight, c.MaxPoints, c.Position)
+		if err != nil {
+			return mapConstraint(err)
+		}
+		cid, _ := res.LastInsertId()
+		c.ID = exercise.CriterionID(cid)
+	}
+	return tx.Commit()
+}
+
+func (r *ExercisesRepo) Difficulty(ctx context.Context, id exercise.ExerciseID) (exercise.Difficulty, error) {
+	var d exercise.Difficulty
+	rows, err := r.db.QueryContext(ctx, `SELECT kind, min_value, max_value FROM exercise_parameters WHERE exercise_id=? ORDER BY position, kind`, id)
+	if err != nil {
+		return d, err
+	}
+	for rows.Next() {
+		var p exercise.Parameter
+		if err := rows.Scan(&p.Kind, &p.Min, &p.Max); err != nil {
+			rows.Close()
+			return d, err
+		}
+		d.Parameters = append(d.Parameters, p)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return d, err
+	}
+	rows, err = r.db.QueryContext(ctx, `SELECT level, name, description FROM exercise_levels WHERE exercise_id=? ORDER BY level`, id)
+	if err != nil {
+		return d, err
+	}
+	for rows.Next() {
+		l := exercise.Level{Values: map[exercise.ParameterKind]float64{}}
+		if err := rows.Scan(&l.Level, &l.Name, &l.Description); err != nil {
+			rows.Close()
+			return d, err
+		}
+		d.Levels = append(d.Levels, l)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return d, err
+	}
+	rows, err = r.db.QueryContext(ctx, `SELECT level, kind, value FROM exercise_level_values WHERE exercise_id=?`, id)
+	if err != nil {
+		return d, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var level int
+		var kind exercise.ParameterKind
+		var v float64
+		if err := rows.Scan(&level, &kind, &v); err != nil {
+			return d, err
+		}
+		for i := range d.Levels {
+			if d.Levels[i].Level == level {
+				d.Levels[i].Values[kind] = v
+			}
+		}
+	}
+	return d, rows.Err()
+}
+
+func (r *ExercisesRepo) SetDifficulty(ctx context.Context, id exercise.ExerciseID, d exercise.Difficulty) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	rows, err := tx.QueryContext(ctx, `SELECT DISTINCT l.level, l.name FROM exercise_levels l
+		JOIN rounds r ON r.exercise_id = l.exercise_id AND r.difficulty_level = l.level WHERE l.exercise_id=?`, id)
+	if err != nil {
+		return err
+	}
+	var used []exercise.Level
+	for rows.Next() {
+		var l exercise.Level
+		if err := rows.Scan(&l.Level, &l.Name); err != nil {
+			rows.Close()
+			return err
+		}
+		used = append(used, l)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return err
+	}
+	for _, l := range used {
+		if !d.Keeps(l) {
+			return common.ErrConflict
+		}
+	}
+	for _, q := range []string{`DELETE FROM exercise_levels WHERE exercise_id=?`, `DELETE FROM exercise_parameters WHERE exercise_id=?`} {
+		if _, err := tx.ExecContext(ctx, q, id); err != nil {
+			return err
+		}
+	}
+	for i, p := range d.Parameters {
+		if _, err := tx.ExecContext(ctx, `INSERT INTO exercise_parameters (exercise_id, kind, min_value, max_value, position) VALUES (?, ?, ?, ?, ?)`,
+			id, p.Kind, p.Min, p.Max, i); err != nil {
+			if isForeignKeyViolation(err) {
+				return common.ErrNotFound
+			}
+			return mapConstraint(err)
+		}
+	}
+	for _, l := range d.Levels {
+		if _, err := tx.ExecContext(ctx, `INSERT INTO exercise_levels (exercise_id, level, name, description) VALUES (?, ?, ?, ?)`,
+			id, l.Level, l.Name, l.Description); err != nil {
+			return mapConstraint(err)
+		}
+		for k, v := range l.Values {
+			if _, err := tx.ExecContext(ctx, `INSERT INTO exercise_level_values (exercise_id, level, kind, value) VALUES (?, ?, ?, ?)`,
+				id, l.Level, k, v); err != nil {
+				if isForeignKeyViolation(err) {
+					return common.ErrValidation
+				}
+				return err
+			}
+		}
+	}
+	return tx.Commit()
+}
@@ -0,0 +1,259 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/tnosaj/sar-training/backend/internal/domain/common"
+	"github.com/tnosaj/sar-training/backend/internal/domain/figurant"
+	logx "github.com/tnosaj/sar-training/backend/internal/infra/log"
+)
+
+type FigurantsRepo struct{ db *sql.DB }
+
+func NewFigurantsRepo(db *sql.DB) *FigurantsRepo {
+	logx.Std.Trace("starting figurants repo")
+	return &FigurantsRepo{db: db}
+}
+
+const figurantColumns = `f.id, f.name, f.phone, f.email, f.scent_consent, f.consent_date, f.active, f.notes, f.created_at, f.updated_at`
+
+func (r *FigurantsRepo) Create(ctx context.Context, f *figurant.Figurant) error {
+	res, err := r.db.ExecContext(ctx, `INSERT INTO figurants (name, phone, email, scent_consent, consent_date, active, notes, created_at, updated_at) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		f.Name, f.Phone, f.Email, f.ScentConsent, formatDate(f.ConsentDate), f.Active, f.Notes,
+		f.CreatedAt.Format(time.RFC3339), f.UpdatedAt.Format(time.RFC3339))
+	if err != nil {
+		return err
+	}
+	id, _ := res.LastInsertId()
+	f.ID = figurant.FigurantID(id)
+	return nil
+}
+
+func (r *FigurantsRepo) Get(ctx context.Context, id figurant.FigurantID) (*figurant.Figurant, error) {
+	row := r.db.QueryRowContext(ctx, `SELECT `+figurantColumns+` FROM figurants f WHERE f.id=?`, id)
+	f, err := scanFigurant(row)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, common.ErrNotFound
+	}
+	return f, err
+}
+
+func (r *FigurantsRepo) List(ctx context.Context, flt figurant.Filter) ([]*figurant.Figurant, error) {
+	query := `SELECT ` + figurantColumns + ` FROM figurants f WHERE 1=1`
+	args := []any{}
+	if flt.ActiveOnly {
+		query += ` AND f.active = 1`
+	}
+	if flt.AvailableOn != nil {
+		day := flt.AvailableOn.UTC().Format(common.DateLayout)
+		query += ` AND EXISTS (SELECT 1 FROM figurant_availability a WHERE a.figurant_id = f.id AND a.start_date <= ? AND a.end_date >= ?)`
+		args = append(args, day, day)
+	}
+	return r.queryFigurants(ctx, query+` ORDER BY f.name COLLATE NOCASE, f.id`, args...)
+}
+
+func (r *FigurantsRepo) Update(ctx context.Context, f *figurant.Figurant) error {
+	res, err := r.db.ExecContext(ctx, `UPDATE figurants SET name=?, phone=?, email=?, scent_consent=?, consent_date=?, active=?, notes=?, updated_at=? WHERE id=?`,
+		f.Name, f.Phone, f.Email, f.ScentConsent, formatDate(f.ConsentDate), f.Active, f.Notes, f.UpdatedAt.Format(time.RFC3339), f.ID)
+	if err != nil {
+		return err
+	}
+	if rows, _ := res.RowsAffected(); rows == 0 {
+		return common.ErrNotFound
+	}
+	return nil
+}
+
+func (r *FigurantsRepo) Delete(ctx context.Context, id figurant.FigurantID) error {
+	res, err := r.db.ExecContext(ctx, `DELETE FROM figurants WHERE id=?`, id)
+	if err != nil {
+		if isForeignKeyViolation(err) {
+			return common.ErrConflict
+		}
+		return err
+	}
+	if rows, _ := res.RowsAffected(); rows == 0 {
+		return common.ErrNotFound
+	}
+	return nil
+}
+
+func (r *FigurantsRepo) AddAvailability(ctx context.Context, a *figurant.Availability) error {
+	res, err := r.db.ExecContext(ctx, `INSERT INTO figurant_availability (figurant_id, start_date, end_date, notes) VALUES (?, ?, ?, ?)`,
+		a.FigurantID, a.StartDate.Format(common.DateLayout), a.EndDate.Format(common.DateLayout), a.Notes)
+	if err != nil {
+		if isForeignKeyViolation(err) {
+			return common.ErrNotFound
+		}
+		return err
+	}
+	a.ID, _ = res.LastInsertId()
+	return nil
+}
+
+func (r *FigurantsRepo) ListAvailability(ctx context.Context, id figurant.FigurantID) ([]*figurant.Availability, error) {
+	rows, err := r.db.QueryContext(ctx, `SELECT id, figurant_id, start_date, end_date, notes FROM figurant_availability WHERE figurant_id=? ORDER BY start_date, id`, id)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var out []*figurant.Availability
+	for rows.Next() {
+		var a figurant.Availability
+		var start, end string
+		if err := rows.Scan(&a.ID, &a.FigurantID, &start, &end, &a.Notes); err != nil {
+			return nil, err
+		}
+		a.StartDate, _ = time.Parse(common.DateLayout, start)
+		a.EndDate, _ = time.Parse(common.DateLayout, end)
+		out = append(out, &a)
+	}
+	return out, rows.Err()
+}
+
+func (r *FigurantsRepo) DeleteAvailability(ctx context.Context, id figurant.FigurantID, availabilityID int64) error {
+	res, err := r.db.ExecContext(ctx, `DELETE FROM figurant_availability WHERE id=? AND figurant_id=?`, availabilityID, id)
+	if err != nil {
+		return err
+	}
+	if rows, _ := res.RowsAffected(); rows == 0 {
+		return common.ErrNotFound
+	}
+	return nil
+}
+
+func (r *FigurantsRepo) AvailableOn(ctx context.Context, t time.Time) (map[figurant.FigurantID]bool, error) {
+	day := t.UTC().Format(common.DateLayout)
+	rows, err := r.db.QueryContext(ctx, `SELECT DISTINCT figurant_id FROM figurant_availability WHERE start_date <= ? AND end_date >= ?`, day, day)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	out := map[figurant.FigurantID]bool{}
+	for rows.Next() {
+		var id figurant.FigurantID
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		out[id] = true
+	}
+	return out, rows.Err()
+}
+
+func (r *FigurantsRepo) AssignSession(ctx context.Context, sessionID int64, id figurant.FigurantID) error {
+	_, err := r.db.ExecContext(ctx, `INSERT OR IGNORE INTO session_figurants (session_id, figurant_id) VALUES (?, ?)`, sessionID, id)
+	if err != nil && isForeignKeyViolation(err) {
+		return common.ErrNotFound
+	}
+	return err
+}
+
+func (r *FigurantsRepo) UnassignSession(ctx context.Context, sessionID int64, id figurant.FigurantID) error {
+	res, err := r.db.ExecContext(ctx, `DELETE FROM session_figurants WHERE session_id=? AND figurant_id=?`, sessionID, id)
+	if err != nil {
+		return err
+	}
+	if rows, _ := res.RowsAffected(); rows == 0 {
+		return common.ErrNotFound
+	}
+	return nil
+}
+
+func (r *FigurantsRepo) ListBySession(ctx context.Context, sessionID int64) ([]*figurant.Figurant, error) {
+	return r.queryFigurants(ctx, `SELECT `+figurantColumns+` FROM figurants f JOIN session_figurants sf ON sf.figurant_id = f.id
+		WHERE sf.session_id=? ORDER BY f.name COLLATE NOCASE, f.id`, sessionID)
+}
+
+func (r *FigurantsRepo) SetRoundFigurants(ctx context.Context, roundID int64, ids []figurant.FigurantID) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	if _, err := tx.ExecContext(ctx, `DELETE FROM round_figurants WHERE round_id=?`, roundID); err != nil {
+		return err
+	}
+	for _, id := range ids {
+		if _, err := tx.ExecContext(ctx, `INSERT OR IGNORE INTO round_figurants (round_id, figurant_id) VALUES (?, ?)`, roundID, id); err != nil {
+			if isForeignKeyViolation(err) {
+				return common.ErrNotFound
+			}
+			return err
+		}
+		if _, err := tx.ExecContext(ctx, `INSERT OR IGNORE INTO session_figurants (session_id, figurant_id)
+			SELECT session_id, ? FROM rounds WHERE id=?`, id, roundID); err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+func (r *FigurantsRepo) ListByRound(ctx context.Context, roundID int64) ([]*figurant.Figurant, error) {
+	return r.queryFigurants(ctx, `SELECT `+figurantColumns+` FROM figurants f JOIN round_figurants rf ON rf.figurant_id = f.id
+		WHERE rf.round_id=? ORDER BY f.name COLLATE NOCASE, f.id`, roundID)
+}
+
+func (r *FigurantsRepo) History(ctx context.Context, flt figurant.HistoryFilter) ([]*figurant.Appearance, error) {
+	query := `SELECT rf.figurant_id, r.id, r.session_id, r.round_number, r.dog_id, d.name, COALESCE(r.started_at, s.started_at) AS day
+		FROM round_figurants rf JOIN rounds r ON r.id = rf.round_id JOIN sessions s ON s.id = r.session_id JOIN dogs d ON d.id = r.dog_id
+		WHERE 1=1`
+	args := []any{}
+	if flt.FigurantID != nil {
+		query += ` AND rf.figurant_id = ?`
+		args = append(args, *flt.FigurantID)
+	}
+	if flt.DogID != nil {
+		query += ` AND r.dog_id = ?`
+		args = append(args, *flt.DogID)
+	}
+	rows, err := r.db.QueryContext(ctx, query+` ORDER BY day DESC, r.id DESC`, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var out []*figurant.Appearance
+	for rows.Next() {
+		var a figurant.Appearance
+		if err := rows.Scan(&a.FigurantID, &a.RoundID, &a.SessionID, &a.RoundNumber, &a.DogID, &a.DogName, &a.Date); err != nil {
+			return nil, err
+		}
+		out = append(out, &a)
+	}
+	return out, rows.Err()
+}
+
+func (r *FigurantsRepo) queryFigurants(ctx context.Context, query string, args ...any) ([]*figurant.Figurant, error) {
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var out []*figurant.Figurant
+	for rows.Next() {
+		f, err := scanFigurant(rows)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, f)
+	}
+	return out, rows.Err()
+}
+
+func scanFigurant(row rowScanner) (*figurant.Figurant, error) {
+	var f figurant.Figurant
+	var consent sql.NullString
+	var created, updated string
+	if err := row.Scan(&f.ID, &f.Name, &f.Phone, &f.Email, &f.ScentConsent, &consent, &f.Active, &f.Notes, &created, &updated); err != nil {
+		return nil, err
+	}
+	if consent.Valid {
+		t, _ := time.Parse(common.DateLayout, consent.String)
+		f.ConsentDate = &t
+	}
+	f.CreatedAt, _ = time.Parse(time.RFC3339, created)
+	f.UpdatedAt, _ = time.Parse(time.RFC3339, updated)
+	return &f, nil
+}
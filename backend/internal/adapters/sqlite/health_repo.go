@@ -0,0 +1,138 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/tnosaj/sar-training/backend/internal/domain/common"
+	"github.com/tnosaj/sar-training/backend/internal/domain/health"
+	logx "github.com/tnosaj/sar-training/backend/internal/infra/log"
+)
+
+type HealthRepo struct{ db *sql.DB }
+
+func NewHealthRepo(db *sql.DB) *HealthRepo {
+	logx.Std.Trace("starting health repo")
+	return &HealthRepo{db: db}
+}
+
+const healthColumns = `id, dog_id, kind, date, end_date, due_date, title, vet, weight_kg, not_fit, notes, created_at, updated_at`
+
+func (r *HealthRepo) Create(ctx context.Context, e *health.Entry) error {
+	res, err := r.db.ExecContext(ctx, `INSERT INTO health_entries (dog_id, kind, date, end_date, due_date, title, vet, weight_kg, not_fit, notes, created_at, updated_at) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		e.DogID, e.Kind, e.Date.Format(common.DateLayout), formatDate(e.EndDate), formatDate(e.DueDate), e.Title, e.Vet, e.WeightKg,
+		boolToInt(e.NotFit), e.Notes, e.CreatedAt.Format(time.RFC3339), e.UpdatedAt.Format(time.RFC3339))
+	if err != nil {
+		return err
+	}
+	id, _ := res.LastInsertId()
+	e.ID = health.EntryID(id)
+	return nil
+}
+
+func (r *HealthRepo) Get(ctx context.Context, id health.EntryID) (*health.Entry, error) {
+	row := r.db.QueryRowContext(ctx, `SELECT `+healthColumns+` FROM health_entries WHERE id=?`, id)
+	e, err := scanHealthEntry(row)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, common.ErrNotFound
+	}
+	return e, err
+}
+
+func (r *HealthRepo) List(ctx context.Context, f health.Filter) ([]*health.Entry, error) {
+	query := `SELECT ` + healthColumns + ` FROM health_entries WHERE 1=1`
+	args := []any{}
+	if f.DogID != nil {
+		query += ` AND dog_id = ?`
+		args = append(args, *f.DogID)
+	}
+	if f.Kind != nil {
+		query += ` AND kind = ?`
+		args = append(args, *f.Kind)
+	}
+	if f.From != nil {
+		query += ` AND COALESCE(end_date, date) >= ?`
+		args = append(args, f.From.Format(common.DateLayout))
+	}
+	if f.To != nil {
+		query += ` AND date <= ?`
+		args = append(args, f.To.Format(common.DateLayout))
+	}
+	if f.DueBefore != nil {
+		query += ` AND due_date IS NOT NULL AND due_date <= ?`
+		args = append(args, f.DueBefore.Format(common.DateLayout))
+	}
+	query += ` ORDER BY date DESC, id DESC`
+	return r.query(ctx, query, args...)
+}
+
+func (r *HealthRepo) NotFit(ctx context.Context, dogID int64, t time.Time) ([]*health.Entry, error) {
+	day := t.UTC().Format(common.DateLayout)
+	return r.query(ctx, `SELECT `+healthColumns+` FROM health_entries
+		WHERE dog_id = ? AND not_fit = 1 AND date <= ? AND (end_date IS NULL OR end_date >= ?)
+		ORDER BY date DESC, id DESC`, dogID, day, day)
+}
+
+func (r *HealthRepo) Update(ctx context.Context, e *health.Entry) error {
+	res, err := r.db.ExecContext(ctx, `UPDATE health_entries SET kind=?, date=?, end_date=?, due_date=?, title=?, vet=?, weight_kg=?, not_fit=?, notes=?, updated_at=? WHERE id=?`,
+		e.Kind, e.Date.Format(common.DateLayout), formatDate(e.EndDate), formatDate(e.DueDate), e.Title, e.Vet, e.WeightKg,
+		boolToInt(e.NotFit), e.Notes, e.UpdatedAt.Format(time.RFC3339), e.ID)
+	if err != nil {
+		return err
+	}
+	if rows, _ := res.RowsAffected(); rows == 0 {
+		return common.ErrNotFound
+	}
+	return nil
+}
+
+func (r *HealthRepo) Delete(ctx context.Context, id health.EntryID) error {
+	res, err := r.db.ExecContext(ctx, `DELETE FROM health_entries WHERE id=?`, id)
+	if err != nil {
+		return err
+	}
+	if rows, _ := res.RowsAffected(); rows == 0 {
+		return common.ErrNotFound
+	}
+	return nil
+}
+
+func (r *HealthRepo) query(ctx context.Context, query string, args ...any) ([]*health.Entry, error) {
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var out []*health.Entry
+	for rows.Next() {
+		e, err := scanHealthEntry(rows)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, e)
+	}
+	return out, rows.Err()
+}
+
+func scanHealthEntry(row rowScanner) (*health.Entry, error) {
+	var e health.Entry
+	var date, created, updated string
+	var end, due sql.NullString
+	if err := row.Scan(&e.ID, &e.DogID, &e.Kind, &date, &end, &due, &e.Title, &e.Vet, &e.WeightKg, &e.NotFit, &e.Notes, &created, &updated); err != nil {
+		return nil, err
+	}
+	e.Date, _ = time.Parse(common.DateLayout, date)
+	if end.Valid {
+		t, _ := time.Parse(common.DateLayout, end.String)
+		e.EndDate = &t
+	}
+	if due.Valid {
+		t, _ := time.Parse(common.DateLayout, due.String)
+		e.DueDate = &t
+	}
+	e.CreatedAt, _ = time.Parse(time.RFC3339, created)
+	e.UpdatedAt, _ = time.Parse(time.RFC3339, updated)
+	return &e, nil
+}
@@ -0,0 +1,226 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/tnosaj/sar-training/backend/internal/domain/common"
+	"github.com/tnosaj/sar-training/backend/internal/domain/location"
+	logx "github.com/tnosaj/sar-training/backend/internal/infra/log"
+)
+
+type LocationsRepo struct{ db *sql.DB }
+
+func NewLocationsRepo(db *sql.DB) *LocationsRepo {
+	logx.Std.Trace("starting locations repo")
+	return &LocationsRepo{db: db}
+}
+
+const locationColumns = `id, name, latitude, longitude, terrain, access_notes, boundary, created_at, updated_at`
+
+func (r *LocationsRepo) Create(ctx context.Context, l *location.Location) error {
+	lat, lon := centerArgs(l.Center)
+	boundary, err := encodeBoundary(l.Boundary)
+	if err != nil {
+		return err
+	}
+	res, err := r.db.ExecContext(ctx, `INSERT INTO locations (name, latitude, longitude, terrain, access_notes, boundary, created_at, updated_at) VALUES (?, ?, ?, ?, ?, ?, ?, ?)`,
+		l.Name, lat, lon, l.Terrain, l.AccessNotes, boundary, l.CreatedAt.Format(time.RFC3339), l.UpdatedAt.Format(time.RFC3339))
+	if err != nil {
+		return mapConstraint(err)
+	}
+	id, _ := res.LastInsertId()
+	l.ID = location.LocationID(id)
+	return nil
+}
+
+func (r *LocationsRepo) Get(ctx context.Context, id location.LocationID) (*location.Location, error) {
+	row := r.db.QueryRowContext(ctx, `SELECT `+locationColumns+` FROM locations WHERE id=?`, id)
+	l, err := scanLocation(row)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, common.ErrNotFound
+	}
+	return l, err
+}
+
+func (r *LocationsRepo) FindByName(ctx context.Context, name string) (*location.Location, error) {
+	row := r.db.QueryRowContext(ctx, `SELECT `+locationColumns+` FROM locations WHERE name=?`, strings.TrimSpace(name))
+	l, err := scanLocation(row)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, common.ErrNotFound
+	}
+	return l, err
+}
+
+func (r *LocationsRepo) List(ctx context.Context) ([]*location.Location, error) {
+	rows, err := r.db.QueryContext(ctx, `SELECT `+locationColumns+` FROM locations ORDER BY name`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var out []*location.Location
+	for rows.Next() {
+		l, err := scanLocation(rows)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, l)
+	}
+	return out, rows.Err()
+}
+
+// Update also renames the free-text location of the sessions held there.
+func (r *LocationsRepo) Update(ctx context.Context, l *location.Location) error {
+	lat, lon := centerArgs(l.Center)
+	boundary, err := encodeBoundary(l.Boundary)
+	if err != nil {
+		return err
+	}
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	res, err := tx.ExecContext(ctx, `UPDATE locations SET name=?, latitude=?, longitude=?, terrain=?, access_notes=?, boundary=?, updated_at=? WHERE id=?`,
+		l.Name, lat, lon, l.Terrain, l.AccessNotes, boundary, l.UpdatedAt.Format(time.RFC3339), l.ID)
+	if err != nil {
+		return mapConstraint(err)
+	}
+	if rows, _ := res.RowsAffected(); rows == 0 {
+		return common.ErrNotFound
+	}
+	if _, err := tx.ExecContext(ctx, `UPDATE sessions SET location=? WHERE location_id=?`, l.Name, l.ID); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+func (r *LocationsRepo) Delete(ctx context.Context, id location.LocationID) error {
+	res, err := r.db.ExecContext(ctx, `DELETE FROM locations WHERE id=?`, id)
+	if err != nil {
+		return err
+	}
+	if rows, _ := res.RowsAffected(); rows == 0 {
+		return common.ErrNotFound
+	}
+	return nil
+}
+
+func (r *LocationsRepo) Merge(ctx context.Context, target location.LocationID, sources []location.LocationID) (int64, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+	var name string
+	if err := tx.QueryRowContext(ctx, `SELECT name FROM locations WHERE id=?`, target).Scan(&name); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return 0, common.ErrNotFound
+		}
+		return 0, err
+	}
+	placeholders := strings.TrimSuffix(strings.Repeat("?,", len(sources)), ",")
+	args := []any{}
+	for _, s := range sources {
+		args = append(args, s)
+	}
+	var found int
+	if err := tx.QueryRowContext(ctx, `SELECT COUNT(*) FROM locations WHERE id IN (`+placeholders+`)`, args...).Scan(&found); err != nil {
+		return 0, err
+	}
+	if found != len(sources) {
+		return 0, common.ErrNotFound
+	}
+	res, err := tx.ExecContext(ctx, `UPDATE sessions SET location_id=?, location=? WHERE location_id IN (`+placeholders+`)`,
+		append([]any{target, name}, args...)...)
+	if err != nil {
+		return 0, err
+	}
+	moved, _ := res.RowsAffected()
+	if _, err := tx.ExecContext(ctx, `DELETE FROM locations WHERE id IN (`+placeholders+`)`, args...); err != nil {
+		return 0, err
+	}
+	return moved, tx.Commit()
+}
+
+func (r *LocationsRepo) Stats(ctx context.Context, id location.LocationID) (*location.Stats, error) {
+	var st location.Stats
+	err := r.db.QueryRowContext(ctx, `SELECT COUNT(*), MIN(started_at), MAX(started_at) FROM sessions WHERE location_id=?`, id).
+		Scan(&st.Sessions, &st.FirstSession, &st.LastSession)
+	if err != nil {
+		return nil, err
+	}
+	err = r.db.QueryRowContext(ctx, `SELECT COUNT(*),
+		COALESCE(SUM(r.outcome = 'success'), 0), COALESCE(SUM(r.outcome = 'partial'), 0), COALESCE(SUM(r.outcome = 'fail'), 0),
+		COUNT(DISTINCT r.dog_id), AVG(r.score)
+		FROM rounds r JOIN sessions s ON s.id = r.session_id WHERE s.location_id=?`, id).
+		Scan(&st.Rounds, &st.Successes, &st.Partials, &st.Fails, &st.Dogs, &st.MeanScore)
+	if err != nil {
+		return nil, err
+	}
+	return &st, nil
+}
+
+func scanLocation(row rowScanner) (*location.Location, error) {
+	var l location.Location
+	var lat, lon sql.NullFloat64
+	var boundary sql.NullString
+	var created, updated string
+	if err := row.Scan(&l.ID, &l.Name, &lat, &lon, &l.Terrain, &l.AccessNotes, &boundary, &created, &updated); err != nil {
+		return nil, err
+	}
+	if lat.Valid && lon.Valid {
+		l.Center = &location.Point{Lat: lat.Float64, Lon: lon.Float64}
+	}
+	ring, err := decodeBoundary(boundary)
+	if err != nil {
+		return nil, err
+	}
+	l.Boundary = ring
+	l.CreatedAt, _ = time.Parse(time.RFC3339, created)
+	l.UpdatedAt, _ = time.Parse(time.RFC3339, updated)
+	return &l, nil
+}
+
+func centerArgs(p *location.Point) (any, any) {
+	if p == nil {
+		return nil, nil
+	}
+	return p.Lat, p.Lon
+}
+
+// encodeBoundary stores the ring as GeoJSON-ordered [lon, lat] pairs.
+func encodeBoundary(ring []location.Point) (*string, error) {
+	if len(ring) == 0 {
+		return nil, nil
+	}
+	coords := make([][2]float64, 0, len(ring))
+	for _, p := range ring {
+		coords = append(coords, [2]float64{p.Lon, p.Lat})
+	}
+	b, err := json.Marshal(coords)
+	if err != nil {
+		return nil, err
+	}
+	v := string(b)
+	return &v, nil
+}
+
+func decodeBoundary(v sql.NullString) ([]location.Point, error) {
+	if !v.Valid || v.String == "" {
+		return nil, nil
+	}
+	var coords [][2]float64
+	if err := json.Unmarshal([]byte(v.String), &coords); err != nil {
+		return nil, err
+	}
+	ring := make([]location.Point, 0, len(coords))
+	for _, c := range coords {
+		ring = append(ring, location.Point{Lon: c[0], Lat: c[1]})
+	}
+	return ring, nil
+}
@@ -0,0 +1,150 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"sort"
+	"time"
+
+	"github.com/tnosaj/sar-training/backend/internal/domain/common"
+	"github.com/tnosaj/sar-training/backend/internal/domain/mastery"
+	logx "github.com/tnosaj/sar-training/backend/internal/infra/log"
+)
+
+type MasteryRepo struct{ db *sql.DB }
+
+func NewMasteryRepo(db *sql.DB) *MasteryRepo {
+	logx.Std.Trace("starting mastery repo")
+	return &MasteryRepo{db: db}
+}
+
+func (r *MasteryRepo) Prerequisites(ctx context.Context) ([]mastery.Prerequisite, error) {
+	return prerequisites(ctx, r.db)
+}
+
+func prerequisites(ctx context.Context, q rowsQueryer) ([]mastery.Prerequisite, error) {
+	rows, err := q.QueryContext(ctx, `SELECT behavior_id, requires_id FROM behavior_prerequisites ORDER BY behavior_id, requires_id`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var out []mastery.Prerequisite
+	for rows.Next() {
+		var p mastery.Prerequisite
+		if err := rows.Scan(&p.BehaviorID, &p.RequiresID); err != nil {
+			return nil, err
+		}
+		out = append(out, p)
+	}
+	return out, rows.Err()
+}
+
+// AddPrerequisite checks for cycles against the stored edges and inserts p
+// in one transaction, so concurrent additions cannot close a cycle.
+func (r *MasteryRepo) AddPrerequisite(ctx context.Context, p mastery.Prerequisite) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	edges, err := prerequisites(ctx, tx)
+	if err != nil {
+		return err
+	}
+	if mastery.CreatesCycle(edges, p) {
+		return common.ErrConflict
+	}
+	if _, err := tx.ExecContext(ctx, `INSERT OR IGNORE INTO behavior_prerequisites (behavior_id, requires_id) VALUES (?, ?)`, p.BehaviorID, p.RequiresID); err != nil {
+		if isForeignKeyViolation(err) {
+			return common.ErrValidation
+		}
+		return err
+	}
+	return tx.Commit()
+}
+
+func (r *MasteryRepo) RemovePrerequisite(ctx context.Context, p mastery.Prerequisite) error {
+	res, err := r.db.ExecContext(ctx, `DELETE FROM behavior_prerequisites WHERE behavior_id=? AND requires_id=?`, p.BehaviorID, p.RequiresID)
+	if err != nil {
+		return err
+	}
+	if rows, _ := res.RowsAffected(); rows == 0 {
+		return common.ErrNotFound
+	}
+	return nil
+}
+
+func (r *MasteryRepo) Criteria(ctx context.Context) (map[int64]mastery.Criteria, error) {
+	rows, err := r.db.QueryContext(ctx, `SELECT behavior_id, min_rounds, min_success_rate, min_score, window_days FROM behavior_mastery`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	out := map[int64]mastery.Criteria{}
+	for rows.Next() {
+		var id int64
+		var c mastery.Criteria
+		if err := rows.Scan(&id, &c.MinRounds, &c.MinSuccessRate, &c.MinScore, &c.WindowDays); err != nil {
+			return nil, err
+		}
+		out[id] = c
+	}
+	return out, rows.Err()
+}
+
+func (r *MasteryRepo) SetCriteria(ctx context.Context, behaviorID int64, c mastery.Criteria) error {
+	_, err := r.db.ExecContext(ctx, `INSERT INTO behavior_mastery (behavior_id, min_rounds, min_success_rate, min_score, window_days) VALUES (?, ?, ?, ?, ?)
+		ON CONFLICT(behavior_id) DO UPDATE SET min_rounds=excluded.min_rounds, min_success_rate=excluded.min_success_rate,
+			min_score=excluded.min_score, window_days=excluded.window_days`,
+		behaviorID, c.MinRounds, c.MinSuccessRate, c.MinScore, c.WindowDays)
+	if err != nil && isForeignKeyViolation(err) {
+		return common.ErrNotFound
+	}
+	return err
+}
+
+func (r *MasteryRepo) DeleteCriteria(ctx context.Context, behaviorID int64) error {
+	res, err := r.db.ExecContext(ctx, `DELETE FROM behavior_mastery WHERE behavior_id=?`, behaviorID)
+	if err != nil {
+		return err
+	}
+	if rows, _ := res.RowsAffected(); rows == 0 {
+		return common.ErrNotFound
+	}
+	return nil
+}
+
+// Rounds returns the dog's rounds started at or after since, oldest first.
+// Start times are compared after parsing, since session times are free
+// text; rounds whose time cannot be parsed are skipped.
+func (r *MasteryRepo) Rounds(ctx context.Context, dogID int64, since time.Time) ([]mastery.RoundResult, error) {
+	rows, err := r.db.QueryContext(ctx, `SELECT r.id, r.planned_behavior_id, r.outcome, r.score, COALESCE(r.started_at, s.started_at)
+		FROM rounds r JOIN sessions s ON s.id = r.session_id
+		WHERE r.dog_id = ?`, dogID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var out []mastery.RoundResult
+	for rows.Next() {
+		var rr mastery.RoundResult
+		var id int64
+		var at string
+		if err := rows.Scan(&id, &rr.BehaviorID, &rr.Outcome, &rr.Score, &at); err != nil {
+			return nil, err
+		}
+		if rr.At, err = parseRoundTime(at); err != nil {
+			logx.Std.Warnf("skipping round %d for mastery: unreadable start time %q", id, at)
+			continue
+		}
+		if rr.At.Before(since) {
+			continue
+		}
+		out = append(out, rr)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	sort.SliceStable(out, func(i, j int) bool { return out[i].At.Before(out[j].At) })
+	return out, nil
+}
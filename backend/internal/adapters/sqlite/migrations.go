@@ -4,6 +4,7 @@ import (
 	"embed"
 	"fmt"
 	"io/fs"
+	"time"
 
 	logx "github.com/tnosaj/sar-training/backend/internal/infra/log"
 )
@@ -11,8 +12,13 @@ import (
 //go:embed migrations/*.sql
 var migrationFS embed.FS
 
+// ApplyMigrations runs every embedded migration that is not yet recorded in
+// schema_migrations, in file name order, each in its own transaction.
 func ApplyMigrations(db *DB) error {
 	logx.Std.Trace("starting sqlite migrations")
+	if _, err := db.Exec(`CREATE TABLE IF NOT EXISTS schema_migrations (name TEXT PRIMARY KEY, applied_at TEXT NOT NULL)`); err != nil {
+		return err
+	}
 	entries, err := fs.ReadDir(migrationFS, "migrations")
 	if err != nil {
 		return err
@@ -21,13 +27,33 @@ func ApplyMigrations(db *DB) error {
 		if e.IsDir() {
 			continue
 		}
+		var applied int
+		if err := db.QueryRow(`SELECT COUNT(*) FROM schema_migrations WHERE name=?`, e.Name()).Scan(&applied); err != nil {
+			return err
+		}
+		if applied > 0 {
+			continue
+		}
 		b, err := migrationFS.ReadFile("migrations/" + e.Name())
 		if err != nil {
 			return err
 		}
-		if _, err := db.Exec(string(b)); err != nil {
+		tx, err := db.Begin()
+		if err != nil {
+			return err
+		}
+		if _, err := tx.Exec(string(b)); err != nil {
+			tx.Rollback()
+			return fmt.Errorf("migration %s: %w", e.Name(), err)
+		}
+		if _, err := tx.Exec(`INSERT INTO schema_migrations (name, applied_at) VALUES (?, ?)`, e.Name(), time.Now().UTC().Format(time.RFC3339)); err != nil {
+			tx.Rollback()
+			return fmt.Errorf("migration %s: %w", e.Name(), err)
+		}
+		if err := tx.Commit(); err != nil {
 			return fmt.Errorf("migration %s: %w", e.Name(), err)
 		}
+		logx.Std.Infof("applied migration %s", e.Name())
 	}
 	return nil
 }
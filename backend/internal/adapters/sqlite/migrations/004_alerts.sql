@@ -0,0 +1,23 @@
+CREATE TABLE IF NOT EXISTS alerts (
+  id INTEGER PRIMARY KEY AUTOINCREMENT,
+  dog_id INTEGER NOT NULL REFERENCES dogs(id) ON DELETE CASCADE,
+  behavior_id INTEGER NOT NULL REFERENCES behaviors(id) ON DELETE CASCADE,
+  kind TEXT NOT NULL CHECK (kind IN ('regression','plateau')),
+  metric TEXT NOT NULL CHECK (metric IN ('success_rate','score')),
+  status TEXT NOT NULL DEFAULT 'open' CHECK (status IN ('open','acknowledged','dismissed')),
+  baseline REAL,
+  recent REAL NOT NULL,
+  slope REAL NOT NULL,
+  p_value REAL,
+  samples INTEGER NOT NULL,
+  message TEXT NOT NULL,
+  window_start TEXT NOT NULL,
+  window_end TEXT NOT NULL,
+  created_at TEXT NOT NULL,
+  updated_at TEXT NOT NULL,
+  acknowledged_by INTEGER REFERENCES users(id) ON DELETE SET NULL,
+  acknowledged_at TEXT
+);
+
+CREATE INDEX IF NOT EXISTS idx_alerts_dog_behavior ON alerts(dog_id, behavior_id, kind);
+CREATE INDEX IF NOT EXISTS idx_alerts_status ON alerts(status);
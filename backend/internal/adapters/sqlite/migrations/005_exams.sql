@@ -0,0 +1,23 @@
+CREATE TABLE IF NOT EXISTS exams (
+  id INTEGER PRIMARY KEY AUTOINCREMENT,
+  name TEXT NOT NULL UNIQUE,
+  description TEXT,
+  window_days INTEGER NOT NULL DEFAULT 90 CHECK (window_days > 0),
+  created_at TEXT NOT NULL,
+  updated_at TEXT NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS exam_requirements (
+  id INTEGER PRIMARY KEY AUTOINCREMENT,
+  exam_id INTEGER NOT NULL REFERENCES exams(id) ON DELETE CASCADE,
+  behavior_id INTEGER REFERENCES behaviors(id) ON DELETE CASCADE,
+  skill_id INTEGER REFERENCES skills(id) ON DELETE CASCADE,
+  min_success_rate REAL NOT NULL DEFAULT 0 CHECK (min_success_rate BETWEEN 0 AND 1),
+  min_score REAL CHECK (min_score BETWEEN 0 AND 10),
+  min_rounds INTEGER NOT NULL DEFAULT 1 CHECK (min_rounds > 0),
+  window_days INTEGER CHECK (window_days > 0),
+  weight REAL NOT NULL DEFAULT 1 CHECK (weight > 0),
+  CHECK ((behavior_id IS NULL) <> (skill_id IS NULL))
+);
+
+CREATE INDEX IF NOT EXISTS idx_exam_requirements_exam ON exam_requirements(exam_id);
@@ -0,0 +1,33 @@
+CREATE TABLE IF NOT EXISTS qualifications (
+  id INTEGER PRIMARY KEY AUTOINCREMENT,
+  dog_id INTEGER REFERENCES dogs(id) ON DELETE CASCADE,
+  handler_id INTEGER REFERENCES users(id) ON DELETE CASCADE,
+  exam_type TEXT NOT NULL,
+  exam_id INTEGER REFERENCES exams(id) ON DELETE SET NULL,
+  exam_date TEXT NOT NULL,
+  examiner TEXT,
+  result TEXT NOT NULL CHECK (result IN ('passed','failed')),
+  certificate_number TEXT,
+  valid_until TEXT,
+  remind_days_before INTEGER NOT NULL DEFAULT 60 CHECK (remind_days_before >= 0),
+  notes TEXT,
+  created_at TEXT NOT NULL,
+  updated_at TEXT NOT NULL,
+  CHECK (dog_id IS NOT NULL OR handler_id IS NOT NULL)
+);
+
+CREATE INDEX IF NOT EXISTS idx_qualifications_dog ON qualifications(dog_id);
+CREATE INDEX IF NOT EXISTS idx_qualifications_handler ON qualifications(handler_id);
+CREATE INDEX IF NOT EXISTS idx_qualifications_valid_until ON qualifications(valid_until);
+
+CREATE TABLE IF NOT EXISTS qualification_attachments (
+  id INTEGER PRIMARY KEY AUTOINCREMENT,
+  qualification_id INTEGER NOT NULL REFERENCES qualifications(id) ON DELETE CASCADE,
+  filename TEXT NOT NULL,
+  content_type TEXT NOT NULL,
+  size INTEGER NOT NULL,
+  data BLOB NOT NULL,
+  uploaded_at TEXT NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_qualification_attachments_q ON qualification_attachments(qualification_id);
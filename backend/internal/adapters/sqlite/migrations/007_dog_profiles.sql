@@ -0,0 +1,19 @@
+ALTER TABLE dogs ADD COLUMN breed TEXT;
+ALTER TABLE dogs ADD COLUMN sex TEXT CHECK (sex IN ('male','female'));
+ALTER TABLE dogs ADD COLUMN microchip TEXT;
+ALTER TABLE dogs ADD COLUMN registration_number TEXT;
+ALTER TABLE dogs ADD COLUMN owner TEXT;
+ALTER TABLE dogs ADD COLUMN handler_id INTEGER REFERENCES users(id) ON DELETE SET NULL;
+ALTER TABLE dogs ADD COLUMN status TEXT NOT NULL DEFAULT 'in_training' CHECK (status IN ('in_training','operational','retired'));
+-- free attributes as a JSON object of strings
+ALTER TABLE dogs ADD COLUMN attributes TEXT;
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_dogs_microchip ON dogs(microchip) WHERE microchip IS NOT NULL;
+CREATE INDEX IF NOT EXISTS idx_dogs_status ON dogs(status);
+
+CREATE TABLE IF NOT EXISTS dog_photos (
+  dog_id INTEGER PRIMARY KEY REFERENCES dogs(id) ON DELETE CASCADE,
+  content_type TEXT NOT NULL,
+  data BLOB NOT NULL,
+  updated_at TEXT NOT NULL
+);
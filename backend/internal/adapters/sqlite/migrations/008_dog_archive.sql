@@ -0,0 +1,3 @@
+ALTER TABLE dogs ADD COLUMN archived_at TEXT;
+
+CREATE INDEX IF NOT EXISTS idx_dogs_archived ON dogs(archived_at);
@@ -0,0 +1,19 @@
+CREATE TABLE IF NOT EXISTS dog_handlers (
+  id INTEGER PRIMARY KEY AUTOINCREMENT,
+  dog_id INTEGER NOT NULL REFERENCES dogs(id) ON DELETE CASCADE,
+  user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+  role TEXT NOT NULL CHECK (role IN ('primary','secondary')),
+  start_date TEXT NOT NULL,
+  end_date TEXT,
+  notes TEXT,
+  created_at TEXT NOT NULL,
+  updated_at TEXT NOT NULL,
+  CHECK (end_date IS NULL OR end_date >= start_date)
+);
+
+CREATE INDEX IF NOT EXISTS idx_dog_handlers_dog ON dog_handlers(dog_id);
+CREATE INDEX IF NOT EXISTS idx_dog_handlers_user ON dog_handlers(user_id);
+
+ALTER TABLE rounds ADD COLUMN handler_id INTEGER REFERENCES users(id) ON DELETE SET NULL;
+
+CREATE INDEX IF NOT EXISTS idx_rounds_handler ON rounds(handler_id);
@@ -0,0 +1,19 @@
+CREATE TABLE IF NOT EXISTS health_entries (
+  id INTEGER PRIMARY KEY AUTOINCREMENT,
+  dog_id INTEGER NOT NULL REFERENCES dogs(id) ON DELETE CASCADE,
+  kind TEXT NOT NULL CHECK (kind IN ('vet_visit','vaccination','injury','medication','rest','heat','weight')),
+  date TEXT NOT NULL,
+  end_date TEXT,
+  due_date TEXT,
+  title TEXT,
+  vet TEXT,
+  weight_kg REAL,
+  not_fit INTEGER NOT NULL DEFAULT 0,
+  notes TEXT,
+  created_at TEXT NOT NULL,
+  updated_at TEXT NOT NULL,
+  CHECK (end_date IS NULL OR end_date >= date)
+);
+
+CREATE INDEX IF NOT EXISTS idx_health_entries_dog ON health_entries(dog_id, date);
+CREATE INDEX IF NOT EXISTS idx_health_entries_due ON health_entries(due_date) WHERE due_date IS NOT NULL;
@@ -0,0 +1,14 @@
+ALTER TABLE sessions ADD COLUMN temperature_c REAL;
+ALTER TABLE sessions ADD COLUMN humidity_pct REAL CHECK (humidity_pct BETWEEN 0 AND 100);
+ALTER TABLE sessions ADD COLUMN wind_speed_kmh REAL CHECK (wind_speed_kmh >= 0);
+ALTER TABLE sessions ADD COLUMN wind_direction_deg INTEGER CHECK (wind_direction_deg BETWEEN 0 AND 359);
+ALTER TABLE sessions ADD COLUMN precipitation TEXT CHECK (precipitation IN ('none','light','moderate','heavy'));
+ALTER TABLE sessions ADD COLUMN terrain TEXT;
+
+-- per-round values override the session's when conditions change during it
+ALTER TABLE rounds ADD COLUMN temperature_c REAL;
+ALTER TABLE rounds ADD COLUMN humidity_pct REAL CHECK (humidity_pct BETWEEN 0 AND 100);
+ALTER TABLE rounds ADD COLUMN wind_speed_kmh REAL CHECK (wind_speed_kmh >= 0);
+ALTER TABLE rounds ADD COLUMN wind_direction_deg INTEGER CHECK (wind_direction_deg BETWEEN 0 AND 359);
+ALTER TABLE rounds ADD COLUMN precipitation TEXT CHECK (precipitation IN ('none','light','moderate','heavy'));
+ALTER TABLE rounds ADD COLUMN terrain TEXT;
@@ -0,0 +1,16 @@
+CREATE TABLE IF NOT EXISTS weather_observations (
+  id INTEGER PRIMARY KEY AUTOINCREMENT,
+  location TEXT NOT NULL COLLATE NOCASE,
+  station TEXT,
+  observed_at TEXT NOT NULL,
+  temperature_c REAL,
+  humidity_pct REAL,
+  wind_speed_kmh REAL,
+  wind_direction_deg INTEGER,
+  precipitation TEXT CHECK (precipitation IN ('none','light','moderate','heavy')),
+  source TEXT NOT NULL,
+  imported_at TEXT NOT NULL,
+  UNIQUE (location, observed_at)
+);
+
+CREATE INDEX IF NOT EXISTS idx_weather_observations_time ON weather_observations(location, observed_at);
@@ -0,0 +1,23 @@
+CREATE TABLE IF NOT EXISTS locations (
+  id INTEGER PRIMARY KEY AUTOINCREMENT,
+  name TEXT NOT NULL COLLATE NOCASE UNIQUE,
+  latitude REAL CHECK (latitude BETWEEN -90 AND 90),
+  longitude REAL CHECK (longitude BETWEEN -180 AND 180),
+  terrain TEXT,
+  access_notes TEXT,
+  boundary TEXT,
+  created_at TEXT NOT NULL,
+  updated_at TEXT NOT NULL
+);
+
+ALTER TABLE sessions ADD COLUMN location_id INTEGER REFERENCES locations(id) ON DELETE SET NULL;
+
+CREATE INDEX IF NOT EXISTS idx_sessions_location ON sessions(location_id);
+
+-- seed the catalogue from the free-text locations recorded so far
+INSERT OR IGNORE INTO locations (name, created_at, updated_at)
+  SELECT TRIM(location), strftime('%Y-%m-%dT%H:%M:%SZ', 'now'), strftime('%Y-%m-%dT%H:%M:%SZ', 'now')
+  FROM sessions WHERE TRIM(COALESCE(location, '')) <> '';
+
+UPDATE sessions SET location_id = (SELECT l.id FROM locations l WHERE l.name = TRIM(sessions.location))
+  WHERE TRIM(COALESCE(location, '')) <> '';
@@ -0,0 +1,12 @@
+CREATE TABLE IF NOT EXISTS tracks (
+  id INTEGER PRIMARY KEY AUTOINCREMENT,
+  session_id INTEGER NOT NULL REFERENCES sessions(id) ON DELETE CASCADE,
+  round_id INTEGER REFERENCES rounds(id) ON DELETE CASCADE,
+  kind TEXT NOT NULL CHECK (kind IN ('handler','dog')),
+  name TEXT,
+  segments TEXT NOT NULL,
+  created_at TEXT NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_tracks_session ON tracks(session_id);
+CREATE INDEX IF NOT EXISTS idx_tracks_round ON tracks(round_id);
@@ -0,0 +1,19 @@
+CREATE TABLE IF NOT EXISTS round_area_searches (
+  round_id INTEGER PRIMARY KEY REFERENCES rounds(id) ON DELETE CASCADE,
+  sector_name TEXT,
+  sector TEXT,
+  false_alerts INTEGER NOT NULL DEFAULT 0 CHECK (false_alerts >= 0)
+);
+
+CREATE TABLE IF NOT EXISTS hidden_persons (
+  id INTEGER PRIMARY KEY AUTOINCREMENT,
+  round_id INTEGER NOT NULL REFERENCES round_area_searches(round_id) ON DELETE CASCADE,
+  label TEXT,
+  latitude REAL NOT NULL CHECK (latitude BETWEEN -90 AND 90),
+  longitude REAL NOT NULL CHECK (longitude BETWEEN -180 AND 180),
+  found INTEGER NOT NULL DEFAULT 0,
+  time_to_find_s INTEGER CHECK (time_to_find_s >= 0),
+  notes TEXT
+);
+
+CREATE INDEX IF NOT EXISTS idx_hidden_persons_round ON hidden_persons(round_id);
@@ -0,0 +1,10 @@
+CREATE TABLE IF NOT EXISTS round_trailing (
+  round_id INTEGER PRIMARY KEY REFERENCES rounds(id) ON DELETE CASCADE,
+  trail_age_min INTEGER NOT NULL CHECK (trail_age_min >= 0),
+  trail_length_m REAL NOT NULL CHECK (trail_length_m > 0),
+  scent_article TEXT CHECK (scent_article IN ('clothing','gauze','touched_object','footwear','other')),
+  start_type TEXT CHECK (start_type IN ('directional','non_directional','scent_pool','vehicle')),
+  surfaces TEXT,
+  contamination TEXT CHECK (contamination IN ('none','low','medium','high')),
+  reached_subject INTEGER NOT NULL DEFAULT 0
+);
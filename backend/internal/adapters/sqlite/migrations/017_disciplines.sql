@@ -0,0 +1,21 @@
+CREATE TABLE IF NOT EXISTS disciplines (
+  id INTEGER PRIMARY KEY AUTOINCREMENT,
+  name TEXT NOT NULL COLLATE NOCASE UNIQUE,
+  description TEXT,
+  round_extensions TEXT NOT NULL DEFAULT '',
+  created_at TEXT NOT NULL,
+  updated_at TEXT NOT NULL
+);
+
+INSERT OR IGNORE INTO disciplines (name, description, round_extensions, created_at, updated_at) VALUES
+  ('Area search', 'Searching an assigned sector for hidden persons', 'area_search', strftime('%Y-%m-%dT%H:%M:%SZ', 'now'), strftime('%Y-%m-%dT%H:%M:%SZ', 'now')),
+  ('Mantrailing', 'Following the individual scent of a missing person', 'trailing', strftime('%Y-%m-%dT%H:%M:%SZ', 'now'), strftime('%Y-%m-%dT%H:%M:%SZ', 'now')),
+  ('Rubble', 'Searching collapsed structures for buried persons', 'area_search', strftime('%Y-%m-%dT%H:%M:%SZ', 'now'), strftime('%Y-%m-%dT%H:%M:%SZ', 'now')),
+  ('Water', 'Locating persons under water from shore or boat', 'area_search', strftime('%Y-%m-%dT%H:%M:%SZ', 'now'), strftime('%Y-%m-%dT%H:%M:%SZ', 'now')),
+  ('Avalanche', 'Locating persons buried in snow', 'area_search', strftime('%Y-%m-%dT%H:%M:%SZ', 'now'), strftime('%Y-%m-%dT%H:%M:%SZ', 'now'));
+
+ALTER TABLE skills ADD COLUMN discipline_id INTEGER REFERENCES disciplines(id) ON DELETE SET NULL;
+ALTER TABLE qualifications ADD COLUMN discipline_id INTEGER REFERENCES disciplines(id) ON DELETE SET NULL;
+
+CREATE INDEX IF NOT EXISTS idx_skills_discipline ON skills(discipline_id);
+CREATE INDEX IF NOT EXISTS idx_qualifications_discipline ON qualifications(discipline_id);
@@ -0,0 +1,39 @@
+CREATE TABLE IF NOT EXISTS figurants (
+  id INTEGER PRIMARY KEY AUTOINCREMENT,
+  name TEXT NOT NULL,
+  phone TEXT,
+  email TEXT,
+  scent_consent INTEGER NOT NULL DEFAULT 0,
+  consent_date TEXT,
+  active INTEGER NOT NULL DEFAULT 1,
+  notes TEXT,
+  created_at TEXT NOT NULL,
+  updated_at TEXT NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS figurant_availability (
+  id INTEGER PRIMARY KEY AUTOINCREMENT,
+  figurant_id INTEGER NOT NULL REFERENCES figurants(id) ON DELETE CASCADE,
+  start_date TEXT NOT NULL,
+  end_date TEXT NOT NULL,
+  notes TEXT,
+  CHECK (end_date >= start_date)
+);
+
+CREATE INDEX IF NOT EXISTS idx_figurant_availability_figurant ON figurant_availability(figurant_id, start_date);
+
+CREATE TABLE IF NOT EXISTS session_figurants (
+  session_id INTEGER NOT NULL REFERENCES sessions(id) ON DELETE CASCADE,
+  figurant_id INTEGER NOT NULL REFERENCES figurants(id) ON DELETE CASCADE,
+  PRIMARY KEY (session_id, figurant_id)
+);
+
+-- figurants who acted in a round are kept: deleting them is restricted so
+-- that their history with each dog survives; deactivate them instead
+CREATE TABLE IF NOT EXISTS round_figurants (
+  round_id INTEGER NOT NULL REFERENCES rounds(id) ON DELETE CASCADE,
+  figurant_id INTEGER NOT NULL REFERENCES figurants(id) ON DELETE RESTRICT,
+  PRIMARY KEY (round_id, figurant_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_round_figurants_figurant ON round_figurants(figurant_id);
@@ -0,0 +1,55 @@
+CREATE TABLE IF NOT EXISTS missions (
+  id INTEGER PRIMARY KEY AUTOINCREMENT,
+  started_at TEXT NOT NULL,
+  ended_at TEXT,
+  alerting_agency TEXT NOT NULL,
+  reference TEXT,
+  location TEXT,
+  location_id INTEGER REFERENCES locations(id) ON DELETE SET NULL,
+  search_area TEXT,
+  outcome TEXT NOT NULL CHECK (outcome IN ('find', 'no_find', 'cancelled')),
+  debrief TEXT,
+  created_at TEXT NOT NULL,
+  updated_at TEXT NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_missions_started ON missions(started_at);
+
+-- deployed dog–handler teams; dogs with missions are only removed by purge
+CREATE TABLE IF NOT EXISTS mission_teams (
+  id INTEGER PRIMARY KEY AUTOINCREMENT,
+  mission_id INTEGER NOT NULL REFERENCES missions(id) ON DELETE CASCADE,
+  dog_id INTEGER NOT NULL REFERENCES dogs(id) ON DELETE RESTRICT,
+  handler_id INTEGER REFERENCES users(id) ON DELETE SET NULL,
+  discipline_id INTEGER REFERENCES disciplines(id) ON DELETE SET NULL,
+  found INTEGER NOT NULL DEFAULT 0,
+  search_minutes INTEGER CHECK (search_minutes >= 0),
+  notes TEXT,
+  UNIQUE (mission_id, dog_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_mission_teams_dog ON mission_teams(dog_id);
+
+-- exam requirements may count deployments instead of training rounds; the
+-- table is rebuilt because its CHECK constraint cannot be altered in place
+CREATE TABLE exam_requirements_new (
+  id INTEGER PRIMARY KEY AUTOINCREMENT,
+  exam_id INTEGER NOT NULL REFERENCES exams(id) ON DELETE CASCADE,
+  behavior_id INTEGER REFERENCES behaviors(id) ON DELETE CASCADE,
+  skill_id INTEGER REFERENCES skills(id) ON DELETE CASCADE,
+  missions INTEGER NOT NULL DEFAULT 0,
+  min_success_rate REAL NOT NULL DEFAULT 0 CHECK (min_success_rate BETWEEN 0 AND 1),
+  min_score REAL CHECK (min_score BETWEEN 0 AND 10),
+  min_rounds INTEGER NOT NULL DEFAULT 1 CHECK (min_rounds > 0),
+  window_days INTEGER CHECK (window_days > 0),
+  weight REAL NOT NULL DEFAULT 1 CHECK (weight > 0),
+  CHECK ((behavior_id IS NOT NULL) + (skill_id IS NOT NULL) + (missions <> 0) = 1)
+);
+
+INSERT INTO exam_requirements_new (id, exam_id, behavior_id, skill_id, min_success_rate, min_score, min_rounds, window_days, weight)
+  SELECT id, exam_id, behavior_id, skill_id, min_success_rate, min_score, min_rounds, window_days, weight FROM exam_requirements;
+
+DROP TABLE exam_requirements;
+ALTER TABLE exam_requirements_new RENAME TO exam_requirements;
+
+CREATE INDEX IF NOT EXISTS idx_exam_requirements_exam ON exam_requirements(exam_id);
@@ -0,0 +1,7 @@
+ALTER TABLE dogs ADD COLUMN indication TEXT
+  CHECK (indication IN ('bark','bringsel','recall_refind','passive_sit'));
+
+ALTER TABLE rounds ADD COLUMN indication_result TEXT
+  CHECK (indication_result IN ('correct','late','false','missing'));
+
+CREATE INDEX IF NOT EXISTS idx_rounds_dog_indication ON rounds(dog_id, indication_result);
@@ -0,0 +1,24 @@
+-- round times were stored as free text. Keep every value that is not
+-- already UTC RFC 3339 in a legacy column, then convert only values that
+-- start with a date and a time of day; strftime would turn bare numbers
+-- into Julian days and bare times into dates in the year 2000. The rest
+-- are cleared and stay readable in the legacy columns.
+ALTER TABLE rounds ADD COLUMN started_at_legacy TEXT;
+ALTER TABLE rounds ADD COLUMN ended_at_legacy TEXT;
+
+UPDATE rounds SET started_at_legacy = started_at
+  WHERE started_at IS NOT NULL AND started_at IS NOT strftime('%Y-%m-%dT%H:%M:%SZ', started_at);
+UPDATE rounds SET ended_at_legacy = ended_at
+  WHERE ended_at IS NOT NULL AND ended_at IS NOT strftime('%Y-%m-%dT%H:%M:%SZ', ended_at);
+
+UPDATE rounds SET started_at = CASE
+    WHEN trim(started_at) GLOB '[0-9][0-9][0-9][0-9]-[0-9][0-9]-[0-9][0-9][T ][0-9][0-9]:[0-9][0-9]*'
+    THEN strftime('%Y-%m-%dT%H:%M:%SZ', trim(started_at)) END
+  WHERE started_at_legacy IS NOT NULL;
+UPDATE rounds SET ended_at = CASE
+    WHEN trim(ended_at) GLOB '[0-9][0-9][0-9][0-9]-[0-9][0-9]-[0-9][0-9][T ][0-9][0-9]:[0-9][0-9]*'
+    THEN strftime('%Y-%m-%dT%H:%M:%SZ', trim(ended_at)) END
+  WHERE ended_at_legacy IS NOT NULL;
+
+ALTER TABLE rounds ADD COLUMN time_to_first_alert_s INTEGER CHECK (time_to_first_alert_s >= 0);
+ALTER TABLE rounds ADD COLUMN time_to_indication_s INTEGER CHECK (time_to_indication_s >= 0);
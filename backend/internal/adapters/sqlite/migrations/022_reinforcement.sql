@@ -0,0 +1,8 @@
+ALTER TABLE rounds ADD COLUMN reward TEXT
+  CHECK (reward IN ('food','toy','play','praise','none'));
+ALTER TABLE rounds ADD COLUMN reinforcement_schedule TEXT
+  CHECK (reinforcement_schedule IN ('continuous','fixed_ratio','variable_ratio','jackpot'));
+-- comma-separated distraction kinds present on the round
+ALTER TABLE rounds ADD COLUMN distractions TEXT;
+ALTER TABLE rounds ADD COLUMN distraction_level TEXT
+  CHECK (distraction_level IN ('none','low','medium','high'));
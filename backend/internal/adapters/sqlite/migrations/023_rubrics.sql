@@ -0,0 +1,24 @@
+CREATE TABLE IF NOT EXISTS rubric_criteria (
+  id INTEGER PRIMARY KEY AUTOINCREMENT,
+  exercise_id INTEGER NOT NULL REFERENCES exercises(id) ON DELETE CASCADE,
+  name TEXT NOT NULL COLLATE NOCASE,
+  description TEXT,
+  weight REAL NOT NULL DEFAULT 1 CHECK (weight > 0),
+  max_points INTEGER NOT NULL DEFAULT 10 CHECK (max_points > 0),
+  position INTEGER NOT NULL DEFAULT 0,
+  UNIQUE (exercise_id, name)
+);
+
+-- scored criteria cannot be removed from a rubric
+CREATE TABLE IF NOT EXISTS round_scores (
+  round_id INTEGER NOT NULL REFERENCES rounds(id) ON DELETE CASCADE,
+  criterion_id INTEGER NOT NULL REFERENCES rubric_criteria(id) ON DELETE RESTRICT,
+  points INTEGER NOT NULL CHECK (points >= 0),
+  PRIMARY KEY (round_id, criterion_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_rubric_criteria_exercise ON rubric_criteria(exercise_id, position);
+CREATE INDEX IF NOT EXISTS idx_round_scores_criterion ON round_scores(criterion_id);
+
+-- weighted rubric total on the 0-10 scale; score holds it rounded
+ALTER TABLE rounds ADD COLUMN weighted_score REAL CHECK (weighted_score BETWEEN 0 AND 10);
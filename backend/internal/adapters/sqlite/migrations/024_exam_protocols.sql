@@ -0,0 +1,50 @@
+ALTER TABLE sessions ADD COLUMN kind TEXT NOT NULL DEFAULT 'training' CHECK (kind IN ('training','mock_exam'));
+
+CREATE TABLE IF NOT EXISTS exam_protocols (
+  id INTEGER PRIMARY KEY AUTOINCREMENT,
+  name TEXT NOT NULL COLLATE NOCASE UNIQUE,
+  description TEXT,
+  pass_ratio REAL NOT NULL DEFAULT 0.7 CHECK (pass_ratio BETWEEN 0 AND 1),
+  created_at TEXT NOT NULL,
+  updated_at TEXT NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS protocol_tasks (
+  id INTEGER PRIMARY KEY AUTOINCREMENT,
+  protocol_id INTEGER NOT NULL REFERENCES exam_protocols(id) ON DELETE CASCADE,
+  position INTEGER NOT NULL,
+  name TEXT NOT NULL,
+  description TEXT,
+  behavior_id INTEGER REFERENCES behaviors(id) ON DELETE RESTRICT,
+  exercise_id INTEGER REFERENCES exercises(id) ON DELETE RESTRICT,
+  time_limit_s INTEGER CHECK (time_limit_s > 0),
+  max_points INTEGER NOT NULL CHECK (max_points > 0),
+  min_points INTEGER NOT NULL DEFAULT 0 CHECK (min_points >= 0 AND min_points <= max_points),
+  required INTEGER NOT NULL DEFAULT 0
+);
+
+-- a run is one dog taking a protocol in its own mock-exam session
+CREATE TABLE IF NOT EXISTS exam_runs (
+  id INTEGER PRIMARY KEY AUTOINCREMENT,
+  protocol_id INTEGER NOT NULL REFERENCES exam_protocols(id) ON DELETE RESTRICT,
+  session_id INTEGER NOT NULL UNIQUE REFERENCES sessions(id) ON DELETE CASCADE,
+  dog_id INTEGER NOT NULL REFERENCES dogs(id) ON DELETE CASCADE,
+  judge TEXT,
+  created_at TEXT NOT NULL,
+  completed_at TEXT,
+  passed INTEGER,
+  total_points REAL
+);
+
+-- the round judged for each task; tasks with attempts cannot be removed
+CREATE TABLE IF NOT EXISTS exam_run_rounds (
+  run_id INTEGER NOT NULL REFERENCES exam_runs(id) ON DELETE CASCADE,
+  task_id INTEGER NOT NULL REFERENCES protocol_tasks(id) ON DELETE RESTRICT,
+  round_id INTEGER NOT NULL UNIQUE REFERENCES rounds(id) ON DELETE CASCADE,
+  PRIMARY KEY (run_id, task_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_protocol_tasks_protocol ON protocol_tasks(protocol_id, position);
+CREATE INDEX IF NOT EXISTS idx_exam_runs_dog ON exam_runs(dog_id);
+CREATE INDEX IF NOT EXISTS idx_exam_runs_protocol ON exam_runs(protocol_id);
+CREATE INDEX IF NOT EXISTS idx_exam_run_rounds_task ON exam_run_rounds(task_id);
@@ -0,0 +1,18 @@
+-- behavior_id is trained once requires_id is mastered
+CREATE TABLE IF NOT EXISTS behavior_prerequisites (
+  behavior_id INTEGER NOT NULL REFERENCES behaviors(id) ON DELETE CASCADE,
+  requires_id INTEGER NOT NULL REFERENCES behaviors(id) ON DELETE CASCADE,
+  PRIMARY KEY (behavior_id, requires_id),
+  CHECK (behavior_id <> requires_id)
+);
+
+-- per-behavior mastery criteria; behaviors without a row use the defaults
+CREATE TABLE IF NOT EXISTS behavior_mastery (
+  behavior_id INTEGER PRIMARY KEY REFERENCES behaviors(id) ON DELETE CASCADE,
+  min_rounds INTEGER NOT NULL CHECK (min_rounds > 0),
+  min_success_rate REAL NOT NULL CHECK (min_success_rate BETWEEN 0 AND 1),
+  min_score REAL CHECK (min_score BETWEEN 0 AND 10),
+  window_days INTEGER NOT NULL CHECK (window_days > 0)
+);
+
+CREATE INDEX IF NOT EXISTS idx_behavior_prerequisites_requires ON behavior_prerequisites(requires_id);
@@ -0,0 +1,41 @@
+-- parameters an exercise is varied by, with the range allowed for each
+CREATE TABLE IF NOT EXISTS exercise_parameters (
+  exercise_id INTEGER NOT NULL REFERENCES exercises(id) ON DELETE CASCADE,
+  kind TEXT NOT NULL CHECK (kind IN ('distance_m','duration_s','hides','trail_age_min')),
+  min_value REAL,
+  max_value REAL,
+  position INTEGER NOT NULL DEFAULT 0,
+  PRIMARY KEY (exercise_id, kind),
+  CHECK (min_value IS NULL OR max_value IS NULL OR min_value <= max_value)
+);
+
+-- numbered difficulty levels of an exercise, 1 being the easiest
+CREATE TABLE IF NOT EXISTS exercise_levels (
+  exercise_id INTEGER NOT NULL REFERENCES exercises(id) ON DELETE CASCADE,
+  level INTEGER NOT NULL CHECK (level > 0),
+  name TEXT NOT NULL,
+  description TEXT,
+  PRIMARY KEY (exercise_id, level)
+);
+
+-- preset parameter values of a level
+CREATE TABLE IF NOT EXISTS exercise_level_values (
+  exercise_id INTEGER NOT NULL,
+  level INTEGER NOT NULL,
+  kind TEXT NOT NULL,
+  value REAL NOT NULL,
+  PRIMARY KEY (exercise_id, level, kind),
+  FOREIGN KEY (exercise_id, level) REFERENCES exercise_levels(exercise_id, level) ON DELETE CASCADE,
+  FOREIGN KEY (exercise_id, kind) REFERENCES exercise_parameters(exercise_id, kind) ON DELETE CASCADE
+);
+
+-- level and parameter values a round was trained at; kept as recorded when
+-- the exercise's levels or parameters change later
+ALTER TABLE rounds ADD COLUMN difficulty_level INTEGER CHECK (difficulty_level > 0);
+
+CREATE TABLE IF NOT EXISTS round_parameters (
+  round_id INTEGER NOT NULL REFERENCES rounds(id) ON DELETE CASCADE,
+  kind TEXT NOT NULL CHECK (kind IN ('distance_m','duration_s','hides','trail_age_min')),
+  value REAL NOT NULL CHECK (value >= 0),
+  PRIMARY KEY (round_id, kind)
+);
@@ -0,0 +1,10 @@
+CREATE TABLE IF NOT EXISTS round_rubble (
+  round_id INTEGER PRIMARY KEY REFERENCES rounds(id) ON DELETE CASCADE,
+  structure_type TEXT NOT NULL CHECK (structure_type IN ('building','debris_pile','tunnel','vehicle','other')),
+  victims INTEGER NOT NULL DEFAULT 0 CHECK (victims >= 0),
+  victims_found INTEGER NOT NULL DEFAULT 0 CHECK (victims_found >= 0 AND victims_found <= victims),
+  max_depth_m REAL CHECK (max_depth_m >= 0),
+  false_alerts INTEGER NOT NULL DEFAULT 0 CHECK (false_alerts >= 0)
+);
+
+UPDATE disciplines SET round_extensions = 'rubble' WHERE name = 'Rubble' AND round_extensions = 'area_search';
@@ -0,0 +1,52 @@
+CREATE TABLE IF NOT EXISTS rewards (
+  code TEXT PRIMARY KEY,
+  name TEXT NOT NULL,
+  position INTEGER NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS reinforcement_schedules (
+  code TEXT PRIMARY KEY,
+  name TEXT NOT NULL,
+  position INTEGER NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS distractions (
+  code TEXT PRIMARY KEY,
+  name TEXT NOT NULL,
+  position INTEGER NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS distraction_levels (
+  code TEXT PRIMARY KEY,
+  name TEXT NOT NULL,
+  position INTEGER NOT NULL
+);
+
+INSERT OR IGNORE INTO rewards (code, name, position) VALUES
+  ('food', 'Food', 1), ('toy', 'Toy', 2), ('play', 'Play', 3), ('praise', 'Praise', 4), ('none', 'None', 5);
+INSERT OR IGNORE INTO reinforcement_schedules (code, name, position) VALUES
+  ('continuous', 'Continuous', 1), ('fixed_ratio', 'Fixed ratio', 2), ('variable_ratio', 'Variable ratio', 3), ('jackpot', 'Jackpot', 4);
+INSERT OR IGNORE INTO distractions (code, name, position) VALUES
+  ('other_dogs', 'Other dogs', 1), ('game_scent', 'Game scent', 2), ('traffic', 'Traffic', 3), ('people', 'People', 4),
+  ('noise', 'Noise', 5), ('food', 'Food', 6), ('livestock', 'Livestock', 7);
+INSERT OR IGNORE INTO distraction_levels (code, name, position) VALUES
+  ('none', 'None', 1), ('low', 'Low', 2), ('medium', 'Medium', 3), ('high', 'High', 4);
+
+CREATE TABLE IF NOT EXISTS round_distractions (
+  round_id INTEGER NOT NULL REFERENCES rounds(id) ON DELETE CASCADE,
+  distraction TEXT NOT NULL REFERENCES distractions(code),
+  PRIMARY KEY (round_id, distraction)
+);
+
+CREATE INDEX IF NOT EXISTS idx_round_distractions_distraction ON round_distractions(distraction);
+
+-- move the comma-separated kinds of rounds.distractions into the join table
+WITH RECURSIVE split(round_id, item, rest) AS (
+  SELECT id, '', distractions || ',' FROM rounds WHERE distractions IS NOT NULL AND distractions <> ''
+  UNION ALL
+  SELECT round_id, trim(substr(rest, 1, instr(rest, ',') - 1)), substr(rest, instr(rest, ',') + 1) FROM split WHERE rest <> ''
+)
+INSERT OR IGNORE INTO round_distractions (round_id, distraction)
+  SELECT round_id, item FROM split WHERE item IN (SELECT code FROM distractions);
+
+ALTER TABLE rounds DROP COLUMN distractions;
@@ -0,0 +1,18 @@
+-- rounds.reward, reinforcement_schedule and distraction_level were checked
+-- against fixed lists; make them reference the catalogs instead, so catalog
+-- values can be added and values in use cannot be removed. SQLite cannot
+-- alter a column's constraints, so each is replaced by a new column.
+ALTER TABLE rounds ADD COLUMN reward_code TEXT REFERENCES rewards(code);
+UPDATE rounds SET reward_code = reward;
+ALTER TABLE rounds DROP COLUMN reward;
+ALTER TABLE rounds RENAME COLUMN reward_code TO reward;
+
+ALTER TABLE rounds ADD COLUMN schedule_code TEXT REFERENCES reinforcement_schedules(code);
+UPDATE rounds SET schedule_code = reinforcement_schedule;
+ALTER TABLE rounds DROP COLUMN reinforcement_schedule;
+ALTER TABLE rounds RENAME COLUMN schedule_code TO reinforcement_schedule;
+
+ALTER TABLE rounds ADD COLUMN distraction_level_code TEXT REFERENCES distraction_levels(code);
+UPDATE rounds SET distraction_level_code = distraction_level;
+ALTER TABLE rounds DROP COLUMN distraction_level;
+ALTER TABLE rounds RENAME COLUMN distraction_level_code TO distraction_level;
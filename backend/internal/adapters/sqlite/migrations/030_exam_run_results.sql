@@ -0,0 +1,14 @@
+-- the per-task result a run was completed with, so the result and sheet of
+-- a completed run no longer depend on the rounds as they are now
+CREATE TABLE IF NOT EXISTS exam_run_results (
+  run_id INTEGER NOT NULL REFERENCES exam_runs(id) ON DELETE CASCADE,
+  task_id INTEGER NOT NULL REFERENCES protocol_tasks(id) ON DELETE RESTRICT,
+  round_id INTEGER REFERENCES rounds(id) ON DELETE SET NULL,
+  state TEXT NOT NULL CHECK (state IN ('pending','passed','failed')),
+  points REAL NOT NULL,
+  duration_s INTEGER,
+  overtime INTEGER NOT NULL DEFAULT 0,
+  PRIMARY KEY (run_id, task_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_exam_run_results_task ON exam_run_results(task_id);
@@ -0,0 +1,299 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/tnosaj/sar-training/backend/internal/domain/common"
+	"github.com/tnosaj/sar-training/backend/internal/domain/mission"
+	logx "github.com/tnosaj/sar-training/backend/internal/infra/log"
+)
+
+type MissionsRepo struct{ db *sql.DB }
+
+func NewMissionsRepo(db *sql.DB) *MissionsRepo {
+	logx.Std.Trace("starting missions repo")
+	return &MissionsRepo{db: db}
+}
+
+const missionColumns = `m.id, m.started_at, m.ended_at, m.alerting_agency, m.reference, m.location, m.location_id, m.search_area, m.outcome, m.debrief, m.created_at, m.updated_at`
+
+func (r *MissionsRepo) Create(ctx context.Context, m *mission.Mission) error {
+	area, err := encodeBoundary(m.SearchArea)
+	if err != nil {
+		return err
+	}
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	res, err := tx.ExecContext(ctx, `INSERT INTO missions (started_at, ended_at, alerting_agency, reference, location, location_id, search_area, outcome, debrief, created_at, updated_at) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		m.StartedAt.Format(time.RFC3339), formatTime(m.EndedAt), m.AlertingAgency, m.Reference, m.Location, m.LocationID, area, m.Outcome, m.Debrief,
+		m.CreatedAt.Format(time.RFC3339), m.UpdatedAt.Format(time.RFC3339))
+	if err != nil {
+		if isForeignKeyViolation(err) {
+			return common.ErrValidation
+		}
+		return err
+	}
+	id, _ := res.LastInsertId()
+	if err := insertTeams(ctx, tx, id, m.Teams); err != nil {
+		return err
+	}
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+	m.ID = mission.MissionID(id)
+	return nil
+}
+
+func (r *MissionsRepo) Get(ctx context.Context, id mission.MissionID) (*mission.Mission, error) {
+	items, err := r.query(ctx, `SELECT `+missionColumns+` FROM missions m WHERE m.id=?`, id)
+	if err != nil {
+		return nil, err
+	}
+	if len(items) == 0 {
+		return nil, common.ErrNotFound
+	}
+	return items[0], nil
+}
+
+func (r *MissionsRepo) List(ctx context.Context, f mission.Filter) ([]*mission.Mission, error) {
+	where, args := missionWhere(f)
+	if f.Outcome != nil {
+		where += ` AND m.outcome = ?`
+		args = append(args, *f.Outcome)
+	}
+	return r.query(ctx, `SELECT `+missionColumns+` FROM missions m WHERE `+where+` ORDER BY m.started_at DESC, m.id DESC`, args...)
+}
+
+func (r *MissionsRepo) Update(ctx context.Context, m *mission.Mission) error {
+	area, err := encodeBoundary(m.SearchArea)
+	if err != nil {
+		return err
+	}
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	res, err := tx.ExecContext(ctx, `UPDATE missions SET started_at=?, ended_at=?, alerting_agency=?, reference=?, location=?, location_id=?, search_area=?, outcome=?, debrief=?, updated_at=? WHERE id=?`,
+		m.StartedAt.Format(time.RFC3339), formatTime(m.EndedAt), m.AlertingAgency, m.Reference, m.Location, m.LocationID, area, m.Outcome, m.Debrief,
+		m.UpdatedAt.Format(time.RFC3339), m.ID)
+	if err != nil {
+		if isForeignKeyViolation(err) {
+			return common.ErrValidation
+		}
+		return err
+	}
+	if rows, _ := res.RowsAffected(); rows == 0 {
+		return common.ErrNotFound
+	}
+	if _, err := tx.ExecContext(ctx, `DELETE FROM mission_teams WHERE mission_id=?`, m.ID); err != nil {
+		return err
+	}
+	if err := insertTeams(ctx, tx, int64(m.ID), m.Teams); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+func (r *MissionsRepo) Delete(ctx context.Context, id mission.MissionID) error {
+	res, err := r.db.ExecContext(ctx, `DELETE FROM missions WHERE id=?`, id)
+	if err != nil {
+		return err
+	}
+	if rows, _ := res.RowsAffected(); rows == 0 {
+		return common.ErrNotFound
+	}
+	return nil
+}
+
+func (r *MissionsRepo) DogRecords(ctx context.Context, f mission.Filter) ([]*mission.DogRecord, error) {
+	where, args := missionWhere(f)
+	rows, err := r.db.QueryContext(ctx, `SELECT t.dog_id, d.name, COUNT(*), SUM(t.found), COALESCE(SUM(t.search_minutes), 0)
+		FROM mission_teams t JOIN missions m ON m.id = t.mission_id JOIN dogs d ON d.id = t.dog_id
+		WHERE m.outcome <> 'cancelled' AND `+where+` GROUP BY t.dog_id ORDER BY d.name`, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var out []*mission.DogRecord
+	byDog := map[int64]*mission.DogRecord{}
+	for rows.Next() {
+		var rec mission.DogRecord
+		if err := rows.Scan(&rec.DogID, &rec.DogName, &rec.Missions, &rec.Finds, &rec.SearchMinutes); err != nil {
+			return nil, err
+		}
+		out = append(out, &rec)
+		byDog[rec.DogID] = &rec
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	query := `SELECT r.dog_id, COUNT(*), SUM(r.outcome = 'success') FROM rounds r JOIN sessions s ON s.id = r.session_id WHERE 1=1`
+	args = []any{}
+	if f.DogID != nil {
+		query += ` AND r.dog_id = ?`
+		args = append(args, *f.DogID)
+	}
+	if f.HandlerID != nil {
+		query += ` AND r.handler_id = ?`
+		args = append(args, *f.HandlerID)
+	}
+	if f.From != nil {
+		query += ` AND COALESCE(r.started_at, s.started_at) >= ?`
+		args = append(args, f.From.Format(time.RFC3339))
+	}
+	if f.To != nil {
+		query += ` AND COALESCE(r.started_at, s.started_at) < ?`
+		args = append(args, f.To.Format(time.RFC3339))
+	}
+	rrows, err := r.db.QueryContext(ctx, query+` GROUP BY r.dog_id`, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rrows.Close()
+	for rrows.Next() {
+		var dogID int64
+		var n, ok int
+		if err := rrows.Scan(&dogID, &n, &ok); err != nil {
+			return nil, err
+		}
+		if rec := byDog[dogID]; rec != nil {
+			rec.Rounds, rec.RoundSuccesses = n, ok
+		}
+	}
+	return out, rrows.Err()
+}
+
+// missionWhere builds the condition on missions m shared by List and
+// DogRecords.
+func missionWhere(f mission.Filter) (string, []any) {
+	where := `1=1`
+	args := []any{}
+	if f.DogID != nil {
+		where += ` AND m.id IN (SELECT mission_id FROM mission_teams WHERE dog_id = ?)`
+		args = append(args, *f.DogID)
+	}
+	if f.HandlerID != nil {
+		where += ` AND m.id IN (SELECT mission_id FROM mission_teams WHERE handler_id = ?)`
+		args = append(args, *f.HandlerID)
+	}
+	if f.From != nil {
+		where += ` AND m.started_at >= ?`
+		args = append(args, f.From.Format(time.RFC3339))
+	}
+	if f.To != nil {
+		where += ` AND m.started_at < ?`
+		args = append(args, f.To.Format(time.RFC3339))
+	}
+	return where, args
+}
+
+func (r *MissionsRepo) query(ctx context.Context, query string, args ...any) ([]*mission.Mission, error) {
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var out []*mission.Mission
+	byID := map[mission.MissionID]*mission.Mission{}
+	for rows.Next() {
+		m, err := scanMission(rows)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, m)
+		byID[m.ID] = m
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	if len(out) == 0 {
+		return out, nil
+	}
+	// load the teams of every selected mission in one go
+	trows, err := r.db.QueryContext(ctx, `SELECT t.mission_id, t.id, t.dog_id, t.handler_id, t.discipline_id, t.found, t.search_minutes, t.notes
+		FROM mission_teams t WHERE t.mission_id IN (SELECT m.id FROM (`+query+`) m) ORDER BY t.id`, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer trows.Close()
+	for trows.Next() {
+		var missionID mission.MissionID
+		var t mission.Team
+		if err := trows.Scan(&missionID, &t.ID, &t.DogID, &t.HandlerID, &t.DisciplineID, &t.Found, &t.SearchMinutes, &t.Notes); err != nil {
+			return nil, err
+		}
+		if m := byID[missionID]; m != nil {
+			m.Teams = append(m.Teams, t)
+		}
+	}
+	return out, trows.Err()
+}
+
+func insertTeams(ctx context.Context, tx execer, missionID int64, teams []mission.Team) error {
+	for i := range teams {
+		t := &teams[i]
+		res, err := tx.ExecContext(ctx, `INSERT INTO mission_teams (mission_id, dog_id, handler_id, discipline_id, found, search_minutes, notes) VALUES (?, ?, ?, ?, ?, ?, ?)`,
+			missionID, t.DogID, t.HandlerID, t.DisciplineID, t.Found, t.SearchMinutes, t.Notes)
+		if err != nil {
+			if isForeignKeyViolation(err) {
+				return common.ErrValidation
+			}
+			return mapConstraint(err)
+		}
+		t.ID, _ = res.LastInsertId()
+	}
+	return nil
+}
+
+func scanMission(row rowScanner) (*mission.Mission, error) {
+	var m mission.Mission
+	var started, created, updated string
+	var ended, area sql.NullString
+	if err := row.Scan(&m.ID, &started, &ended, &m.AlertingAgency, &m.Reference, &m.Location, &m.LocationID, &area, &m.Outcome, &m.Debrief, &created, &updated); err != nil {
+		return nil, err
+	}
+	m.StartedAt, _ = time.Parse(time.RFC3339, started)
+	m.EndedAt = parseTime(ended)
+	var err error
+	if m.SearchArea, err = decodeBoundary(area); err != nil {
+		return nil, err
+	}
+	m.CreatedAt, _ = time.Parse(time.RFC3339, created)
+	m.UpdatedAt, _ = time.Parse(time.RFC3339, updated)
+	return &m, nil
+}
+
+func parseTime(s sql.NullString) *time.Time {
+	if !s.Valid {
+		return nil
+	}
+	t, err := time.Parse(time.RFC3339, s.String)
+	if err != nil {
+		return nil
+	}
+	return &t
+}
+
+// parseRoundTime reads the start of a round as stored: an RFC3339 time or,
+// for sessions entered by day only, a plain date taken as midnight UTC.
+// Free text from before times were checked is an error.
+func parseRoundTime(v string) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, v); err == nil {
+		return t.UTC(), nil
+	}
+	return time.Parse(common.DateLayout, v)
+}
+
+func formatTime(t *time.Time) *string {
+	if t == nil {
+		return nil
+	}
+	v := t.UTC().Format(time.RFC3339)
+	return &v
+}
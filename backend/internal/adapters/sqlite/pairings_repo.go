@@ -0,0 +1,115 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/tnosaj/sar-training/backend/internal/domain/common"
+	"github.com/tnosaj/sar-training/backend/internal/domain/pairing"
+	logx "github.com/tnosaj/sar-training/backend/internal/infra/log"
+)
+
+type PairingsRepo struct{ db *sql.DB }
+
+func NewPairingsRepo(db *sql.DB) *PairingsRepo {
+	logx.Std.Trace("starting pairings repo")
+	return &PairingsRepo{db: db}
+}
+
+const pairingColumns = `id, dog_id, user_id, role, start_date, end_date, notes, created_at, updated_at`
+
+func (r *PairingsRepo) Create(ctx context.Context, p *pairing.Pairing) error {
+	res, err := r.db.ExecContext(ctx, `INSERT INTO dog_handlers (dog_id, user_id, role, start_date, end_date, notes, created_at, updated_at) VALUES (?, ?, ?, ?, ?, ?, ?, ?)`,
+		p.DogID, p.UserID, p.Role, p.StartDate.Format(common.DateLayout), formatDate(p.EndDate), p.Notes,
+		p.CreatedAt.Format(time.RFC3339), p.UpdatedAt.Format(time.RFC3339))
+	if err != nil {
+		return err
+	}
+	id, _ := res.LastInsertId()
+	p.ID = pairing.PairingID(id)
+	return nil
+}
+
+func (r *PairingsRepo) Get(ctx context.Context, id pairing.PairingID) (*pairing.Pairing, error) {
+	row := r.db.QueryRowContext(ctx, `SELECT `+pairingColumns+` FROM dog_handlers WHERE id=?`, id)
+	p, err := scanPairing(row)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, common.ErrNotFound
+	}
+	return p, err
+}
+
+func (r *PairingsRepo) List(ctx context.Context, f pairing.Filter) ([]*pairing.Pairing, error) {
+	query := `SELECT ` + pairingColumns + ` FROM dog_handlers WHERE 1=1`
+	args := []any{}
+	if f.DogID != nil {
+		query += ` AND dog_id = ?`
+		args = append(args, *f.DogID)
+	}
+	if f.UserID != nil {
+		query += ` AND user_id = ?`
+		args = append(args, *f.UserID)
+	}
+	if f.ActiveOn != nil {
+		day := f.ActiveOn.UTC().Format(common.DateLayout)
+		query += ` AND start_date <= ? AND (end_date IS NULL OR end_date >= ?)`
+		args = append(args, day, day)
+	}
+	query += ` ORDER BY start_date DESC, id DESC`
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var out []*pairing.Pairing
+	for rows.Next() {
+		p, err := scanPairing(rows)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, p)
+	}
+	return out, rows.Err()
+}
+
+func (r *PairingsRepo) Update(ctx context.Context, p *pairing.Pairing) error {
+	res, err := r.db.ExecContext(ctx, `UPDATE dog_handlers SET user_id=?, role=?, start_date=?, end_date=?, notes=?, updated_at=? WHERE id=?`,
+		p.UserID, p.Role, p.StartDate.Format(common.DateLayout), formatDate(p.EndDate), p.Notes, p.UpdatedAt.Format(time.RFC3339), p.ID)
+	if err != nil {
+		return err
+	}
+	if rows, _ := res.RowsAffected(); rows == 0 {
+		return common.ErrNotFound
+	}
+	return nil
+}
+
+func (r *PairingsRepo) Delete(ctx context.Context, id pairing.PairingID) error {
+	res, err := r.db.ExecContext(ctx, `DELETE FROM dog_handlers WHERE id=?`, id)
+	if err != nil {
+		return err
+	}
+	if rows, _ := res.RowsAffected(); rows == 0 {
+		return common.ErrNotFound
+	}
+	return nil
+}
+
+func scanPairing(row rowScanner) (*pairing.Pairing, error) {
+	var p pairing.Pairing
+	var start, created, updated string
+	var end sql.NullString
+	if err := row.Scan(&p.ID, &p.DogID, &p.UserID, &p.Role, &start, &end, &p.Notes, &created, &updated); err != nil {
+		return nil, err
+	}
+	p.StartDate, _ = time.Parse(common.DateLayout, start)
+	if end.Valid {
+		t, _ := time.Parse(common.DateLayout, end.String)
+		p.EndDate = &t
+	}
+	p.CreatedAt, _ = time.Parse(time.RFC3339, created)
+	p.UpdatedAt, _ = time.Parse(time.RFC3339, updated)
+	return &p, nil
+}
@@ -0,0 +1,397 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/tnosaj/sar-training/backend/internal/domain/common"
+	"github.com/tnosaj/sar-training/backend/internal/domain/protocol"
+	"github.com/tnosaj/sar-training/backend/internal/domain/session"
+	logx "github.com/tnosaj/sar-training/backend/internal/infra/log"
+)
+
+type ProtocolsRepo struct{ db *sql.DB }
+
+func NewProtocolsRepo(db *sql.DB) *ProtocolsRepo {
+	logx.Std.Trace("starting protocols repo")
+	return &ProtocolsRepo{db: db}
+}
+
+func (r *ProtocolsRepo) Create(ctx context.Context, p *protocol.Protocol) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	res, err := tx.ExecContext(ctx, `INSERT INTO exam_protocols (name, description, pass_ratio, created_at, updated_at) VALUES (?, ?, ?, ?, ?)`,
+		p.Name, p.Description, p.PassRatio, p.CreatedAt.Format(time.RFC3339), p.UpdatedAt.Format(time.RFC3339))
+	if err != nil {
+		return mapConstraint(err)
+	}
+	id, _ := res.LastInsertId()
+	p.ID = protocol.ProtocolID(id)
+	if err := insertTasks(ctx, tx, p); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+func (r *ProtocolsRepo) Get(ctx context.Context, id protocol.ProtocolID) (*protocol.Protocol, error) {
+	row := r.db.QueryRowContext(ctx, `SELECT id, name, description, pass_ratio, created_at, updated_at FROM exam_protocols WHERE id=?`, id)
+	p, err := scanProtocol(row)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, common.ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	if p.Tasks, err = listTasks(ctx, r.db, p.ID); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+func (r *ProtocolsRepo) List(ctx context.Context) ([]*protocol.Protocol, error) {
+	rows, err := r.db.QueryContext(ctx, `SELECT id, name, description, pass_ratio, created_at, updated_at FROM exam_protocols ORDER BY name`)
+	if err != nil {
+		return nil, err
+	}
+	var out []*protocol.Protocol
+	for rows.Next() {
+		p, err := scanProtocol(rows)
+		if err != nil {
+			rows.Close()
+			return nil, err
+		}
+		out = append(out, p)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	for _, p := range out {
+		if p.Tasks, err = listTasks(ctx, r.db, p.ID); err != nil {
+			return nil, err
+		}
+	}
+	return out, nil
+}
+
+func (r *ProtocolsRepo) Update(ctx context.Context, p *protocol.Protocol) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	cur, err := scanProtocol(tx.QueryRowContext(ctx, `SELECT id, name, description, pass_ratio, created_at, updated_at FROM exam_protocols WHERE id=?`, p.ID))
+	if errors.Is(err, sql.ErrNoRows) {
+		return common.ErrNotFound
+	}
+	if err != nil {
+		return err
+	}
+	if cur.Tasks, err = listTasks(ctx, tx, p.ID); err != nil {
+		return err
+	}
+	// tasks given with an ID update that task, each at most once
+	existing := map[protocol.TaskID]bool{}
+	for _, t := range cur.Tasks {
+		existing[t.ID] = true
+	}
+	kept := map[protocol.TaskID]bool{}
+	for _, t := range p.Tasks {
+		if t.ID != 0 {
+			if !existing[t.ID] || kept[t.ID] {
+				return common.ErrValidation
+			}
+			kept[t.ID] = true
+		}
+	}
+	// runs are judged against the current tasks, so their scoring is fixed
+	// once the first run starts
+	var hasRuns bool
+	if err := tx.QueryRowContext(ctx, `SELECT EXISTS (SELECT 1 FROM exam_runs WHERE protocol_id=?)`, p.ID).Scan(&hasRuns); err != nil {
+		return err
+	}
+	if hasRuns && !cur.SameScoring(p) {
+		return common.ErrConflict
+	}
+	if _, err := tx.ExecContext(ctx, `UPDATE exam_protocols SET name=?, description=?, pass_ratio=?, updated_at=? WHERE id=?`,
+		p.Name, p.Description, p.PassRatio, p.UpdatedAt.Format(time.RFC3339), p.ID); err != nil {
+		return mapConstraint(err)
+	}
+	for tid := range existing {
+		if kept[tid] {
+			continue
+		}
+		if _, err := tx.ExecContext(ctx, `DELETE FROM protocol_tasks WHERE id=?`, tid); err != nil {
+			if isForeignKeyViolation(err) {
+				return common.ErrConflict
+			}
+			return err
+		}
+	}
+	if err := insertTasks(ctx, tx, p); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+func (r *ProtocolsRepo) Delete(ctx context.Context, id protocol.ProtocolID) error {
+	res, err := r.db.ExecContext(ctx, `DELETE FROM exam_protocols WHERE id=?`, id)
+	if err != nil {
+		if isForeignKeyViolation(err) {
+			return common.ErrConflict
+		}
+		return err
+	}
+	if rows, _ := res.RowsAffected(); rows == 0 {
+		return common.ErrNotFound
+	}
+	return nil
+}
+
+// rowsQueryer runs multi-row queries on a *sql.DB or *sql.Tx.
+type rowsQueryer interface {
+	QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error)
+}
+
+func listTasks(ctx context.Context, q rowsQueryer, id protocol.ProtocolID) ([]protocol.Task, error) {
+	rows, err := q.QueryContext(ctx, `SELECT id, protocol_id, position, name, description, behavior_id, exercise_id, time_limit_s, max_points, min_points, required
+		FROM protocol_tasks WHERE protocol_id=? ORDER BY position, id`, id)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var out []protocol.Task
+	for rows.Next() {
+		var t protocol.Task
+		var required int
+		if err := rows.Scan(&t.ID, &t.ProtocolID, &t.Position, &t.Name, &t.Description, &t.BehaviorID, &t.ExerciseID, &t.TimeLimitS,
+			&t.MaxPoints, &t.MinPoints, &required); err != nil {
+			return nil, err
+		}
+		t.Required = required != 0
+		out = append(out, t)
+	}
+	return out, rows.Err()
+}
+
+// insertTasks writes the protocol's tasks in order, updating those that
+// already have an ID.
+func insertTasks(ctx context.Context, tx *sql.Tx, p *protocol.Protocol) error {
+	for i := range p.Tasks {
+		t := &p.Tasks[i]
+		t.ProtocolID, t.Position = p.ID, i+1
+		if t.ID != 0 {
+			_, err := tx.ExecContext(ctx, `UPDATE protocol_tasks SET position=?, name=?, description=?, behavior_id=?, exercise_id=?, time_limit_s=?, max_points=?, min_points=?, required=?
+				WHERE id=?`,
+				t.Position, t.Name, t.Description, t.BehaviorID, t.ExerciseID, t.TimeLimitS, t.MaxPoints, t.MinPoints, boolToInt(t.Required), t.ID)
+			if err != nil {
+				if isForeignKeyViolation(err) {
+					return common.ErrValidation
+				}
+				return err
+			}
+			continue
+		}
+		res, err := tx.ExecContext(ctx, `INSERT INTO protocol_tasks (protocol_id, position, name, description, behavior_id, exercise_id, time_limit_s, max_points, min_points, required)
+			VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+			p.ID, t.Position, t.Name, t.Description, t.BehaviorID, t.ExerciseID, t.TimeLimitS, t.MaxPoints, t.MinPoints, boolToInt(t.Required))
+		if err != nil {
+			if isForeignKeyViolation(err) {
+				return common.ErrValidation
+			}
+			return err
+		}
+		id, _ := res.LastInsertId()
+		t.ID = protocol.TaskID(id)
+	}
+	return nil
+}
+
+func scanProtocol(row rowScanner) (*protocol.Protocol, error) {
+	var p protocol.Protocol
+	var c, u string
+	if err := row.Scan(&p.ID, &p.Name, &p.Description, &p.PassRatio, &c, &u); err != nil {
+		return nil, err
+	}
+	p.CreatedAt, _ = time.Parse(time.RFC3339, c)
+	p.UpdatedAt, _ = time.Parse(time.RFC3339, u)
+	return &p, nil
+}
+
+func (r *ProtocolsRepo) StartRun(ctx context.Context, run *protocol.Run) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	var name string
+	if err := tx.QueryRowContext(ctx, `SELECT name FROM exam_protocols WHERE id=?`, run.ProtocolID).Scan(&name); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return common.ErrNotFound
+		}
+		return err
+	}
+	notes := "Mock exam: " + name
+	res, err := tx.ExecContext(ctx, `INSERT INTO sessions (started_at, notes, kind) VALUES (?, ?, ?)`,
+		run.StartedAt.Format(time.RFC3339), notes, session.KindMockExam)
+	if err != nil {
+		return err
+	}
+	run.SessionID, _ = res.LastInsertId()
+	if _, err := tx.ExecContext(ctx, `INSERT INTO session_dogs (session_id, dog_id) VALUES (?, ?)`, run.SessionID, run.DogID); err != nil {
+		return err
+	}
+	res, err = tx.ExecContext(ctx, `INSERT INTO exam_runs (protocol_id, session_id, dog_id, judge, created_at) VALUES (?, ?, ?, ?, ?)`,
+		run.ProtocolID, run.SessionID, run.DogID, run.Judge, run.CreatedAt.Format(time.RFC3339))
+	if err != nil {
+		return err
+	}
+	id, _ := res.LastInsertId()
+	run.ID = protocol.RunID(id)
+	if err := tx.QueryRowContext(ctx, `SELECT name FROM dogs WHERE id=?`, run.DogID).Scan(&run.DogName); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+const runColumns = `x.id, x.protocol_id, x.session_id, x.dog_id, d.name, x.judge, s.started_at, x.created_at, x.completed_at, x.passed, x.total_points`
+
+func (r *ProtocolsRepo) GetRun(ctx context.Context, id protocol.RunID) (*protocol.Run, error) {
+	row := r.db.QueryRowContext(ctx, `SELECT `+runColumns+` FROM exam_runs x JOIN dogs d ON d.id = x.dog_id JOIN sessions s ON s.id = x.session_id WHERE x.id=?`, id)
+	run, err := scanRun(row)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, common.ErrNotFound
+	}
+	return run, err
+}
+
+func (r *ProtocolsRepo) ListRuns(ctx context.Context, f protocol.RunFilter) ([]*protocol.Run, error) {
+	query := `SELECT ` + runColumns + ` FROM exam_runs x JOIN dogs d ON d.id = x.dog_id JOIN sessions s ON s.id = x.session_id WHERE 1=1`
+	args := []any{}
+	if f.DogID != nil {
+		query += ` AND x.dog_id = ?`
+		args = append(args, *f.DogID)
+	}
+	if f.ProtocolID != nil {
+		query += ` AND x.protocol_id = ?`
+		args = append(args, *f.ProtocolID)
+	}
+	query += ` ORDER BY s.started_at DESC, x.id DESC`
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var out []*protocol.Run
+	for rows.Next() {
+		run, err := scanRun(rows)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, run)
+	}
+	return out, rows.Err()
+}
+
+func scanRun(row rowScanner) (*protocol.Run, error) {
+	var run protocol.Run
+	var started, created string
+	var completed sql.NullString
+	var passed sql.NullBool
+	if err := row.Scan(&run.ID, &run.ProtocolID, &run.SessionID, &run.DogID, &run.DogName, &run.Judge, &started, &created,
+		&completed, &passed, &run.TotalPoints); err != nil {
+		return nil, err
+	}
+	run.StartedAt, _ = time.Parse(time.RFC3339, started)
+	run.CreatedAt, _ = time.Parse(time.RFC3339, created)
+	run.CompletedAt = parseTime(completed)
+	if passed.Valid {
+		run.Passed = &passed.Bool
+	}
+	return &run, nil
+}
+
+func (r *ProtocolsRepo) SetTaskRound(ctx context.Context, runID protocol.RunID, taskID protocol.TaskID, roundID int64) error {
+	_, err := r.db.ExecContext(ctx, `INSERT INTO exam_run_rounds (run_id, task_id, round_id) VALUES (?, ?, ?)
+		ON CONFLICT(run_id, task_id) DO UPDATE SET round_id=excluded.round_id`, runID, taskID, roundID)
+	return mapConstraint(err)
+}
+
+func (r *ProtocolsRepo) DeleteTaskRound(ctx context.Context, runID protocol.RunID, taskID protocol.TaskID) error {
+	res, err := r.db.ExecContext(ctx, `DELETE FROM exam_run_rounds WHERE run_id=? AND task_id=?`, runID, taskID)
+	if err != nil {
+		return err
+	}
+	if rows, _ := res.RowsAffected(); rows == 0 {
+		return common.ErrNotFound
+	}
+	return nil
+}
+
+func (r *ProtocolsRepo) TaskRounds(ctx context.Context, runID protocol.RunID) ([]protocol.TaskRound, error) {
+	rows, err := r.db.QueryContext(ctx, `SELECT er.task_id, r.id, r.outcome, r.score, r.started_at, r.ended_at
+		FROM exam_run_rounds er JOIN rounds r ON r.id = er.round_id WHERE er.run_id=?`, runID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var out []protocol.TaskRound
+	for rows.Next() {
+		var tr protocol.TaskRound
+		var started, ended sql.NullString
+		if err := rows.Scan(&tr.TaskID, &tr.RoundID, &tr.Outcome, &tr.Score, &started, &ended); err != nil {
+			return nil, err
+		}
+		tr.StartedAt, tr.EndedAt = parseTime(started), parseTime(ended)
+		out = append(out, tr)
+	}
+	return out, rows.Err()
+}
+
+func (r *ProtocolsRepo) CompleteRun(ctx context.Context, run *protocol.Run, tasks []protocol.TaskResult) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	res, err := tx.ExecContext(ctx, `UPDATE exam_runs SET completed_at=?, passed=?, total_points=? WHERE id=? AND completed_at IS NULL`,
+		formatTime(run.CompletedAt), run.Passed, run.TotalPoints, run.ID)
+	if err != nil {
+		return err
+	}
+	if rows, _ := res.RowsAffected(); rows == 0 {
+		return common.ErrConflict
+	}
+	for _, t := range tasks {
+		if _, err := tx.ExecContext(ctx, `INSERT INTO exam_run_results (run_id, task_id, round_id, state, points, duration_s, overtime) VALUES (?, ?, ?, ?, ?, ?, ?)`,
+			run.ID, t.Task.ID, t.RoundID, t.State, t.Points, t.DurationS, t.Overtime); err != nil {
+			return err
+		}
+	}
+	if _, err := tx.ExecContext(ctx, `UPDATE sessions SET ended_at=COALESCE(ended_at, ?) WHERE id=?`, formatTime(run.CompletedAt), run.SessionID); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+func (r *ProtocolsRepo) TaskResults(ctx context.Context, runID protocol.RunID) ([]protocol.TaskResult, error) {
+	rows, err := r.db.QueryContext(ctx, `SELECT task_id, round_id, state, points, duration_s, overtime FROM exam_run_results WHERE run_id=?`, runID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var out []protocol.TaskResult
+	for rows.Next() {
+		var tr protocol.TaskResult
+		if err := rows.Scan(&tr.Task.ID, &tr.RoundID, &tr.State, &tr.Points, &tr.DurationS, &tr.Overtime); err != nil {
+			return nil, err
+		}
+		out = append(out, tr)
+	}
+	return out, rows.Err()
+}
@@ -0,0 +1,204 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/tnosaj/sar-training/backend/internal/domain/common"
+	"github.com/tnosaj/sar-training/backend/internal/domain/qualification"
+	logx "github.com/tnosaj/sar-training/backend/internal/infra/log"
+)
+
+type QualificationsRepo struct{ db *sql.DB }
+
+func NewQualificationsRepo(db *sql.DB) *QualificationsRepo {
+	logx.Std.Trace("starting qualifications repo")
+	return &QualificationsRepo{db: db}
+}
+
+const qualificationColumns = `id, dog_id, handler_id, exam_type, exam_id, exam_date, examiner, result, certificate_number, valid_until, remind_days_before, discipline_id, notes, created_at, updated_at`
+
+func (r *QualificationsRepo) Create(ctx context.Context, q *qualification.Qualification) error {
+	res, err := r.db.ExecContext(ctx, `INSERT INTO qualifications (dog_id, handler_id, exam_type, exam_id, exam_date, examiner, result, certificate_number, valid_until, remind_days_before, discipline_id, notes, created_at, updated_at) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		q.DogID, q.HandlerID, q.ExamType, q.ExamID, q.ExamDate.Format(common.DateLayout), q.Examiner, q.Result, q.CertificateNumber,
+		formatDate(q.ValidUntil), q.RemindDaysBefore, q.DisciplineID, q.Notes, q.CreatedAt.Format(time.RFC3339), q.UpdatedAt.Format(time.RFC3339))
+	if err != nil {
+		return err
+	}
+	id, _ := res.LastInsertId()
+	q.ID = qualification.QualificationID(id)
+	return nil
+}
+
+func (r *QualificationsRepo) Get(ctx context.Context, id qualification.QualificationID) (*qualification.Qualification, error) {
+	row := r.db.QueryRowContext(ctx, `SELECT `+qualificationColumns+` FROM qualifications WHERE id=?`, id)
+	q, err := scanQualification(row)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, common.ErrNotFound
+	}
+	return q, err
+}
+
+func (r *QualificationsRepo) List(ctx context.Context, f qualification.Filter) ([]*qualification.Qualification, error) {
+	query := `SELECT ` + qualificationColumns + ` FROM qualifications WHERE 1=1`
+	args := []any{}
+	if f.DogID != nil {
+		query += ` AND dog_id = ?`
+		args = append(args, *f.DogID)
+	}
+	if f.HandlerID != nil {
+		query += ` AND handler_id = ?`
+		args = append(args, *f.HandlerID)
+	}
+	if f.ExamType != nil {
+		query += ` AND exam_type = ?`
+		args = append(args, *f.ExamType)
+	}
+	if f.DisciplineID != nil {
+		query += ` AND discipline_id = ?`
+		args = append(args, *f.DisciplineID)
+	}
+	if f.TeamsOnly {
+		query += ` AND dog_id IS NOT NULL AND handler_id IS NOT NULL`
+	}
+	query += ` ORDER BY exam_date DESC, id DESC`
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var out []*qualification.Qualification
+	for rows.Next() {
+		q, err := scanQualification(rows)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, q)
+	}
+	return out, rows.Err()
+}
+
+func (r *QualificationsRepo) Update(ctx context.Context, q *qualification.Qualification) error {
+	res, err := r.db.ExecContext(ctx, `UPDATE qualifications SET dog_id=?, handler_id=?, exam_type=?, exam_id=?, exam_date=?, examiner=?, result=?, certificate_number=?, valid_until=?, remind_days_before=?, discipline_id=?, notes=?, updated_at=? WHERE id=?`,
+		q.DogID, q.HandlerID, q.ExamType, q.ExamID, q.ExamDate.Format(common.DateLayout), q.Examiner, q.Result, q.CertificateNumber,
+		formatDate(q.ValidUntil), q.RemindDaysBefore, q.DisciplineID, q.Notes, q.UpdatedAt.Format(time.RFC3339), q.ID)
+	if err != nil {
+		return err
+	}
+	if rows, _ := res.RowsAffected(); rows == 0 {
+		return common.ErrNotFound
+	}
+	return nil
+}
+
+func (r *QualificationsRepo) Delete(ctx context.Context, id qualification.QualificationID) error {
+	res, err := r.db.ExecContext(ctx, `DELETE FROM qualifications WHERE id=?`, id)
+	if err != nil {
+		return err
+	}
+	if a, _ := res.RowsAffected(); a == 0 {
+		return common.ErrNotFound
+	}
+	return nil
+}
+
+func (r *QualificationsRepo) ListTeams(ctx context.Context) ([]qualification.Team, error) {
+	rows, err := r.db.QueryContext(ctx, `SELECT DISTINCT d.id, d.name, u.id, u.email
+		FROM qualifications q JOIN dogs d ON d.id = q.dog_id JOIN users u ON u.id = q.handler_id
+		ORDER BY d.name, u.email`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var out []qualification.Team
+	for rows.Next() {
+		var t qualification.Team
+		if err := rows.Scan(&t.DogID, &t.DogName, &t.HandlerID, &t.HandlerEmail); err != nil {
+			return nil, err
+		}
+		out = append(out, t)
+	}
+	return out, rows.Err()
+}
+
+func (r *QualificationsRepo) AddAttachment(ctx context.Context, a *qualification.Attachment) error {
+	res, err := r.db.ExecContext(ctx, `INSERT INTO qualification_attachments (qualification_id, filename, content_type, size, data, uploaded_at) VALUES (?, ?, ?, ?, ?, ?)`,
+		a.QualificationID, a.Filename, a.ContentType, a.Size, a.Data, a.UploadedAt.Format(time.RFC3339))
+	if err != nil {
+		return err
+	}
+	a.ID, _ = res.LastInsertId()
+	return nil
+}
+
+func (r *QualificationsRepo) ListAttachments(ctx context.Context, id qualification.QualificationID) ([]*qualification.Attachment, error) {
+	rows, err := r.db.QueryContext(ctx, `SELECT id, qualification_id, filename, content_type, size, uploaded_at FROM qualification_attachments WHERE qualification_id=? ORDER BY id`, id)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var out []*qualification.Attachment
+	for rows.Next() {
+		var a qualification.Attachment
+		var up string
+		if err := rows.Scan(&a.ID, &a.QualificationID, &a.Filename, &a.ContentType, &a.Size, &up); err != nil {
+			return nil, err
+		}
+		a.UploadedAt, _ = time.Parse(time.RFC3339, up)
+		out = append(out, &a)
+	}
+	return out, rows.Err()
+}
+
+func (r *QualificationsRepo) GetAttachment(ctx context.Context, id qualification.QualificationID, attachmentID int64) (*qualification.Attachment, error) {
+	row := r.db.QueryRowContext(ctx, `SELECT id, qualification_id, filename, content_type, size, data, uploaded_at FROM qualification_attachments WHERE qualification_id=? AND id=?`, id, attachmentID)
+	var a qualification.Attachment
+	var up string
+	if err := row.Scan(&a.ID, &a.QualificationID, &a.Filename, &a.ContentType, &a.Size, &a.Data, &up); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, common.ErrNotFound
+		}
+		return nil, err
+	}
+	a.UploadedAt, _ = time.Parse(time.RFC3339, up)
+	return &a, nil
+}
+
+func (r *QualificationsRepo) DeleteAttachment(ctx context.Context, id qualification.QualificationID, attachmentID int64) error {
+	res, err := r.db.ExecContext(ctx, `DELETE FROM qualification_attachments WHERE qualification_id=? AND id=?`, id, attachmentID)
+	if err != nil {
+		return err
+	}
+	if a, _ := res.RowsAffected(); a == 0 {
+		return common.ErrNotFound
+	}
+	return nil
+}
+
+func scanQualification(row rowScanner) (*qualification.Qualification, error) {
+	var q qualification.Qualification
+	var examDate, c, u string
+	var validUntil sql.NullString
+	if err := row.Scan(&q.ID, &q.DogID, &q.HandlerID, &q.ExamType, &q.ExamID, &examDate, &q.Examiner, &q.Result, &q.CertificateNumber,
+		&validUntil, &q.RemindDaysBefore, &q.DisciplineID, &q.Notes, &c, &u); err != nil {
+		return nil, err
+	}
+	q.ExamDate, _ = time.Parse(common.DateLayout, examDate)
+	if validUntil.Valid {
+		t, _ := time.Parse(common.DateLayout, validUntil.String)
+		q.ValidUntil = &t
+	}
+	q.CreatedAt, _ = time.Parse(time.RFC3339, c)
+	q.UpdatedAt, _ = time.Parse(time.RFC3339, u)
+	return &q, nil
+}
+
+func formatDate(t *time.Time) *string {
+	if t == nil {
+		return nil
+	}
+	v := t.Format(common.DateLayout)
+	return &v
+}
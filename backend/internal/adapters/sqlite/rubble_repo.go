@@ -0,0 +1,70 @@
+package sqlite
+
+import (
+	"context"
+
+	"github.com/tnosaj/sar-training/backend/internal/domain/common"
+	"github.com/tnosaj/sar-training/backend/internal/domain/session"
+)
+
+// Rubble details live in round_rubble keyed by round; these are the
+// SessionsRepo methods reading and writing them.
+
+func (r *SessionsRepo) SaveRubble(ctx context.Context, roundID int64, rb *session.Rubble) error {
+	err := saveRubble(ctx, r.db, roundID, rb)
+	if isForeignKeyViolation(err) {
+		return common.ErrNotFound
+	}
+	return err
+}
+
+func (r *SessionsRepo) GetRubble(ctx context.Context, roundID int64) (*session.Rubble, error) {
+	m, err := r.loadRubble(ctx, `r.id = ?`, roundID)
+	if err != nil {
+		return nil, err
+	}
+	rb, ok := m[roundID]
+	if !ok {
+		return nil, common.ErrNotFound
+	}
+	return rb, nil
+}
+
+func (r *SessionsRepo) DeleteRubble(ctx context.Context, roundID int64) error {
+	res, err := r.db.ExecContext(ctx, `DELETE FROM round_rubble WHERE round_id=?`, roundID)
+	if err != nil {
+		return err
+	}
+	if rows, _ := res.RowsAffected(); rows == 0 {
+		return common.ErrNotFound
+	}
+	return nil
+}
+
+func (r *SessionsRepo) loadRubble(ctx context.Context, where string, args ...any) (map[int64]*session.Rubble, error) {
+	rows, err := r.db.QueryContext(ctx, `SELECT b.round_id, b.structure_type, b.victims, b.victims_found, b.max_depth_m, b.false_alerts
+		FROM round_rubble b JOIN rounds r ON r.id = b.round_id JOIN sessions s ON s.id = r.session_id WHERE `+where, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	out := map[int64]*session.Rubble{}
+	for rows.Next() {
+		var id int64
+		var rb session.Rubble
+		if err := rows.Scan(&id, &rb.StructureType, &rb.Victims, &rb.VictimsFound, &rb.MaxDepthM, &rb.FalseAlerts); err != nil {
+			return nil, err
+		}
+		out[id] = &rb
+	}
+	return out, rows.Err()
+}
+
+func saveRubble(ctx context.Context, tx execer, roundID int64, rb *session.Rubble) error {
+	_, err := tx.ExecContext(ctx, `INSERT INTO round_rubble (round_id, structure_type, victims, victims_found, max_depth_m, false_alerts)
+		VALUES (?, ?, ?, ?, ?, ?)
+		ON CONFLICT(round_id) DO UPDATE SET structure_type=excluded.structure_type, victims=excluded.victims,
+		victims_found=excluded.victims_found, max_depth_m=excluded.max_depth_m, false_alerts=excluded.false_alerts`,
+		roundID, rb.StructureType, rb.Victims, rb.VictimsFound, rb.MaxDepthM, rb.FalseAlerts)
+	return err
+}
@@ -3,6 +3,8 @@ package sqlite
 import (
 	"context"
 	"database/sql"
+	"errors"
+	"time"
 
 	"github.com/tnosaj/sar-training/backend/internal/domain/common"
 	"github.com/tnosaj/sar-training/backend/internal/domain/session"
@@ -17,8 +19,11 @@ func NewSessionsRepo(db *sql.DB) *SessionsRepo {
 }
 
 func (r *SessionsRepo) CreateSession(ctx context.Context, s *session.Session) error {
-	res, err := r.db.ExecContext(ctx, `INSERT INTO sessions (started_at, ended_at, location, notes) VALUES (?, ?, ?, ?)`,
-		s.StartedAt, s.EndedAt, s.Location, s.Notes)
+	if s.Kind == "" {
+		s.Kind = session.KindTraining
+	}
+	res, err := r.db.ExecContext(ctx, `INSERT INTO sessions (started_at, ended_at, location, location_id, notes, kind, `+conditionColumns+`) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		append([]any{s.StartedAt, s.EndedAt, s.Location, s.LocationID, s.Notes, s.Kind}, conditionArgs(s.Conditions)...)...)
 	if err != nil {
 		return err
 	}
@@ -28,8 +33,10 @@ func (r *SessionsRepo) CreateSession(ctx context.Context, s *session.Session) er
 }
 
 func (r *SessionsRepo) UpdateSession(ctx context.Context, s *session.Session) error {
-	res, err := r.db.ExecContext(ctx, `UPDATE sessions SET started_at=?, ended_at=?, location=?, notes=? WHERE id=?`,
-		s.StartedAt, s.EndedAt, s.Location, s.Notes, s.ID)
+	args := append([]any{s.StartedAt, s.EndedAt, s.Location, s.LocationID, s.Notes}, conditionArgs(s.Conditions)...)
+	res, err := r.db.ExecContext(ctx, `UPDATE sessions SET started_at=?, ended_at=?, location=?, location_id=?, notes=?,
+		temperature_c=?, humidity_pct=?, wind_speed_kmh=?, wind_direction_deg=?, precipitation=?, terrain=? WHERE id=?`,
+		append(args, s.ID)...)
 	if err != nil {
 		return err
 	}
@@ -54,7 +61,7 @@ func (r *SessionsRepo) CloseSession(ctx context.Context, s *session.Session) err
 }
 
 func (r *SessionsRepo) ListSessions(ctx context.Context) ([]*session.Session, error) {
-	rows, err := r.db.QueryContext(ctx, `SELECT id, started_at, ended_at, location, notes FROM sessions ORDER BY id DESC`)
+	rows, err := r.db.QueryContext(ctx, `SELECT id, started_at, ended_at, location, location_id, notes, kind, `+conditionColumns+` FROM sessions ORDER BY id DESC`)
 	if err != nil {
 		return nil, err
 	}
@@ -62,7 +69,8 @@ func (r *SessionsRepo) ListSessions(ctx context.Context) ([]*session.Session, er
 	var out []*session.Session
 	for rows.Next() {
 		var s session.Session
-		if err := rows.Scan(&s.ID, &s.StartedAt, &s.EndedAt, &s.Location, &s.Notes); err != nil {
+		dest := append([]any{&s.ID, &s.StartedAt, &s.EndedAt, &s.Location, &s.LocationID, &s.Notes, &s.Kind}, conditionDest(&s.Conditions)...)
+		if err := rows.Scan(dest...); err != nil {
 			return nil, err
 		}
 		out = append(out, &s)
@@ -70,6 +78,19 @@ func (r *SessionsRepo) ListSessions(ctx context.Context) ([]*session.Session, er
 	return out, rows.Err()
 }
 
+func (r *SessionsRepo) GetSession(ctx context.Context, id int64) (*session.Session, error) {
+	var s session.Session
+	dest := append([]any{&s.ID, &s.StartedAt, &s.EndedAt, &s.Location, &s.LocationID, &s.Notes, &s.Kind}, conditionDest(&s.Conditions)...)
+	err := r.db.QueryRowContext(ctx, `SELECT id, started_at, ended_at, location, location_id, notes, kind, `+conditionColumns+` FROM sessions WHERE id=?`, id).Scan(dest...)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, common.ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &s, nil
+}
+
 func (r *SessionsRepo) AddDog(ctx context.Context, sessionID int64, dogID int64) error {
 	_, err := r.db.ExecContext(ctx, `INSERT OR IGNORE INTO session_dogs (session_id, dog_id) VALUES (?, ?)`, sessionID, dogID)
 	return err
@@ -103,79 +124,482 @@ func (r *SessionsRepo) ListDogs(ctx context.Context, sessionID int64) ([]struct
 }
 
 func (r *SessionsRepo) CreateRound(ctx context.Context, ro *session.Round) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
 	var next int64 = 1
-	row := r.db.QueryRowContext(ctx, `SELECT COALESCE(MAX(round_number),0)+1 FROM rounds WHERE session_id=?`, ro.SessionID)
+	row := tx.QueryRowContext(ctx, `SELECT COALESCE(MAX(round_number),0)+1 FROM rounds WHERE session_id=?`, ro.SessionID)
 	_ = row.Scan(&next)
-	res, err := r.db.ExecContext(ctx, `INSERT INTO rounds (session_id, round_number, dog_id, exercise_id, planned_behavior_id, exhibited_behavior_id, exhibited_free_text, outcome, score, notes, started_at, ended_at) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
-		ro.SessionID, next, ro.DogID, ro.ExerciseID, ro.PlannedBehaviorID, ro.ExhibitedBehaviorID, ro.ExhibitedFreeText, ro.Outcome, ro.Score, ro.Notes, ro.StartedAt, ro.EndedAt)
+	res, err := tx.ExecContext(ctx, `INSERT INTO rounds (session_id, round_number, dog_id, exercise_id, planned_behavior_id, exhibited_behavior_id, exhibited_free_text, outcome, score, notes, started_at, ended_at, time_to_first_alert_s, time_to_indication_s, handler_id, indication_result, weighted_score, difficulty_level, `+reinforcementColumns+`, `+conditionColumns+`) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		append([]any{ro.SessionID, next, ro.DogID, ro.ExerciseID, ro.PlannedBehaviorID, ro.ExhibitedBehaviorID, ro.ExhibitedFreeText, ro.Outcome, ro.Score, ro.Notes, formatTime(ro.StartedAt), formatTime(ro.EndedAt), ro.TimeToFirstAlertS, ro.TimeToIndicationS, ro.HandlerID, ro.IndicationResult, ro.WeightedScore, ro.DifficultyLevel, ro.Reward, ro.Schedule, ro.DistractionLevel},
+			conditionArgs(ro.Conditions)...)...)
 	if err != nil {
 		return err
 	}
 	id, _ := res.LastInsertId()
+	for _, cs := range ro.CriterionScores {
+		if _, err := tx.ExecContext(ctx, `INSERT INTO round_scores (round_id, criterion_id, points) VALUES (?, ?, ?)`, id, cs.CriterionID, cs.Points); err != nil {
+			return err
+		}
+	}
+	if err := saveDistractions(ctx, tx, id, ro.Distractions); err != nil {
+		return err
+	}
+	for kind, v := range ro.Parameters {
+		if _, err := tx.ExecContext(ctx, `INSERT INTO round_parameters (round_id, kind, value) VALUES (?, ?, ?)`, id, kind, v); err != nil {
+			return err
+		}
+	}
+	if ro.AreaSearch != nil {
+		if err := saveAreaSearch(ctx, tx, id, ro.AreaSearch); err != nil {
+			return err
+		}
+	}
+	if ro.Trailing != nil {
+		if err := saveTrailing(ctx, tx, id, ro.Trailing); err != nil {
+			return err
+		}
+	}
+	if ro.Rubble != nil {
+		if err := saveRubble(ctx, tx, id, ro.Rubble); err != nil {
+			return err
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return err
+	}
 	ro.ID = id
 	ro.RoundNumber = next
 	return nil
 }
 
 func (r *SessionsRepo) ListRounds(ctx context.Context, sessionID int64) ([]*session.Round, error) {
-	rows, err := r.db.QueryContext(ctx, `SELECT id, session_id, round_number, dog_id, exercise_id, planned_behavior_id, exhibited_behavior_id, exhibited_free_text, outcome, score, notes, started_at, ended_at FROM rounds WHERE session_id=? ORDER BY round_number ASC`, sessionID)
+	out, err := r.queryRounds(ctx, `SELECT `+roundColumns+` FROM rounds WHERE session_id=? ORDER BY round_number ASC`, sessionID)
+	if err != nil {
+		return nil, err
+	}
+	return out, r.attachExtensions(ctx, out, `r.session_id = ?`, sessionID)
+}
+
+func (r *SessionsRepo) ListRoundsByDog(ctx context.Context, dogID int64) ([]*session.Round, error) {
+	out, err := r.queryRounds(ctx, `SELECT `+roundColumns+` FROM rounds WHERE dog_id=? ORDER BY session_id ASC, round_number ASC`, dogID)
+	if err != nil {
+		return nil, err
+	}
+	return out, r.attachExtensions(ctx, out, `r.dog_id = ?`, dogID)
+}
+
+func (r *SessionsRepo) GetRound(ctx context.Context, id int64) (*session.Round, error) {
+	ro, err := scanRound(r.db.QueryRowContext(ctx, `SELECT `+roundColumns+` FROM rounds WHERE id=?`, id))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, common.ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return ro, r.attachExtensions(ctx, []*session.Round{ro}, `r.id = ?`, id)
+}
+
+func (r *SessionsRepo) queryRounds(ctx context.Context, query string, args ...any) ([]*session.Round, error) {
+	rows, err := r.db.QueryContext(ctx, query, args...)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
 	var out []*session.Round
 	for rows.Next() {
-		var ro session.Round
-		if err := rows.Scan(&ro.ID, &ro.SessionID, &ro.RoundNumber, &ro.DogID, &ro.ExerciseID, &ro.PlannedBehaviorID, &ro.ExhibitedBehaviorID, &ro.ExhibitedFreeText, &ro.Outcome, &ro.Score, &ro.Notes, &ro.StartedAt, &ro.EndedAt); err != nil {
+		ro, err := scanRound(rows)
+		if err != nil {
 			return nil, err
 		}
-		out = append(out, &ro)
+		out = append(out, ro)
 	}
 	return out, rows.Err()
 }
 
-func (r *SessionsRepo) ListRoundsByDog(ctx context.Context, dogID int64) ([]*session.Round, error) {
-	rows, err := r.db.QueryContext(ctx, `SELECT id, session_id, round_number, dog_id, exercise_id, planned_behavior_id, exhibited_behavior_id, outcome, score, notes, started_at, ended_at, exhibited_free_text FROM rounds WHERE dog_id=? ORDER BY session_id ASC, round_number ASC`, dogID)
+// attachExtensions fills in the discipline-specific details of rounds
+// selected by where, a condition on rounds r and sessions s.
+func (r *SessionsRepo) attachExtensions(ctx context.Context, rounds []*session.Round, where string, args ...any) error {
+	if len(rounds) == 0 {
+		return nil
+	}
+	areas, err := r.loadAreaSearches(ctx, where, args...)
+	if err != nil {
+		return err
+	}
+	trails, err := r.loadTrailing(ctx, where, args...)
+	if err != nil {
+		return err
+	}
+	rubble, err := r.loadRubble(ctx, where, args...)
+	if err != nil {
+		return err
+	}
+	distractions, err := r.loadDistractions(ctx, where, args...)
+	if err != nil {
+		return err
+	}
+	scores, err := r.loadScores(ctx, where, args...)
+	if err != nil {
+		return err
+	}
+	params, err := r.loadParameters(ctx, where, args...)
+	if err != nil {
+		return err
+	}
+	for _, ro := range rounds {
+		ro.AreaSearch, ro.Trailing, ro.Rubble = areas[ro.ID], trails[ro.ID], rubble[ro.ID]
+		ro.Distractions, ro.CriterionScores, ro.Parameters = distractions[ro.ID], scores[ro.ID], params[ro.ID]
+	}
+	return nil
+}
+
+func (r *SessionsRepo) loadScores(ctx context.Context, where string, args ...any) (map[int64][]session.CriterionScore, error) {
+	rows, err := r.db.QueryContext(ctx, `SELECT rs.round_id, rs.criterion_id, c.name, rs.points, c.max_points, c.weight
+		FROM round_scores rs JOIN rubric_criteria c ON c.id = rs.criterion_id
+		JOIN rounds r ON r.id = rs.round_id JOIN sessions s ON s.id = r.session_id
+		WHERE `+where+` ORDER BY c.position, c.id`, args...)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	var out []*session.Round
+	out := map[int64][]session.CriterionScore{}
 	for rows.Next() {
-		var ro session.Round
-		var exhibited sql.NullInt64
-		var score sql.NullInt64
-		var notes sql.NullString
-		var sstart sql.NullString
-		var send sql.NullString
-		var free sql.NullString
-		if err := rows.Scan(&ro.ID, &ro.SessionID, &ro.RoundNumber, &ro.DogID, &ro.ExerciseID, &ro.PlannedBehaviorID, &exhibited, &ro.Outcome, &score, &notes, &sstart, &send, &free); err != nil {
+		var id int64
+		var cs session.CriterionScore
+		if err := rows.Scan(&id, &cs.CriterionID, &cs.Name, &cs.Points, &cs.MaxPoints, &cs.Weight); err != nil {
 			return nil, err
 		}
-		if exhibited.Valid {
-			v := exhibited.Int64
-			ro.ExhibitedBehaviorID = &v
+		out[id] = append(out[id], cs)
+	}
+	return out, rows.Err()
+}
+
+func (r *SessionsRepo) loadParameters(ctx context.Context, where string, args ...any) (map[int64]map[string]float64, error) {
+	rows, err := r.db.QueryContext(ctx, `SELECT rp.round_id, rp.kind, rp.value
+		FROM round_parameters rp JOIN rounds r ON r.id = rp.round_id JOIN sessions s ON s.id = r.session_id
+		WHERE `+where, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	out := map[int64]map[string]float64{}
+	for rows.Next() {
+		var id int64
+		var kind string
+		var v float64
+		if err := rows.Scan(&id, &kind, &v); err != nil {
+			return nil, err
 		}
-		if score.Valid {
-			v := int(score.Int64)
-			ro.Score = &v
+		if out[id] == nil {
+			out[id] = map[string]float64{}
 		}
-		if notes.Valid {
-			ro.Notes = &notes.String
+		out[id][kind] = v
+	}
+	return out, rows.Err()
+}
+
+const roundColumns = `id, session_id, round_number, dog_id, exercise_id, planned_behavior_id, exhibited_behavior_id, exhibited_free_text, outcome, score, notes, started_at, ended_at, time_to_first_alert_s, time_to_indication_s, handler_id, indication_result, weighted_score, difficulty_level, ` + reinforcementColumns + `, ` + conditionColumns
+
+func scanRound(row rowScanner) (*session.Round, error) {
+	var ro session.Round
+	var started, ended sql.NullString
+	dest := append([]any{&ro.ID, &ro.SessionID, &ro.RoundNumber, &ro.DogID, &ro.ExerciseID, &ro.PlannedBehaviorID, &ro.ExhibitedBehaviorID, &ro.ExhibitedFreeText, &ro.Outcome, &ro.Score, &ro.Notes,
+		&started, &ended, &ro.TimeToFirstAlertS, &ro.TimeToIndicationS, &ro.HandlerID, &ro.IndicationResult, &ro.WeightedScore, &ro.DifficultyLevel, &ro.Reward, &ro.Schedule, &ro.DistractionLevel},
+		conditionDest(&ro.Conditions)...)
+	if err := row.Scan(dest...); err != nil {
+		return nil, err
+	}
+	ro.StartedAt, ro.EndedAt = parseTime(started), parseTime(ended)
+	return &ro, nil
+}
+
+// ListConditionRounds returns round outcomes with the effective conditions,
+// each round field falling back to its session's.
+func (r *SessionsRepo) ListConditionRounds(ctx context.Context, f session.ConditionFilter) ([]*session.ConditionRound, error) {
+	query := `SELECT r.dog_id, r.planned_behavior_id, r.outcome, r.score,
+		COALESCE(r.temperature_c, s.temperature_c), COALESCE(r.humidity_pct, s.humidity_pct), COALESCE(r.wind_speed_kmh, s.wind_speed_kmh),
+		COALESCE(r.wind_direction_deg, s.wind_direction_deg), COALESCE(r.precipitation, s.precipitation), COALESCE(r.terrain, s.terrain)
+		FROM rounds r JOIN sessions s ON s.id = r.session_id WHERE 1=1`
+	args := []any{}
+	if f.DogID != nil {
+		query += ` AND r.dog_id = ?`
+		args = append(args, *f.DogID)
+	}
+	if f.BehaviorID != nil {
+		query += ` AND r.planned_behavior_id = ?`
+		args = append(args, *f.BehaviorID)
+	}
+	if f.From != nil {
+		query += ` AND COALESCE(r.started_at, s.started_at) >= ?`
+		args = append(args, f.From.Format(time.RFC3339))
+	}
+	if f.To != nil {
+		query += ` AND COALESCE(r.started_at, s.started_at) < ?`
+		args = append(args, f.To.Format(time.RFC3339))
+	}
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var out []*session.ConditionRound
+	for rows.Next() {
+		var cr session.ConditionRound
+		dest := append([]any{&cr.DogID, &cr.BehaviorID, &cr.Outcome, &cr.Score}, conditionDest(&cr.Conditions)...)
+		if err := rows.Scan(dest...); err != nil {
+			return nil, err
 		}
-		if sstart.Valid {
-			ro.StartedAt = &sstart.String
+		out = append(out, &cr)
+	}
+	return out, rows.Err()
+}
+
+func (r *SessionsRepo) ListIndicationCounts(ctx context.Context, f session.IndicationFilter) ([]*session.IndicationCount, error) {
+	query := `SELECT d.id, d.name, d.indication, r.indication_result, COUNT(*)
+		FROM rounds r JOIN sessions s ON s.id = r.session_id JOIN dogs d ON d.id = r.dog_id
+		WHERE r.indication_result IS NOT NULL`
+	args := []any{}
+	if f.DogID != nil {
+		query += ` AND r.dog_id = ?`
+		args = append(args, *f.DogID)
+	}
+	if f.From != nil {
+		query += ` AND COALESCE(r.started_at, s.started_at) >= ?`
+		args = append(args, f.From.Format(time.RFC3339))
+	}
+	if f.To != nil {
+		query += ` AND COALESCE(r.started_at, s.started_at) < ?`
+		args = append(args, f.To.Format(time.RFC3339))
+	}
+	query += ` GROUP BY d.id, r.indication_result ORDER BY d.name, d.id`
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var out []*session.IndicationCount
+	for rows.Next() {
+		var c session.IndicationCount
+		if err := rows.Scan(&c.DogID, &c.DogName, &c.Indication, &c.Result, &c.Rounds); err != nil {
+			return nil, err
 		}
-		if send.Valid {
-			ro.EndedAt = &send.String
+		out = append(out, &c)
+	}
+	return out, rows.Err()
+}
+
+// ListReinforcementRounds returns round outcomes with their reward and
+// distraction details.
+func (r *SessionsRepo) ListReinforcementRounds(ctx context.Context, f session.ReinforcementFilter) ([]*session.ReinforcementRound, error) {
+	where := `1=1`
+	args := []any{}
+	if f.DogID != nil {
+		where += ` AND r.dog_id = ?`
+		args = append(args, *f.DogID)
+	}
+	if f.BehaviorID != nil {
+		where += ` AND r.planned_behavior_id = ?`
+		args = append(args, *f.BehaviorID)
+	}
+	if f.From != nil {
+		where += ` AND COALESCE(r.started_at, s.started_at) >= ?`
+		args = append(args, f.From.Format(time.RFC3339))
+	}
+	if f.To != nil {
+		where += ` AND COALESCE(r.started_at, s.started_at) < ?`
+		args = append(args, f.To.Format(time.RFC3339))
+	}
+	rows, err := r.db.QueryContext(ctx, `SELECT r.id, r.dog_id, r.planned_behavior_id, r.outcome, r.score, r.reward, r.reinforcement_schedule, r.distraction_level
+		FROM rounds r JOIN sessions s ON s.id = r.session_id WHERE `+where, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var out []*session.ReinforcementRound
+	for rows.Next() {
+		var rr session.ReinforcementRound
+		if err := rows.Scan(&rr.RoundID, &rr.DogID, &rr.BehaviorID, &rr.Outcome, &rr.Score, &rr.Reward, &rr.Schedule, &rr.DistractionLevel); err != nil {
+			return nil, err
 		}
-		if free.Valid {
-			ro.ExhibitedFreeText = &free.String
+		out = append(out, &rr)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	distractions, err := r.loadDistractions(ctx, where, args...)
+	if err != nil {
+		return nil, err
+	}
+	for _, rr := range out {
+		rr.Distractions = distractions[rr.RoundID]
+	}
+	return out, nil
+}
+
+func (r *SessionsRepo) ListRoundTimings(ctx context.Context, f session.TimingFilter) ([]*session.RoundTiming, error) {
+	query := `SELECT r.id, r.dog_id, r.planned_behavior_id, r.exercise_id, r.outcome, r.started_at, r.ended_at, r.time_to_first_alert_s, r.time_to_indication_s
+		FROM rounds r JOIN sessions s ON s.id = r.session_id
+		WHERE ((r.started_at IS NOT NULL AND r.ended_at IS NOT NULL) OR r.time_to_first_alert_s IS NOT NULL OR r.time_to_indication_s IS NOT NULL)`
+	args := []any{}
+	for _, p := range []struct {
+		cond string
+		v    *int64
+	}{{` AND r.dog_id = ?`, f.DogID}, {` AND r.planned_behavior_id = ?`, f.BehaviorID}, {` AND r.exercise_id = ?`, f.ExerciseID}} {
+		if p.v != nil {
+			query += p.cond
+			args = append(args, *p.v)
 		}
-		out = append(out, &ro)
+	}
+	if f.From != nil {
+		query += ` AND COALESCE(r.started_at, s.started_at) >= ?`
+		args = append(args, f.From.Format(time.RFC3339))
+	}
+	if f.To != nil {
+		query += ` AND COALESCE(r.started_at, s.started_at) < ?`
+		args = append(args, f.To.Format(time.RFC3339))
+	}
+	query += ` ORDER BY r.id`
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var out []*session.RoundTiming
+	for rows.Next() {
+		var rt session.RoundTiming
+		var started, ended sql.NullString
+		if err := rows.Scan(&rt.RoundID, &rt.DogID, &rt.BehaviorID, &rt.ExerciseID, &rt.Outcome, &started, &ended, &rt.TimeToFirstAlertS, &rt.TimeToIndicationS); err != nil {
+			return nil, err
+		}
+		rt.StartedAt, rt.EndedAt = parseTime(started), parseTime(ended)
+		out = append(out, &rt)
+	}
+	return out, rows.Err()
+}
+
+func (r *SessionsRepo) ListDifficultyRounds(ctx context.Context, f session.DifficultyFilter) ([]*session.DifficultyRound, error) {
+	where := `r.dog_id = ? AND (r.difficulty_level IS NOT NULL OR EXISTS (SELECT 1 FROM round_parameters rp WHERE rp.round_id = r.id))`
+	args := []any{f.DogID}
+	if f.ExerciseID != nil {
+		where += ` AND r.exercise_id = ?`
+		args = append(args, *f.ExerciseID)
+	}
+	if f.From != nil {
+		where += ` AND COALESCE(r.started_at, s.started_at) >= ?`
+		args = append(args, f.From.Format(time.RFC3339))
+	}
+	if f.To != nil {
+		where += ` AND COALESCE(r.started_at, s.started_at) < ?`
+		args = append(args, f.To.Format(time.RFC3339))
+	}
+	rows, err := r.db.QueryContext(ctx, `SELECT r.id, r.dog_id, r.exercise_id, e.name, r.outcome, COALESCE(r.started_at, s.started_at) AS at, r.difficulty_level
+		FROM rounds r JOIN sessions s ON s.id = r.session_id JOIN exercises e ON e.id = r.exercise_id
+		WHERE `+where+` ORDER BY at, r.id`, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var out []*session.DifficultyRound
+	for rows.Next() {
+		var dr session.DifficultyRound
+		var at sql.NullString
+		if err := rows.Scan(&dr.RoundID, &dr.DogID, &dr.ExerciseID, &dr.ExerciseName, &dr.Outcome, &at, &dr.DifficultyLevel); err != nil {
+			return nil, err
+		}
+		if t := parseTime(at); t != nil {
+			dr.At = *t
+		}
+		out = append(out, &dr)
 	}
 	if err := rows.Err(); err != nil {
 		return nil, err
 	}
+	params, err := r.loadParameters(ctx, where, args...)
+	if err != nil {
+		return nil, err
+	}
+	for _, dr := range out {
+		dr.Parameters = params[dr.RoundID]
+	}
 	return out, nil
 }
+
+const reinforcementColumns = `reward, reinforcement_schedule, distraction_level`
+
+// loadDistractions returns the distraction kinds of the rounds selected by
+// where, in catalog order.
+func (r *SessionsRepo) loadDistractions(ctx context.Context, where string, args ...any) (map[int64][]session.Distraction, error) {
+	rows, err := r.db.QueryContext(ctx, `SELECT rd.round_id, rd.distraction FROM round_distractions rd
+		JOIN distractions d ON d.code = rd.distraction JOIN rounds r ON r.id = rd.round_id JOIN sessions s ON s.id = r.session_id
+		WHERE `+where+` ORDER BY rd.round_id, d.position`, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	out := map[int64][]session.Distraction{}
+	for rows.Next() {
+		var id int64
+		var d session.Distraction
+		if err := rows.Scan(&id, &d); err != nil {
+			return nil, err
+		}
+		out[id] = append(out[id], d)
+	}
+	return out, rows.Err()
+}
+
+func saveDistractions(ctx context.Context, tx execer, roundID int64, ds []session.Distraction) error {
+	for _, d := range ds {
+		if _, err := tx.ExecContext(ctx, `INSERT INTO round_distractions (round_id, distraction) VALUES (?, ?)`, roundID, d); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ReinforcementCatalog reads the reward, schedule, distraction and
+// distraction level catalogs.
+func (r *SessionsRepo) ReinforcementCatalog(ctx context.Context) (*session.ReinforcementCatalog, error) {
+	var c session.ReinforcementCatalog
+	for _, t := range []struct {
+		table string
+		dest  *[]session.CatalogEntry
+	}{{"rewards", &c.Rewards}, {"reinforcement_schedules", &c.Schedules}, {"distractions", &c.Distractions}, {"distraction_levels", &c.DistractionLevels}} {
+		entries, err := r.catalog(ctx, t.table)
+		if err != nil {
+			return nil, err
+		}
+		*t.dest = entries
+	}
+	return &c, nil
+}
+
+func (r *SessionsRepo) catalog(ctx context.Context, table string) ([]session.CatalogEntry, error) {
+	rows, err := r.db.QueryContext(ctx, `SELECT code, name FROM `+table+` ORDER BY position`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	out := []session.CatalogEntry{}
+	for rows.Next() {
+		var e session.CatalogEntry
+		if err := rows.Scan(&e.Code, &e.Name); err != nil {
+			return nil, err
+		}
+		out = append(out, e)
+	}
+	return out, rows.Err()
+}
+
+const conditionColumns = `temperature_c, humidity_pct, wind_speed_kmh, wind_direction_deg, precipitation, terrain`
+
+func conditionArgs(c session.Conditions) []any {
+	return []any{c.TemperatureC, c.HumidityPct, c.WindSpeedKmh, c.WindDirectionDeg, c.Precipitation, c.Terrain}
+}
+
+func conditionDest(c *session.Conditions) []any {
+	return []any{&c.TemperatureC, &c.HumidityPct, &c.WindSpeedKmh, &c.WindDirectionDeg, &c.Precipitation, &c.Terrain}
+}
@@ -19,8 +19,8 @@ func NewSkillsRepo(db *sql.DB) *SkillsRepo {
 }
 
 func (r *SkillsRepo) Create(ctx context.Context, s *skill.Skill) error {
-	res, err := r.db.ExecContext(ctx, `INSERT INTO skills (name, description, created_at, updated_at) VALUES (?, ?, ?, ?)`,
-		s.Name, s.Description, s.CreatedAt.Format(time.RFC3339), s.UpdatedAt.Format(time.RFC3339))
+	res, err := r.db.ExecContext(ctx, `INSERT INTO skills (name, description, discipline_id, created_at, updated_at) VALUES (?, ?, ?, ?, ?)`,
+		s.Name, s.Description, s.DisciplineID, s.CreatedAt.Format(time.RFC3339), s.UpdatedAt.Format(time.RFC3339))
 	if err != nil {
 		return err
 	}
@@ -30,10 +30,10 @@ func (r *SkillsRepo) Create(ctx context.Context, s *skill.Skill) error {
 }
 
 func (r *SkillsRepo) Get(ctx context.Context, id skill.SkillID) (*skill.Skill, error) {
-	row := r.db.QueryRowContext(ctx, `SELECT id, name, description, created_at, updated_at FROM skills WHERE id=?`, id)
+	row := r.db.QueryRowContext(ctx, `SELECT id, name, description, discipline_id, created_at, updated_at FROM skills WHERE id=?`, id)
 	var out skill.Skill
 	var c, u string
-	if err := row.Scan(&out.ID, &out.Name, &out.Description, &c, &u); err != nil {
+	if err := row.Scan(&out.ID, &out.Name, &out.Description, &out.DisciplineID, &c, &u); err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return nil, common.ErrNotFound
 		}
@@ -45,7 +45,7 @@ func (r *SkillsRepo) Get(ctx context.Context, id skill.SkillID) (*skill.Skill, e
 }
 
 func (r *SkillsRepo) List(ctx context.Context) ([]*skill.Skill, error) {
-	rows, err := r.db.QueryContext(ctx, `SELECT id, name, description, created_at, updated_at FROM skills ORDER BY id DESC`)
+	rows, err := r.db.QueryContext(ctx, `SELECT id, name, description, discipline_id, created_at, updated_at FROM skills ORDER BY id DESC`)
 	if err != nil {
 		return nil, err
 	}
@@ -54,7 +54,7 @@ func (r *SkillsRepo) List(ctx context.Context) ([]*skill.Skill, error) {
 	for rows.Next() {
 		var s skill.Skill
 		var c, u string
-		if err := rows.Scan(&s.ID, &s.Name, &s.Description, &c, &u); err != nil {
+		if err := rows.Scan(&s.ID, &s.Name, &s.Description, &s.DisciplineID, &c, &u); err != nil {
 			return nil, err
 		}
 		s.CreatedAt, _ = time.Parse(time.RFC3339, c)
@@ -65,8 +65,8 @@ func (r *SkillsRepo) List(ctx context.Context) ([]*skill.Skill, error) {
 }
 
 func (r *SkillsRepo) Update(ctx context.Context, s *skill.Skill) error {
-	_, err := r.db.ExecContext(ctx, `UPDATE skills SET name=?, description=?, updated_at=? WHERE id=?`,
-		s.Name, s.Description, s.UpdatedAt.Format(time.RFC3339), s.ID)
+	_, err := r.db.ExecContext(ctx, `UPDATE skills SET name=?, description=?, discipline_id=?, updated_at=? WHERE id=?`,
+		s.Name, s.Description, s.DisciplineID, s.UpdatedAt.Format(time.RFC3339), s.ID)
 	return err
 }
 
@@ -0,0 +1,129 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/tnosaj/sar-training/backend/internal/domain/common"
+	"github.com/tnosaj/sar-training/backend/internal/domain/track"
+	logx "github.com/tnosaj/sar-training/backend/internal/infra/log"
+)
+
+type TracksRepo struct{ db *sql.DB }
+
+func NewTracksRepo(db *sql.DB) *TracksRepo {
+	logx.Std.Trace("starting tracks repo")
+	return &TracksRepo{db: db}
+}
+
+const trackColumns = `id, session_id, round_id, kind, name, segments, created_at`
+
+// trackPoint is the stored form of a GPS fix, kept short because tracks
+// easily hold thousands of points.
+type trackPoint struct {
+	Lat  float64    `json:"y"`
+	Lon  float64    `json:"x"`
+	Ele  *float64   `json:"z,omitempty"`
+	Time *time.Time `json:"t,omitempty"`
+}
+
+func (r *TracksRepo) Create(ctx context.Context, t *track.Track) error {
+	segs, err := encodeSegments(t.Segments)
+	if err != nil {
+		return err
+	}
+	res, err := r.db.ExecContext(ctx, `INSERT INTO tracks (session_id, round_id, kind, name, segments, created_at) VALUES (?, ?, ?, ?, ?, ?)`,
+		t.SessionID, t.RoundID, t.Kind, t.Name, segs, t.CreatedAt.Format(time.RFC3339))
+	if err != nil {
+		if isForeignKeyViolation(err) {
+			return common.ErrNotFound
+		}
+		return err
+	}
+	id, _ := res.LastInsertId()
+	t.ID = track.TrackID(id)
+	return nil
+}
+
+func (r *TracksRepo) Get(ctx context.Context, id track.TrackID) (*track.Track, error) {
+	row := r.db.QueryRowContext(ctx, `SELECT `+trackColumns+` FROM tracks WHERE id=?`, id)
+	t, err := scanTrack(row)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, common.ErrNotFound
+	}
+	return t, err
+}
+
+func (r *TracksRepo) List(ctx context.Context, f track.Filter) ([]*track.Track, error) {
+	query := `SELECT ` + trackColumns + ` FROM tracks WHERE 1=1`
+	args := []any{}
+	if f.SessionID != nil {
+		query += ` AND session_id = ?`
+		args = append(args, *f.SessionID)
+	}
+	if f.RoundID != nil {
+		query += ` AND round_id = ?`
+		args = append(args, *f.RoundID)
+	}
+	rows, err := r.db.QueryContext(ctx, query+` ORDER BY id`, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var out []*track.Track
+	for rows.Next() {
+		t, err := scanTrack(rows)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, t)
+	}
+	return out, rows.Err()
+}
+
+func (r *TracksRepo) Delete(ctx context.Context, id track.TrackID) error {
+	res, err := r.db.ExecContext(ctx, `DELETE FROM tracks WHERE id=?`, id)
+	if err != nil {
+		return err
+	}
+	if rows, _ := res.RowsAffected(); rows == 0 {
+		return common.ErrNotFound
+	}
+	return nil
+}
+
+func scanTrack(row rowScanner) (*track.Track, error) {
+	var t track.Track
+	var segs, created string
+	if err := row.Scan(&t.ID, &t.SessionID, &t.RoundID, &t.Kind, &t.Name, &segs, &created); err != nil {
+		return nil, err
+	}
+	var stored [][]trackPoint
+	if err := json.Unmarshal([]byte(segs), &stored); err != nil {
+		return nil, err
+	}
+	for _, seg := range stored {
+		pts := make([]track.Point, len(seg))
+		for i, p := range seg {
+			pts[i] = track.Point{Lat: p.Lat, Lon: p.Lon, Ele: p.Ele, Time: p.Time}
+		}
+		t.Segments = append(t.Segments, pts)
+	}
+	t.CreatedAt, _ = time.Parse(time.RFC3339, created)
+	return &t, nil
+}
+
+func encodeSegments(segments [][]track.Point) (string, error) {
+	stored := make([][]trackPoint, len(segments))
+	for i, seg := range segments {
+		stored[i] = make([]trackPoint, len(seg))
+		for j, p := range seg {
+			stored[i][j] = trackPoint{Lat: p.Lat, Lon: p.Lon, Ele: p.Ele, Time: p.Time}
+		}
+	}
+	b, err := json.Marshal(stored)
+	return string(b), err
+}
@@ -0,0 +1,141 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+	"time"
+
+	"github.com/tnosaj/sar-training/backend/internal/domain/common"
+	"github.com/tnosaj/sar-training/backend/internal/domain/session"
+)
+
+// Mantrailing details live in round_trailing keyed by round; these are the
+// SessionsRepo methods reading and writing them.
+
+const trailingColumns = `t.round_id, t.trail_age_min, t.trail_length_m, t.scent_article, t.start_type, t.surfaces, t.contamination, t.reached_subject`
+
+func (r *SessionsRepo) SaveTrailing(ctx context.Context, roundID int64, t *session.Trailing) error {
+	err := saveTrailing(ctx, r.db, roundID, t)
+	if isForeignKeyViolation(err) {
+		return common.ErrNotFound
+	}
+	return err
+}
+
+func (r *SessionsRepo) GetTrailing(ctx context.Context, roundID int64) (*session.Trailing, error) {
+	m, err := r.loadTrailing(ctx, `r.id = ?`, roundID)
+	if err != nil {
+		return nil, err
+	}
+	t, ok := m[roundID]
+	if !ok {
+		return nil, common.ErrNotFound
+	}
+	return t, nil
+}
+
+func (r *SessionsRepo) DeleteTrailing(ctx context.Context, roundID int64) error {
+	res, err := r.db.ExecContext(ctx, `DELETE FROM round_trailing WHERE round_id=?`, roundID)
+	if err != nil {
+		return err
+	}
+	if rows, _ := res.RowsAffected(); rows == 0 {
+		return common.ErrNotFound
+	}
+	return nil
+}
+
+// ListTrailing returns the trailing details of the rounds matching f. A
+// round without its own start time uses its session's.
+func (r *SessionsRepo) ListTrailing(ctx context.Context, f session.TrailingFilter) ([]*session.TrailingRound, error) {
+	query := `SELECT r.dog_id, r.outcome, ` + trailingColumns + ` FROM round_trailing t
+		JOIN rounds r ON r.id = t.round_id JOIN sessions s ON s.id = r.session_id WHERE 1=1`
+	args := []any{}
+	if f.DogID != nil {
+		query += ` AND r.dog_id = ?`
+		args = append(args, *f.DogID)
+	}
+	if f.From != nil {
+		query += ` AND COALESCE(r.started_at, s.started_at) >= ?`
+		args = append(args, f.From.Format(time.RFC3339))
+	}
+	if f.To != nil {
+		query += ` AND COALESCE(r.started_at, s.started_at) <= ?`
+		args = append(args, f.To.Format(time.RFC3339))
+	}
+	rows, err := r.db.QueryContext(ctx, query+` ORDER BY r.id`, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var out []*session.TrailingRound
+	for rows.Next() {
+		var tr session.TrailingRound
+		var dogID int64
+		var outcome string
+		id, t, err := scanTrailing(rows, &dogID, &outcome)
+		if err != nil {
+			return nil, err
+		}
+		tr.RoundID, tr.DogID, tr.Outcome, tr.Trailing = id, dogID, outcome, *t
+		out = append(out, &tr)
+	}
+	return out, rows.Err()
+}
+
+func (r *SessionsRepo) loadTrailing(ctx context.Context, where string, args ...any) (map[int64]*session.Trailing, error) {
+	rows, err := r.db.QueryContext(ctx, `SELECT `+trailingColumns+` FROM round_trailing t
+		JOIN rounds r ON r.id = t.round_id JOIN sessions s ON s.id = r.session_id WHERE `+where, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	out := map[int64]*session.Trailing{}
+	for rows.Next() {
+		id, t, err := scanTrailing(rows)
+		if err != nil {
+			return nil, err
+		}
+		out[id] = t
+	}
+	return out, rows.Err()
+}
+
+// scanTrailing reads trailingColumns after any leading destinations.
+func scanTrailing(row rowScanner, lead ...any) (int64, *session.Trailing, error) {
+	var id int64
+	var t session.Trailing
+	var surfaces sql.NullString
+	var reached int
+	dest := append(lead, &id, &t.TrailAgeMin, &t.TrailLengthM, &t.ScentArticle, &t.StartType, &surfaces, &t.Contamination, &reached)
+	if err := row.Scan(dest...); err != nil {
+		return 0, nil, err
+	}
+	if surfaces.Valid && surfaces.String != "" {
+		for _, s := range strings.Split(surfaces.String, ",") {
+			t.Surfaces = append(t.Surfaces, session.Surface(s))
+		}
+	}
+	t.ReachedSubject = reached != 0
+	return id, &t, nil
+}
+
+func saveTrailing(ctx context.Context, tx execer, roundID int64, t *session.Trailing) error {
+	var surfaces *string
+	if len(t.Surfaces) > 0 {
+		parts := make([]string, len(t.Surfaces))
+		for i, s := range t.Surfaces {
+			parts[i] = string(s)
+		}
+		v := strings.Join(parts, ",")
+		surfaces = &v
+	}
+	_, err := tx.ExecContext(ctx, `INSERT INTO round_trailing (round_id, trail_age_min, trail_length_m, scent_article, start_type, surfaces, contamination, reached_subject)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT(round_id) DO UPDATE SET trail_age_min=excluded.trail_age_min, trail_length_m=excluded.trail_length_m,
+		scent_article=excluded.scent_article, start_type=excluded.start_type, surfaces=excluded.surfaces,
+		contamination=excluded.contamination, reached_subject=excluded.reached_subject`,
+		roundID, t.TrailAgeMin, t.TrailLengthM, t.ScentArticle, t.StartType, surfaces, t.Contamination, boolToInt(t.ReachedSubject))
+	return err
+}
@@ -0,0 +1,93 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/tnosaj/sar-training/backend/internal/domain/common"
+	"github.com/tnosaj/sar-training/backend/internal/domain/weather"
+	logx "github.com/tnosaj/sar-training/backend/internal/infra/log"
+)
+
+type WeatherRepo struct{ db *sql.DB }
+
+func NewWeatherRepo(db *sql.DB) *WeatherRepo {
+	logx.Std.Trace("starting weather repo")
+	return &WeatherRepo{db: db}
+}
+
+const observationColumns = `id, location, station, observed_at, temperature_c, humidity_pct, wind_speed_kmh, wind_direction_deg, precipitation, source, imported_at`
+
+func (r *WeatherRepo) Save(ctx context.Context, o *weather.Observation) (bool, error) {
+	res, err := r.db.ExecContext(ctx, `INSERT OR IGNORE INTO weather_observations (location, station, observed_at, temperature_c, humidity_pct, wind_speed_kmh, wind_direction_deg, precipitation, source, imported_at) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		o.Location, o.Station, o.ObservedAt.UTC().Format(time.RFC3339), o.TemperatureC, o.HumidityPct, o.WindSpeedKmh, o.WindDirectionDeg,
+		o.Precipitation, o.Source, o.ImportedAt.Format(time.RFC3339))
+	if err != nil {
+		return false, err
+	}
+	if rows, _ := res.RowsAffected(); rows == 0 {
+		return false, nil
+	}
+	id, _ := res.LastInsertId()
+	o.ID = weather.ObservationID(id)
+	return true, nil
+}
+
+func (r *WeatherRepo) List(ctx context.Context, f weather.Filter) ([]*weather.Observation, error) {
+	query := `SELECT ` + observationColumns + ` FROM weather_observations WHERE 1=1`
+	args := []any{}
+	if f.Location != nil {
+		query += ` AND location = ?`
+		args = append(args, *f.Location)
+	}
+	if f.From != nil {
+		query += ` AND observed_at >= ?`
+		args = append(args, f.From.UTC().Format(time.RFC3339))
+	}
+	if f.To != nil {
+		query += ` AND observed_at < ?`
+		args = append(args, f.To.UTC().Format(time.RFC3339))
+	}
+	query += ` ORDER BY observed_at ASC, id ASC`
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var out []*weather.Observation
+	for rows.Next() {
+		o, err := scanObservation(rows)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, o)
+	}
+	return out, rows.Err()
+}
+
+func (r *WeatherRepo) Nearest(ctx context.Context, location string, t time.Time, maxGap time.Duration) (*weather.Observation, error) {
+	at := t.UTC()
+	row := r.db.QueryRowContext(ctx, `SELECT `+observationColumns+` FROM weather_observations
+		WHERE location = ? AND observed_at BETWEEN ? AND ?
+		ORDER BY ABS(julianday(observed_at) - julianday(?)) ASC, observed_at ASC LIMIT 1`,
+		location, at.Add(-maxGap).Format(time.RFC3339), at.Add(maxGap).Format(time.RFC3339), at.Format(time.RFC3339))
+	o, err := scanObservation(row)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, common.ErrNotFound
+	}
+	return o, err
+}
+
+func scanObservation(row rowScanner) (*weather.Observation, error) {
+	var o weather.Observation
+	var observed, imported string
+	if err := row.Scan(&o.ID, &o.Location, &o.Station, &observed, &o.TemperatureC, &o.HumidityPct, &o.WindSpeedKmh,
+		&o.WindDirectionDeg, &o.Precipitation, &o.Source, &imported); err != nil {
+		return nil, err
+	}
+	o.ObservedAt, _ = time.Parse(time.RFC3339, observed)
+	o.ImportedAt, _ = time.Parse(time.RFC3339, imported)
+	return &o, nil
+}
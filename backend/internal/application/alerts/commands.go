@@ -0,0 +1,17 @@
+package alerts
+
+type ListAlertsQuery struct {
+	DogID      *int64
+	BehaviorID *int64
+	Status     *string
+}
+
+type AcknowledgeAlertCommand struct {
+	ID     int64 `json:"-"`
+	UserID int64 `json:"-"`
+}
+
+type DismissAlertCommand struct {
+	ID     int64 `json:"-"`
+	UserID int64 `json:"-"`
+}
@@ -0,0 +1,157 @@
+package alerts
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/tnosaj/sar-training/backend/internal/application/dto"
+	"github.com/tnosaj/sar-training/backend/internal/domain/alert"
+	"github.com/tnosaj/sar-training/backend/internal/domain/common"
+	logx "github.com/tnosaj/sar-training/backend/internal/infra/log"
+)
+
+// lookback bounds how far back rounds are considered when fitting trends.
+const lookback = 180 * 24 * time.Hour
+
+type Service struct {
+	repo       alert.Repository
+	thresholds alert.Thresholds
+}
+
+func NewService(r alert.Repository, t alert.Thresholds) *Service {
+	logx.Std.Trace("starting alerts service")
+	return &Service{repo: r, thresholds: t}
+}
+
+// Run analyses trends once immediately and then on every tick until ctx is
+// cancelled. It is meant to be started in its own goroutine.
+func (s *Service) Run(ctx context.Context, interval time.Duration) {
+	logx.Std.Infof("alert analysis every %s", interval)
+	t := time.NewTicker(interval)
+	defer t.Stop()
+	for {
+		if n, err := s.Analyze(ctx); err != nil {
+			logx.Std.Errorf("alert analysis failed: %s", err)
+		} else if n > 0 {
+			logx.Std.Infof("alert analysis raised %d alerts", n)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-t.C:
+		}
+	}
+}
+
+// Analyze fits trends for every dog and behavior and stores new alerts. An
+// alert is only raised again for the same dog, behavior, kind and metric
+// once its whole window consists of rounds newer than the previous alert's.
+func (s *Service) Analyze(ctx context.Context) (int, error) {
+	logx.Std.Trace("analyze alerts")
+	now := time.Now().UTC()
+	obs, err := s.repo.ListObservations(ctx, now.Add(-lookback))
+	if err != nil {
+		return 0, err
+	}
+	created := 0
+	for start := 0; start < len(obs); {
+		end := start
+		for end < len(obs) && obs[end].DogID == obs[start].DogID && obs[end].BehaviorID == obs[start].BehaviorID {
+			end++
+		}
+		for _, a := range alert.Analyze(obs[start:end], s.thresholds) {
+			prev, err := s.repo.Latest(ctx, a.DogID, a.BehaviorID, a.Kind, a.Metric)
+			if err != nil && !errors.Is(err, common.ErrNotFound) {
+				return created, err
+			}
+			if !a.Supersedes(prev) {
+				continue
+			}
+			a.Status = alert.StatusOpen
+			a.CreatedAt, a.UpdatedAt = now, now
+			if err := s.repo.Create(ctx, a); err != nil {
+				return created, err
+			}
+			created++
+		}
+		start = end
+	}
+	return created, nil
+}
+
+func (s *Service) List(ctx context.Context, q ListAlertsQuery) ([]*dto.Alert, error) {
+	logx.Std.Tracef("list alerts %v", q)
+	f := alert.Filter{DogID: q.DogID, BehaviorID: q.BehaviorID}
+	if q.Status != nil {
+		st := alert.Status(*q.Status)
+		if st != alert.StatusOpen && st != alert.StatusAcknowledged && st != alert.StatusDismissed {
+			return nil, common.ErrValidation
+		}
+		f.Status = &st
+	}
+	items, err := s.repo.List(ctx, f)
+	if err != nil {
+		logx.Std.Errorf("list alerts failed: %s", err)
+		return nil, err
+	}
+	out := make([]*dto.Alert, 0, len(items))
+	for _, it := range items {
+		out = append(out, toDTO(it))
+	}
+	return out, nil
+}
+
+func (s *Service) Acknowledge(ctx context.Context, cmd AcknowledgeAlertCommand) (*dto.Alert, error) {
+	logx.Std.Tracef("acknowledge alert %v", cmd)
+	return s.transition(ctx, cmd.ID, cmd.UserID, alert.StatusAcknowledged)
+}
+
+func (s *Service) Dismiss(ctx context.Context, cmd DismissAlertCommand) (*dto.Alert, error) {
+	logx.Std.Tracef("dismiss alert %v", cmd)
+	return s.transition(ctx, cmd.ID, cmd.UserID, alert.StatusDismissed)
+}
+
+func (s *Service) transition(ctx context.Context, id, userID int64, to alert.Status) (*dto.Alert, error) {
+	if id <= 0 {
+		return nil, common.ErrValidation
+	}
+	a, err := s.repo.Get(ctx, alert.AlertID(id))
+	if err != nil {
+		return nil, err
+	}
+	switch {
+	case a.Status == alert.StatusDismissed:
+		return nil, common.ErrConflict
+	case a.Status == to:
+		return toDTO(a), nil
+	}
+	now := time.Now().UTC()
+	a.Status = to
+	a.UpdatedAt = now
+	if a.AcknowledgedBy == nil && userID > 0 {
+		a.AcknowledgedBy = &userID
+		a.AcknowledgedAt = &now
+	}
+	if err := s.repo.UpdateStatus(ctx, a); err != nil {
+		logx.Std.Errorf("update alert failed: %s", err)
+		return nil, err
+	}
+	return toDTO(a), nil
+}
+
+func toDTO(a *alert.Alert) *dto.Alert {
+	out := &dto.Alert{
+		ID: int64(a.ID), DogID: a.DogID, BehaviorID: a.BehaviorID, Kind: string(a.Kind), Metric: string(a.Metric),
+		Status: string(a.Status), Baseline: a.Baseline, Recent: a.Recent, Slope: a.Slope, PValue: a.PValue,
+		Samples: a.Samples, Message: a.Message,
+		WindowStart: a.WindowStart.Format(time.RFC3339), WindowEnd: a.WindowEnd.Format(time.RFC3339),
+		CreatedAt: a.CreatedAt.Format(time.RFC3339), UpdatedAt: a.UpdatedAt.Format(time.RFC3339),
+		AcknowledgedBy: a.AcknowledgedBy,
+	}
+	if a.AcknowledgedAt != nil {
+		v := a.AcknowledgedAt.Format(time.RFC3339)
+		out.AcknowledgedAt = &v
+	}
+	return out
+}
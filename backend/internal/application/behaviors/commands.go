@@ -7,17 +7,21 @@ type CreateBehaviorCommand struct {
 }
 
 type UpdateBehaviorCommand struct {
+	ID          int64   `json:"id"`
 	SkillID     int64   `json:"skill_id"`
 	Name        string  `json:"name"`
 	Description *string `json:"description,omitempty"`
 }
 
-type DeleteBehaviorCommand struct {
-	SkillID     int64   `json:"skill_id"`
-	Name        string  `json:"name"`
-	Description *string `json:"description,omitempty"`
+type ListBehaviorsQuery struct {
+	SkillID    *int64
+	ExerciseID *int64
 }
 
-type ListBehaviorsQuery struct {
-	SkillID *int64
+// PromoteCommand links rounds logged with one of FreeTexts to an existing
+// behavior (BehaviorID) or to a new one (Behavior).
+type PromoteCommand struct {
+	FreeTexts  []string               `json:"free_texts"`
+	BehaviorID *int64                 `json:"behavior_id,omitempty"`
+	Behavior   *CreateBehaviorCommand `json:"behavior,omitempty"`
 }
@@ -0,0 +1,69 @@
+package behaviors
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/tnosaj/sar-training/backend/internal/application/dto"
+	"github.com/tnosaj/sar-training/backend/internal/domain/behavior"
+	"github.com/tnosaj/sar-training/backend/internal/domain/common"
+	logx "github.com/tnosaj/sar-training/backend/internal/infra/log"
+)
+
+// FreeTexts lists the exhibited behaviors logged as text and not yet
+// promoted, most frequent first.
+func (s *Service) FreeTexts(ctx context.Context) ([]dto.FreeTextBehavior, error) {
+	logx.Std.Trace("list free-text behaviors")
+	items, err := s.repo.FreeTexts(ctx)
+	if err != nil {
+		logx.Std.Errorf("list free-text behaviors failed: %s", err)
+		return nil, err
+	}
+	out := make([]dto.FreeTextBehavior, 0, len(items))
+	for _, f := range items {
+		out = append(out, dto.FreeTextBehavior{
+			Text: f.Text, Rounds: f.Rounds, Dogs: f.Dogs, FirstSeen: f.FirstSeen, LastSeen: f.LastSeen, MatchBehaviorID: (*int64)(f.MatchID),
+		})
+	}
+	return out, nil
+}
+
+// Promote turns free-text entries into a behavior: the matching rounds get
+// it as their exhibited behavior, keeping the text as logged.
+func (s *Service) Promote(ctx context.Context, cmd PromoteCommand) (*dto.Promotion, error) {
+	logx.Std.Tracef("promote free text %v", cmd)
+	texts := make([]string, 0, len(cmd.FreeTexts))
+	for _, t := range cmd.FreeTexts {
+		if t = strings.TrimSpace(t); t != "" {
+			texts = append(texts, t)
+		}
+	}
+	if len(texts) == 0 || (cmd.BehaviorID == nil) == (cmd.Behavior == nil) {
+		return nil, common.ErrValidation
+	}
+	var b *behavior.Behavior
+	if cmd.BehaviorID != nil {
+		var err error
+		if b, err = s.repo.Get(ctx, behavior.BehaviorID(*cmd.BehaviorID)); err != nil {
+			if err == common.ErrNotFound {
+				return nil, common.ErrValidation
+			}
+			return nil, err
+		}
+	} else {
+		if cmd.Behavior.SkillID <= 0 || cmd.Behavior.Name == "" {
+			return nil, common.ErrValidation
+		}
+		now := time.Now().UTC()
+		b = &behavior.Behavior{SkillID: cmd.Behavior.SkillID, Name: cmd.Behavior.Name, Description: cmd.Behavior.Description, CreatedAt: now, UpdatedAt: now}
+	}
+	n, err := s.repo.Promote(ctx, texts, b)
+	if err != nil {
+		if err != common.ErrValidation && err != common.ErrNotFound {
+			logx.Std.Errorf("promote free text failed: %s", err)
+		}
+		return nil, err
+	}
+	return &dto.Promotion{Behavior: *toDTO(b), Created: cmd.BehaviorID == nil, RoundsUpdated: n}, nil
+}
@@ -31,37 +31,47 @@ func (s *Service) Create(ctx context.Context, cmd CreateBehaviorCommand) (*dto.B
 	return toDTO(b), nil
 }
 
+func (s *Service) Get(ctx context.Context, id int64) (*dto.Behavior, error) {
+	logx.Std.Tracef("get behavior %d", id)
+	b, err := s.repo.Get(ctx, behavior.BehaviorID(id))
+	if err != nil {
+		return nil, err
+	}
+	return toDTO(b), nil
+}
+
 func (s *Service) Update(ctx context.Context, cmd UpdateBehaviorCommand) (*dto.Behavior, error) {
 	logx.Std.Tracef("update behavior %v", cmd)
-	if cmd.SkillID <= 0 || cmd.Name == "" {
+	if cmd.ID <= 0 || cmd.SkillID <= 0 || cmd.Name == "" {
 		return nil, common.ErrValidation
 	}
-	now := time.Now().UTC()
-	b := &behavior.Behavior{SkillID: cmd.SkillID, Name: cmd.Name, Description: cmd.Description, CreatedAt: now, UpdatedAt: now}
-	if err := s.repo.Create(ctx, b); err != nil {
-		logx.Std.Errorf("update behavior failed: %s", err)
+	b, err := s.repo.Get(ctx, behavior.BehaviorID(cmd.ID))
+	if err != nil {
+		return nil, err
+	}
+	b.SkillID, b.Name, b.Description = cmd.SkillID, cmd.Name, cmd.Description
+	b.UpdatedAt = time.Now().UTC()
+	if err := s.repo.Update(ctx, b); err != nil {
+		if err != common.ErrValidation {
+			logx.Std.Errorf("update behavior failed: %s", err)
+		}
 		return nil, err
 	}
 	return toDTO(b), nil
 }
 
-func (s *Service) Delete(ctx context.Context, cmd DeleteBehaviorCommand) (*dto.Behavior, error) {
-	logx.Std.Tracef("delete behavior %v", cmd)
-	if cmd.SkillID <= 0 || cmd.Name == "" {
-		return nil, common.ErrValidation
-	}
-	now := time.Now().UTC()
-	b := &behavior.Behavior{SkillID: cmd.SkillID, Name: cmd.Name, Description: cmd.Description, CreatedAt: now, UpdatedAt: now}
-	if err := s.repo.Create(ctx, b); err != nil {
+func (s *Service) Delete(ctx context.Context, id int64) error {
+	logx.Std.Tracef("delete behavior %d", id)
+	err := s.repo.Delete(ctx, behavior.BehaviorID(id))
+	if err != nil && err != common.ErrNotFound && err != common.ErrConflict {
 		logx.Std.Errorf("delete behavior failed: %s", err)
-		return nil, err
 	}
-	return toDTO(b), nil
+	return err
 }
 
 func (s *Service) List(ctx context.Context, q ListBehaviorsQuery) ([]*dto.Behavior, error) {
 	logx.Std.Tracef("list behavior %v", q)
-	items, err := s.repo.List(ctx, q.SkillID)
+	items, err := s.repo.List(ctx, behavior.ListFilter{SkillID: q.SkillID, ExerciseID: q.ExerciseID})
 	if err != nil {
 		logx.Std.Errorf("list behaviors failed: %s", err)
 		return nil, err
@@ -0,0 +1,21 @@
+package curriculum
+
+type AddPrerequisiteCommand struct {
+	BehaviorID int64 `json:"-"`
+	RequiresID int64 `json:"requires_id"`
+}
+
+// SetCriteriaCommand sets the mastery criteria of a behavior; omitted
+// fields take the defaults.
+type SetCriteriaCommand struct {
+	BehaviorID     int64    `json:"-"`
+	MinRounds      *int     `json:"min_rounds,omitempty"`
+	MinSuccessRate *float64 `json:"min_success_rate,omitempty"`
+	MinScore       *float64 `json:"min_score,omitempty"`
+	WindowDays     *int     `json:"window_days,omitempty"`
+}
+
+type CurriculumQuery struct {
+	DogID   int64
+	SkillID *int64
+}
@@ -0,0 +1,278 @@
+package curriculum
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/tnosaj/sar-training/backend/internal/application/dto"
+	"github.com/tnosaj/sar-training/backend/internal/domain/behavior"
+	"github.com/tnosaj/sar-training/backend/internal/domain/common"
+	"github.com/tnosaj/sar-training/backend/internal/domain/dog"
+	"github.com/tnosaj/sar-training/backend/internal/domain/mastery"
+	logx "github.com/tnosaj/sar-training/backend/internal/infra/log"
+)
+
+type Service struct {
+	repo      mastery.Repository
+	behaviors behavior.Repository
+	dogs      dog.Repository
+}
+
+func NewService(r mastery.Repository, b behavior.Repository, d dog.Repository) *Service {
+	logx.Std.Trace("starting curriculum service")
+	return &Service{repo: r, behaviors: b, dogs: d}
+}
+
+// Prerequisites lists what the behavior requires and what requires it.
+func (s *Service) Prerequisites(ctx context.Context, behaviorID int64) (*dto.Prerequisites, error) {
+	logx.Std.Tracef("list prerequisites of behavior %d", behaviorID)
+	if _, err := s.behaviors.Get(ctx, behavior.BehaviorID(behaviorID)); err != nil {
+		return nil, err
+	}
+	edges, err := s.repo.Prerequisites(ctx)
+	if err != nil {
+		logx.Std.Errorf("list prerequisites failed: %s", err)
+		return nil, err
+	}
+	names, err := s.names(ctx)
+	if err != nil {
+		return nil, err
+	}
+	out := &dto.Prerequisites{BehaviorID: behaviorID, Requires: []dto.BehaviorRef{}, RequiredBy: []dto.BehaviorRef{}}
+	for _, e := range edges {
+		switch behaviorID {
+		case e.BehaviorID:
+			out.Requires = append(out.Requires, dto.BehaviorRef{ID: e.RequiresID, Name: names[e.RequiresID]})
+		case e.RequiresID:
+			out.RequiredBy = append(out.RequiredBy, dto.BehaviorRef{ID: e.BehaviorID, Name: names[e.BehaviorID]})
+		}
+	}
+	return out, nil
+}
+
+// AddPrerequisite makes a behavior require another; edges closing a cycle
+// are rejected with common.ErrConflict.
+func (s *Service) AddPrerequisite(ctx context.Context, cmd AddPrerequisiteCommand) (*dto.Prerequisites, error) {
+	logx.Std.Tracef("add prerequisite %v", cmd)
+	if cmd.BehaviorID <= 0 || cmd.RequiresID <= 0 {
+		return nil, common.ErrValidation
+	}
+	if _, err := s.behaviors.Get(ctx, behavior.BehaviorID(cmd.BehaviorID)); err != nil {
+		return nil, err
+	}
+	if _, err := s.behaviors.Get(ctx, behavior.BehaviorID(cmd.RequiresID)); err != nil {
+		if err == common.ErrNotFound {
+			return nil, common.ErrValidation
+		}
+		return nil, err
+	}
+	p := mastery.Prerequisite{BehaviorID: cmd.BehaviorID, RequiresID: cmd.RequiresID}
+	if err := s.repo.AddPrerequisite(ctx, p); err != nil {
+		if err != common.ErrConflict {
+			logx.Std.Errorf("add prerequisite failed: %s", err)
+		}
+		return nil, err
+	}
+	return s.Prerequisites(ctx, cmd.BehaviorID)
+}
+
+func (s *Service) RemovePrerequisite(ctx context.Context, behaviorID, requiresID int64) error {
+	logx.Std.Tracef("remove prerequisite %d of behavior %d", requiresID, behaviorID)
+	err := s.repo.RemovePrerequisite(ctx, mastery.Prerequisite{BehaviorID: behaviorID, RequiresID: requiresID})
+	if err != nil && err != common.ErrNotFound {
+		logx.Std.Errorf("remove prerequisite failed: %s", err)
+	}
+	return err
+}
+
+// Criteria returns the mastery criteria in effect for the behavior.
+func (s *Service) Criteria(ctx context.Context, behaviorID int64) (*dto.MasteryCriteria, error) {
+	logx.Std.Tracef("get mastery criteria of behavior %d", behaviorID)
+	if _, err := s.behaviors.Get(ctx, behavior.BehaviorID(behaviorID)); err != nil {
+		return nil, err
+	}
+	all, err := s.repo.Criteria(ctx)
+	if err != nil {
+		logx.Std.Errorf("get mastery criteria failed: %s", err)
+		return nil, err
+	}
+	c, ok := all[behaviorID]
+	out := toCriteriaDTO(behaviorID, c, !ok)
+	return &out, nil
+}
+
+func (s *Service) SetCriteria(ctx context.Context, cmd SetCriteriaCommand) (*dto.MasteryCriteria, error) {
+	logx.Std.Tracef("set mastery criteria %v", cmd)
+	if _, err := s.behaviors.Get(ctx, behavior.BehaviorID(cmd.BehaviorID)); err != nil {
+		return nil, err
+	}
+	c := mastery.DefaultCriteria
+	if cmd.MinRounds != nil {
+		c.MinRounds = *cmd.MinRounds
+	}
+	if cmd.MinSuccessRate != nil {
+		c.MinSuccessRate = *cmd.MinSuccessRate
+	}
+	if cmd.WindowDays != nil {
+		c.WindowDays = *cmd.WindowDays
+	}
+	c.MinScore = cmd.MinScore
+	if c.MinRounds <= 0 || c.MinSuccessRate < 0 || c.MinSuccessRate > 1 || c.WindowDays <= 0 {
+		return nil, common.ErrValidation
+	}
+	if c.MinScore != nil && (*c.MinScore < 0 || *c.MinScore > 10) {
+		return nil, common.ErrValidation
+	}
+	if err := s.repo.SetCriteria(ctx, cmd.BehaviorID, c); err != nil {
+		if err != common.ErrNotFound {
+			logx.Std.Errorf("set mastery criteria failed: %s", err)
+		}
+		return nil, err
+	}
+	out := toCriteriaDTO(cmd.BehaviorID, c, false)
+	return &out, nil
+}
+
+// DeleteCriteria puts the behavior back on the default criteria.
+func (s *Service) DeleteCriteria(ctx context.Context, behaviorID int64) error {
+	logx.Std.Tracef("delete mastery criteria of behavior %d", behaviorID)
+	err := s.repo.DeleteCriteria(ctx, behaviorID)
+	if err != nil && err != common.ErrNotFound {
+		logx.Std.Errorf("delete mastery criteria failed: %s", err)
+	}
+	return err
+}
+
+// Curriculum places every behavior for the dog: mastered by the criteria
+// over its recent rounds, locked behind unmastered prerequisites, in progress
+// or unlocked. Behaviors come after their prerequisites.
+func (s *Service) Curriculum(ctx context.Context, q CurriculumQuery) (*dto.Curriculum, error) {
+	logx.Std.Tracef("curriculum %v", q)
+	if _, err := s.dogs.Get(ctx, dog.DogID(q.DogID)); err != nil {
+		return nil, err
+	}
+	behaviors, err := s.behaviors.List(ctx, behavior.ListFilter{})
+	if err != nil {
+		logx.Std.Errorf("list behaviors failed: %s", err)
+		return nil, err
+	}
+	edges, err := s.repo.Prerequisites(ctx)
+	if err != nil {
+		logx.Std.Errorf("list prerequisites failed: %s", err)
+		return nil, err
+	}
+	criteria, err := s.repo.Criteria(ctx)
+	if err != nil {
+		logx.Std.Errorf("get mastery criteria failed: %s", err)
+		return nil, err
+	}
+	window := mastery.DefaultCriteria.WindowDays
+	for _, c := range criteria {
+		if c.WindowDays > window {
+			window = c.WindowDays
+		}
+	}
+	now := time.Now().UTC()
+	rounds, err := s.repo.Rounds(ctx, q.DogID, now.AddDate(0, 0, -window))
+	if err != nil {
+		logx.Std.Errorf("list curriculum rounds failed: %s", err)
+		return nil, err
+	}
+	byBehavior := map[int64][]mastery.RoundResult{}
+	for _, r := range rounds {
+		byBehavior[r.BehaviorID] = append(byBehavior[r.BehaviorID], r)
+	}
+
+	sort.Slice(behaviors, func(i, j int) bool {
+		if behaviors[i].SkillID != behaviors[j].SkillID {
+			return behaviors[i].SkillID < behaviors[j].SkillID
+		}
+		return behaviors[i].ID < behaviors[j].ID
+	})
+	ids := make([]int64, 0, len(behaviors))
+	byID := map[int64]*behavior.Behavior{}
+	assessments := map[int64]mastery.Assessment{}
+	for _, b := range behaviors {
+		id := int64(b.ID)
+		ids = append(ids, id)
+		byID[id] = b
+		c, ok := criteria[id]
+		if !ok {
+			c = mastery.DefaultCriteria
+		}
+		since := now.AddDate(0, 0, -c.WindowDays)
+		var recent []mastery.RoundResult
+		for _, r := range byBehavior[id] {
+			if !r.At.Before(since) {
+				recent = append(recent, r)
+			}
+		}
+		assessments[id] = c.Assess(recent)
+	}
+	requires := map[int64][]int64{}
+	for _, e := range edges {
+		requires[e.BehaviorID] = append(requires[e.BehaviorID], e.RequiresID)
+	}
+
+	out := &dto.Curriculum{DogID: q.DogID, Behaviors: []dto.CurriculumBehavior{}, EvaluatedAt: now.Format(time.RFC3339)}
+	for _, id := range mastery.Order(ids, edges) {
+		b := byID[id]
+		if q.SkillID != nil && b.SkillID != *q.SkillID {
+			continue
+		}
+		a := assessments[id]
+		item := dto.CurriculumBehavior{
+			BehaviorID: id, SkillID: b.SkillID, Name: b.Name, Requires: []int64{},
+			Rounds: a.Rounds, SuccessRate: a.SuccessRate, MeanScore: a.MeanScore,
+		}
+		for _, req := range requires[id] {
+			item.Requires = append(item.Requires, req)
+			if !assessments[req].Mastered {
+				item.BlockedBy = append(item.BlockedBy, req)
+			}
+		}
+		c, ok := criteria[id]
+		if !ok {
+			c = mastery.DefaultCriteria
+		}
+		item.Criteria = toCriteriaDTO(id, c, !ok)
+		state := mastery.StateOf(a, len(item.BlockedBy) == 0)
+		item.State = string(state)
+		switch state {
+		case mastery.StateMastered:
+			out.Mastered++
+		case mastery.StateInProgress:
+			out.InProgress++
+		case mastery.StateUnlocked:
+			out.Unlocked++
+		case mastery.StateLocked:
+			out.Locked++
+		}
+		out.Behaviors = append(out.Behaviors, item)
+	}
+	return out, nil
+}
+
+func (s *Service) names(ctx context.Context) (map[int64]string, error) {
+	items, err := s.behaviors.List(ctx, behavior.ListFilter{})
+	if err != nil {
+		logx.Std.Errorf("list behaviors failed: %s", err)
+		return nil, err
+	}
+	out := make(map[int64]string, len(items))
+	for _, b := range items {
+		out[int64(b.ID)] = b.Name
+	}
+	return out, nil
+}
+
+func toCriteriaDTO(behaviorID int64, c mastery.Criteria, isDefault bool) dto.MasteryCriteria {
+	if isDefault {
+		c = mastery.DefaultCriteria
+	}
+	return dto.MasteryCriteria{
+		BehaviorID: behaviorID, MinRounds: c.MinRounds, MinSuccessRate: c.MinSuccessRate, MinScore: c.MinScore,
+		WindowDays: c.WindowDays, Default: isDefault,
+	}
+}
@@ -0,0 +1,14 @@
+package disciplines
+
+type CreateDisciplineCommand struct {
+	Name            string   `json:"name"`
+	Description     *string  `json:"description,omitempty"`
+	RoundExtensions []string `json:"round_extensions"`
+}
+
+type UpdateDisciplineCommand struct {
+	ID              int64    `json:"-"`
+	Name            string   `json:"name"`
+	Description     *string  `json:"description,omitempty"`
+	RoundExtensions []string `json:"round_extensions"`
+}
@@ -0,0 +1,113 @@
+package disciplines
+
+import (
+	"context"
+	"time"
+
+	"github.com/tnosaj/sar-training/backend/internal/application/dto"
+	"github.com/tnosaj/sar-training/backend/internal/domain/common"
+	"github.com/tnosaj/sar-training/backend/internal/domain/discipline"
+	logx "github.com/tnosaj/sar-training/backend/internal/infra/log"
+)
+
+type Service struct{ repo discipline.Repository }
+
+func NewService(r discipline.Repository) *Service {
+	logx.Std.Trace("starting disciplines service")
+	return &Service{repo: r}
+}
+
+func (s *Service) Create(ctx context.Context, cmd CreateDisciplineCommand) (*dto.Discipline, error) {
+	logx.Std.Tracef("create discipline %v", cmd)
+	now := time.Now().UTC()
+	d := &discipline.Discipline{Description: cmd.Description, CreatedAt: now, UpdatedAt: now}
+	if err := apply(d, cmd.Name, cmd.RoundExtensions); err != nil {
+		return nil, err
+	}
+	if err := s.repo.Create(ctx, d); err != nil {
+		logx.Std.Errorf("create discipline failed: %s", err)
+		return nil, err
+	}
+	return toDTO(d), nil
+}
+
+func (s *Service) Get(ctx context.Context, id int64) (*dto.Discipline, error) {
+	logx.Std.Tracef("get discipline %d", id)
+	d, err := s.repo.Get(ctx, discipline.DisciplineID(id))
+	if err != nil {
+		return nil, err
+	}
+	return toDTO(d), nil
+}
+
+func (s *Service) List(ctx context.Context) ([]*dto.Discipline, error) {
+	logx.Std.Trace("list disciplines")
+	items, err := s.repo.List(ctx)
+	if err != nil {
+		logx.Std.Errorf("list disciplines failed: %s", err)
+		return nil, err
+	}
+	out := make([]*dto.Discipline, 0, len(items))
+	for _, it := range items {
+		out = append(out, toDTO(it))
+	}
+	return out, nil
+}
+
+func (s *Service) Update(ctx context.Context, cmd UpdateDisciplineCommand) (*dto.Discipline, error) {
+	logx.Std.Tracef("update discipline %v", cmd)
+	d, err := s.repo.Get(ctx, discipline.DisciplineID(cmd.ID))
+	if err != nil {
+		return nil, err
+	}
+	if err := apply(d, cmd.Name, cmd.RoundExtensions); err != nil {
+		return nil, err
+	}
+	d.Description, d.UpdatedAt = cmd.Description, time.Now().UTC()
+	if err := s.repo.Update(ctx, d); err != nil {
+		logx.Std.Errorf("update discipline failed: %s", err)
+		return nil, err
+	}
+	return toDTO(d), nil
+}
+
+// Delete removes the discipline. Its skills and qualifications are kept
+// without discipline.
+func (s *Service) Delete(ctx context.Context, id int64) error {
+	logx.Std.Tracef("delete discipline %d", id)
+	err := s.repo.Delete(ctx, discipline.DisciplineID(id))
+	if err != nil && err != common.ErrNotFound {
+		logx.Std.Errorf("delete discipline failed: %s", err)
+	}
+	return err
+}
+
+func apply(d *discipline.Discipline, name string, extensions []string) error {
+	if name == "" {
+		return common.ErrValidation
+	}
+	d.Name, d.Extensions = name, nil
+	seen := map[discipline.Extension]bool{}
+	for _, v := range extensions {
+		e := discipline.Extension(v)
+		if !e.Valid() {
+			return common.ErrValidation
+		}
+		if !seen[e] {
+			seen[e] = true
+			d.Extensions = append(d.Extensions, e)
+		}
+	}
+	return nil
+}
+
+func toDTO(d *discipline.Discipline) *dto.Discipline {
+	out := &dto.Discipline{
+		ID: int64(d.ID), Name: d.Name, Description: d.Description, RoundExtensions: []string{},
+		CreatedAt: d.CreatedAt.Format(time.RFC3339), UpdatedAt: d.UpdatedAt.Format(time.RFC3339),
+	}
+	for _, e := range d.Extensions {
+		out.RoundExtensions = append(out.RoundExtensions, string(e))
+	}
+	return out
+}
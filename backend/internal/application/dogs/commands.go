@@ -1,18 +1,53 @@
 package dogs
 
 type CreateDogCommand struct {
-	Name      string  `json:"name"`
-	Callname  *string `json:"callname,omitempty"`
-	Birthdate *string `json:"birthdate,omitempty"`
+	Name               string            `json:"name"`
+	Callname           *string           `json:"callname,omitempty"`
+	Birthdate          *string           `json:"birthdate,omitempty"`
+	Breed              *string           `json:"breed,omitempty"`
+	Sex                *string           `json:"sex,omitempty"`
+	Microchip          *string           `json:"microchip,omitempty"`
+	RegistrationNumber *string           `json:"registration_number,omitempty"`
+	Owner              *string           `json:"owner,omitempty"`
+	HandlerID          *int64            `json:"handler_id,omitempty"`
+	Status             *string           `json:"status,omitempty"`
+	Indication         *string           `json:"indication,omitempty"`
+	Attributes         map[string]string `json:"attributes,omitempty"`
 }
 
 type UpdateDogCommand struct {
-	ID        int64
-	Name      string  `json:"name"`
-	Callname  *string `json:"callname,omitempty"`
-	Birthdate *string `json:"birthdate,omitempty"`
+	ID                 int64
+	Name               string            `json:"name"`
+	Callname           *string           `json:"callname,omitempty"`
+	Birthdate          *string           `json:"birthdate,omitempty"`
+	Breed              *string           `json:"breed,omitempty"`
+	Sex                *string           `json:"sex,omitempty"`
+	Microchip          *string           `json:"microchip,omitempty"`
+	RegistrationNumber *string           `json:"registration_number,omitempty"`
+	Owner              *string           `json:"owner,omitempty"`
+	HandlerID          *int64            `json:"handler_id,omitempty"`
+	Status             *string           `json:"status,omitempty"`
+	Indication         *string           `json:"indication,omitempty"`
+	Attributes         map[string]string `json:"attributes,omitempty"`
 }
 
 type DeleteDogCommand struct {
 	ID int64
 }
+
+type ListDogsQuery struct {
+	Status          *string
+	IncludeArchived bool
+	HandlerUserID   *int64
+}
+
+type SetPhotoCommand struct {
+	DogID       int64
+	ContentType string
+	Data        []byte
+}
+
+type PurgeDogCommand struct {
+	ID     int64
+	DryRun bool
+}
@@ -2,6 +2,9 @@ package dogs
 
 import (
 	"context"
+	"mime"
+	"net/http"
+	"time"
 
 	"github.com/tnosaj/sar-training/backend/internal/application/dto"
 	"github.com/tnosaj/sar-training/backend/internal/domain/common"
@@ -21,7 +24,14 @@ func (s *Service) Create(ctx context.Context, cmd CreateDogCommand) (*dto.Dog, e
 	if cmd.Name == "" {
 		return nil, common.ErrValidation
 	}
-	d := &dog.Dog{Name: cmd.Name, Callname: cmd.Callname, Birthdate: cmd.Birthdate}
+	d := &dog.Dog{
+		Name: cmd.Name, Callname: cmd.Callname, Birthdate: cmd.Birthdate, Breed: cmd.Breed, Microchip: cmd.Microchip,
+		RegistrationNumber: cmd.RegistrationNumber, Owner: cmd.Owner, HandlerID: cmd.HandlerID, Attributes: cmd.Attributes,
+		Status: dog.StatusInTraining,
+	}
+	if err := applyProfile(d, cmd.Sex, cmd.Status, cmd.Indication); err != nil {
+		return nil, err
+	}
 	if err := s.repo.Create(ctx, d); err != nil {
 		logx.Std.Errorf("create dog failed: %s", err)
 		return nil, err
@@ -29,12 +39,37 @@ func (s *Service) Create(ctx context.Context, cmd CreateDogCommand) (*dto.Dog, e
 	return toDTO(d), nil
 }
 
+// Update changes the fields the command carries and keeps all others as
+// they are, so partial edits do not reset the profile.
 func (s *Service) Update(ctx context.Context, cmd UpdateDogCommand) (*dto.Dog, error) {
 	logx.Std.Tracef("update dog %v", cmd)
-	if cmd.Name == "" {
-		return nil, common.ErrValidation
+	d, err := s.repo.Get(ctx, dog.DogID(cmd.ID))
+	if err != nil {
+		return nil, err
+	}
+	if cmd.Name != "" {
+		d.Name = cmd.Name
+	}
+	for _, f := range []struct {
+		in  *string
+		out **string
+	}{
+		{cmd.Callname, &d.Callname}, {cmd.Birthdate, &d.Birthdate}, {cmd.Breed, &d.Breed}, {cmd.Microchip, &d.Microchip},
+		{cmd.RegistrationNumber, &d.RegistrationNumber}, {cmd.Owner, &d.Owner},
+	} {
+		if f.in != nil {
+			*f.out = f.in
+		}
+	}
+	if cmd.HandlerID != nil {
+		d.HandlerID = cmd.HandlerID
+	}
+	if cmd.Attributes != nil {
+		d.Attributes = cmd.Attributes
+	}
+	if err := applyProfile(d, cmd.Sex, cmd.Status, cmd.Indication); err != nil {
+		return nil, err
 	}
-	d := &dog.Dog{ID: dog.DogID(cmd.ID), Name: cmd.Name, Callname: cmd.Callname, Birthdate: cmd.Birthdate}
 	if err := s.repo.Update(ctx, d); err != nil {
 		logx.Std.Errorf("update dog failed: %s", err)
 		return nil, err
@@ -48,7 +83,7 @@ func (s *Service) Delete(ctx context.Context, cmd DeleteDogCommand) error {
 		return common.ErrValidation
 	}
 	err := s.repo.Delete(ctx, dog.DogID(cmd.ID))
-	if err == common.ErrNotFound {
+	if err == common.ErrNotFound || err == common.ErrConflict {
 		return err
 	}
 	if err != nil {
@@ -58,9 +93,90 @@ func (s *Service) Delete(ctx context.Context, cmd DeleteDogCommand) error {
 	return nil
 }
 
-func (s *Service) List(ctx context.Context) ([]*dto.Dog, error) {
-	logx.Std.Trace("list dogs")
-	items, err := s.repo.List(ctx)
+// Archive retires the dog and hides it from active lists. Its rounds,
+// sessions and qualifications are kept.
+func (s *Service) Archive(ctx context.Context, id int64) (*dto.Dog, error) {
+	logx.Std.Tracef("archive dog %d", id)
+	d, err := s.repo.Get(ctx, dog.DogID(id))
+	if err != nil {
+		return nil, err
+	}
+	if d.ArchivedAt != nil {
+		return toDTO(d), nil
+	}
+	now := time.Now().UTC()
+	if err := s.repo.Archive(ctx, d.ID, now); err != nil {
+		logx.Std.Errorf("archive dog failed: %s", err)
+		return nil, err
+	}
+	d.Status, d.ArchivedAt = dog.StatusRetired, &now
+	return toDTO(d), nil
+}
+
+// Unarchive makes the dog visible in active lists again. The status stays
+// as it is.
+func (s *Service) Unarchive(ctx context.Context, id int64) (*dto.Dog, error) {
+	logx.Std.Tracef("unarchive dog %d", id)
+	d, err := s.repo.Get(ctx, dog.DogID(id))
+	if err != nil {
+		return nil, err
+	}
+	if err := s.repo.SetArchived(ctx, d.ID, nil); err != nil {
+		logx.Std.Errorf("unarchive dog failed: %s", err)
+		return nil, err
+	}
+	d.ArchivedAt = nil
+	return toDTO(d), nil
+}
+
+// Purge deletes the dog with all its history. With DryRun set nothing is
+// deleted and the report only counts the affected rows.
+func (s *Service) Purge(ctx context.Context, cmd PurgeDogCommand) (*dto.PurgeReport, error) {
+	logx.Std.Tracef("purge dog %v", cmd)
+	if cmd.ID <= 0 {
+		return nil, common.ErrValidation
+	}
+	if _, err := s.repo.Get(ctx, dog.DogID(cmd.ID)); err != nil {
+		return nil, err
+	}
+	var h dog.History
+	var err error
+	if cmd.DryRun {
+		h, err = s.repo.History(ctx, dog.DogID(cmd.ID))
+	} else {
+		h, err = s.repo.Purge(ctx, dog.DogID(cmd.ID))
+	}
+	if err != nil {
+		logx.Std.Errorf("purge dog failed: %s", err)
+		return nil, err
+	}
+	if !cmd.DryRun {
+		logx.Std.Warnf("purged dog %d with %d history rows", cmd.ID, h.Total())
+	}
+	h["dogs"] = 1
+	return &dto.PurgeReport{DogID: cmd.ID, DryRun: cmd.DryRun, Rows: h, Total: h.Total()}, nil
+}
+
+func (s *Service) Get(ctx context.Context, id int64) (*dto.Dog, error) {
+	logx.Std.Tracef("get dog %d", id)
+	d, err := s.repo.Get(ctx, dog.DogID(id))
+	if err != nil {
+		return nil, err
+	}
+	return toDTO(d), nil
+}
+
+func (s *Service) List(ctx context.Context, q ListDogsQuery) ([]*dto.Dog, error) {
+	logx.Std.Tracef("list dogs %v", q)
+	f := dog.Filter{IncludeArchived: q.IncludeArchived, HandlerUserID: q.HandlerUserID}
+	if q.Status != nil {
+		st := dog.Status(*q.Status)
+		if !st.Valid() {
+			return nil, common.ErrValidation
+		}
+		f.Status = &st
+	}
+	items, err := s.repo.List(ctx, f)
 	if err != nil {
 		logx.Std.Errorf("list dogs failed: %s", err)
 		return nil, err
@@ -72,6 +188,85 @@ func (s *Service) List(ctx context.Context) ([]*dto.Dog, error) {
 	return out, nil
 }
 
+func (s *Service) SetPhoto(ctx context.Context, cmd SetPhotoCommand) error {
+	logx.Std.Tracef("set photo of dog %d", cmd.DogID)
+	if cmd.DogID <= 0 || len(cmd.Data) == 0 {
+		return common.ErrValidation
+	}
+	if _, err := s.repo.Get(ctx, dog.DogID(cmd.DogID)); err != nil {
+		return err
+	}
+	// the type is sniffed from the contents; what the client claims is ignored
+	ct, _, _ := mime.ParseMediaType(http.DetectContentType(cmd.Data))
+	if !dog.PhotoTypes[ct] {
+		return common.ErrValidation
+	}
+	cmd.ContentType = ct
+	err := s.repo.SetPhoto(ctx, &dog.Photo{DogID: dog.DogID(cmd.DogID), ContentType: cmd.ContentType, Data: cmd.Data, UpdatedAt: time.Now().UTC()})
+	if err != nil {
+		logx.Std.Errorf("set dog photo failed: %s", err)
+	}
+	return err
+}
+
+func (s *Service) GetPhoto(ctx context.Context, id int64) (*dog.Photo, error) {
+	logx.Std.Tracef("get photo of dog %d", id)
+	return s.repo.GetPhoto(ctx, dog.DogID(id))
+}
+
+func (s *Service) DeletePhoto(ctx context.Context, id int64) error {
+	logx.Std.Tracef("delete photo of dog %d", id)
+	err := s.repo.DeletePhoto(ctx, dog.DogID(id))
+	if err != nil && err != common.ErrNotFound {
+		logx.Std.Errorf("delete dog photo failed: %s", err)
+	}
+	return err
+}
+
+// applyProfile sets the enumerated profile fields that are given and leaves
+// the others untouched.
+func applyProfile(d *dog.Dog, sex, status, indication *string) error {
+	if sex != nil {
+		sx := dog.Sex(*sex)
+		if sx != dog.SexMale && sx != dog.SexFemale {
+			return common.ErrValidation
+		}
+		d.Sex = &sx
+	}
+	if status != nil {
+		st := dog.Status(*status)
+		if !st.Valid() {
+			return common.ErrValidation
+		}
+		d.Status = st
+	}
+	if indication != nil {
+		in := dog.Indication(*indication)
+		if !in.Valid() {
+			return common.ErrValidation
+		}
+		d.Indication = &in
+	}
+	return nil
+}
+
 func toDTO(d *dog.Dog) *dto.Dog {
-	return &dto.Dog{ID: int64(d.ID), Name: d.Name, Callname: d.Callname, Birthdate: d.Birthdate}
+	out := &dto.Dog{
+		ID: int64(d.ID), Name: d.Name, Callname: d.Callname, Birthdate: d.Birthdate, Breed: d.Breed, Microchip: d.Microchip,
+		RegistrationNumber: d.RegistrationNumber, Owner: d.Owner, HandlerID: d.HandlerID, Status: string(d.Status),
+		Attributes: d.Attributes, HasPhoto: d.HasPhoto,
+	}
+	if d.Sex != nil {
+		v := string(*d.Sex)
+		out.Sex = &v
+	}
+	if d.Indication != nil {
+		v := string(*d.Indication)
+		out.Indication = &v
+	}
+	if d.ArchivedAt != nil {
+		v := d.ArchivedAt.Format(time.RFC3339)
+		out.ArchivedAt = &v
+	}
+	return out
 }
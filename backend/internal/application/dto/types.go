@@ -1,11 +1,12 @@
 package dto
 
 type Skill struct {
-	ID          int64   `json:"id"`
-	Name        string  `json:"name"`
-	Description *string `json:"description,omitempty"`
-	CreatedAt   string  `json:"created_at"`
-	UpdatedAt   string  `json:"updated_at"`
+	ID           int64   `json:"id"`
+	Name         string  `json:"name"`
+	Description  *string `json:"description,omitempty"`
+	DisciplineID *int64  `json:"discipline_id,omitempty"`
+	CreatedAt    string  `json:"created_at"`
+	UpdatedAt    string  `json:"updated_at"`
 }
 
 type Behavior struct {
@@ -17,6 +18,64 @@ type Behavior struct {
 	UpdatedAt   string  `json:"updated_at"`
 }
 
+type FreeTextBehavior struct {
+	Text            string `json:"text"`
+	Rounds          int    `json:"rounds"`
+	Dogs            int    `json:"dogs"`
+	FirstSeen       string `json:"first_seen"`
+	LastSeen        string `json:"last_seen"`
+	MatchBehaviorID *int64 `json:"match_behavior_id,omitempty"`
+}
+
+type Promotion struct {
+	Behavior      Behavior `json:"behavior"`
+	Created       bool     `json:"created"`
+	RoundsUpdated int64    `json:"rounds_updated"`
+}
+
+type BehaviorRef struct {
+	ID   int64  `json:"id"`
+	Name string `json:"name"`
+}
+
+type Prerequisites struct {
+	BehaviorID int64         `json:"behavior_id"`
+	Requires   []BehaviorRef `json:"requires"`
+	RequiredBy []BehaviorRef `json:"required_by"`
+}
+
+type MasteryCriteria struct {
+	BehaviorID     int64    `json:"behavior_id"`
+	MinRounds      int      `json:"min_rounds"`
+	MinSuccessRate float64  `json:"min_success_rate"`
+	MinScore       *float64 `json:"min_score,omitempty"`
+	WindowDays     int      `json:"window_days"`
+	Default        bool     `json:"default"`
+}
+
+type CurriculumBehavior struct {
+	BehaviorID  int64           `json:"behavior_id"`
+	SkillID     int64           `json:"skill_id"`
+	Name        string          `json:"name"`
+	State       string          `json:"state"`
+	Requires    []int64         `json:"requires"`
+	BlockedBy   []int64         `json:"blocked_by,omitempty"`
+	Rounds      int             `json:"rounds"`
+	SuccessRate float64         `json:"success_rate"`
+	MeanScore   *float64        `json:"mean_score,omitempty"`
+	Criteria    MasteryCriteria `json:"criteria"`
+}
+
+type Curriculum struct {
+	DogID       int64                `json:"dog_id"`
+	Mastered    int                  `json:"mastered"`
+	InProgress  int                  `json:"in_progress"`
+	Unlocked    int                  `json:"unlocked"`
+	Locked      int                  `json:"locked"`
+	Behaviors   []CurriculumBehavior `json:"behaviors"`
+	EvaluatedAt string               `json:"evaluated_at"`
+}
+
 type Exercise struct {
 	ID          int64   `json:"id"`
 	Name        string  `json:"name"`
@@ -25,19 +84,86 @@ type Exercise struct {
 	UpdatedAt   string  `json:"updated_at"`
 }
 
+type BehaviorExercise struct {
+	BehaviorID   int64  `json:"behavior_id"`
+	BehaviorName string `json:"behavior_name"`
+	ExerciseID   int64  `json:"exercise_id"`
+	ExerciseName string `json:"exercise_name"`
+	Strength     int    `json:"strength"`
+}
+
+type RubricCriterion struct {
+	ID          int64   `json:"id"`
+	Name        string  `json:"name"`
+	Description *string `json:"description,omitempty"`
+	Weight      float64 `json:"weight"`
+	MaxPoints   int     `json:"max_points"`
+	Position    int     `json:"position"`
+}
+
+type ExerciseParameter struct {
+	Kind string   `json:"kind"`
+	Min  *float64 `json:"min,omitempty"`
+	Max  *float64 `json:"max,omitempty"`
+}
+
+type ExerciseLevel struct {
+	Level       int                `json:"level"`
+	Name        string             `json:"name"`
+	Description *string            `json:"description,omitempty"`
+	Values      map[string]float64 `json:"values"`
+}
+
+type ExerciseDifficulty struct {
+	ExerciseID int64               `json:"exercise_id"`
+	Parameters []ExerciseParameter `json:"parameters"`
+	Levels     []ExerciseLevel     `json:"levels"`
+}
+
+type CriterionScore struct {
+	CriterionID int64   `json:"criterion_id"`
+	Name        string  `json:"name,omitempty"`
+	Points      int     `json:"points"`
+	MaxPoints   int     `json:"max_points,omitempty"`
+	Weight      float64 `json:"weight,omitempty"`
+}
+
 type Dog struct {
-	ID        int64   `json:"id"`
-	Name      string  `json:"name"`
-	Callname  *string `json:"callname,omitempty"`
-	Birthdate *string `json:"birthdate,omitempty"`
+	ID                 int64             `json:"id"`
+	Name               string            `json:"name"`
+	Callname           *string           `json:"callname,omitempty"`
+	Birthdate          *string           `json:"birthdate,omitempty"`
+	Breed              *string           `json:"breed,omitempty"`
+	Sex                *string           `json:"sex,omitempty"`
+	Microchip          *string           `json:"microchip,omitempty"`
+	RegistrationNumber *string           `json:"registration_number,omitempty"`
+	Owner              *string           `json:"owner,omitempty"`
+	HandlerID          *int64            `json:"handler_id,omitempty"`
+	Status             string            `json:"status"`
+	Indication         *string           `json:"indication,omitempty"`
+	Attributes         map[string]string `json:"attributes,omitempty"`
+	HasPhoto           bool              `json:"has_photo"`
+	ArchivedAt         *string           `json:"archived_at,omitempty"`
+}
+
+type Conditions struct {
+	TemperatureC     *float64 `json:"temperature_c,omitempty"`
+	HumidityPct      *float64 `json:"humidity_pct,omitempty"`
+	WindSpeedKmh     *float64 `json:"wind_speed_kmh,omitempty"`
+	WindDirectionDeg *int     `json:"wind_direction_deg,omitempty"`
+	Precipitation    *string  `json:"precipitation,omitempty"`
+	Terrain          *string  `json:"terrain,omitempty"`
 }
 
 type Session struct {
-	ID        int64   `json:"id"`
-	StartedAt string  `json:"started_at"`
-	EndedAt   *string `json:"ended_at"`
-	Location  *string `json:"location,omitempty"`
-	Notes     *string `json:"notes,omitempty"`
+	ID         int64   `json:"id"`
+	StartedAt  string  `json:"started_at"`
+	EndedAt    *string `json:"ended_at"`
+	Location   *string `json:"location,omitempty"`
+	LocationID *int64  `json:"location_id,omitempty"`
+	Notes      *string `json:"notes,omitempty"`
+	Kind       string  `json:"kind,omitempty"`
+	Conditions
 }
 
 type Round struct {
@@ -54,6 +180,39 @@ type Round struct {
 	Notes               *string `json:"notes,omitempty"`
 	StartedAt           *string `json:"started_at,omitempty"`
 	EndedAt             *string `json:"ended_at,omitempty"`
+	DurationS           *int    `json:"duration_s,omitempty"`
+	TimeToFirstAlertS   *int    `json:"time_to_first_alert_s,omitempty"`
+	TimeToIndicationS   *int    `json:"time_to_indication_s,omitempty"`
+	HandlerID           *int64  `json:"handler_id,omitempty"`
+	IndicationResult    *string `json:"indication_result,omitempty"`
+	Conditions
+	Reinforcement
+	WeightedScore   *float64           `json:"weighted_score,omitempty"`
+	CriterionScores []CriterionScore   `json:"criterion_scores,omitempty"`
+	AreaSearch      *AreaSearch        `json:"area_search,omitempty"`
+	Trailing        *Trailing          `json:"trailing,omitempty"`
+	Rubble          *Rubble            `json:"rubble,omitempty"`
+	DifficultyLevel *int               `json:"difficulty_level,omitempty"`
+	Parameters      map[string]float64 `json:"parameters,omitempty"`
+}
+
+type Reinforcement struct {
+	Reward           *string  `json:"reward,omitempty"`
+	Schedule         *string  `json:"reinforcement_schedule,omitempty"`
+	Distractions     []string `json:"distractions,omitempty"`
+	DistractionLevel *string  `json:"distraction_level,omitempty"`
+}
+
+type CatalogEntry struct {
+	Code string `json:"code"`
+	Name string `json:"name"`
+}
+
+type ReinforcementCatalog struct {
+	Rewards           []CatalogEntry `json:"rewards"`
+	Schedules         []CatalogEntry `json:"reinforcement_schedules"`
+	Distractions      []CatalogEntry `json:"distractions"`
+	DistractionLevels []CatalogEntry `json:"distraction_levels"`
 }
 
 type User struct {
@@ -63,3 +222,550 @@ type User struct {
 	IsAdmin      bool   `json:"is_admin"`
 	CreatedAt    string `json:"created_at"`
 }
+
+type Alert struct {
+	ID             int64    `json:"id"`
+	DogID          int64    `json:"dog_id"`
+	BehaviorID     int64    `json:"behavior_id"`
+	Kind           string   `json:"kind"`
+	Metric         string   `json:"metric"`
+	Status         string   `json:"status"`
+	Baseline       *float64 `json:"baseline,omitempty"`
+	Recent         float64  `json:"recent"`
+	Slope          float64  `json:"slope"`
+	PValue         *float64 `json:"p_value,omitempty"`
+	Samples        int      `json:"samples"`
+	Message        string   `json:"message"`
+	WindowStart    string   `json:"window_start"`
+	WindowEnd      string   `json:"window_end"`
+	CreatedAt      string   `json:"created_at"`
+	UpdatedAt      string   `json:"updated_at"`
+	AcknowledgedBy *int64   `json:"acknowledged_by,omitempty"`
+	AcknowledgedAt *string  `json:"acknowledged_at,omitempty"`
+}
+
+type ExamRequirement struct {
+	ID             int64    `json:"id"`
+	BehaviorID     *int64   `json:"behavior_id,omitempty"`
+	SkillID        *int64   `json:"skill_id,omitempty"`
+	Missions       bool     `json:"missions,omitempty"`
+	MinSuccessRate float64  `json:"min_success_rate"`
+	MinScore       *float64 `json:"min_score,omitempty"`
+	MinRounds      int      `json:"min_rounds"`
+	WindowDays     *int     `json:"window_days,omitempty"`
+	Weight         float64  `json:"weight"`
+}
+
+type Exam struct {
+	ID           int64             `json:"id"`
+	Name         string            `json:"name"`
+	Description  *string           `json:"description,omitempty"`
+	WindowDays   int               `json:"window_days"`
+	Requirements []ExamRequirement `json:"requirements"`
+	CreatedAt    string            `json:"created_at"`
+	UpdatedAt    string            `json:"updated_at"`
+}
+
+type RequirementStatus struct {
+	Requirement    ExamRequirement `json:"requirement"`
+	Status         string          `json:"status"`
+	WindowStart    string          `json:"window_start"`
+	Rounds         int             `json:"rounds"`
+	SuccessRate    float64         `json:"success_rate"`
+	MeanScore      *float64        `json:"mean_score,omitempty"`
+	RoundsGap      int             `json:"rounds_gap"`
+	SuccessRateGap float64         `json:"success_rate_gap"`
+	ScoreGap       float64         `json:"score_gap"`
+	Readiness      float64         `json:"readiness"`
+}
+
+type Readiness struct {
+	DogID        int64               `json:"dog_id"`
+	ExamID       int64               `json:"exam_id"`
+	ExamName     string              `json:"exam_name"`
+	Ready        bool                `json:"ready"`
+	Percentage   float64             `json:"percentage"`
+	Requirements []RequirementStatus `json:"requirements"`
+	EvaluatedAt  string              `json:"evaluated_at"`
+}
+
+type ProtocolTask struct {
+	ID          int64   `json:"id"`
+	Position    int     `json:"position"`
+	Name        string  `json:"name"`
+	Description *string `json:"description,omitempty"`
+	BehaviorID  *int64  `json:"behavior_id,omitempty"`
+	ExerciseID  *int64  `json:"exercise_id,omitempty"`
+	TimeLimitS  *int    `json:"time_limit_s,omitempty"`
+	MaxPoints   int     `json:"max_points"`
+	MinPoints   int     `json:"min_points"`
+	Required    bool    `json:"required"`
+}
+
+type Protocol struct {
+	ID          int64          `json:"id"`
+	Name        string         `json:"name"`
+	Description *string        `json:"description,omitempty"`
+	PassRatio   float64        `json:"pass_ratio"`
+	MaxPoints   int            `json:"max_points"`
+	Tasks       []ProtocolTask `json:"tasks"`
+	CreatedAt   string         `json:"created_at"`
+	UpdatedAt   string         `json:"updated_at"`
+}
+
+type ExamRun struct {
+	ID          int64    `json:"id"`
+	ProtocolID  int64    `json:"protocol_id"`
+	SessionID   int64    `json:"session_id"`
+	DogID       int64    `json:"dog_id"`
+	DogName     string   `json:"dog_name"`
+	Judge       *string  `json:"judge,omitempty"`
+	StartedAt   string   `json:"started_at"`
+	CompletedAt *string  `json:"completed_at,omitempty"`
+	Passed      *bool    `json:"passed,omitempty"`
+	TotalPoints *float64 `json:"total_points,omitempty"`
+}
+
+type ExamRunTask struct {
+	Task      ProtocolTask `json:"task"`
+	RoundID   *int64       `json:"round_id,omitempty"`
+	State     string       `json:"state"`
+	Points    float64      `json:"points"`
+	DurationS *int         `json:"duration_s,omitempty"`
+	Overtime  bool         `json:"overtime"`
+}
+
+type ExamRunResult struct {
+	Run          ExamRun       `json:"run"`
+	ProtocolName string        `json:"protocol_name"`
+	Tasks        []ExamRunTask `json:"tasks"`
+	Points       float64       `json:"points"`
+	MaxPoints    float64       `json:"max_points"`
+	Ratio        float64       `json:"ratio"`
+	PassRatio    float64       `json:"pass_ratio"`
+	Complete     bool          `json:"complete"`
+	Passed       bool          `json:"passed"`
+}
+
+type Qualification struct {
+	ID                int64   `json:"id"`
+	DogID             *int64  `json:"dog_id,omitempty"`
+	HandlerID         *int64  `json:"handler_id,omitempty"`
+	ExamType          string  `json:"exam_type"`
+	ExamID            *int64  `json:"exam_id,omitempty"`
+	ExamDate          string  `json:"exam_date"`
+	Examiner          *string `json:"examiner,omitempty"`
+	Result            string  `json:"result"`
+	CertificateNumber *string `json:"certificate_number,omitempty"`
+	ValidUntil        *string `json:"valid_until,omitempty"`
+	RemindDaysBefore  int     `json:"remind_days_before"`
+	DisciplineID      *int64  `json:"discipline_id,omitempty"`
+	Status            string  `json:"status"`
+	Notes             *string `json:"notes,omitempty"`
+	CreatedAt         string  `json:"created_at"`
+	UpdatedAt         string  `json:"updated_at"`
+}
+
+type Attachment struct {
+	ID              int64  `json:"id"`
+	QualificationID int64  `json:"qualification_id"`
+	Filename        string `json:"filename"`
+	ContentType     string `json:"content_type"`
+	Size            int64  `json:"size"`
+	UploadedAt      string `json:"uploaded_at"`
+}
+
+type Reminder struct {
+	Qualification *Qualification `json:"qualification"`
+	DaysLeft      int            `json:"days_left"`
+}
+
+type Team struct {
+	DogID          int64            `json:"dog_id"`
+	DogName        string           `json:"dog_name"`
+	HandlerID      int64            `json:"handler_id"`
+	HandlerEmail   string           `json:"handler_email"`
+	Deployable     bool             `json:"deployable"`
+	ValidUntil     *string          `json:"valid_until,omitempty"`
+	Qualifications []*Qualification `json:"qualifications"`
+}
+
+type PurgeReport struct {
+	DogID  int64            `json:"dog_id"`
+	DryRun bool             `json:"dry_run"`
+	Rows   map[string]int64 `json:"rows"`
+	Total  int64            `json:"total"`
+}
+
+type Pairing struct {
+	ID        int64   `json:"id"`
+	DogID     int64   `json:"dog_id"`
+	UserID    int64   `json:"user_id"`
+	Role      string  `json:"role"`
+	StartDate string  `json:"start_date"`
+	EndDate   *string `json:"end_date,omitempty"`
+	Active    bool    `json:"active"`
+	Notes     *string `json:"notes,omitempty"`
+	CreatedAt string  `json:"created_at"`
+	UpdatedAt string  `json:"updated_at"`
+}
+
+type HealthEntry struct {
+	ID        int64    `json:"id"`
+	DogID     int64    `json:"dog_id"`
+	Kind      string   `json:"kind"`
+	Date      string   `json:"date"`
+	EndDate   *string  `json:"end_date,omitempty"`
+	DueDate   *string  `json:"due_date,omitempty"`
+	Title     *string  `json:"title,omitempty"`
+	Vet       *string  `json:"vet,omitempty"`
+	WeightKg  *float64 `json:"weight_kg,omitempty"`
+	NotFit    bool     `json:"not_fit"`
+	Notes     *string  `json:"notes,omitempty"`
+	CreatedAt string   `json:"created_at"`
+	UpdatedAt string   `json:"updated_at"`
+}
+
+type Fitness struct {
+	DogID   int64          `json:"dog_id"`
+	Date    string         `json:"date"`
+	Fit     bool           `json:"fit"`
+	Entries []*HealthEntry `json:"entries"`
+}
+
+type ConditionBand struct {
+	Band        string   `json:"band"`
+	Rounds      int      `json:"rounds"`
+	Successes   int      `json:"successes"`
+	Partials    int      `json:"partials"`
+	Fails       int      `json:"fails"`
+	SuccessRate float64  `json:"success_rate"`
+	MeanScore   *float64 `json:"mean_score,omitempty"`
+}
+
+type ConditionStats struct {
+	Factor string          `json:"factor"`
+	Bands  []ConditionBand `json:"bands"`
+}
+
+type Observation struct {
+	ID               int64    `json:"id"`
+	Location         string   `json:"location"`
+	Station          *string  `json:"station,omitempty"`
+	ObservedAt       string   `json:"observed_at"`
+	TemperatureC     *float64 `json:"temperature_c,omitempty"`
+	HumidityPct      *float64 `json:"humidity_pct,omitempty"`
+	WindSpeedKmh     *float64 `json:"wind_speed_kmh,omitempty"`
+	WindDirectionDeg *int     `json:"wind_direction_deg,omitempty"`
+	Precipitation    *string  `json:"precipitation,omitempty"`
+	Source           string   `json:"source"`
+	ImportedAt       string   `json:"imported_at"`
+}
+
+type WeatherImport struct {
+	Parsed     int     `json:"parsed"`
+	Stored     int     `json:"stored"`
+	Duplicates int     `json:"duplicates"`
+	Skipped    int     `json:"skipped"`
+	Sessions   []int64 `json:"sessions_updated"`
+}
+
+// GeoPolygon is a GeoJSON polygon. Positions are [longitude, latitude];
+// only the outer ring is used.
+type GeoPolygon struct {
+	Type        string         `json:"type"`
+	Coordinates [][][2]float64 `json:"coordinates"`
+}
+
+type Location struct {
+	ID          int64       `json:"id"`
+	Name        string      `json:"name"`
+	Latitude    *float64    `json:"latitude,omitempty"`
+	Longitude   *float64    `json:"longitude,omitempty"`
+	Terrain     *string     `json:"terrain,omitempty"`
+	AccessNotes *string     `json:"access_notes,omitempty"`
+	Boundary    *GeoPolygon `json:"boundary,omitempty"`
+	DistanceKm  *float64    `json:"distance_km,omitempty"`
+	CreatedAt   string      `json:"created_at"`
+	UpdatedAt   string      `json:"updated_at"`
+}
+
+type LocationStats struct {
+	LocationID   int64    `json:"location_id"`
+	Name         string   `json:"name"`
+	Sessions     int      `json:"sessions"`
+	Rounds       int      `json:"rounds"`
+	Successes    int      `json:"successes"`
+	Partials     int      `json:"partials"`
+	Fails        int      `json:"fails"`
+	SuccessRate  float64  `json:"success_rate"`
+	MeanScore    *float64 `json:"mean_score,omitempty"`
+	Dogs         int      `json:"dogs"`
+	FirstSession *string  `json:"first_session,omitempty"`
+	LastSession  *string  `json:"last_session,omitempty"`
+}
+
+type Track struct {
+	ID          int64          `json:"id"`
+	SessionID   int64          `json:"session_id"`
+	RoundID     *int64         `json:"round_id,omitempty"`
+	Kind        string         `json:"kind"`
+	Name        *string        `json:"name,omitempty"`
+	Points      int            `json:"points"`
+	DistanceM   float64        `json:"distance_m"`
+	StartedAt   *string        `json:"started_at,omitempty"`
+	EndedAt     *string        `json:"ended_at,omitempty"`
+	DurationS   *float64       `json:"duration_s,omitempty"`
+	AvgSpeedKmh *float64       `json:"avg_speed_kmh,omitempty"`
+	Coverage    *TrackCoverage `json:"coverage,omitempty"`
+	CreatedAt   string         `json:"created_at"`
+}
+
+// TrackCoverage relates a track to its assigned search sector. Rates are
+// fractions between 0 and 1.
+type TrackCoverage struct {
+	SectorAreaM2 float64 `json:"sector_area_m2"`
+	SweepWidthM  float64 `json:"sweep_width_m"`
+	CoverageRate float64 `json:"coverage_rate"`
+	InSectorRate float64 `json:"in_sector_rate"`
+}
+
+type GeoGeometry struct {
+	Type        string `json:"type"`
+	Coordinates any    `json:"coordinates"`
+}
+
+type GeoFeature struct {
+	Type       string      `json:"type"`
+	Geometry   GeoGeometry `json:"geometry"`
+	Properties any         `json:"properties"`
+}
+
+// GeoFeatureCollection is a GeoJSON feature collection. Coverage is a
+// foreign member holding the combined coverage of all tracks in it.
+type GeoFeatureCollection struct {
+	Type     string         `json:"type"`
+	Features []GeoFeature   `json:"features"`
+	Coverage *TrackCoverage `json:"coverage,omitempty"`
+}
+
+type HiddenPerson struct {
+	ID          int64   `json:"id"`
+	Label       *string `json:"label,omitempty"`
+	Latitude    float64 `json:"latitude"`
+	Longitude   float64 `json:"longitude"`
+	Found       bool    `json:"found"`
+	TimeToFindS *int    `json:"time_to_find_s,omitempty"`
+	Notes       *string `json:"notes,omitempty"`
+}
+
+type AreaSearch struct {
+	SectorName             *string        `json:"sector_name,omitempty"`
+	Sector                 *GeoPolygon    `json:"sector,omitempty"`
+	FalseAlerts            int            `json:"false_alerts"`
+	HiddenPersons          []HiddenPerson `json:"hidden_persons"`
+	Found                  int            `json:"found"`
+	ProbabilityOfDetection *float64       `json:"probability_of_detection,omitempty"`
+	MeanTimeToFindS        *float64       `json:"mean_time_to_find_s,omitempty"`
+}
+
+// AreaSearchSectorStats aggregates the area-search rounds run in one
+// sector, identified by its name and location.
+type AreaSearchSectorStats struct {
+	SectorName             *string  `json:"sector_name,omitempty"`
+	LocationID             *int64   `json:"location_id,omitempty"`
+	Rounds                 int      `json:"rounds"`
+	HiddenPersons          int      `json:"hidden_persons"`
+	Found                  int      `json:"found"`
+	ProbabilityOfDetection *float64 `json:"probability_of_detection,omitempty"`
+	MeanTimeToFindS        *float64 `json:"mean_time_to_find_s,omitempty"`
+	FalseAlerts            int      `json:"false_alerts"`
+	FalseAlertsPerRound    float64  `json:"false_alerts_per_round"`
+}
+
+type Trailing struct {
+	TrailAgeMin    int      `json:"trail_age_min"`
+	TrailLengthM   float64  `json:"trail_length_m"`
+	ScentArticle   *string  `json:"scent_article,omitempty"`
+	StartType      *string  `json:"start_type,omitempty"`
+	Surfaces       []string `json:"surfaces,omitempty"`
+	Contamination  *string  `json:"contamination,omitempty"`
+	ReachedSubject bool     `json:"reached_subject"`
+}
+
+type Rubble struct {
+	StructureType string   `json:"structure_type"`
+	Victims       int      `json:"victims"`
+	VictimsFound  int      `json:"victims_found"`
+	MaxDepthM     *float64 `json:"max_depth_m,omitempty"`
+	FalseAlerts   int      `json:"false_alerts"`
+}
+
+type TrailingBand struct {
+	Band        string  `json:"band"`
+	Rounds      int     `json:"rounds"`
+	Reached     int     `json:"reached"`
+	ReachRate   float64 `json:"reach_rate"`
+	Successes   int     `json:"successes"`
+	SuccessRate float64 `json:"success_rate"`
+}
+
+type TrailingStats struct {
+	Factor string         `json:"factor"`
+	Bands  []TrailingBand `json:"bands"`
+}
+
+type Discipline struct {
+	ID              int64    `json:"id"`
+	Name            string   `json:"name"`
+	Description     *string  `json:"description,omitempty"`
+	RoundExtensions []string `json:"round_extensions"`
+	CreatedAt       string   `json:"created_at"`
+	UpdatedAt       string   `json:"updated_at"`
+}
+
+type Certification struct {
+	DisciplineID   int64            `json:"discipline_id"`
+	DisciplineName string           `json:"discipline_name"`
+	Certified      bool             `json:"certified"`
+	ValidUntil     *string          `json:"valid_until,omitempty"`
+	Qualifications []*Qualification `json:"qualifications"`
+}
+
+type Figurant struct {
+	ID           int64   `json:"id"`
+	Name         string  `json:"name"`
+	Phone        *string `json:"phone,omitempty"`
+	Email        *string `json:"email,omitempty"`
+	ScentConsent bool    `json:"scent_consent"`
+	ConsentDate  *string `json:"consent_date,omitempty"`
+	Active       bool    `json:"active"`
+	Notes        *string `json:"notes,omitempty"`
+	CreatedAt    string  `json:"created_at"`
+	UpdatedAt    string  `json:"updated_at"`
+}
+
+type FigurantAvailability struct {
+	ID         int64   `json:"id"`
+	FigurantID int64   `json:"figurant_id"`
+	StartDate  string  `json:"start_date"`
+	EndDate    string  `json:"end_date"`
+	Notes      *string `json:"notes,omitempty"`
+}
+
+type FigurantAppearance struct {
+	FigurantID  int64  `json:"figurant_id"`
+	RoundID     int64  `json:"round_id"`
+	SessionID   int64  `json:"session_id"`
+	RoundNumber int64  `json:"round_number"`
+	DogID       int64  `json:"dog_id"`
+	DogName     string `json:"dog_name"`
+	Date        string `json:"date"`
+}
+
+type SessionFigurant struct {
+	Figurant  *Figurant `json:"figurant"`
+	Available bool      `json:"available"`
+}
+
+// FigurantReuse tells how often a figurant recently worked with a dog.
+type FigurantReuse struct {
+	Figurant      *Figurant `json:"figurant"`
+	Available     bool      `json:"available"`
+	InSession     bool      `json:"in_session"`
+	RoundsWithDog int       `json:"rounds_with_dog"`
+	LastWithDog   *string   `json:"last_with_dog,omitempty"`
+	TooOften      bool      `json:"too_often"`
+}
+
+type MissionTeam struct {
+	ID            int64   `json:"id"`
+	DogID         int64   `json:"dog_id"`
+	HandlerID     *int64  `json:"handler_id,omitempty"`
+	DisciplineID  *int64  `json:"discipline_id,omitempty"`
+	Found         bool    `json:"found"`
+	SearchMinutes *int    `json:"search_minutes,omitempty"`
+	Notes         *string `json:"notes,omitempty"`
+}
+
+type Mission struct {
+	ID             int64         `json:"id"`
+	StartedAt      string        `json:"started_at"`
+	EndedAt        *string       `json:"ended_at,omitempty"`
+	AlertingAgency string        `json:"alerting_agency"`
+	Reference      *string       `json:"reference,omitempty"`
+	Location       *string       `json:"location,omitempty"`
+	LocationID     *int64        `json:"location_id,omitempty"`
+	SearchArea     *GeoPolygon   `json:"search_area,omitempty"`
+	Outcome        string        `json:"outcome"`
+	Debrief        *string       `json:"debrief,omitempty"`
+	Teams          []MissionTeam `json:"teams"`
+	CreatedAt      string        `json:"created_at"`
+	UpdatedAt      string        `json:"updated_at"`
+}
+
+// DeploymentRecord sets a dog's deployments against its training over the
+// same period.
+type DeploymentRecord struct {
+	DogID               int64    `json:"dog_id"`
+	DogName             string   `json:"dog_name"`
+	Missions            int      `json:"missions"`
+	Finds               int      `json:"finds"`
+	FindRate            float64  `json:"find_rate"`
+	SearchHours         float64  `json:"search_hours"`
+	TrainingRounds      int      `json:"training_rounds"`
+	TrainingSuccessRate *float64 `json:"training_success_rate,omitempty"`
+}
+
+type IndicationStats struct {
+	DogID          int64   `json:"dog_id"`
+	DogName        string  `json:"dog_name"`
+	Indication     *string `json:"indication,omitempty"`
+	Rounds         int     `json:"rounds"`
+	Correct        int     `json:"correct"`
+	Late           int     `json:"late"`
+	False          int     `json:"false"`
+	Missing        int     `json:"missing"`
+	CorrectRate    float64 `json:"correct_rate"`
+	FalseAlertRate float64 `json:"false_alert_rate"`
+	MissRate       float64 `json:"miss_rate"`
+}
+
+type ParameterProgress struct {
+	Mean float64 `json:"mean"`
+	Max  float64 `json:"max"`
+}
+
+// DifficultyPeriod summarizes the difficulty a dog trained an exercise at
+// during one week or month.
+type DifficultyPeriod struct {
+	Period      string                       `json:"period"`
+	Rounds      int                          `json:"rounds"`
+	SuccessRate float64                      `json:"success_rate"`
+	MeanLevel   *float64                     `json:"mean_level,omitempty"`
+	MaxLevel    *int                         `json:"max_level,omitempty"`
+	Parameters  map[string]ParameterProgress `json:"parameters,omitempty"`
+}
+
+type DifficultyProgress struct {
+	DogID        int64              `json:"dog_id"`
+	ExerciseID   int64              `json:"exercise_id"`
+	ExerciseName string             `json:"exercise_name"`
+	Rounds       int                `json:"rounds"`
+	FirstLevel   *int               `json:"first_level,omitempty"`
+	LatestLevel  *int               `json:"latest_level,omitempty"`
+	Periods      []DifficultyPeriod `json:"periods"`
+}
+
+type DurationStats struct {
+	BehaviorID            *int64   `json:"behavior_id,omitempty"`
+	ExerciseID            *int64   `json:"exercise_id,omitempty"`
+	Rounds                int      `json:"rounds"`
+	TimedRounds           int      `json:"timed_rounds"`
+	MeanDurationS         *float64 `json:"mean_duration_s,omitempty"`
+	MedianDurationS       *float64 `json:"median_duration_s,omitempty"`
+	MinDurationS          *int     `json:"min_duration_s,omitempty"`
+	MaxDurationS          *int     `json:"max_duration_s,omitempty"`
+	MeanTimeToFirstAlertS *float64 `json:"mean_time_to_first_alert_s,omitempty"`
+	MeanTimeToIndicationS *float64 `json:"mean_time_to_indication_s,omitempty"`
+}
@@ -0,0 +1,32 @@
+package exams
+
+type RequirementInput struct {
+	BehaviorID     *int64   `json:"behavior_id,omitempty"`
+	SkillID        *int64   `json:"skill_id,omitempty"`
+	Missions       bool     `json:"missions,omitempty"`
+	MinSuccessRate float64  `json:"min_success_rate"`
+	MinScore       *float64 `json:"min_score,omitempty"`
+	MinRounds      int      `json:"min_rounds"`
+	WindowDays     *int     `json:"window_days,omitempty"`
+	Weight         *float64 `json:"weight,omitempty"`
+}
+
+type CreateExamCommand struct {
+	Name         string             `json:"name"`
+	Description  *string            `json:"description,omitempty"`
+	WindowDays   int                `json:"window_days"`
+	Requirements []RequirementInput `json:"requirements"`
+}
+
+type UpdateExamCommand struct {
+	ID           int64              `json:"-"`
+	Name         string             `json:"name"`
+	Description  *string            `json:"description,omitempty"`
+	WindowDays   int                `json:"window_days"`
+	Requirements []RequirementInput `json:"requirements"`
+}
+
+type ReadinessQuery struct {
+	DogID  int64
+	ExamID int64
+}
@@ -0,0 +1,194 @@
+package exams
+
+import (
+	"context"
+	"math"
+	"time"
+
+	"github.com/tnosaj/sar-training/backend/internal/application/dto"
+	"github.com/tnosaj/sar-training/backend/internal/domain/common"
+	"github.com/tnosaj/sar-training/backend/internal/domain/dog"
+	"github.com/tnosaj/sar-training/backend/internal/domain/exam"
+	logx "github.com/tnosaj/sar-training/backend/internal/infra/log"
+)
+
+const defaultWindowDays = 90
+
+type Service struct {
+	repo exam.Repository
+	dogs dog.Repository
+}
+
+func NewService(r exam.Repository, d dog.Repository) *Service {
+	logx.Std.Trace("starting exams service")
+	return &Service{repo: r, dogs: d}
+}
+
+func (s *Service) Create(ctx context.Context, cmd CreateExamCommand) (*dto.Exam, error) {
+	logx.Std.Tracef("create exam %v", cmd)
+	reqs, err := toRequirements(cmd.Requirements)
+	if err != nil || cmd.Name == "" || cmd.WindowDays < 0 {
+		return nil, common.ErrValidation
+	}
+	if cmd.WindowDays == 0 {
+		cmd.WindowDays = defaultWindowDays
+	}
+	now := time.Now().UTC()
+	e := &exam.Exam{Name: cmd.Name, Description: cmd.Description, WindowDays: cmd.WindowDays, Requirements: reqs, CreatedAt: now, UpdatedAt: now}
+	if err := s.repo.Create(ctx, e); err != nil {
+		logx.Std.Errorf("create exam failed: %s", err)
+		return nil, err
+	}
+	return toDTO(e), nil
+}
+
+func (s *Service) Get(ctx context.Context, id int64) (*dto.Exam, error) {
+	logx.Std.Tracef("get exam %d", id)
+	e, err := s.repo.Get(ctx, exam.ExamID(id))
+	if err != nil {
+		return nil, err
+	}
+	return toDTO(e), nil
+}
+
+func (s *Service) List(ctx context.Context) ([]*dto.Exam, error) {
+	logx.Std.Trace("list exams")
+	items, err := s.repo.List(ctx)
+	if err != nil {
+		logx.Std.Errorf("list exams failed: %s", err)
+		return nil, err
+	}
+	out := make([]*dto.Exam, 0, len(items))
+	for _, it := range items {
+		out = append(out, toDTO(it))
+	}
+	return out, nil
+}
+
+func (s *Service) Update(ctx context.Context, cmd UpdateExamCommand) (*dto.Exam, error) {
+	logx.Std.Tracef("update exam %v", cmd)
+	reqs, err := toRequirements(cmd.Requirements)
+	if err != nil || cmd.ID <= 0 || cmd.Name == "" || cmd.WindowDays < 0 {
+		return nil, common.ErrValidation
+	}
+	e, err := s.repo.Get(ctx, exam.ExamID(cmd.ID))
+	if err != nil {
+		return nil, err
+	}
+	if cmd.WindowDays == 0 {
+		cmd.WindowDays = defaultWindowDays
+	}
+	e.Name, e.Description, e.WindowDays, e.Requirements = cmd.Name, cmd.Description, cmd.WindowDays, reqs
+	e.UpdatedAt = time.Now().UTC()
+	if err := s.repo.Update(ctx, e); err != nil {
+		logx.Std.Errorf("update exam failed: %s", err)
+		return nil, err
+	}
+	return toDTO(e), nil
+}
+
+func (s *Service) Delete(ctx context.Context, id int64) error {
+	logx.Std.Tracef("delete exam %d", id)
+	err := s.repo.Delete(ctx, exam.ExamID(id))
+	if err != nil && err != common.ErrNotFound {
+		logx.Std.Errorf("delete exam failed: %s", err)
+	}
+	return err
+}
+
+// Readiness evaluates every requirement of an exam against the dog's rounds,
+// or its deployments for a missions requirement, within the requirement's
+// window.
+func (s *Service) Readiness(ctx context.Context, q ReadinessQuery) (*dto.Readiness, error) {
+	logx.Std.Tracef("readiness %v", q)
+	if q.DogID <= 0 || q.ExamID <= 0 {
+		return nil, common.ErrValidation
+	}
+	if _, err := s.dogs.Get(ctx, dog.DogID(q.DogID)); err != nil {
+		return nil, err
+	}
+	e, err := s.repo.Get(ctx, exam.ExamID(q.ExamID))
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now().UTC()
+	evs := make([]exam.Evaluation, 0, len(e.Requirements))
+	out := &dto.Readiness{DogID: q.DogID, ExamID: int64(e.ID), ExamName: e.Name, EvaluatedAt: now.Format(time.RFC3339)}
+	for _, req := range e.Requirements {
+		since := now.AddDate(0, 0, -req.Window(e))
+		results, err := s.repo.ListResults(ctx, q.DogID, req, since)
+		if err != nil {
+			logx.Std.Errorf("list readiness results failed: %s", err)
+			return nil, err
+		}
+		ev := exam.Evaluate(req, results)
+		evs = append(evs, ev)
+		out.Requirements = append(out.Requirements, dto.RequirementStatus{
+			Requirement: toRequirementDTO(req), Status: string(ev.State), WindowStart: since.Format(time.RFC3339),
+			Rounds: ev.Rounds, SuccessRate: ev.SuccessRate, MeanScore: ev.MeanScore,
+			RoundsGap: ev.RoundsGap, SuccessRateGap: ev.SuccessRateGap, ScoreGap: ev.ScoreGap, Readiness: ev.Readiness,
+		})
+	}
+	overall, ready := exam.Overall(evs)
+	out.Percentage = math.Round(overall*1000) / 10
+	out.Ready = ready
+	return out, nil
+}
+
+func toRequirements(in []RequirementInput) ([]exam.Requirement, error) {
+	out := make([]exam.Requirement, 0, len(in))
+	for _, r := range in {
+		targets := 0
+		for _, set := range []bool{r.BehaviorID != nil, r.SkillID != nil, r.Missions} {
+			if set {
+				targets++
+			}
+		}
+		if targets != 1 || (r.Missions && r.MinScore != nil) {
+			return nil, common.ErrValidation
+		}
+		if r.MinSuccessRate < 0 || r.MinSuccessRate > 1 || r.MinRounds < 0 {
+			return nil, common.ErrValidation
+		}
+		if r.MinScore != nil && (*r.MinScore < 0 || *r.MinScore > 10) {
+			return nil, common.ErrValidation
+		}
+		if r.WindowDays != nil && *r.WindowDays <= 0 {
+			return nil, common.ErrValidation
+		}
+		weight := 1.0
+		if r.Weight != nil {
+			if *r.Weight <= 0 {
+				return nil, common.ErrValidation
+			}
+			weight = *r.Weight
+		}
+		minRounds := r.MinRounds
+		if minRounds == 0 {
+			minRounds = 1
+		}
+		out = append(out, exam.Requirement{
+			BehaviorID: r.BehaviorID, SkillID: r.SkillID, Missions: r.Missions, MinSuccessRate: r.MinSuccessRate, MinScore: r.MinScore,
+			MinRounds: minRounds, WindowDays: r.WindowDays, Weight: weight,
+		})
+	}
+	return out, nil
+}
+
+func toDTO(e *exam.Exam) *dto.Exam {
+	reqs := make([]dto.ExamRequirement, 0, len(e.Requirements))
+	for _, r := range e.Requirements {
+		reqs = append(reqs, toRequirementDTO(r))
+	}
+	return &dto.Exam{
+		ID: int64(e.ID), Name: e.Name, Description: e.Description, WindowDays: e.WindowDays, Requirements: reqs,
+		CreatedAt: e.CreatedAt.Format(time.RFC3339), UpdatedAt: e.UpdatedAt.Format(time.RFC3339),
+	}
+}
+
+func toRequirementDTO(r exam.Requirement) dto.ExamRequirement {
+	return dto.ExamRequirement{
+		ID: r.ID, BehaviorID: r.BehaviorID, SkillID: r.SkillID, Missions: r.Missions, MinSuccessRate: r.MinSuccessRate, MinScore: r.MinScore,
+		MinRounds: r.MinRounds, WindowDays: r.WindowDays, Weight: r.Weight,
+	}
+}
@@ -6,12 +6,13 @@ type CreateExerciseCommand struct {
 }
 
 type UpdateExerciseCommand struct {
+	ID          int64   `json:"id"`
 	Name        string  `json:"name"`
 	Description *string `json:"description,omitempty"`
 }
-type DeleteExerciseCommand struct {
-	Name        string  `json:"name"`
-	Description *string `json:"description,omitempty"`
+
+type ListExercisesQuery struct {
+	BehaviorID *int64
 }
 
 type LinkCommand struct {
@@ -19,3 +20,43 @@ type LinkCommand struct {
 	ExerciseID int64 `json:"exercise_id"`
 	Strength   int   `json:"strength"`
 }
+
+type ListLinksQuery struct {
+	BehaviorID *int64
+	ExerciseID *int64
+}
+
+type CriterionInput struct {
+	ID          *int64   `json:"id,omitempty"`
+	Name        string   `json:"name"`
+	Description *string  `json:"description,omitempty"`
+	Weight      *float64 `json:"weight,omitempty"`
+	MaxPoints   *int     `json:"max_points,omitempty"`
+}
+
+// SetRubricCommand replaces the rubric of an exercise; criteria are kept in
+// the given order and an empty list removes the rubric.
+type SetRubricCommand struct {
+	ExerciseID int64            `json:"-"`
+	Criteria   []CriterionInput `json:"criteria"`
+}
+
+type ParameterInput struct {
+	Kind string   `json:"kind"`
+	Min  *float64 `json:"min,omitempty"`
+	Max  *float64 `json:"max,omitempty"`
+}
+
+type LevelInput struct {
+	Name        string             `json:"name"`
+	Description *string            `json:"description,omitempty"`
+	Values      map[string]float64 `json:"values,omitempty"`
+}
+
+// SetDifficultyCommand replaces the parameters and levels of an exercise.
+// Levels are numbered from 1 in the given order, easiest first.
+type SetDifficultyCommand struct {
+	ExerciseID int64            `json:"-"`
+	Parameters []ParameterInput `json:"parameters"`
+	Levels     []LevelInput     `json:"levels"`
+}
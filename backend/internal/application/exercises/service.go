@@ -2,6 +2,7 @@ package exercises
 
 import (
 	"context"
+	"strings"
 	"time"
 
 	"github.com/tnosaj/sar-training/backend/internal/application/dto"
@@ -31,9 +32,9 @@ func (s *Service) Create(ctx context.Context, cmd CreateExerciseCommand) (*dto.E
 	return toDTO(e), nil
 }
 
-func (s *Service) List(ctx context.Context) ([]*dto.Exercise, error) {
-	logx.Std.Trace("list exercises")
-	items, err := s.repo.List(ctx)
+func (s *Service) List(ctx context.Context, q ListExercisesQuery) ([]*dto.Exercise, error) {
+	logx.Std.Tracef("list exercises %v", q)
+	items, err := s.repo.List(ctx, exercise.ListFilter{BehaviorID: q.BehaviorID})
 	if err != nil {
 		logx.Std.Errorf("list exercise failed: %s", err)
 		return nil, err
@@ -45,23 +46,238 @@ func (s *Service) List(ctx context.Context) ([]*dto.Exercise, error) {
 	return out, nil
 }
 
-func (s *Service) LinkBehavior(ctx context.Context, cmd LinkCommand) error {
+func (s *Service) Get(ctx context.Context, id int64) (*dto.Exercise, error) {
+	logx.Std.Tracef("get exercise %d", id)
+	e, err := s.repo.Get(ctx, exercise.ExerciseID(id))
+	if err != nil {
+		return nil, err
+	}
+	return toDTO(e), nil
+}
+
+func (s *Service) Update(ctx context.Context, cmd UpdateExerciseCommand) (*dto.Exercise, error) {
+	logx.Std.Tracef("update exercise %v", cmd)
+	if cmd.ID <= 0 || cmd.Name == "" {
+		return nil, common.ErrValidation
+	}
+	e, err := s.repo.Get(ctx, exercise.ExerciseID(cmd.ID))
+	if err != nil {
+		return nil, err
+	}
+	e.Name, e.Description = cmd.Name, cmd.Description
+	e.UpdatedAt = time.Now().UTC()
+	if err := s.repo.Update(ctx, e); err != nil {
+		if err != common.ErrConflict {
+			logx.Std.Errorf("update exercise failed: %s", err)
+		}
+		return nil, err
+	}
+	return toDTO(e), nil
+}
+
+func (s *Service) Delete(ctx context.Context, id int64) error {
+	logx.Std.Tracef("delete exercise %d", id)
+	err := s.repo.Delete(ctx, exercise.ExerciseID(id))
+	if err != nil && err != common.ErrNotFound && err != common.ErrConflict {
+		logx.Std.Errorf("delete exercise failed: %s", err)
+	}
+	return err
+}
+
+// LinkBehavior links a behavior to an exercise, or changes the strength of an
+// existing link.
+func (s *Service) LinkBehavior(ctx context.Context, cmd LinkCommand) (*dto.BehaviorExercise, error) {
+	logx.Std.Tracef("link behavior %v", cmd)
 	if cmd.BehaviorID <= 0 || cmd.ExerciseID <= 0 {
-		return common.ErrValidation
+		return nil, common.ErrValidation
 	}
 	if cmd.Strength < 1 || cmd.Strength > 5 {
-		return common.ErrValidation
+		return nil, common.ErrValidation
+	}
+	if err := s.repo.LinkBehavior(ctx, cmd.BehaviorID, cmd.ExerciseID, cmd.Strength); err != nil {
+		if err != common.ErrValidation {
+			logx.Std.Errorf("link behavior failed: %s", err)
+		}
+		return nil, err
 	}
-	err := s.repo.LinkBehavior(ctx, cmd.BehaviorID, cmd.ExerciseID, cmd.Strength)
+	links, err := s.repo.Links(ctx, exercise.LinkFilter{BehaviorID: &cmd.BehaviorID, ExerciseID: &cmd.ExerciseID})
 	if err != nil {
-		logx.Std.Errorf("link behavior failed: %s", err)
+		logx.Std.Errorf("get link failed: %s", err)
+		return nil, err
+	}
+	if len(links) == 0 {
+		return nil, common.ErrNotFound
+	}
+	return toLinkDTO(links[0]), nil
+}
+
+func (s *Service) UnlinkBehavior(ctx context.Context, behaviorID, exerciseID int64) error {
+	logx.Std.Tracef("unlink behavior %d from exercise %d", behaviorID, exerciseID)
+	err := s.repo.UnlinkBehavior(ctx, behaviorID, exerciseID)
+	if err != nil && err != common.ErrNotFound {
+		logx.Std.Errorf("unlink behavior failed: %s", err)
 	}
 	return err
 }
 
+func (s *Service) Links(ctx context.Context, q ListLinksQuery) ([]*dto.BehaviorExercise, error) {
+	logx.Std.Tracef("list behavior links %v", q)
+	links, err := s.repo.Links(ctx, exercise.LinkFilter{BehaviorID: q.BehaviorID, ExerciseID: q.ExerciseID})
+	if err != nil {
+		logx.Std.Errorf("list behavior links failed: %s", err)
+		return nil, err
+	}
+	out := make([]*dto.BehaviorExercise, 0, len(links))
+	for _, l := range links {
+		out = append(out, toLinkDTO(l))
+	}
+	return out, nil
+}
+
+func (s *Service) Rubric(ctx context.Context, exerciseID int64) ([]*dto.RubricCriterion, error) {
+	logx.Std.Tracef("get rubric of exercise %d", exerciseID)
+	if _, err := s.repo.Get(ctx, exercise.ExerciseID(exerciseID)); err != nil {
+		return nil, err
+	}
+	rb, err := s.repo.Rubric(ctx, exercise.ExerciseID(exerciseID))
+	if err != nil {
+		logx.Std.Errorf("get rubric failed: %s", err)
+		return nil, err
+	}
+	return toRubricDTO(rb), nil
+}
+
+func (s *Service) SetRubric(ctx context.Context, cmd SetRubricCommand) ([]*dto.RubricCriterion, error) {
+	logx.Std.Tracef("set rubric %v", cmd)
+	if _, err := s.repo.Get(ctx, exercise.ExerciseID(cmd.ExerciseID)); err != nil {
+		return nil, err
+	}
+	rb := make(exercise.Rubric, 0, len(cmd.Criteria))
+	seen := map[string]bool{}
+	for i, in := range cmd.Criteria {
+		name := strings.TrimSpace(in.Name)
+		if name == "" || seen[strings.ToLower(name)] {
+			return nil, common.ErrValidation
+		}
+		seen[strings.ToLower(name)] = true
+		c := &exercise.Criterion{Name: name, Description: in.Description, Weight: 1, MaxPoints: exercise.MaxScore, Position: i}
+		if in.ID != nil {
+			c.ID = exercise.CriterionID(*in.ID)
+		}
+		if in.Weight != nil {
+			c.Weight = *in.Weight
+		}
+		if in.MaxPoints != nil {
+			c.MaxPoints = *in.MaxPoints
+		}
+		if c.Weight <= 0 || c.MaxPoints <= 0 {
+			return nil, common.ErrValidation
+		}
+		rb = append(rb, c)
+	}
+	if err := s.repo.SetRubric(ctx, exercise.ExerciseID(cmd.ExerciseID), rb); err != nil {
+		if err != common.ErrValidation && err != common.ErrConflict {
+			logx.Std.Errorf("set rubric failed: %s", err)
+		}
+		return nil, err
+	}
+	return toRubricDTO(rb), nil
+}
+
+func toRubricDTO(rb exercise.Rubric) []*dto.RubricCriterion {
+	out := make([]*dto.RubricCriterion, 0, len(rb))
+	for _, c := range rb {
+		out = append(out, &dto.RubricCriterion{
+			ID: int64(c.ID), Name: c.Name, Description: c.Description, Weight: c.Weight, MaxPoints: c.MaxPoints, Position: c.Position,
+		})
+	}
+	return out
+}
+
 func toDTO(e *exercise.Exercise) *dto.Exercise {
 	return &dto.Exercise{
 		ID: int64(e.ID), Name: e.Name, Description: e.Description,
 		CreatedAt: e.CreatedAt.Format(time.RFC3339), UpdatedAt: e.UpdatedAt.Format(time.RFC3339),
 	}
 }
+
+func toLinkDTO(l exercise.Link) *dto.BehaviorExercise {
+	return &dto.BehaviorExercise{
+		BehaviorID: l.BehaviorID, BehaviorName: l.BehaviorName, ExerciseID: int64(l.ExerciseID), ExerciseName: l.ExerciseName, Strength: l.Strength,
+	}
+}
+
+func (s *Service) Difficulty(ctx context.Context, exerciseID int64) (*dto.ExerciseDifficulty, error) {
+	logx.Std.Tracef("get difficulty of exercise %d", exerciseID)
+	if _, err := s.repo.Get(ctx, exercise.ExerciseID(exerciseID)); err != nil {
+		return nil, err
+	}
+	d, err := s.repo.Difficulty(ctx, exercise.ExerciseID(exerciseID))
+	if err != nil {
+		logx.Std.Errorf("get difficulty failed: %s", err)
+		return nil, err
+	}
+	return toDifficultyDTO(exerciseID, d), nil
+}
+
+// SetDifficulty replaces the parameters and levels of an exercise. Level
+// presets must lie within the parameter ranges.
+func (s *Service) SetDifficulty(ctx context.Context, cmd SetDifficultyCommand) (*dto.ExerciseDifficulty, error) {
+	logx.Std.Tracef("set difficulty %v", cmd)
+	if _, err := s.repo.Get(ctx, exercise.ExerciseID(cmd.ExerciseID)); err != nil {
+		return nil, err
+	}
+	var d exercise.Difficulty
+	for _, in := range cmd.Parameters {
+		p := exercise.Parameter{Kind: exercise.ParameterKind(in.Kind), Min: in.Min, Max: in.Max}
+		if !p.Kind.Valid() {
+			return nil, common.ErrValidation
+		}
+		if _, dup := d.Parameter(p.Kind); dup {
+			return nil, common.ErrValidation
+		}
+		if (p.Min != nil && *p.Min < 0) || (p.Min != nil && p.Max != nil && *p.Min > *p.Max) {
+			return nil, common.ErrValidation
+		}
+		d.Parameters = append(d.Parameters, p)
+	}
+	seen := map[string]bool{}
+	for i, in := range cmd.Levels {
+		name := strings.TrimSpace(in.Name)
+		if name == "" || seen[strings.ToLower(name)] {
+			return nil, common.ErrValidation
+		}
+		seen[strings.ToLower(name)] = true
+		l := exercise.Level{Level: i + 1, Name: name, Description: in.Description, Values: map[exercise.ParameterKind]float64{}}
+		for k, v := range in.Values {
+			p, ok := d.Parameter(exercise.ParameterKind(k))
+			if !ok || !p.Allows(v) {
+				return nil, common.ErrValidation
+			}
+			l.Values[p.Kind] = v
+		}
+		d.Levels = append(d.Levels, l)
+	}
+	if err := s.repo.SetDifficulty(ctx, exercise.ExerciseID(cmd.ExerciseID), d); err != nil {
+		if err != common.ErrValidation && err != common.ErrNotFound && err != common.ErrConflict {
+			logx.Std.Errorf("set difficulty failed: %s", err)
+		}
+		return nil, err
+	}
+	return toDifficultyDTO(cmd.ExerciseID, d), nil
+}
+
+func toDifficultyDTO(exerciseID int64, d exercise.Difficulty) *dto.ExerciseDifficulty {
+	out := &dto.ExerciseDifficulty{ExerciseID: exerciseID, Parameters: []dto.ExerciseParameter{}, Levels: []dto.ExerciseLevel{}}
+	for _, p := range d.Parameters {
+		out.Parameters = append(out.Parameters, dto.ExerciseParameter{Kind: string(p.Kind), Min: p.Min, Max: p.Max})
+	}
+	for _, l := range d.Levels {
+		values := make(map[string]float64, len(l.Values))
+		for k, v := range l.Values {
+			values[string(k)] = v
+		}
+		out.Levels = append(out.Levels, dto.ExerciseLevel{Level: l.Level, Name: l.Name, Description: l.Description, Values: values})
+	}
+	return out
+}
@@ -0,0 +1,48 @@
+package figurants
+
+type CreateFigurantCommand struct {
+	Name         string  `json:"name"`
+	Phone        *string `json:"phone,omitempty"`
+	Email        *string `json:"email,omitempty"`
+	ScentConsent bool    `json:"scent_consent"`
+	ConsentDate  *string `json:"consent_date,omitempty"`
+	Active       *bool   `json:"active,omitempty"`
+	Notes        *string `json:"notes,omitempty"`
+}
+
+type UpdateFigurantCommand struct {
+	ID           int64   `json:"-"`
+	Name         string  `json:"name"`
+	Phone        *string `json:"phone,omitempty"`
+	Email        *string `json:"email,omitempty"`
+	ScentConsent bool    `json:"scent_consent"`
+	ConsentDate  *string `json:"consent_date,omitempty"`
+	Active       *bool   `json:"active,omitempty"`
+	Notes        *string `json:"notes,omitempty"`
+}
+
+type ListFigurantsQuery struct {
+	ActiveOnly  bool
+	AvailableOn *string
+}
+
+type AddAvailabilityCommand struct {
+	FigurantID int64   `json:"-"`
+	StartDate  string  `json:"start_date"`
+	EndDate    *string `json:"end_date,omitempty"`
+	Notes      *string `json:"notes,omitempty"`
+}
+
+type SetRoundFigurantsCommand struct {
+	SessionID   int64   `json:"-"`
+	RoundID     int64   `json:"-"`
+	FigurantIDs []int64 `json:"figurant_ids"`
+	WindowDays  *int    `json:"-"`
+	UserID      int64   `json:"-"`
+}
+
+type SuggestFigurantsQuery struct {
+	SessionID  int64
+	DogID      int64
+	WindowDays *int
+}
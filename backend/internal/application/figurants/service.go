@@ -0,0 +1,475 @@
+package figurants
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/tnosaj/sar-training/backend/internal/application/dto"
+	"github.com/tnosaj/sar-training/backend/internal/domain/common"
+	"github.com/tnosaj/sar-training/backend/internal/domain/figurant"
+	"github.com/tnosaj/sar-training/backend/internal/domain/pairing"
+	"github.com/tnosaj/sar-training/backend/internal/domain/session"
+	"github.com/tnosaj/sar-training/backend/internal/domain/user"
+	logx "github.com/tnosaj/sar-training/backend/internal/infra/log"
+)
+
+const (
+	// DefaultReuseWindowDays is how far back rounds with the same dog count
+	// toward reuse.
+	DefaultReuseWindowDays = 30
+	// MaxRoundsWithDog is how many rounds with the same dog within the
+	// window are fine; from then on the figurant is reused too often.
+	MaxRoundsWithDog = 2
+)
+
+type Service struct {
+	repo     figurant.Repository
+	sessions session.Repository
+	pairings pairing.Repository
+	users    user.Repository
+}
+
+func NewService(r figurant.Repository, s session.Repository, p pairing.Repository, u user.Repository) *Service {
+	logx.Std.Trace("starting figurants service")
+	return &Service{repo: r, sessions: s, pairings: p, users: u}
+}
+
+func (s *Service) Create(ctx context.Context, cmd CreateFigurantCommand) (*dto.Figurant, error) {
+	logx.Std.Tracef("create figurant %v", cmd)
+	now := time.Now().UTC()
+	f := &figurant.Figurant{CreatedAt: now, UpdatedAt: now}
+	if err := apply(f, UpdateFigurantCommand{
+		Name: cmd.Name, Phone: cmd.Phone, Email: cmd.Email, ScentConsent: cmd.ScentConsent,
+		ConsentDate: cmd.ConsentDate, Active: cmd.Active, Notes: cmd.Notes,
+	}); err != nil {
+		return nil, err
+	}
+	if err := s.repo.Create(ctx, f); err != nil {
+		logx.Std.Errorf("create figurant failed: %s", err)
+		return nil, err
+	}
+	return toDTO(f), nil
+}
+
+func (s *Service) Get(ctx context.Context, id int64) (*dto.Figurant, error) {
+	logx.Std.Tracef("get figurant %d", id)
+	f, err := s.repo.Get(ctx, figurant.FigurantID(id))
+	if err != nil {
+		return nil, err
+	}
+	return toDTO(f), nil
+}
+
+func (s *Service) List(ctx context.Context, q ListFigurantsQuery) ([]*dto.Figurant, error) {
+	logx.Std.Tracef("list figurants %v", q)
+	flt := figurant.Filter{ActiveOnly: q.ActiveOnly}
+	if q.AvailableOn != nil {
+		t, err := common.ParseDate(*q.AvailableOn)
+		if err != nil {
+			return nil, common.ErrValidation
+		}
+		flt.AvailableOn = &t
+	}
+	items, err := s.repo.List(ctx, flt)
+	if err != nil {
+		logx.Std.Errorf("list figurants failed: %s", err)
+		return nil, err
+	}
+	out := make([]*dto.Figurant, 0, len(items))
+	for _, it := range items {
+		out = append(out, toDTO(it))
+	}
+	return out, nil
+}
+
+func (s *Service) Update(ctx context.Context, cmd UpdateFigurantCommand) (*dto.Figurant, error) {
+	logx.Std.Tracef("update figurant %v", cmd)
+	f, err := s.repo.Get(ctx, figurant.FigurantID(cmd.ID))
+	if err != nil {
+		return nil, err
+	}
+	if err := apply(f, cmd); err != nil {
+		return nil, err
+	}
+	f.UpdatedAt = time.Now().UTC()
+	if err := s.repo.Update(ctx, f); err != nil {
+		logx.Std.Errorf("update figurant failed: %s", err)
+		return nil, err
+	}
+	return toDTO(f), nil
+}
+
+func (s *Service) Delete(ctx context.Context, id int64) error {
+	logx.Std.Tracef("delete figurant %d", id)
+	err := s.repo.Delete(ctx, figurant.FigurantID(id))
+	if err != nil && err != common.ErrNotFound && err != common.ErrConflict {
+		logx.Std.Errorf("delete figurant failed: %s", err)
+	}
+	return err
+}
+
+func (s *Service) AddAvailability(ctx context.Context, cmd AddAvailabilityCommand) (*dto.FigurantAvailability, error) {
+	logx.Std.Tracef("add availability %v", cmd)
+	start, err := common.ParseDate(cmd.StartDate)
+	if err != nil {
+		return nil, common.ErrValidation
+	}
+	end := start
+	if cmd.EndDate != nil {
+		if end, err = common.ParseDate(*cmd.EndDate); err != nil || end.Before(start) {
+			return nil, common.ErrValidation
+		}
+	}
+	a := &figurant.Availability{FigurantID: figurant.FigurantID(cmd.FigurantID), StartDate: start, EndDate: end, Notes: cmd.Notes}
+	if err := s.repo.AddAvailability(ctx, a); err != nil {
+		if err != common.ErrNotFound {
+			logx.Std.Errorf("add availability failed: %s", err)
+		}
+		return nil, err
+	}
+	return toAvailabilityDTO(a), nil
+}
+
+func (s *Service) ListAvailability(ctx context.Context, id int64) ([]*dto.FigurantAvailability, error) {
+	logx.Std.Tracef("list availability of figurant %d", id)
+	if _, err := s.repo.Get(ctx, figurant.FigurantID(id)); err != nil {
+		return nil, err
+	}
+	items, err := s.repo.ListAvailability(ctx, figurant.FigurantID(id))
+	if err != nil {
+		logx.Std.Errorf("list availability failed: %s", err)
+		return nil, err
+	}
+	out := make([]*dto.FigurantAvailability, 0, len(items))
+	for _, it := range items {
+		out = append(out, toAvailabilityDTO(it))
+	}
+	return out, nil
+}
+
+func (s *Service) DeleteAvailability(ctx context.Context, id, availabilityID int64) error {
+	logx.Std.Tracef("delete availability %d of figurant %d", availabilityID, id)
+	err := s.repo.DeleteAvailability(ctx, figurant.FigurantID(id), availabilityID)
+	if err != nil && err != common.ErrNotFound {
+		logx.Std.Errorf("delete availability failed: %s", err)
+	}
+	return err
+}
+
+// History lists the rounds the figurant acted in, newest first, optionally
+// only those with one dog.
+func (s *Service) History(ctx context.Context, id int64, dogID *int64) ([]*dto.FigurantAppearance, error) {
+	logx.Std.Tracef("list history of figurant %d", id)
+	if _, err := s.repo.Get(ctx, figurant.FigurantID(id)); err != nil {
+		return nil, err
+	}
+	fid := figurant.FigurantID(id)
+	items, err := s.repo.History(ctx, figurant.HistoryFilter{FigurantID: &fid, DogID: dogID})
+	if err != nil {
+		logx.Std.Errorf("list figurant history failed: %s", err)
+		return nil, err
+	}
+	out := make([]*dto.FigurantAppearance, 0, len(items))
+	for _, it := range items {
+		out = append(out, &dto.FigurantAppearance{
+			FigurantID: int64(it.FigurantID), RoundID: it.RoundID, SessionID: it.SessionID, RoundNumber: it.RoundNumber,
+			DogID: it.DogID, DogName: it.DogName, Date: it.Date,
+		})
+	}
+	return out, nil
+}
+
+// SessionFigurants lists the figurants scheduled for a session and whether
+// their availability covers the session's day.
+func (s *Service) SessionFigurants(ctx context.Context, sessionID int64) ([]*dto.SessionFigurant, error) {
+	logx.Std.Tracef("list figurants of session %d", sessionID)
+	day, err := s.sessionDay(ctx, sessionID)
+	if err != nil {
+		return nil, err
+	}
+	items, err := s.repo.ListBySession(ctx, sessionID)
+	if err != nil {
+		logx.Std.Errorf("list session figurants failed: %s", err)
+		return nil, err
+	}
+	available, err := s.repo.AvailableOn(ctx, day)
+	if err != nil {
+		logx.Std.Errorf("list available figurants failed: %s", err)
+		return nil, err
+	}
+	out := make([]*dto.SessionFigurant, 0, len(items))
+	for _, it := range items {
+		out = append(out, &dto.SessionFigurant{Figurant: toDTO(it), Available: available[it.ID]})
+	}
+	return out, nil
+}
+
+func (s *Service) AssignSession(ctx context.Context, sessionID, id int64) error {
+	logx.Std.Tracef("assign figurant %d to session %d", id, sessionID)
+	if _, err := s.sessions.GetSession(ctx, sessionID); err != nil {
+		return err
+	}
+	f, err := s.repo.Get(ctx, figurant.FigurantID(id))
+	if err == common.ErrNotFound {
+		return common.ErrValidation
+	}
+	if err != nil {
+		return err
+	}
+	if !f.Active {
+		return common.ErrValidation
+	}
+	if err := s.repo.AssignSession(ctx, sessionID, f.ID); err != nil {
+		logx.Std.Errorf("assign figurant failed: %s", err)
+		return err
+	}
+	return nil
+}
+
+func (s *Service) UnassignSession(ctx context.Context, sessionID, id int64) error {
+	logx.Std.Tracef("unassign figurant %d from session %d", id, sessionID)
+	err := s.repo.UnassignSession(ctx, sessionID, figurant.FigurantID(id))
+	if err != nil && err != common.ErrNotFound {
+		logx.Std.Errorf("unassign figurant failed: %s", err)
+	}
+	return err
+}
+
+func (s *Service) RoundFigurants(ctx context.Context, sessionID, roundID int64, windowDays *int) ([]*dto.FigurantReuse, error) {
+	logx.Std.Tracef("list figurants of round %d", roundID)
+	r, err := s.round(ctx, sessionID, roundID)
+	if err != nil {
+		return nil, err
+	}
+	items, err := s.repo.ListByRound(ctx, roundID)
+	if err != nil {
+		logx.Std.Errorf("list round figurants failed: %s", err)
+		return nil, err
+	}
+	return s.reuse(ctx, r.SessionID, r.DogID, r.ID, windowDays, items)
+}
+
+// SetRoundFigurants replaces who acted as hidden person or trail layer in a
+// round. Figurants must be active, and mantrailing rounds need their scent
+// consent. The result tells how often each of them recently worked with the
+// round's dog. Only the dog's handlers on the round's day and admins may
+// change them.
+func (s *Service) SetRoundFigurants(ctx context.Context, cmd SetRoundFigurantsCommand) ([]*dto.FigurantReuse, error) {
+	logx.Std.Tracef("set round figurants %v", cmd)
+	r, err := s.round(ctx, cmd.SessionID, cmd.RoundID)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.authorize(ctx, r, cmd.UserID); err != nil {
+		return nil, err
+	}
+	var items []*figurant.Figurant
+	ids := make([]figurant.FigurantID, 0, len(cmd.FigurantIDs))
+	for _, id := range cmd.FigurantIDs {
+		f, err := s.repo.Get(ctx, figurant.FigurantID(id))
+		if err == common.ErrNotFound {
+			return nil, common.ErrValidation
+		}
+		if err != nil {
+			return nil, err
+		}
+		if !f.Active || (r.Trailing != nil && !f.ScentConsent) {
+			return nil, common.ErrValidation
+		}
+		items, ids = append(items, f), append(ids, f.ID)
+	}
+	if err := s.repo.SetRoundFigurants(ctx, r.ID, ids); err != nil {
+		logx.Std.Errorf("set round figurants failed: %s", err)
+		return nil, err
+	}
+	return s.reuse(ctx, r.SessionID, r.DogID, r.ID, cmd.WindowDays, items)
+}
+
+// Suggest ranks the active figurants for a dog in a session: those not
+// reused too often first, then those available that day, then those who
+// worked with the dog least recently.
+func (s *Service) Suggest(ctx context.Context, q SuggestFigurantsQuery) ([]*dto.FigurantReuse, error) {
+	logx.Std.Tracef("suggest figurants %v", q)
+	if q.DogID <= 0 {
+		return nil, common.ErrValidation
+	}
+	items, err := s.repo.List(ctx, figurant.Filter{ActiveOnly: true})
+	if err != nil {
+		logx.Std.Errorf("list figurants failed: %s", err)
+		return nil, err
+	}
+	out, err := s.reuse(ctx, q.SessionID, q.DogID, 0, q.WindowDays, items)
+	if err != nil {
+		return nil, err
+	}
+	sort.SliceStable(out, func(i, j int) bool {
+		a, b := out[i], out[j]
+		if a.TooOften != b.TooOften {
+			return !a.TooOften
+		}
+		if a.Available != b.Available {
+			return a.Available
+		}
+		if a.RoundsWithDog != b.RoundsWithDog {
+			return a.RoundsWithDog < b.RoundsWithDog
+		}
+		if (a.LastWithDog == nil) != (b.LastWithDog == nil) {
+			return a.LastWithDog == nil
+		}
+		return a.LastWithDog != nil && *a.LastWithDog < *b.LastWithDog
+	})
+	return out, nil
+}
+
+// reuse reports for each figurant their rounds with the dog in the window
+// before the session's day, not counting round skipRoundID.
+func (s *Service) reuse(ctx context.Context, sessionID, dogID, skipRoundID int64, windowDays *int, items []*figurant.Figurant) ([]*dto.FigurantReuse, error) {
+	window := DefaultReuseWindowDays
+	if windowDays != nil {
+		if *windowDays <= 0 {
+			return nil, common.ErrValidation
+		}
+		window = *windowDays
+	}
+	day, err := s.sessionDay(ctx, sessionID)
+	if err != nil {
+		return nil, err
+	}
+	available, err := s.repo.AvailableOn(ctx, day)
+	if err != nil {
+		logx.Std.Errorf("list available figurants failed: %s", err)
+		return nil, err
+	}
+	scheduled, err := s.repo.ListBySession(ctx, sessionID)
+	if err != nil {
+		logx.Std.Errorf("list session figurants failed: %s", err)
+		return nil, err
+	}
+	inSession := map[figurant.FigurantID]bool{}
+	for _, f := range scheduled {
+		inSession[f.ID] = true
+	}
+	history, err := s.repo.History(ctx, figurant.HistoryFilter{DogID: &dogID})
+	if err != nil {
+		logx.Std.Errorf("list figurant history failed: %s", err)
+		return nil, err
+	}
+	from := day.AddDate(0, 0, -window)
+	counts := map[figurant.FigurantID]int{}
+	last := map[figurant.FigurantID]string{}
+	// history is ordered newest first
+	for _, a := range history {
+		if a.RoundID == skipRoundID {
+			continue
+		}
+		t, err := common.ParseDate(a.Date)
+		if err != nil || t.After(day) {
+			continue
+		}
+		if _, ok := last[a.FigurantID]; !ok {
+			last[a.FigurantID] = t.Format(common.DateLayout)
+		}
+		if t.After(from) {
+			counts[a.FigurantID]++
+		}
+	}
+	out := make([]*dto.FigurantReuse, 0, len(items))
+	for _, f := range items {
+		res := &dto.FigurantReuse{
+			Figurant: toDTO(f), Available: available[f.ID], InSession: inSession[f.ID],
+			RoundsWithDog: counts[f.ID], TooOften: counts[f.ID] >= MaxRoundsWithDog,
+		}
+		if v, ok := last[f.ID]; ok {
+			res.LastWithDog = &v
+		}
+		out = append(out, res)
+	}
+	return out, nil
+}
+
+// round returns the round, checking that it belongs to the session.
+func (s *Service) round(ctx context.Context, sessionID, roundID int64) (*session.Round, error) {
+	r, err := s.sessions.GetRound(ctx, roundID)
+	if err != nil {
+		return nil, err
+	}
+	if r.SessionID != sessionID {
+		return nil, common.ErrNotFound
+	}
+	return r, nil
+}
+
+// authorize lets admins and anyone handling the round's dog on the round's
+// day change the round. Dogs without a handler that day are open to all.
+func (s *Service) authorize(ctx context.Context, r *session.Round, userID int64) error {
+	day := time.Now().UTC()
+	if r.StartedAt != nil {
+		day = *r.StartedAt
+	}
+	active, err := s.pairings.List(ctx, pairing.Filter{DogID: &r.DogID, ActiveOn: &day})
+	if err != nil {
+		logx.Std.Errorf("list pairings failed: %s", err)
+		return err
+	}
+	if len(active) > 0 && !pairing.Handles(active, userID) {
+		u, err := s.users.GetUserByID(ctx, userID)
+		if err != nil || !u.IsAdmin {
+			return common.ErrForbidden
+		}
+	}
+	return nil
+}
+
+func (s *Service) sessionDay(ctx context.Context, sessionID int64) (time.Time, error) {
+	ses, err := s.sessions.GetSession(ctx, sessionID)
+	if err != nil {
+		return time.Time{}, err
+	}
+	day, err := common.ParseDate(ses.StartedAt)
+	if err != nil {
+		y, m, d := time.Now().UTC().Date()
+		return time.Date(y, m, d, 0, 0, 0, 0, time.UTC), nil
+	}
+	return day, nil
+}
+
+func apply(f *figurant.Figurant, cmd UpdateFigurantCommand) error {
+	if cmd.Name == "" {
+		return common.ErrValidation
+	}
+	f.ConsentDate = nil
+	if cmd.ScentConsent {
+		y, m, d := time.Now().UTC().Date()
+		t := time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
+		if cmd.ConsentDate != nil {
+			var err error
+			if t, err = common.ParseDate(*cmd.ConsentDate); err != nil {
+				return common.ErrValidation
+			}
+		}
+		f.ConsentDate = &t
+	}
+	f.Active = cmd.Active == nil || *cmd.Active
+	f.Name, f.Phone, f.Email, f.ScentConsent, f.Notes = cmd.Name, cmd.Phone, cmd.Email, cmd.ScentConsent, cmd.Notes
+	return nil
+}
+
+func toDTO(f *figurant.Figurant) *dto.Figurant {
+	out := &dto.Figurant{
+		ID: int64(f.ID), Name: f.Name, Phone: f.Phone, Email: f.Email, ScentConsent: f.ScentConsent,
+		Active: f.Active, Notes: f.Notes, CreatedAt: f.CreatedAt.Format(time.RFC3339), UpdatedAt: f.UpdatedAt.Format(time.RFC3339),
+	}
+	if f.ConsentDate != nil {
+		v := f.ConsentDate.Format(common.DateLayout)
+		out.ConsentDate = &v
+	}
+	return out
+}
+
+func toAvailabilityDTO(a *figurant.Availability) *dto.FigurantAvailability {
+	return &dto.FigurantAvailability{
+		ID: a.ID, FigurantID: int64(a.FigurantID), StartDate: a.StartDate.Format(common.DateLayout),
+		EndDate: a.EndDate.Format(common.DateLayout), Notes: a.Notes,
+	}
+}
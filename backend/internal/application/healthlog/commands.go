@@ -0,0 +1,35 @@
+package healthlog
+
+type CreateEntryCommand struct {
+	DogID    int64    `json:"-"`
+	Kind     string   `json:"kind"`
+	Date     *string  `json:"date,omitempty"`
+	EndDate  *string  `json:"end_date,omitempty"`
+	DueDate  *string  `json:"due_date,omitempty"`
+	Title    *string  `json:"title,omitempty"`
+	Vet      *string  `json:"vet,omitempty"`
+	WeightKg *float64 `json:"weight_kg,omitempty"`
+	NotFit   *bool    `json:"not_fit,omitempty"`
+	Notes    *string  `json:"notes,omitempty"`
+}
+
+type UpdateEntryCommand struct {
+	ID       int64    `json:"-"`
+	DogID    int64    `json:"-"`
+	Kind     string   `json:"kind"`
+	Date     *string  `json:"date,omitempty"`
+	EndDate  *string  `json:"end_date,omitempty"`
+	DueDate  *string  `json:"due_date,omitempty"`
+	Title    *string  `json:"title,omitempty"`
+	Vet      *string  `json:"vet,omitempty"`
+	WeightKg *float64 `json:"weight_kg,omitempty"`
+	NotFit   *bool    `json:"not_fit,omitempty"`
+	Notes    *string  `json:"notes,omitempty"`
+}
+
+type ListEntriesQuery struct {
+	DogID int64
+	Kind  *string
+	From  *string
+	To    *string
+}
@@ -0,0 +1,257 @@
+package healthlog
+
+import (
+	"context"
+	"time"
+
+	"github.com/tnosaj/sar-training/backend/internal/application/dto"
+	"github.com/tnosaj/sar-training/backend/internal/domain/common"
+	"github.com/tnosaj/sar-training/backend/internal/domain/dog"
+	"github.com/tnosaj/sar-training/backend/internal/domain/health"
+	logx "github.com/tnosaj/sar-training/backend/internal/infra/log"
+)
+
+// DefaultDueWithinDays is how far ahead Due looks when no horizon is given.
+const DefaultDueWithinDays = 30
+
+type Service struct {
+	repo health.Repository
+	dogs dog.Repository
+}
+
+func NewService(r health.Repository, d dog.Repository) *Service {
+	logx.Std.Trace("starting health log service")
+	return &Service{repo: r, dogs: d}
+}
+
+func (s *Service) Create(ctx context.Context, cmd CreateEntryCommand) (*dto.HealthEntry, error) {
+	logx.Std.Tracef("create health entry %v", cmd)
+	if _, err := s.dogs.Get(ctx, dog.DogID(cmd.DogID)); err != nil {
+		return nil, err
+	}
+	now := time.Now().UTC()
+	e := &health.Entry{DogID: cmd.DogID, Title: cmd.Title, Vet: cmd.Vet, WeightKg: cmd.WeightKg, Notes: cmd.Notes, CreatedAt: now, UpdatedAt: now}
+	if err := apply(e, cmd.Kind, cmd.Date, cmd.EndDate, cmd.DueDate, cmd.NotFit); err != nil {
+		return nil, err
+	}
+	if err := s.repo.Create(ctx, e); err != nil {
+		logx.Std.Errorf("create health entry failed: %s", err)
+		return nil, err
+	}
+	return toDTO(e), nil
+}
+
+func (s *Service) Update(ctx context.Context, cmd UpdateEntryCommand) (*dto.HealthEntry, error) {
+	logx.Std.Tracef("update health entry %v", cmd)
+	e, err := s.repo.Get(ctx, health.EntryID(cmd.ID))
+	if err != nil {
+		return nil, err
+	}
+	if e.DogID != cmd.DogID {
+		return nil, common.ErrNotFound
+	}
+	e.Title, e.Vet, e.WeightKg, e.Notes, e.UpdatedAt = cmd.Title, cmd.Vet, cmd.WeightKg, cmd.Notes, time.Now().UTC()
+	if err := apply(e, cmd.Kind, cmd.Date, cmd.EndDate, cmd.DueDate, cmd.NotFit); err != nil {
+		return nil, err
+	}
+	if err := s.repo.Update(ctx, e); err != nil {
+		logx.Std.Errorf("update health entry failed: %s", err)
+		return nil, err
+	}
+	return toDTO(e), nil
+}
+
+func (s *Service) Delete(ctx context.Context, dogID, id int64) error {
+	logx.Std.Tracef("delete health entry %d of dog %d", id, dogID)
+	e, err := s.repo.Get(ctx, health.EntryID(id))
+	if err != nil {
+		return err
+	}
+	if e.DogID != dogID {
+		return common.ErrNotFound
+	}
+	if err := s.repo.Delete(ctx, e.ID); err != nil {
+		logx.Std.Errorf("delete health entry failed: %s", err)
+		return err
+	}
+	return nil
+}
+
+func (s *Service) Get(ctx context.Context, dogID, id int64) (*dto.HealthEntry, error) {
+	logx.Std.Tracef("get health entry %d of dog %d", id, dogID)
+	e, err := s.repo.Get(ctx, health.EntryID(id))
+	if err != nil {
+		return nil, err
+	}
+	if e.DogID != dogID {
+		return nil, common.ErrNotFound
+	}
+	return toDTO(e), nil
+}
+
+func (s *Service) List(ctx context.Context, q ListEntriesQuery) ([]*dto.HealthEntry, error) {
+	logx.Std.Tracef("list health entries %v", q)
+	f := health.Filter{DogID: &q.DogID}
+	if q.Kind != nil {
+		k := health.Kind(*q.Kind)
+		if !k.Valid() {
+			return nil, common.ErrValidation
+		}
+		f.Kind = &k
+	}
+	for _, p := range []struct {
+		in  *string
+		out **time.Time
+	}{{q.From, &f.From}, {q.To, &f.To}} {
+		if p.in == nil {
+			continue
+		}
+		t, err := common.ParseDate(*p.in)
+		if err != nil {
+			return nil, common.ErrValidation
+		}
+		*p.out = &t
+	}
+	items, err := s.repo.List(ctx, f)
+	if err != nil {
+		logx.Std.Errorf("list health entries failed: %s", err)
+		return nil, err
+	}
+	return toDTOs(items), nil
+}
+
+// Fitness reports whether the dog is fit for training on the day of t, with
+// the entries that say otherwise.
+func (s *Service) Fitness(ctx context.Context, dogID int64, t time.Time) (*dto.Fitness, error) {
+	logx.Std.Tracef("fitness of dog %d on %s", dogID, t)
+	if _, err := s.dogs.Get(ctx, dog.DogID(dogID)); err != nil {
+		return nil, err
+	}
+	items, err := s.repo.NotFit(ctx, dogID, t)
+	if err != nil {
+		logx.Std.Errorf("fitness check failed: %s", err)
+		return nil, err
+	}
+	return &dto.Fitness{DogID: dogID, Date: t.UTC().Format(common.DateLayout), Fit: len(items) == 0, Entries: toDTOs(items)}, nil
+}
+
+// Due lists vaccinations and other entries whose due date falls within the
+// next withinDays days or has already passed. Only the latest entry per dog,
+// kind and title counts, so a repeated vaccination clears the earlier one.
+func (s *Service) Due(ctx context.Context, withinDays int) ([]*dto.HealthEntry, error) {
+	logx.Std.Tracef("health entries due within %d days", withinDays)
+	if withinDays < 0 {
+		return nil, common.ErrValidation
+	}
+	if withinDays == 0 {
+		withinDays = DefaultDueWithinDays
+	}
+	horizon := time.Now().UTC().AddDate(0, 0, withinDays)
+	items, err := s.repo.List(ctx, health.Filter{DueBefore: &horizon})
+	if err != nil {
+		logx.Std.Errorf("list due health entries failed: %s", err)
+		return nil, err
+	}
+	out := []*dto.HealthEntry{}
+	for _, e := range items {
+		superseded, err := s.superseded(ctx, e)
+		if err != nil {
+			return nil, err
+		}
+		if !superseded {
+			out = append(out, toDTO(e))
+		}
+	}
+	return out, nil
+}
+
+func (s *Service) superseded(ctx context.Context, e *health.Entry) (bool, error) {
+	after := e.Date.AddDate(0, 0, 1)
+	later, err := s.repo.List(ctx, health.Filter{DogID: &e.DogID, Kind: &e.Kind, From: &after})
+	if err != nil {
+		return false, err
+	}
+	for _, l := range later {
+		if l.ID != e.ID && l.Date.After(e.Date) && sameTitle(l.Title, e.Title) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func sameTitle(a, b *string) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
+
+func apply(e *health.Entry, kind string, date, end, due *string, notFit *bool) error {
+	e.Kind = health.Kind(kind)
+	if !e.Kind.Valid() {
+		return common.ErrValidation
+	}
+	y, m, d := time.Now().UTC().Date()
+	e.Date = time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
+	if date != nil {
+		t, err := common.ParseDate(*date)
+		if err != nil {
+			return common.ErrValidation
+		}
+		e.Date = t
+	}
+	e.EndDate, e.DueDate = nil, nil
+	if end != nil {
+		t, err := common.ParseDate(*end)
+		if err != nil || t.Before(e.Date) {
+			return common.ErrValidation
+		}
+		e.EndDate = &t
+	}
+	if due != nil {
+		t, err := common.ParseDate(*due)
+		if err != nil {
+			return common.ErrValidation
+		}
+		e.DueDate = &t
+	}
+	if (e.Kind == health.KindVaccination || e.Kind == health.KindMedication) && (e.Title == nil || *e.Title == "") {
+		return common.ErrValidation
+	}
+	if e.Kind == health.KindWeight && e.WeightKg == nil {
+		return common.ErrValidation
+	}
+	if e.WeightKg != nil && *e.WeightKg <= 0 {
+		return common.ErrValidation
+	}
+	// a rest period means no training unless stated otherwise
+	e.NotFit = e.Kind == health.KindRest
+	if notFit != nil {
+		e.NotFit = *notFit
+	}
+	return nil
+}
+
+func toDTOs(items []*health.Entry) []*dto.HealthEntry {
+	out := make([]*dto.HealthEntry, 0, len(items))
+	for _, it := range items {
+		out = append(out, toDTO(it))
+	}
+	return out
+}
+
+func toDTO(e *health.Entry) *dto.HealthEntry {
+	out := &dto.HealthEntry{
+		ID: int64(e.ID), DogID: e.DogID, Kind: string(e.Kind), Date: e.Date.Format(common.DateLayout), Title: e.Title, Vet: e.Vet,
+		WeightKg: e.WeightKg, NotFit: e.NotFit, Notes: e.Notes, CreatedAt: e.CreatedAt.Format(time.RFC3339), UpdatedAt: e.UpdatedAt.Format(time.RFC3339),
+	}
+	if e.EndDate != nil {
+		v := e.EndDate.Format(common.DateLayout)
+		out.EndDate = &v
+	}
+	if e.DueDate != nil {
+		v := e.DueDate.Format(common.DateLayout)
+		out.DueDate = &v
+	}
+	return out
+}
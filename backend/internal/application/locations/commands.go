@@ -0,0 +1,33 @@
+package locations
+
+import "github.com/tnosaj/sar-training/backend/internal/application/dto"
+
+type CreateLocationCommand struct {
+	Name        string          `json:"name"`
+	Latitude    *float64        `json:"latitude,omitempty"`
+	Longitude   *float64        `json:"longitude,omitempty"`
+	Terrain     *string         `json:"terrain,omitempty"`
+	AccessNotes *string         `json:"access_notes,omitempty"`
+	Boundary    *dto.GeoPolygon `json:"boundary,omitempty"`
+}
+
+type UpdateLocationCommand struct {
+	ID          int64           `json:"-"`
+	Name        string          `json:"name"`
+	Latitude    *float64        `json:"latitude,omitempty"`
+	Longitude   *float64        `json:"longitude,omitempty"`
+	Terrain     *string         `json:"terrain,omitempty"`
+	AccessNotes *string         `json:"access_notes,omitempty"`
+	Boundary    *dto.GeoPolygon `json:"boundary,omitempty"`
+}
+
+type NearbyQuery struct {
+	Latitude  float64
+	Longitude float64
+	RadiusKm  float64
+}
+
+type MergeLocationsCommand struct {
+	TargetID  int64   `json:"-"`
+	SourceIDs []int64 `json:"source_ids"`
+}
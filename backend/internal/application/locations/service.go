@@ -0,0 +1,221 @@
+package locations
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/tnosaj/sar-training/backend/internal/application/dto"
+	"github.com/tnosaj/sar-training/backend/internal/domain/common"
+	"github.com/tnosaj/sar-training/backend/internal/domain/location"
+	logx "github.com/tnosaj/sar-training/backend/internal/infra/log"
+)
+
+// DefaultRadiusKm is the search radius of Nearby when none is given.
+const DefaultRadiusKm = 10
+
+type Service struct{ repo location.Repository }
+
+func NewService(r location.Repository) *Service {
+	logx.Std.Trace("starting locations service")
+	return &Service{repo: r}
+}
+
+func (s *Service) Create(ctx context.Context, cmd CreateLocationCommand) (*dto.Location, error) {
+	logx.Std.Tracef("create location %v", cmd)
+	now := time.Now().UTC()
+	l := &location.Location{Terrain: cmd.Terrain, AccessNotes: cmd.AccessNotes, CreatedAt: now, UpdatedAt: now}
+	if err := apply(l, cmd.Name, cmd.Latitude, cmd.Longitude, cmd.Boundary); err != nil {
+		return nil, err
+	}
+	if err := s.repo.Create(ctx, l); err != nil {
+		if err != common.ErrConflict {
+			logx.Std.Errorf("create location failed: %s", err)
+		}
+		return nil, err
+	}
+	return toDTO(l), nil
+}
+
+func (s *Service) Update(ctx context.Context, cmd UpdateLocationCommand) (*dto.Location, error) {
+	logx.Std.Tracef("update location %v", cmd)
+	l, err := s.repo.Get(ctx, location.LocationID(cmd.ID))
+	if err != nil {
+		return nil, err
+	}
+	l.Terrain, l.AccessNotes, l.UpdatedAt = cmd.Terrain, cmd.AccessNotes, time.Now().UTC()
+	if err := apply(l, cmd.Name, cmd.Latitude, cmd.Longitude, cmd.Boundary); err != nil {
+		return nil, err
+	}
+	if err := s.repo.Update(ctx, l); err != nil {
+		if err != common.ErrConflict {
+			logx.Std.Errorf("update location failed: %s", err)
+		}
+		return nil, err
+	}
+	return toDTO(l), nil
+}
+
+func (s *Service) Delete(ctx context.Context, id int64) error {
+	logx.Std.Tracef("delete location %d", id)
+	err := s.repo.Delete(ctx, location.LocationID(id))
+	if err != nil && err != common.ErrNotFound {
+		logx.Std.Errorf("delete location failed: %s", err)
+	}
+	return err
+}
+
+func (s *Service) Get(ctx context.Context, id int64) (*dto.Location, error) {
+	logx.Std.Tracef("get location %d", id)
+	l, err := s.repo.Get(ctx, location.LocationID(id))
+	if err != nil {
+		return nil, err
+	}
+	return toDTO(l), nil
+}
+
+func (s *Service) List(ctx context.Context) ([]*dto.Location, error) {
+	logx.Std.Trace("list locations")
+	items, err := s.repo.List(ctx)
+	if err != nil {
+		logx.Std.Errorf("list locations failed: %s", err)
+		return nil, err
+	}
+	out := make([]*dto.Location, 0, len(items))
+	for _, it := range items {
+		out = append(out, toDTO(it))
+	}
+	return out, nil
+}
+
+// Nearby returns the locations within q.RadiusKm of the point, nearest
+// first. A location whose boundary contains the point is at distance 0.
+func (s *Service) Nearby(ctx context.Context, q NearbyQuery) ([]*dto.Location, error) {
+	logx.Std.Tracef("nearby locations %v", q)
+	p := location.Point{Lat: q.Latitude, Lon: q.Longitude}
+	if !p.Valid() || q.RadiusKm < 0 {
+		return nil, common.ErrValidation
+	}
+	if q.RadiusKm == 0 {
+		q.RadiusKm = DefaultRadiusKm
+	}
+	items, err := s.repo.List(ctx)
+	if err != nil {
+		logx.Std.Errorf("list locations failed: %s", err)
+		return nil, err
+	}
+	out := []*dto.Location{}
+	for _, it := range items {
+		var d float64
+		switch {
+		case len(it.Boundary) > 0 && location.Contains(it.Boundary, p):
+			d = 0
+		case it.Center != nil:
+			d = location.DistanceKm(*it.Center, p)
+		default:
+			continue
+		}
+		if d > q.RadiusKm {
+			continue
+		}
+		l := toDTO(it)
+		l.DistanceKm = &d
+		out = append(out, l)
+	}
+	sort.SliceStable(out, func(i, j int) bool { return *out[i].DistanceKm < *out[j].DistanceKm })
+	return out, nil
+}
+
+// Merge folds duplicate locations into the target, moving their sessions.
+func (s *Service) Merge(ctx context.Context, cmd MergeLocationsCommand) (*dto.Location, error) {
+	logx.Std.Tracef("merge locations %v", cmd)
+	var sources []location.LocationID
+	seen := map[int64]bool{}
+	for _, id := range cmd.SourceIDs {
+		if id == cmd.TargetID || id <= 0 {
+			return nil, common.ErrValidation
+		}
+		if !seen[id] {
+			seen[id] = true
+			sources = append(sources, location.LocationID(id))
+		}
+	}
+	if len(sources) == 0 {
+		return nil, common.ErrValidation
+	}
+	moved, err := s.repo.Merge(ctx, location.LocationID(cmd.TargetID), sources)
+	if err != nil {
+		if err != common.ErrNotFound {
+			logx.Std.Errorf("merge locations failed: %s", err)
+		}
+		return nil, err
+	}
+	logx.Std.Infof("merged %d locations into %d, moved %d sessions", len(sources), cmd.TargetID, moved)
+	return s.Get(ctx, cmd.TargetID)
+}
+
+func (s *Service) Stats(ctx context.Context, id int64) (*dto.LocationStats, error) {
+	logx.Std.Tracef("stats of location %d", id)
+	l, err := s.repo.Get(ctx, location.LocationID(id))
+	if err != nil {
+		return nil, err
+	}
+	st, err := s.repo.Stats(ctx, l.ID)
+	if err != nil {
+		logx.Std.Errorf("location stats failed: %s", err)
+		return nil, err
+	}
+	out := &dto.LocationStats{
+		LocationID: id, Name: l.Name, Sessions: st.Sessions, Rounds: st.Rounds, Successes: st.Successes, Partials: st.Partials,
+		Fails: st.Fails, Dogs: st.Dogs, MeanScore: st.MeanScore, FirstSession: st.FirstSession, LastSession: st.LastSession,
+	}
+	if st.Rounds > 0 {
+		out.SuccessRate = float64(st.Successes) / float64(st.Rounds)
+	}
+	return out, nil
+}
+
+func apply(l *location.Location, name string, lat, lon *float64, boundary *dto.GeoPolygon) error {
+	l.Name = strings.TrimSpace(name)
+	if l.Name == "" || (lat == nil) != (lon == nil) {
+		return common.ErrValidation
+	}
+	l.Center, l.Boundary = nil, nil
+	if lat != nil {
+		p := location.Point{Lat: *lat, Lon: *lon}
+		if !p.Valid() {
+			return common.ErrValidation
+		}
+		l.Center = &p
+	}
+	if boundary != nil {
+		if boundary.Type != "Polygon" || len(boundary.Coordinates) == 0 {
+			return common.ErrValidation
+		}
+		ring, ok := location.RingFromPositions(boundary.Coordinates[0])
+		if !ok {
+			return common.ErrValidation
+		}
+		l.Boundary = ring
+		if l.Center == nil {
+			c := location.Centroid(l.Boundary)
+			l.Center = &c
+		}
+	}
+	return nil
+}
+
+func toDTO(l *location.Location) *dto.Location {
+	out := &dto.Location{
+		ID: int64(l.ID), Name: l.Name, Terrain: l.Terrain, AccessNotes: l.AccessNotes,
+		CreatedAt: l.CreatedAt.Format(time.RFC3339), UpdatedAt: l.UpdatedAt.Format(time.RFC3339),
+	}
+	if l.Center != nil {
+		out.Latitude, out.Longitude = &l.Center.Lat, &l.Center.Lon
+	}
+	if len(l.Boundary) > 0 {
+		out.Boundary = &dto.GeoPolygon{Type: "Polygon", Coordinates: [][][2]float64{location.Positions(l.Boundary)}}
+	}
+	return out
+}
@@ -0,0 +1,47 @@
+package missions
+
+import "github.com/tnosaj/sar-training/backend/internal/application/dto"
+
+type TeamInput struct {
+	DogID         int64   `json:"dog_id"`
+	HandlerID     *int64  `json:"handler_id,omitempty"`
+	DisciplineID  *int64  `json:"discipline_id,omitempty"`
+	Found         bool    `json:"found"`
+	SearchMinutes *int    `json:"search_minutes,omitempty"`
+	Notes         *string `json:"notes,omitempty"`
+}
+
+type CreateMissionCommand struct {
+	StartedAt      string          `json:"started_at"`
+	EndedAt        *string         `json:"ended_at,omitempty"`
+	AlertingAgency string          `json:"alerting_agency"`
+	Reference      *string         `json:"reference,omitempty"`
+	Location       *string         `json:"location,omitempty"`
+	LocationID     *int64          `json:"location_id,omitempty"`
+	SearchArea     *dto.GeoPolygon `json:"search_area,omitempty"`
+	Outcome        string          `json:"outcome"`
+	Debrief        *string         `json:"debrief,omitempty"`
+	Teams          []TeamInput     `json:"teams"`
+}
+
+type UpdateMissionCommand struct {
+	ID             int64           `json:"-"`
+	StartedAt      string          `json:"started_at"`
+	EndedAt        *string         `json:"ended_at,omitempty"`
+	AlertingAgency string          `json:"alerting_agency"`
+	Reference      *string         `json:"reference,omitempty"`
+	Location       *string         `json:"location,omitempty"`
+	LocationID     *int64          `json:"location_id,omitempty"`
+	SearchArea     *dto.GeoPolygon `json:"search_area,omitempty"`
+	Outcome        string          `json:"outcome"`
+	Debrief        *string         `json:"debrief,omitempty"`
+	Teams          []TeamInput     `json:"teams"`
+}
+
+type ListMissionsQuery struct {
+	DogID     *int64
+	HandlerID *int64
+	Outcome   *string
+	From      *string
+	To        *string
+}
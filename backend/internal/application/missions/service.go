@@ -0,0 +1,267 @@
+package missions
+
+import (
+	"context"
+	"math"
+	"time"
+
+	"github.com/tnosaj/sar-training/backend/internal/application/dto"
+	"github.com/tnosaj/sar-training/backend/internal/domain/common"
+	"github.com/tnosaj/sar-training/backend/internal/domain/location"
+	"github.com/tnosaj/sar-training/backend/internal/domain/mission"
+	logx "github.com/tnosaj/sar-training/backend/internal/infra/log"
+)
+
+type Service struct {
+	repo      mission.Repository
+	locations location.Repository
+}
+
+func NewService(r mission.Repository, l location.Repository) *Service {
+	logx.Std.Trace("starting missions service")
+	return &Service{repo: r, locations: l}
+}
+
+func (s *Service) Create(ctx context.Context, cmd CreateMissionCommand) (*dto.Mission, error) {
+	logx.Std.Tracef("create mission %v", cmd)
+	now := time.Now().UTC()
+	m := &mission.Mission{CreatedAt: now, UpdatedAt: now}
+	if err := s.apply(ctx, m, UpdateMissionCommand{
+		StartedAt: cmd.StartedAt, EndedAt: cmd.EndedAt, AlertingAgency: cmd.AlertingAgency, Reference: cmd.Reference,
+		Location: cmd.Location, LocationID: cmd.LocationID, SearchArea: cmd.SearchArea, Outcome: cmd.Outcome,
+		Debrief: cmd.Debrief, Teams: cmd.Teams,
+	}); err != nil {
+		return nil, err
+	}
+	if err := s.repo.Create(ctx, m); err != nil {
+		if err != common.ErrValidation && err != common.ErrConflict {
+			logx.Std.Errorf("create mission failed: %s", err)
+		}
+		return nil, err
+	}
+	return toDTO(m), nil
+}
+
+func (s *Service) Get(ctx context.Context, id int64) (*dto.Mission, error) {
+	logx.Std.Tracef("get mission %d", id)
+	m, err := s.repo.Get(ctx, mission.MissionID(id))
+	if err != nil {
+		return nil, err
+	}
+	return toDTO(m), nil
+}
+
+func (s *Service) List(ctx context.Context, q ListMissionsQuery) ([]*dto.Mission, error) {
+	logx.Std.Tracef("list missions %v", q)
+	f, err := toFilter(q)
+	if err != nil {
+		return nil, err
+	}
+	if q.Outcome != nil {
+		o := mission.Outcome(*q.Outcome)
+		if !o.Valid() {
+			return nil, common.ErrValidation
+		}
+		f.Outcome = &o
+	}
+	items, err := s.repo.List(ctx, f)
+	if err != nil {
+		logx.Std.Errorf("list missions failed: %s", err)
+		return nil, err
+	}
+	out := make([]*dto.Mission, 0, len(items))
+	for _, it := range items {
+		out = append(out, toDTO(it))
+	}
+	return out, nil
+}
+
+func (s *Service) Update(ctx context.Context, cmd UpdateMissionCommand) (*dto.Mission, error) {
+	logx.Std.Tracef("update mission %v", cmd)
+	m, err := s.repo.Get(ctx, mission.MissionID(cmd.ID))
+	if err != nil {
+		return nil, err
+	}
+	if err := s.apply(ctx, m, cmd); err != nil {
+		return nil, err
+	}
+	m.UpdatedAt = time.Now().UTC()
+	if err := s.repo.Update(ctx, m); err != nil {
+		if err != common.ErrValidation && err != common.ErrConflict && err != common.ErrNotFound {
+			logx.Std.Errorf("update mission failed: %s", err)
+		}
+		return nil, err
+	}
+	return toDTO(m), nil
+}
+
+func (s *Service) Delete(ctx context.Context, id int64) error {
+	logx.Std.Tracef("delete mission %d", id)
+	err := s.repo.Delete(ctx, mission.MissionID(id))
+	if err != nil && err != common.ErrNotFound {
+		logx.Std.Errorf("delete mission failed: %s", err)
+	}
+	return err
+}
+
+// Deployments compares each deployed dog's mission record with its training
+// rounds in the same period. Cancelled missions do not count.
+func (s *Service) Deployments(ctx context.Context, q ListMissionsQuery) ([]*dto.DeploymentRecord, error) {
+	logx.Std.Tracef("deployment records %v", q)
+	f, err := toFilter(q)
+	if err != nil {
+		return nil, err
+	}
+	items, err := s.repo.DogRecords(ctx, f)
+	if err != nil {
+		logx.Std.Errorf("list deployment records failed: %s", err)
+		return nil, err
+	}
+	out := make([]*dto.DeploymentRecord, 0, len(items))
+	for _, it := range items {
+		rec := &dto.DeploymentRecord{
+			DogID: it.DogID, DogName: it.DogName, Missions: it.Missions, Finds: it.Finds,
+			SearchHours: math.Round(float64(it.SearchMinutes)/60*10) / 10, TrainingRounds: it.Rounds,
+		}
+		if it.Missions > 0 {
+			rec.FindRate = float64(it.Finds) / float64(it.Missions)
+		}
+		if it.Rounds > 0 {
+			v := float64(it.RoundSuccesses) / float64(it.Rounds)
+			rec.TrainingSuccessRate = &v
+		}
+		out = append(out, rec)
+	}
+	return out, nil
+}
+
+// apply validates the command and copies it onto m. A location_id sets the
+// location name from the catalogue. Teams may only be marked as having
+// found the person on a mission that ended with a find.
+func (s *Service) apply(ctx context.Context, m *mission.Mission, cmd UpdateMissionCommand) error {
+	if cmd.AlertingAgency == "" {
+		return common.ErrValidation
+	}
+	outcome := mission.Outcome(cmd.Outcome)
+	if !outcome.Valid() {
+		return common.ErrValidation
+	}
+	started, err := parseTime(cmd.StartedAt)
+	if err != nil {
+		return common.ErrValidation
+	}
+	var ended *time.Time
+	if cmd.EndedAt != nil {
+		t, err := parseTime(*cmd.EndedAt)
+		if err != nil || t.Before(started) {
+			return common.ErrValidation
+		}
+		ended = &t
+	}
+	var area []location.Point
+	if cmd.SearchArea != nil {
+		if cmd.SearchArea.Type != "Polygon" || len(cmd.SearchArea.Coordinates) == 0 {
+			return common.ErrValidation
+		}
+		ring, ok := location.RingFromPositions(cmd.SearchArea.Coordinates[0])
+		if !ok {
+			return common.ErrValidation
+		}
+		area = ring
+	}
+	teams := make([]mission.Team, 0, len(cmd.Teams))
+	seen := map[int64]bool{}
+	for _, t := range cmd.Teams {
+		if t.DogID <= 0 || seen[t.DogID] || (t.SearchMinutes != nil && *t.SearchMinutes < 0) {
+			return common.ErrValidation
+		}
+		if t.Found && outcome != mission.OutcomeFind {
+			return common.ErrValidation
+		}
+		seen[t.DogID] = true
+		teams = append(teams, mission.Team{
+			DogID: t.DogID, HandlerID: t.HandlerID, DisciplineID: t.DisciplineID, Found: t.Found,
+			SearchMinutes: t.SearchMinutes, Notes: t.Notes,
+		})
+	}
+	name := cmd.Location
+	if cmd.LocationID != nil {
+		l, err := s.locations.Get(ctx, location.LocationID(*cmd.LocationID))
+		if err == common.ErrNotFound {
+			return common.ErrValidation
+		}
+		if err != nil {
+			return err
+		}
+		name = &l.Name
+	}
+	m.StartedAt, m.EndedAt, m.AlertingAgency, m.Reference = started, ended, cmd.AlertingAgency, cmd.Reference
+	m.Location, m.LocationID, m.SearchArea, m.Outcome = name, cmd.LocationID, area, outcome
+	m.Debrief, m.Teams = cmd.Debrief, teams
+	return nil
+}
+
+func toFilter(q ListMissionsQuery) (mission.Filter, error) {
+	f := mission.Filter{DogID: q.DogID, HandlerID: q.HandlerID}
+	var err error
+	if f.From, err = parseBound(q.From, false); err != nil {
+		return f, err
+	}
+	if f.To, err = parseBound(q.To, true); err != nil {
+		return f, err
+	}
+	return f, nil
+}
+
+// parseBound reads a from or to bound of a listing. RFC3339 times are
+// taken as given; a plain date covers its whole day, so as the exclusive
+// upper bound it becomes the following midnight.
+func parseBound(v *string, upper bool) (*time.Time, error) {
+	if v == nil {
+		return nil, nil
+	}
+	if len(*v) == len(common.DateLayout) {
+		d, err := common.ParseDate(*v)
+		if err != nil {
+			return nil, common.ErrValidation
+		}
+		if upper {
+			d = d.AddDate(0, 0, 1)
+		}
+		return &d, nil
+	}
+	t, _, err := common.ParseRange(v, nil)
+	return t, err
+}
+
+// parseTime accepts an RFC3339 timestamp or a plain date, read as midnight
+// UTC.
+func parseTime(v string) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, v); err == nil {
+		return t.UTC(), nil
+	}
+	return common.ParseDate(v)
+}
+
+func toDTO(m *mission.Mission) *dto.Mission {
+	out := &dto.Mission{
+		ID: int64(m.ID), StartedAt: m.StartedAt.Format(time.RFC3339), AlertingAgency: m.AlertingAgency,
+		Reference: m.Reference, Location: m.Location, LocationID: m.LocationID, Outcome: string(m.Outcome),
+		Debrief: m.Debrief, Teams: []dto.MissionTeam{},
+		CreatedAt: m.CreatedAt.Format(time.RFC3339), UpdatedAt: m.UpdatedAt.Format(time.RFC3339),
+	}
+	if m.EndedAt != nil {
+		v := m.EndedAt.Format(time.RFC3339)
+		out.EndedAt = &v
+	}
+	if len(m.SearchArea) > 0 {
+		out.SearchArea = &dto.GeoPolygon{Type: "Polygon", Coordinates: [][][2]float64{location.Positions(m.SearchArea)}}
+	}
+	for _, t := range m.Teams {
+		out.Teams = append(out.Teams, dto.MissionTeam{
+			ID: t.ID, DogID: t.DogID, HandlerID: t.HandlerID, DisciplineID: t.DisciplineID, Found: t.Found,
+			SearchMinutes: t.SearchMinutes, Notes: t.Notes,
+		})
+	}
+	return out
+}
@@ -0,0 +1,28 @@
+package observations
+
+import (
+	"io"
+	"time"
+)
+
+type ImportCommand struct {
+	// Format is "csv" or "metar".
+	Format string
+	Data   io.Reader
+	// Location names the place the file's observations belong to. Without
+	// it each observation's station id is used.
+	Location *string
+	// Reference anchors METAR day-of-month times; defaults to now.
+	Reference *time.Time
+	// MaxGap is how far an observation may be from a session's start to be
+	// attached to it; defaults to DefaultMaxGap.
+	MaxGap time.Duration
+	// Overwrite replaces conditions already recorded on a session.
+	Overwrite bool
+}
+
+type ListObservationsQuery struct {
+	Location *string
+	From     *string
+	To       *string
+}
@@ -0,0 +1,12 @@
+package observations
+
+import "errors"
+
+var errNoLocation = errors.New("observation has no station and no location was given")
+
+// ParseError reports a weather file that could not be read.
+type ParseError struct{ Err error }
+
+func (e *ParseError) Error() string { return "invalid weather file: " + e.Err.Error() }
+
+func (e *ParseError) Unwrap() error { return e.Err }
@@ -0,0 +1,207 @@
+package observations
+
+import (
+	"context"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/tnosaj/sar-training/backend/internal/application/dto"
+	"github.com/tnosaj/sar-training/backend/internal/domain/common"
+	"github.com/tnosaj/sar-training/backend/internal/domain/session"
+	"github.com/tnosaj/sar-training/backend/internal/domain/weather"
+	logx "github.com/tnosaj/sar-training/backend/internal/infra/log"
+)
+
+// DefaultMaxGap is the largest distance in time between a session's start
+// and the observation attached to it.
+const DefaultMaxGap = 3 * time.Hour
+
+const (
+	FormatCSV   = "csv"
+	FormatMETAR = "metar"
+)
+
+type Service struct {
+	repo     weather.Repository
+	sessions session.Repository
+}
+
+func NewService(r weather.Repository, s session.Repository) *Service {
+	logx.Std.Trace("starting observations service")
+	return &Service{repo: r, sessions: s}
+}
+
+// DetectFormat guesses the file format from its name: .csv files are CSV,
+// everything else is read as METAR text.
+func DetectFormat(filename string) string {
+	if strings.EqualFold(filepath.Ext(filename), ".csv") {
+		return FormatCSV
+	}
+	return FormatMETAR
+}
+
+// Import parses a weather file, stores its observations and attaches the
+// nearest one to every session held at the same location around that time.
+func (s *Service) Import(ctx context.Context, cmd ImportCommand) (*dto.WeatherImport, error) {
+	logx.Std.Tracef("import weather %s", cmd.Format)
+	if cmd.MaxGap < 0 || cmd.Data == nil {
+		return nil, common.ErrValidation
+	}
+	if cmd.MaxGap == 0 {
+		cmd.MaxGap = DefaultMaxGap
+	}
+	if cmd.Location != nil && strings.TrimSpace(*cmd.Location) == "" {
+		cmd.Location = nil
+	}
+	var obs []*weather.Observation
+	skipped := 0
+	var err error
+	switch cmd.Format {
+	case FormatCSV:
+		obs, err = weather.ParseCSV(cmd.Data)
+	case FormatMETAR:
+		ref := time.Now().UTC()
+		if cmd.Reference != nil {
+			ref = *cmd.Reference
+		}
+		obs, skipped, err = weather.ParseMETAR(cmd.Data, ref)
+	default:
+		return nil, common.ErrValidation
+	}
+	if err != nil {
+		logx.Std.Infof("weather file rejected: %s", err)
+		return nil, &ParseError{Err: err}
+	}
+
+	report := &dto.WeatherImport{Parsed: len(obs), Skipped: skipped, Sessions: []int64{}}
+	now := time.Now().UTC()
+	spans := map[string][2]time.Time{}
+	for _, o := range obs {
+		switch {
+		case cmd.Location != nil:
+			o.Location = strings.TrimSpace(*cmd.Location)
+		case o.Station != nil:
+			o.Location = *o.Station
+		default:
+			return nil, &ParseError{Err: errNoLocation}
+		}
+		o.ImportedAt = now
+	}
+	for _, o := range obs {
+		stored, err := s.repo.Save(ctx, o)
+		if err != nil {
+			logx.Std.Errorf("save observation failed: %s", err)
+			return nil, err
+		}
+		if stored {
+			report.Stored++
+		} else {
+			report.Duplicates++
+		}
+		key := strings.ToLower(o.Location)
+		span, ok := spans[key]
+		if !ok || o.ObservedAt.Before(span[0]) {
+			span[0] = o.ObservedAt
+		}
+		if !ok || o.ObservedAt.After(span[1]) {
+			span[1] = o.ObservedAt
+		}
+		spans[key] = span
+	}
+
+	ids, err := s.attach(ctx, spans, cmd.MaxGap, cmd.Overwrite)
+	if err != nil {
+		return nil, err
+	}
+	report.Sessions = append(report.Sessions, ids...)
+	logx.Std.Infof("imported %d of %d weather observations, updated %d sessions", report.Stored, report.Parsed, len(ids))
+	return report, nil
+}
+
+// attach fills the conditions of sessions whose location and start fall
+// within one of the imported spans.
+func (s *Service) attach(ctx context.Context, spans map[string][2]time.Time, maxGap time.Duration, overwrite bool) ([]int64, error) {
+	sessions, err := s.sessions.ListSessions(ctx)
+	if err != nil {
+		logx.Std.Errorf("list sessions failed: %s", err)
+		return nil, err
+	}
+	var ids []int64
+	for _, ses := range sessions {
+		if ses.Location == nil {
+			continue
+		}
+		span, ok := spans[strings.ToLower(strings.TrimSpace(*ses.Location))]
+		if !ok {
+			continue
+		}
+		started, err := time.Parse(time.RFC3339, ses.StartedAt)
+		if err != nil || started.Before(span[0].Add(-maxGap)) || started.After(span[1].Add(maxGap)) {
+			continue
+		}
+		o, err := s.repo.Nearest(ctx, strings.TrimSpace(*ses.Location), started, maxGap)
+		if err == common.ErrNotFound {
+			continue
+		}
+		if err != nil {
+			logx.Std.Errorf("nearest observation failed: %s", err)
+			return nil, err
+		}
+		if !o.Fill(&ses.Conditions, overwrite) {
+			continue
+		}
+		if err := s.sessions.UpdateSession(ctx, ses); err != nil {
+			logx.Std.Errorf("update session conditions failed: %s", err)
+			return nil, err
+		}
+		ids = append(ids, int64(ses.ID))
+	}
+	return ids, nil
+}
+
+func (s *Service) List(ctx context.Context, q ListObservationsQuery) ([]*dto.Observation, error) {
+	logx.Std.Tracef("list observations %v", q)
+	f := weather.Filter{Location: q.Location}
+	from, to, err := common.ParseRange(q.From, q.To)
+	if err != nil {
+		return nil, err
+	}
+	f.From, f.To = from, to
+	items, err := s.repo.List(ctx, f)
+	if err != nil {
+		logx.Std.Errorf("list observations failed: %s", err)
+		return nil, err
+	}
+	out := make([]*dto.Observation, 0, len(items))
+	for _, o := range items {
+		out = append(out, toDTO(o))
+	}
+	return out, nil
+}
+
+// ParseReference reads an import reference time: a month (2025-01), a date
+// or an RFC3339 time. A month or date stands for its last moment so reports
+// from that whole period resolve into it.
+func ParseReference(v string) (time.Time, error) {
+	if t, err := time.Parse("2006-01", v); err == nil {
+		return t.AddDate(0, 1, 0).Add(-time.Second), nil
+	}
+	if t, err := time.Parse("2006-01-02", v); err == nil {
+		return t.AddDate(0, 0, 1).Add(-time.Second), nil
+	}
+	return time.Parse(time.RFC3339, v)
+}
+
+func toDTO(o *weather.Observation) *dto.Observation {
+	out := &dto.Observation{
+		ID: int64(o.ID), Location: o.Location, Station: o.Station, ObservedAt: o.ObservedAt.Format(time.RFC3339),
+		TemperatureC: o.TemperatureC, HumidityPct: o.HumidityPct, WindSpeedKmh: o.WindSpeedKmh, WindDirectionDeg: o.WindDirectionDeg,
+		Source: o.Source, ImportedAt: o.ImportedAt.Format(time.RFC3339),
+	}
+	if o.Precipitation != nil {
+		v := string(*o.Precipitation)
+		out.Precipitation = &v
+	}
+	return out
+}
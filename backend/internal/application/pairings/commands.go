@@ -0,0 +1,34 @@
+package pairings
+
+type CreatePairingCommand struct {
+	DogID     int64   `json:"-"`
+	ActorID   int64   `json:"-"`
+	UserID    int64   `json:"user_id"`
+	Role      string  `json:"role"`
+	StartDate *string `json:"start_date,omitempty"`
+	EndDate   *string `json:"end_date,omitempty"`
+	Notes     *string `json:"notes,omitempty"`
+}
+
+type UpdatePairingCommand struct {
+	ID        int64   `json:"-"`
+	DogID     int64   `json:"-"`
+	ActorID   int64   `json:"-"`
+	UserID    int64   `json:"user_id"`
+	Role      string  `json:"role"`
+	StartDate *string `json:"start_date,omitempty"`
+	EndDate   *string `json:"end_date,omitempty"`
+	Notes     *string `json:"notes,omitempty"`
+}
+
+type DeletePairingCommand struct {
+	ID      int64
+	DogID   int64
+	ActorID int64
+}
+
+type ListPairingsQuery struct {
+	DogID    *int64
+	UserID   *int64
+	ActiveOn *string
+}
@@ -0,0 +1,204 @@
+package pairings
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/tnosaj/sar-training/backend/internal/application/dto"
+	"github.com/tnosaj/sar-training/backend/internal/domain/common"
+	"github.com/tnosaj/sar-training/backend/internal/domain/dog"
+	"github.com/tnosaj/sar-training/backend/internal/domain/pairing"
+	"github.com/tnosaj/sar-training/backend/internal/domain/user"
+	logx "github.com/tnosaj/sar-training/backend/internal/infra/log"
+)
+
+type Service struct {
+	repo  pairing.Repository
+	dogs  dog.Repository
+	users user.Repository
+}
+
+func NewService(r pairing.Repository, d dog.Repository, u user.Repository) *Service {
+	logx.Std.Trace("starting pairings service")
+	return &Service{repo: r, dogs: d, users: u}
+}
+
+func (s *Service) Create(ctx context.Context, cmd CreatePairingCommand) (*dto.Pairing, error) {
+	logx.Std.Tracef("create pairing %v", cmd)
+	now := time.Now().UTC()
+	p := &pairing.Pairing{DogID: cmd.DogID, UserID: cmd.UserID, Notes: cmd.Notes, CreatedAt: now, UpdatedAt: now}
+	if err := s.apply(ctx, p, cmd.Role, cmd.StartDate, cmd.EndDate); err != nil {
+		return nil, err
+	}
+	if err := s.authorize(ctx, cmd.ActorID, cmd.DogID); err != nil {
+		return nil, err
+	}
+	if err := s.checkOverlap(ctx, p); err != nil {
+		return nil, err
+	}
+	if err := s.repo.Create(ctx, p); err != nil {
+		logx.Std.Errorf("create pairing failed: %s", err)
+		return nil, err
+	}
+	return toDTO(p), nil
+}
+
+func (s *Service) Update(ctx context.Context, cmd UpdatePairingCommand) (*dto.Pairing, error) {
+	logx.Std.Tracef("update pairing %v", cmd)
+	p, err := s.repo.Get(ctx, pairing.PairingID(cmd.ID))
+	if err != nil {
+		return nil, err
+	}
+	if p.DogID != cmd.DogID {
+		return nil, common.ErrNotFound
+	}
+	if err := s.authorize(ctx, cmd.ActorID, cmd.DogID); err != nil {
+		return nil, err
+	}
+	p.UserID, p.Notes, p.UpdatedAt = cmd.UserID, cmd.Notes, time.Now().UTC()
+	if err := s.apply(ctx, p, cmd.Role, cmd.StartDate, cmd.EndDate); err != nil {
+		return nil, err
+	}
+	if err := s.checkOverlap(ctx, p); err != nil {
+		return nil, err
+	}
+	if err := s.repo.Update(ctx, p); err != nil {
+		logx.Std.Errorf("update pairing failed: %s", err)
+		return nil, err
+	}
+	return toDTO(p), nil
+}
+
+func (s *Service) Delete(ctx context.Context, cmd DeletePairingCommand) error {
+	logx.Std.Tracef("delete pairing %v", cmd)
+	p, err := s.repo.Get(ctx, pairing.PairingID(cmd.ID))
+	if err != nil {
+		return err
+	}
+	if p.DogID != cmd.DogID {
+		return common.ErrNotFound
+	}
+	if err := s.authorize(ctx, cmd.ActorID, cmd.DogID); err != nil {
+		return err
+	}
+	if err := s.repo.Delete(ctx, p.ID); err != nil {
+		logx.Std.Errorf("delete pairing failed: %s", err)
+		return err
+	}
+	return nil
+}
+
+func (s *Service) List(ctx context.Context, q ListPairingsQuery) ([]*dto.Pairing, error) {
+	logx.Std.Tracef("list pairings %v", q)
+	f := pairing.Filter{DogID: q.DogID, UserID: q.UserID}
+	if q.ActiveOn != nil {
+		t, err := common.ParseDate(*q.ActiveOn)
+		if err != nil {
+			return nil, common.ErrValidation
+		}
+		f.ActiveOn = &t
+	}
+	items, err := s.repo.List(ctx, f)
+	if err != nil {
+		logx.Std.Errorf("list pairings failed: %s", err)
+		return nil, err
+	}
+	out := make([]*dto.Pairing, 0, len(items))
+	for _, it := range items {
+		out = append(out, toDTO(it))
+	}
+	return out, nil
+}
+
+// apply validates the role and date range and checks that dog and user
+// exist.
+func (s *Service) apply(ctx context.Context, p *pairing.Pairing, role string, start, end *string) error {
+	p.Role = pairing.Role(role)
+	if p.Role != pairing.RolePrimary && p.Role != pairing.RoleSecondary {
+		return common.ErrValidation
+	}
+	y, m, d := time.Now().UTC().Date()
+	p.StartDate = time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
+	if start != nil {
+		t, err := common.ParseDate(*start)
+		if err != nil {
+			return common.ErrValidation
+		}
+		p.StartDate = t
+	}
+	p.EndDate = nil
+	if end != nil {
+		t, err := common.ParseDate(*end)
+		if err != nil || t.Before(p.StartDate) {
+			return common.ErrValidation
+		}
+		p.EndDate = &t
+	}
+	if _, err := s.dogs.Get(ctx, dog.DogID(p.DogID)); err != nil {
+		return err
+	}
+	if _, err := s.users.GetUserByID(ctx, p.UserID); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return common.ErrValidation
+		}
+		return err
+	}
+	return nil
+}
+
+// authorize lets admins and the dog's current primary handler change its
+// pairings. A dog without any current handler can be claimed by anyone.
+func (s *Service) authorize(ctx context.Context, actorID, dogID int64) error {
+	u, err := s.users.GetUserByID(ctx, actorID)
+	if err != nil {
+		return common.ErrForbidden
+	}
+	if u.IsAdmin {
+		return nil
+	}
+	now := time.Now().UTC()
+	active, err := s.repo.List(ctx, pairing.Filter{DogID: &dogID, ActiveOn: &now})
+	if err != nil {
+		return err
+	}
+	if len(active) == 0 {
+		return nil
+	}
+	if pr := pairing.Primary(active); pr != nil && pr.UserID == actorID {
+		return nil
+	}
+	return common.ErrForbidden
+}
+
+// checkOverlap rejects a second primary handler for the same days and
+// duplicate pairings of the same user with the dog.
+func (s *Service) checkOverlap(ctx context.Context, p *pairing.Pairing) error {
+	existing, err := s.repo.List(ctx, pairing.Filter{DogID: &p.DogID})
+	if err != nil {
+		return err
+	}
+	for _, e := range existing {
+		if e.ID == p.ID || !e.Overlaps(p) {
+			continue
+		}
+		if e.UserID == p.UserID || (e.Role == pairing.RolePrimary && p.Role == pairing.RolePrimary) {
+			return common.ErrConflict
+		}
+	}
+	return nil
+}
+
+func toDTO(p *pairing.Pairing) *dto.Pairing {
+	out := &dto.Pairing{
+		ID: int64(p.ID), DogID: p.DogID, UserID: p.UserID, Role: string(p.Role), StartDate: p.StartDate.Format(common.DateLayout),
+		Notes: p.Notes, CreatedAt: p.CreatedAt.Format(time.RFC3339), UpdatedAt: p.UpdatedAt.Format(time.RFC3339),
+	}
+	if p.EndDate != nil {
+		v := p.EndDate.Format(common.DateLayout)
+		out.EndDate = &v
+	}
+	out.Active = p.ActiveOn(time.Now().UTC())
+	return out
+}
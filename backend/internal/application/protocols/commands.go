@@ -0,0 +1,48 @@
+package protocols
+
+type TaskInput struct {
+	// ID keeps an existing task on update; new tasks omit it.
+	ID          int64   `json:"id,omitempty"`
+	Name        string  `json:"name"`
+	Description *string `json:"description,omitempty"`
+	BehaviorID  *int64  `json:"behavior_id,omitempty"`
+	ExerciseID  *int64  `json:"exercise_id,omitempty"`
+	TimeLimitS  *int    `json:"time_limit_s,omitempty"`
+	MaxPoints   int     `json:"max_points"`
+	MinPoints   int     `json:"min_points"`
+	Required    bool    `json:"required"`
+}
+
+type CreateProtocolCommand struct {
+	Name        string      `json:"name"`
+	Description *string     `json:"description,omitempty"`
+	PassRatio   float64     `json:"pass_ratio"`
+	Tasks       []TaskInput `json:"tasks"`
+}
+
+type UpdateProtocolCommand struct {
+	ID          int64       `json:"-"`
+	Name        string      `json:"name"`
+	Description *string     `json:"description,omitempty"`
+	PassRatio   float64     `json:"pass_ratio"`
+	Tasks       []TaskInput `json:"tasks"`
+}
+
+type StartRunCommand struct {
+	ProtocolID int64   `json:"-"`
+	DogID      int64   `json:"dog_id"`
+	Judge      *string `json:"judge,omitempty"`
+	// StartedAt defaults to now.
+	StartedAt *string `json:"started_at,omitempty"`
+}
+
+type SetTaskRoundCommand struct {
+	RunID   int64 `json:"-"`
+	TaskID  int64 `json:"-"`
+	RoundID int64 `json:"round_id"`
+}
+
+type RunQuery struct {
+	DogID      *int64
+	ProtocolID *int64
+}
@@ -0,0 +1,372 @@
+package protocols
+
+import (
+	"context"
+	"time"
+
+	"github.com/tnosaj/sar-training/backend/internal/application/dto"
+	"github.com/tnosaj/sar-training/backend/internal/domain/common"
+	"github.com/tnosaj/sar-training/backend/internal/domain/dog"
+	"github.com/tnosaj/sar-training/backend/internal/domain/health"
+	"github.com/tnosaj/sar-training/backend/internal/domain/protocol"
+	"github.com/tnosaj/sar-training/backend/internal/domain/session"
+	logx "github.com/tnosaj/sar-training/backend/internal/infra/log"
+)
+
+type Service struct {
+	repo     protocol.Repository
+	sessions session.Repository
+	dogs     dog.Repository
+	health   health.Repository
+}
+
+func NewService(r protocol.Repository, s session.Repository, d dog.Repository, h health.Repository) *Service {
+	logx.Std.Trace("starting protocols service")
+	return &Service{repo: r, sessions: s, dogs: d, health: h}
+}
+
+func (s *Service) Create(ctx context.Context, cmd CreateProtocolCommand) (*dto.Protocol, error) {
+	logx.Std.Tracef("create protocol %v", cmd)
+	tasks, err := toTasks(cmd.Tasks)
+	if err != nil || cmd.Name == "" || cmd.PassRatio < 0 || cmd.PassRatio > 1 {
+		return nil, common.ErrValidation
+	}
+	for _, t := range tasks {
+		if t.ID != 0 {
+			return nil, common.ErrValidation
+		}
+	}
+	if cmd.PassRatio == 0 {
+		cmd.PassRatio = protocol.DefaultPassRatio
+	}
+	now := time.Now().UTC()
+	p := &protocol.Protocol{Name: cmd.Name, Description: cmd.Description, PassRatio: cmd.PassRatio, Tasks: tasks, CreatedAt: now, UpdatedAt: now}
+	if err := s.repo.Create(ctx, p); err != nil {
+		logx.Std.Errorf("create protocol failed: %s", err)
+		return nil, err
+	}
+	return toDTO(p), nil
+}
+
+func (s *Service) Get(ctx context.Context, id int64) (*dto.Protocol, error) {
+	logx.Std.Tracef("get protocol %d", id)
+	p, err := s.repo.Get(ctx, protocol.ProtocolID(id))
+	if err != nil {
+		return nil, err
+	}
+	return toDTO(p), nil
+}
+
+func (s *Service) List(ctx context.Context) ([]*dto.Protocol, error) {
+	logx.Std.Trace("list protocols")
+	items, err := s.repo.List(ctx)
+	if err != nil {
+		logx.Std.Errorf("list protocols failed: %s", err)
+		return nil, err
+	}
+	out := make([]*dto.Protocol, 0, len(items))
+	for _, it := range items {
+		out = append(out, toDTO(it))
+	}
+	return out, nil
+}
+
+func (s *Service) Update(ctx context.Context, cmd UpdateProtocolCommand) (*dto.Protocol, error) {
+	logx.Std.Tracef("update protocol %v", cmd)
+	tasks, err := toTasks(cmd.Tasks)
+	if err != nil || cmd.ID <= 0 || cmd.Name == "" || cmd.PassRatio < 0 || cmd.PassRatio > 1 {
+		return nil, common.ErrValidation
+	}
+	p, err := s.repo.Get(ctx, protocol.ProtocolID(cmd.ID))
+	if err != nil {
+		return nil, err
+	}
+	if cmd.PassRatio == 0 {
+		cmd.PassRatio = protocol.DefaultPassRatio
+	}
+	p.Name, p.Description, p.PassRatio, p.Tasks = cmd.Name, cmd.Description, cmd.PassRatio, tasks
+	p.UpdatedAt = time.Now().UTC()
+	if err := s.repo.Update(ctx, p); err != nil {
+		logx.Std.Errorf("update protocol failed: %s", err)
+		return nil, err
+	}
+	return toDTO(p), nil
+}
+
+func (s *Service) Delete(ctx context.Context, id int64) error {
+	logx.Std.Tracef("delete protocol %d", id)
+	err := s.repo.Delete(ctx, protocol.ProtocolID(id))
+	if err != nil && err != common.ErrNotFound {
+		logx.Std.Errorf("delete protocol failed: %s", err)
+	}
+	return err
+}
+
+// StartRun opens a mock-exam session for the dog; the rounds of that session
+// are then judged against the protocol's tasks. Like adding a dog to a
+// session, it fails with common.ErrConflict when the dog is not fit on the
+// day of the run.
+func (s *Service) StartRun(ctx context.Context, cmd StartRunCommand) (*dto.ExamRun, error) {
+	logx.Std.Tracef("start exam run %v", cmd)
+	if cmd.ProtocolID <= 0 || cmd.DogID <= 0 {
+		return nil, common.ErrValidation
+	}
+	now := time.Now().UTC()
+	started := now
+	if cmd.StartedAt != nil {
+		t, err := time.Parse(time.RFC3339, *cmd.StartedAt)
+		if err != nil {
+			return nil, common.ErrValidation
+		}
+		started = t.UTC()
+	}
+	if _, err := s.dogs.Get(ctx, dog.DogID(cmd.DogID)); err != nil {
+		return nil, err
+	}
+	unfit, err := s.health.NotFit(ctx, cmd.DogID, started)
+	if err != nil {
+		logx.Std.Errorf("fitness check failed: %s", err)
+		return nil, err
+	}
+	if len(unfit) > 0 {
+		logx.Std.Infof("dog %d not fit for training, not starting exam run", cmd.DogID)
+		return nil, common.ErrConflict
+	}
+	p, err := s.repo.Get(ctx, protocol.ProtocolID(cmd.ProtocolID))
+	if err != nil {
+		return nil, err
+	}
+	if len(p.Tasks) == 0 {
+		return nil, common.ErrValidation
+	}
+	run := &protocol.Run{ProtocolID: p.ID, DogID: cmd.DogID, Judge: cmd.Judge, StartedAt: started, CreatedAt: now}
+	if err := s.repo.StartRun(ctx, run); err != nil {
+		logx.Std.Errorf("start exam run failed: %s", err)
+		return nil, err
+	}
+	out := toRunDTO(run)
+	return &out, nil
+}
+
+func (s *Service) ListRuns(ctx context.Context, q RunQuery) ([]dto.ExamRun, error) {
+	logx.Std.Tracef("list exam runs %v", q)
+	f := protocol.RunFilter{DogID: q.DogID}
+	if q.ProtocolID != nil {
+		id := protocol.ProtocolID(*q.ProtocolID)
+		f.ProtocolID = &id
+	}
+	runs, err := s.repo.ListRuns(ctx, f)
+	if err != nil {
+		logx.Std.Errorf("list exam runs failed: %s", err)
+		return nil, err
+	}
+	out := make([]dto.ExamRun, 0, len(runs))
+	for _, r := range runs {
+		out = append(out, toRunDTO(r))
+	}
+	return out, nil
+}
+
+// Result evaluates the run against its protocol as judged so far; a
+// completed run reports the points and verdict it was completed with.
+func (s *Service) Result(ctx context.Context, id int64) (*dto.ExamRunResult, error) {
+	logx.Std.Tracef("exam run result %d", id)
+	run, p, res, err := s.evaluate(ctx, protocol.RunID(id))
+	if err != nil {
+		return nil, err
+	}
+	return toResultDTO(run, p, res), nil
+}
+
+// SetTaskRound records the round judged for a task. The round must belong to
+// the run's session and dog and match the task's behavior and exercise.
+func (s *Service) SetTaskRound(ctx context.Context, cmd SetTaskRoundCommand) (*dto.ExamRunResult, error) {
+	logx.Std.Tracef("set exam task round %v", cmd)
+	if cmd.RunID <= 0 || cmd.TaskID <= 0 || cmd.RoundID <= 0 {
+		return nil, common.ErrValidation
+	}
+	run, p, err := s.openRun(ctx, protocol.RunID(cmd.RunID))
+	if err != nil {
+		return nil, err
+	}
+	t := p.Task(protocol.TaskID(cmd.TaskID))
+	if t == nil {
+		return nil, common.ErrNotFound
+	}
+	ro, err := s.sessions.GetRound(ctx, cmd.RoundID)
+	if err == common.ErrNotFound {
+		return nil, common.ErrValidation
+	}
+	if err != nil {
+		return nil, err
+	}
+	if ro.SessionID != run.SessionID || ro.DogID != run.DogID {
+		return nil, common.ErrValidation
+	}
+	if (t.BehaviorID != nil && *t.BehaviorID != ro.PlannedBehaviorID) || (t.ExerciseID != nil && *t.ExerciseID != ro.ExerciseID) {
+		return nil, common.ErrValidation
+	}
+	if err := s.repo.SetTaskRound(ctx, run.ID, t.ID, ro.ID); err != nil {
+		logx.Std.Errorf("set exam task round failed: %s", err)
+		return nil, err
+	}
+	return s.Result(ctx, cmd.RunID)
+}
+
+func (s *Service) DeleteTaskRound(ctx context.Context, runID, taskID int64) error {
+	logx.Std.Tracef("delete exam task round %d/%d", runID, taskID)
+	run, _, err := s.openRun(ctx, protocol.RunID(runID))
+	if err != nil {
+		return err
+	}
+	err = s.repo.DeleteTaskRound(ctx, run.ID, protocol.TaskID(taskID))
+	if err != nil && err != common.ErrNotFound {
+		logx.Std.Errorf("delete exam task round failed: %s", err)
+	}
+	return err
+}
+
+// Complete stores the final result of the run and closes its session. Tasks
+// not judged by then count as failed.
+func (s *Service) Complete(ctx context.Context, id int64) (*dto.ExamRunResult, error) {
+	logx.Std.Tracef("complete exam run %d", id)
+	run, p, res, err := s.evaluate(ctx, protocol.RunID(id))
+	if err != nil {
+		return nil, err
+	}
+	if run.CompletedAt != nil {
+		return nil, common.ErrConflict
+	}
+	now := time.Now().UTC()
+	run.CompletedAt, run.Passed, run.TotalPoints = &now, &res.Passed, &res.Points
+	if err := s.repo.CompleteRun(ctx, run, res.Tasks); err != nil {
+		logx.Std.Errorf("complete exam run failed: %s", err)
+		return nil, err
+	}
+	return toResultDTO(run, p, res), nil
+}
+
+// openRun loads a run that can still be judged.
+func (s *Service) openRun(ctx context.Context, id protocol.RunID) (*protocol.Run, *protocol.Protocol, error) {
+	run, err := s.repo.GetRun(ctx, id)
+	if err != nil {
+		return nil, nil, err
+	}
+	if run.CompletedAt != nil {
+		return nil, nil, common.ErrConflict
+	}
+	p, err := s.repo.Get(ctx, run.ProtocolID)
+	if err != nil {
+		return nil, nil, err
+	}
+	return run, p, nil
+}
+
+func (s *Service) evaluate(ctx context.Context, id protocol.RunID) (*protocol.Run, *protocol.Protocol, protocol.Result, error) {
+	run, err := s.repo.GetRun(ctx, id)
+	if err != nil {
+		return nil, nil, protocol.Result{}, err
+	}
+	p, err := s.repo.Get(ctx, run.ProtocolID)
+	if err != nil {
+		return nil, nil, protocol.Result{}, err
+	}
+	// a completed run keeps the task results it was completed with
+	if run.CompletedAt != nil {
+		stored, err := s.repo.TaskResults(ctx, run.ID)
+		if err != nil {
+			logx.Std.Errorf("list exam task results failed: %s", err)
+			return nil, nil, protocol.Result{}, err
+		}
+		if len(stored) > 0 {
+			return run, p, protocol.Stored(p, run, stored), nil
+		}
+	}
+	rounds, err := s.repo.TaskRounds(ctx, run.ID)
+	if err != nil {
+		logx.Std.Errorf("list exam task rounds failed: %s", err)
+		return nil, nil, protocol.Result{}, err
+	}
+	res := protocol.Evaluate(p, rounds)
+	// runs completed before task results were stored keep at least their
+	// points and verdict
+	if run.CompletedAt != nil && run.Passed != nil && run.TotalPoints != nil {
+		res.Points, res.Passed = *run.TotalPoints, *run.Passed
+		res.Ratio = 0
+		if res.MaxPoints > 0 {
+			res.Ratio = res.Points / res.MaxPoints
+		}
+	}
+	return run, p, res, nil
+}
+
+func toTasks(in []TaskInput) ([]protocol.Task, error) {
+	if len(in) == 0 {
+		return nil, common.ErrValidation
+	}
+	out := make([]protocol.Task, 0, len(in))
+	seen := map[int64]bool{}
+	for _, t := range in {
+		if t.Name == "" || t.MaxPoints <= 0 || t.MinPoints < 0 || t.MinPoints > t.MaxPoints {
+			return nil, common.ErrValidation
+		}
+		if t.ID != 0 {
+			if seen[t.ID] {
+				return nil, common.ErrValidation
+			}
+			seen[t.ID] = true
+		}
+		if t.TimeLimitS != nil && *t.TimeLimitS <= 0 {
+			return nil, common.ErrValidation
+		}
+		out = append(out, protocol.Task{
+			ID: protocol.TaskID(t.ID), Name: t.Name, Description: t.Description, BehaviorID: t.BehaviorID, ExerciseID: t.ExerciseID,
+			TimeLimitS: t.TimeLimitS, MaxPoints: t.MaxPoints, MinPoints: t.MinPoints, Required: t.Required,
+		})
+	}
+	return out, nil
+}
+
+func toDTO(p *protocol.Protocol) *dto.Protocol {
+	out := &dto.Protocol{
+		ID: int64(p.ID), Name: p.Name, Description: p.Description, PassRatio: p.PassRatio, Tasks: make([]dto.ProtocolTask, 0, len(p.Tasks)),
+		CreatedAt: p.CreatedAt.Format(time.RFC3339), UpdatedAt: p.UpdatedAt.Format(time.RFC3339),
+	}
+	for _, t := range p.Tasks {
+		out.MaxPoints += t.MaxPoints
+		out.Tasks = append(out.Tasks, toTaskDTO(t))
+	}
+	return out
+}
+
+func toTaskDTO(t protocol.Task) dto.ProtocolTask {
+	return dto.ProtocolTask{
+		ID: int64(t.ID), Position: t.Position, Name: t.Name, Description: t.Description, BehaviorID: t.BehaviorID, ExerciseID: t.ExerciseID,
+		TimeLimitS: t.TimeLimitS, MaxPoints: t.MaxPoints, MinPoints: t.MinPoints, Required: t.Required,
+	}
+}
+
+func toRunDTO(r *protocol.Run) dto.ExamRun {
+	out := dto.ExamRun{
+		ID: int64(r.ID), ProtocolID: int64(r.ProtocolID), SessionID: r.SessionID, DogID: r.DogID, DogName: r.DogName, Judge: r.Judge,
+		StartedAt: r.StartedAt.Format(time.RFC3339), Passed: r.Passed, TotalPoints: r.TotalPoints,
+	}
+	if r.CompletedAt != nil {
+		c := r.CompletedAt.Format(time.RFC3339)
+		out.CompletedAt = &c
+	}
+	return out
+}
+
+func toResultDTO(run *protocol.Run, p *protocol.Protocol, res protocol.Result) *dto.ExamRunResult {
+	out := &dto.ExamRunResult{
+		Run: toRunDTO(run), ProtocolName: p.Name, Tasks: make([]dto.ExamRunTask, 0, len(res.Tasks)),
+		Points: res.Points, MaxPoints: res.MaxPoints, Ratio: res.Ratio, PassRatio: p.PassRatio, Complete: res.Complete, Passed: res.Passed,
+	}
+	for _, t := range res.Tasks {
+		out.Tasks = append(out.Tasks, dto.ExamRunTask{
+			Task: toTaskDTO(t.Task), RoundID: t.RoundID, State: string(t.State), Points: t.Points, DurationS: t.DurationS, Overtime: t.Overtime,
+		})
+	}
+	return out
+}
@@ -0,0 +1,88 @@
+package protocols
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/tnosaj/sar-training/backend/internal/domain/protocol"
+	logx "github.com/tnosaj/sar-training/backend/internal/infra/log"
+)
+
+// Sheet renders the run as a plain-text protocol sheet for printing: the
+// tasks with points, times and results, the total and signature lines.
+func (s *Service) Sheet(ctx context.Context, id int64) ([]byte, error) {
+	logx.Std.Tracef("exam run sheet %d", id)
+	run, p, res, err := s.evaluate(ctx, protocol.RunID(id))
+	if err != nil {
+		return nil, err
+	}
+	var b bytes.Buffer
+	fmt.Fprintf(&b, "EXAM PROTOCOL: %s\n", p.Name)
+	if p.Description != nil {
+		fmt.Fprintf(&b, "%s\n", *p.Description)
+	}
+	fmt.Fprintln(&b)
+	fmt.Fprintf(&b, "Dog:    %s\n", run.DogName)
+	fmt.Fprintf(&b, "Date:   %s\n", run.StartedAt.Format("2006-01-02 15:04"))
+	judge := ""
+	if run.Judge != nil {
+		judge = *run.Judge
+	}
+	fmt.Fprintf(&b, "Judge:  %s\n\n", judge)
+
+	tw := tabwriter.NewWriter(&b, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "#\tTask\tPoints\tMin\tTime\tLimit\tResult")
+	for _, t := range res.Tasks {
+		name := t.Task.Name
+		if t.Task.Required {
+			name += " *"
+		}
+		fmt.Fprintf(tw, "%d\t%s\t%s / %d\t%d\t%s\t%s\t%s\n", t.Task.Position, name, formatPoints(t.Points), t.Task.MaxPoints,
+			t.Task.MinPoints, formatSeconds(t.DurationS), formatSeconds(t.Task.TimeLimitS), taskResult(t))
+	}
+	tw.Flush()
+	fmt.Fprintln(&b, "* required task")
+	fmt.Fprintln(&b)
+
+	fmt.Fprintf(&b, "Total:  %s / %s (%.0f%%, %.0f%% needed)\n", formatPoints(res.Points), formatPoints(res.MaxPoints), res.Ratio*100, p.PassRatio*100)
+	verdict := "FAILED"
+	if res.Passed {
+		verdict = "PASSED"
+	}
+	switch {
+	case run.CompletedAt == nil:
+		verdict = "IN PROGRESS (" + verdict + " so far)"
+	case !res.Complete:
+		verdict += " (incomplete)"
+	}
+	fmt.Fprintf(&b, "Result: %s\n\n", verdict)
+	line := strings.Repeat("_", 28)
+	fmt.Fprintf(&b, "Judge signature:   %s\n\n", line)
+	fmt.Fprintf(&b, "Handler signature: %s\n", line)
+	return b.Bytes(), nil
+}
+
+func taskResult(t protocol.TaskResult) string {
+	switch {
+	case t.State == protocol.TaskPending:
+		return "not attempted"
+	case t.Overtime:
+		return "failed (overtime)"
+	}
+	return string(t.State)
+}
+
+func formatPoints(p float64) string {
+	return strings.TrimSuffix(fmt.Sprintf("%.1f", p), ".0")
+}
+
+func formatSeconds(s *int) string {
+	if s == nil {
+		return "-"
+	}
+	return (time.Duration(*s) * time.Second).String()
+}
@@ -0,0 +1,51 @@
+package qualifications
+
+type CreateQualificationCommand struct {
+	DogID             *int64  `json:"dog_id,omitempty"`
+	HandlerID         *int64  `json:"handler_id,omitempty"`
+	ExamType          string  `json:"exam_type"`
+	ExamID            *int64  `json:"exam_id,omitempty"`
+	ExamDate          string  `json:"exam_date"`
+	Examiner          *string `json:"examiner,omitempty"`
+	Result            string  `json:"result"`
+	CertificateNumber *string `json:"certificate_number,omitempty"`
+	ValidUntil        *string `json:"valid_until,omitempty"`
+	RemindDaysBefore  *int    `json:"remind_days_before,omitempty"`
+	DisciplineID      *int64  `json:"discipline_id,omitempty"`
+	Notes             *string `json:"notes,omitempty"`
+}
+
+type UpdateQualificationCommand struct {
+	ID                int64   `json:"-"`
+	DogID             *int64  `json:"dog_id,omitempty"`
+	HandlerID         *int64  `json:"handler_id,omitempty"`
+	ExamType          string  `json:"exam_type"`
+	ExamID            *int64  `json:"exam_id,omitempty"`
+	ExamDate          string  `json:"exam_date"`
+	Examiner          *string `json:"examiner,omitempty"`
+	Result            string  `json:"result"`
+	CertificateNumber *string `json:"certificate_number,omitempty"`
+	ValidUntil        *string `json:"valid_until,omitempty"`
+	RemindDaysBefore  *int    `json:"remind_days_before,omitempty"`
+	DisciplineID      *int64  `json:"discipline_id,omitempty"`
+	Notes             *string `json:"notes,omitempty"`
+}
+
+type ListQualificationsQuery struct {
+	DogID        *int64
+	HandlerID    *int64
+	ExamType     *string
+	DisciplineID *int64
+}
+
+type ListTeamsQuery struct {
+	DeployableOnly bool
+	DisciplineID   *int64
+}
+
+type AddAttachmentCommand struct {
+	QualificationID int64
+	Filename        string
+	ContentType     string
+	Data            []byte
+}
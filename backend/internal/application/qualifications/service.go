@@ -0,0 +1,377 @@
+package qualifications
+
+import (
+	"context"
+	"math"
+	"mime"
+	"net/http"
+	"time"
+
+	"github.com/tnosaj/sar-training/backend/internal/application/dto"
+	"github.com/tnosaj/sar-training/backend/internal/domain/common"
+	"github.com/tnosaj/sar-training/backend/internal/domain/discipline"
+	"github.com/tnosaj/sar-training/backend/internal/domain/qualification"
+	logx "github.com/tnosaj/sar-training/backend/internal/infra/log"
+)
+
+const defaultRemindDays = 60
+
+type Service struct {
+	repo        qualification.Repository
+	disciplines discipline.Repository
+}
+
+func NewService(r qualification.Repository, d discipline.Repository) *Service {
+	logx.Std.Trace("starting qualifications service")
+	return &Service{repo: r, disciplines: d}
+}
+
+func (s *Service) Create(ctx context.Context, cmd CreateQualificationCommand) (*dto.Qualification, error) {
+	logx.Std.Tracef("create qualification %v", cmd)
+	if err := s.checkDiscipline(ctx, cmd.DisciplineID); err != nil {
+		return nil, err
+	}
+	now := time.Now().UTC()
+	q := &qualification.Qualification{CreatedAt: now, UpdatedAt: now}
+	if err := apply(q, UpdateQualificationCommand{
+		DogID: cmd.DogID, HandlerID: cmd.HandlerID, ExamType: cmd.ExamType, ExamID: cmd.ExamID, ExamDate: cmd.ExamDate,
+		Examiner: cmd.Examiner, Result: cmd.Result, CertificateNumber: cmd.CertificateNumber, ValidUntil: cmd.ValidUntil,
+		RemindDaysBefore: cmd.RemindDaysBefore, DisciplineID: cmd.DisciplineID, Notes: cmd.Notes,
+	}); err != nil {
+		return nil, err
+	}
+	if err := s.repo.Create(ctx, q); err != nil {
+		logx.Std.Errorf("create qualification failed: %s", err)
+		return nil, err
+	}
+	return toDTO(q, now), nil
+}
+
+func (s *Service) Get(ctx context.Context, id int64) (*dto.Qualification, error) {
+	logx.Std.Tracef("get qualification %d", id)
+	q, err := s.repo.Get(ctx, qualification.QualificationID(id))
+	if err != nil {
+		return nil, err
+	}
+	return toDTO(q, time.Now().UTC()), nil
+}
+
+func (s *Service) List(ctx context.Context, lq ListQualificationsQuery) ([]*dto.Qualification, error) {
+	logx.Std.Tracef("list qualifications %v", lq)
+	items, err := s.repo.List(ctx, qualification.Filter{DogID: lq.DogID, HandlerID: lq.HandlerID, ExamType: lq.ExamType, DisciplineID: lq.DisciplineID})
+	if err != nil {
+		logx.Std.Errorf("list qualifications failed: %s", err)
+		return nil, err
+	}
+	now := time.Now().UTC()
+	out := make([]*dto.Qualification, 0, len(items))
+	for _, it := range items {
+		out = append(out, toDTO(it, now))
+	}
+	return out, nil
+}
+
+func (s *Service) Update(ctx context.Context, cmd UpdateQualificationCommand) (*dto.Qualification, error) {
+	logx.Std.Tracef("update qualification %v", cmd)
+	if cmd.ID <= 0 {
+		return nil, common.ErrValidation
+	}
+	q, err := s.repo.Get(ctx, qualification.QualificationID(cmd.ID))
+	if err != nil {
+		return nil, err
+	}
+	if err := s.checkDiscipline(ctx, cmd.DisciplineID); err != nil {
+		return nil, err
+	}
+	if err := apply(q, cmd); err != nil {
+		return nil, err
+	}
+	q.UpdatedAt = time.Now().UTC()
+	if err := s.repo.Update(ctx, q); err != nil {
+		logx.Std.Errorf("update qualification failed: %s", err)
+		return nil, err
+	}
+	return toDTO(q, q.UpdatedAt), nil
+}
+
+func (s *Service) Delete(ctx context.Context, id int64) error {
+	logx.Std.Tracef("delete qualification %d", id)
+	err := s.repo.Delete(ctx, qualification.QualificationID(id))
+	if err != nil && err != common.ErrNotFound {
+		logx.Std.Errorf("delete qualification failed: %s", err)
+	}
+	return err
+}
+
+// Reminders lists the most recent passed qualification per dog, handler and
+// exam type that is within its reminder period or already expired, i.e.
+// that needs re-certification.
+func (s *Service) Reminders(ctx context.Context) ([]*dto.Reminder, error) {
+	logx.Std.Trace("list qualification reminders")
+	items, err := s.repo.List(ctx, qualification.Filter{})
+	if err != nil {
+		logx.Std.Errorf("list qualifications failed: %s", err)
+		return nil, err
+	}
+	type subject struct {
+		dog, handler int64
+		examType     string
+	}
+	now := time.Now().UTC()
+	seen := map[subject]bool{}
+	out := []*dto.Reminder{}
+	// items are ordered by exam date, newest first
+	for _, q := range items {
+		if q.Result != qualification.ResultPassed {
+			continue
+		}
+		key := subject{examType: q.ExamType}
+		if q.DogID != nil {
+			key.dog = *q.DogID
+		}
+		if q.HandlerID != nil {
+			key.handler = *q.HandlerID
+		}
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		if st := q.Status(now); st != qualification.StatusExpiring && st != qualification.StatusExpired {
+			continue
+		}
+		days := int(math.Ceil(q.ValidUntil.AddDate(0, 0, 1).Sub(now).Hours() / 24))
+		out = append(out, &dto.Reminder{Qualification: toDTO(q, now), DaysLeft: days})
+	}
+	return out, nil
+}
+
+// Teams lists every dog–handler team with a team qualification on record. A
+// team is deployable while at least one of its passed team qualifications
+// is in force. With a discipline set, only qualifications for that
+// discipline count.
+func (s *Service) Teams(ctx context.Context, tq ListTeamsQuery) ([]*dto.Team, error) {
+	logx.Std.Tracef("list teams %v", tq)
+	teams, err := s.repo.ListTeams(ctx)
+	if err != nil {
+		logx.Std.Errorf("list teams failed: %s", err)
+		return nil, err
+	}
+	items, err := s.repo.List(ctx, qualification.Filter{TeamsOnly: true, DisciplineID: tq.DisciplineID})
+	if err != nil {
+		logx.Std.Errorf("list qualifications failed: %s", err)
+		return nil, err
+	}
+	type pair struct{ dog, handler int64 }
+	byTeam := map[pair][]*qualification.Qualification{}
+	for _, q := range items {
+		k := pair{*q.DogID, *q.HandlerID}
+		byTeam[k] = append(byTeam[k], q)
+	}
+	now := time.Now().UTC()
+	out := []*dto.Team{}
+	for _, t := range teams {
+		res := &dto.Team{DogID: t.DogID, DogName: t.DogName, HandlerID: t.HandlerID, HandlerEmail: t.HandlerEmail}
+		var until *time.Time
+		unlimited := false
+		for _, q := range byTeam[pair{t.DogID, t.HandlerID}] {
+			res.Qualifications = append(res.Qualifications, toDTO(q, now))
+			if st := q.Status(now); st != qualification.StatusValid && st != qualification.StatusExpiring {
+				continue
+			}
+			res.Deployable = true
+			if q.ValidUntil == nil {
+				unlimited = true
+			} else if until == nil || q.ValidUntil.After(*until) {
+				until = q.ValidUntil
+			}
+		}
+		if res.Deployable && !unlimited {
+			v := until.Format(common.DateLayout)
+			res.ValidUntil = &v
+		}
+		if tq.DisciplineID != nil && len(res.Qualifications) == 0 {
+			continue
+		}
+		if tq.DeployableOnly && !res.Deployable {
+			continue
+		}
+		out = append(out, res)
+	}
+	return out, nil
+}
+
+// Certifications reports per discipline whether the dog holds a passed
+// qualification for it that is in force, alone or as part of a team.
+func (s *Service) Certifications(ctx context.Context, dogID int64) ([]*dto.Certification, error) {
+	logx.Std.Tracef("list certifications of dog %d", dogID)
+	ds, err := s.disciplines.List(ctx)
+	if err != nil {
+		logx.Std.Errorf("list disciplines failed: %s", err)
+		return nil, err
+	}
+	items, err := s.repo.List(ctx, qualification.Filter{DogID: &dogID})
+	if err != nil {
+		logx.Std.Errorf("list qualifications failed: %s", err)
+		return nil, err
+	}
+	byDiscipline := map[int64][]*qualification.Qualification{}
+	for _, q := range items {
+		if q.DisciplineID != nil {
+			byDiscipline[*q.DisciplineID] = append(byDiscipline[*q.DisciplineID], q)
+		}
+	}
+	now := time.Now().UTC()
+	out := make([]*dto.Certification, 0, len(ds))
+	for _, d := range ds {
+		res := &dto.Certification{DisciplineID: int64(d.ID), DisciplineName: d.Name, Qualifications: []*dto.Qualification{}}
+		var until *time.Time
+		unlimited := false
+		for _, q := range byDiscipline[int64(d.ID)] {
+			res.Qualifications = append(res.Qualifications, toDTO(q, now))
+			if st := q.Status(now); st != qualification.StatusValid && st != qualification.StatusExpiring {
+				continue
+			}
+			res.Certified = true
+			if q.ValidUntil == nil {
+				unlimited = true
+			} else if until == nil || q.ValidUntil.After(*until) {
+				until = q.ValidUntil
+			}
+		}
+		if res.Certified && !unlimited {
+			v := until.Format(common.DateLayout)
+			res.ValidUntil = &v
+		}
+		out = append(out, res)
+	}
+	return out, nil
+}
+
+func (s *Service) AddAttachment(ctx context.Context, cmd AddAttachmentCommand) (*dto.Attachment, error) {
+	logx.Std.Tracef("add attachment %s to qualification %d", cmd.Filename, cmd.QualificationID)
+	if cmd.QualificationID <= 0 || cmd.Filename == "" || len(cmd.Data) == 0 {
+		return nil, common.ErrValidation
+	}
+	if _, err := s.repo.Get(ctx, qualification.QualificationID(cmd.QualificationID)); err != nil {
+		return nil, err
+	}
+	// the type is sniffed from the contents; what the client claims is ignored
+	ct, _, _ := mime.ParseMediaType(http.DetectContentType(cmd.Data))
+	if !qualification.AttachmentTypes[ct] {
+		return nil, common.ErrValidation
+	}
+	cmd.ContentType = ct
+	a := &qualification.Attachment{
+		QualificationID: qualification.QualificationID(cmd.QualificationID), Filename: cmd.Filename,
+		ContentType: cmd.ContentType, Size: int64(len(cmd.Data)), Data: cmd.Data, UploadedAt: time.Now().UTC(),
+	}
+	if err := s.repo.AddAttachment(ctx, a); err != nil {
+		logx.Std.Errorf("add attachment failed: %s", err)
+		return nil, err
+	}
+	return toAttachmentDTO(a), nil
+}
+
+func (s *Service) ListAttachments(ctx context.Context, id int64) ([]*dto.Attachment, error) {
+	logx.Std.Tracef("list attachments of qualification %d", id)
+	if _, err := s.repo.Get(ctx, qualification.QualificationID(id)); err != nil {
+		return nil, err
+	}
+	items, err := s.repo.ListAttachments(ctx, qualification.QualificationID(id))
+	if err != nil {
+		logx.Std.Errorf("list attachments failed: %s", err)
+		return nil, err
+	}
+	out := make([]*dto.Attachment, 0, len(items))
+	for _, it := range items {
+		out = append(out, toAttachmentDTO(it))
+	}
+	return out, nil
+}
+
+// GetAttachment returns the attachment including its contents.
+func (s *Service) GetAttachment(ctx context.Context, id, attachmentID int64) (*qualification.Attachment, error) {
+	logx.Std.Tracef("get attachment %d of qualification %d", attachmentID, id)
+	return s.repo.GetAttachment(ctx, qualification.QualificationID(id), attachmentID)
+}
+
+func (s *Service) DeleteAttachment(ctx context.Context, id, attachmentID int64) error {
+	logx.Std.Tracef("delete attachment %d of qualification %d", attachmentID, id)
+	err := s.repo.DeleteAttachment(ctx, qualification.QualificationID(id), attachmentID)
+	if err != nil && err != common.ErrNotFound {
+		logx.Std.Errorf("delete attachment failed: %s", err)
+	}
+	return err
+}
+
+func apply(q *qualification.Qualification, cmd UpdateQualificationCommand) error {
+	if cmd.DogID == nil && cmd.HandlerID == nil {
+		return common.ErrValidation
+	}
+	if cmd.ExamType == "" {
+		return common.ErrValidation
+	}
+	res := qualification.Result(cmd.Result)
+	if res != qualification.ResultPassed && res != qualification.ResultFailed {
+		return common.ErrValidation
+	}
+	examDate, err := common.ParseDate(cmd.ExamDate)
+	if err != nil {
+		return common.ErrValidation
+	}
+	var validUntil *time.Time
+	if cmd.ValidUntil != nil {
+		t, err := common.ParseDate(*cmd.ValidUntil)
+		if err != nil || t.Before(examDate) {
+			return common.ErrValidation
+		}
+		validUntil = &t
+	}
+	remind := defaultRemindDays
+	if cmd.RemindDaysBefore != nil {
+		if *cmd.RemindDaysBefore < 0 {
+			return common.ErrValidation
+		}
+		remind = *cmd.RemindDaysBefore
+	}
+	q.DogID, q.HandlerID, q.ExamType, q.ExamID = cmd.DogID, cmd.HandlerID, cmd.ExamType, cmd.ExamID
+	q.ExamDate, q.Examiner, q.Result, q.CertificateNumber = examDate, cmd.Examiner, res, cmd.CertificateNumber
+	q.ValidUntil, q.RemindDaysBefore, q.DisciplineID, q.Notes = validUntil, remind, cmd.DisciplineID, cmd.Notes
+	return nil
+}
+
+// checkDiscipline rejects references to disciplines that do not exist.
+func (s *Service) checkDiscipline(ctx context.Context, id *int64) error {
+	if id == nil {
+		return nil
+	}
+	if _, err := s.disciplines.Get(ctx, discipline.DisciplineID(*id)); err != nil {
+		if err == common.ErrNotFound {
+			return common.ErrValidation
+		}
+		logx.Std.Errorf("get discipline failed: %s", err)
+		return err
+	}
+	return nil
+}
+
+func toDTO(q *qualification.Qualification, now time.Time) *dto.Qualification {
+	out := &dto.Qualification{
+		ID: int64(q.ID), DogID: q.DogID, HandlerID: q.HandlerID, ExamType: q.ExamType, ExamID: q.ExamID,
+		ExamDate: q.ExamDate.Format(common.DateLayout), Examiner: q.Examiner, Result: string(q.Result),
+		CertificateNumber: q.CertificateNumber, RemindDaysBefore: q.RemindDaysBefore, DisciplineID: q.DisciplineID, Status: string(q.Status(now)),
+		Notes: q.Notes, CreatedAt: q.CreatedAt.Format(time.RFC3339), UpdatedAt: q.UpdatedAt.Format(time.RFC3339),
+	}
+	if q.ValidUntil != nil {
+		v := q.ValidUntil.Format(common.DateLayout)
+		out.ValidUntil = &v
+	}
+	return out
+}
+
+func toAttachmentDTO(a *qualification.Attachment) *dto.Attachment {
+	return &dto.Attachment{
+		ID: a.ID, QualificationID: int64(a.QualificationID), Filename: a.Filename, ContentType: a.ContentType,
+		Size: a.Size, UploadedAt: a.UploadedAt.Format(time.RFC3339),
+	}
+}
@@ -0,0 +1,197 @@
+package sessions
+
+import (
+	"context"
+	"sort"
+
+	"github.com/tnosaj/sar-training/backend/internal/application/dto"
+	"github.com/tnosaj/sar-training/backend/internal/domain/common"
+	"github.com/tnosaj/sar-training/backend/internal/domain/discipline"
+	"github.com/tnosaj/sar-training/backend/internal/domain/location"
+	"github.com/tnosaj/sar-training/backend/internal/domain/session"
+	logx "github.com/tnosaj/sar-training/backend/internal/infra/log"
+)
+
+// GetAreaSearch returns the area-search details of a round in the session.
+func (s *Service) GetAreaSearch(ctx context.Context, sessionID, roundID int64) (*dto.AreaSearch, error) {
+	logx.Std.Tracef("get area search of round %d", roundID)
+	if _, err := s.round(ctx, sessionID, roundID); err != nil {
+		return nil, err
+	}
+	a, err := s.repo.GetAreaSearch(ctx, roundID)
+	if err != nil {
+		return nil, err
+	}
+	return toAreaSearchDTO(a), nil
+}
+
+// SaveAreaSearch replaces the sector, hidden persons and false alerts of a
+// round. Only users allowed to log rounds for the dog may change them.
+func (s *Service) SaveAreaSearch(ctx context.Context, cmd SaveAreaSearchCommand) (*dto.AreaSearch, error) {
+	logx.Std.Tracef("save area search %v", cmd)
+	r, err := s.round(ctx, cmd.SessionID, cmd.RoundID)
+	if err != nil {
+		return nil, err
+	}
+	a, err := toAreaSearch(cmd.AreaSearchInput)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.allowExtension(ctx, r, discipline.ExtensionAreaSearch); err != nil {
+		return nil, err
+	}
+	if _, err := s.authorize(ctx, r, cmd.UserID); err != nil {
+		return nil, err
+	}
+	if err := s.repo.SaveAreaSearch(ctx, r.ID, a); err != nil {
+		logx.Std.Errorf("save area search failed: %s", err)
+		return nil, err
+	}
+	return toAreaSearchDTO(a), nil
+}
+
+func (s *Service) DeleteAreaSearch(ctx context.Context, sessionID, roundID, userID int64) error {
+	logx.Std.Tracef("delete area search of round %d", roundID)
+	r, err := s.round(ctx, sessionID, roundID)
+	if err != nil {
+		return err
+	}
+	if _, err := s.authorize(ctx, r, userID); err != nil {
+		return err
+	}
+	err = s.repo.DeleteAreaSearch(ctx, roundID)
+	if err != nil && err != common.ErrNotFound {
+		logx.Std.Errorf("delete area search failed: %s", err)
+	}
+	return err
+}
+
+// AreaSearchStats reports the probability of detection, time to find and
+// false alerts per sector. Rounds are grouped by location and sector name.
+func (s *Service) AreaSearchStats(ctx context.Context, q AreaSearchStatsQuery) ([]*dto.AreaSearchSectorStats, error) {
+	logx.Std.Tracef("area search stats %v", q)
+	f := session.AreaSearchFilter{DogID: q.DogID, LocationID: q.LocationID}
+	from, to, err := common.ParseRange(q.From, q.To)
+	if err != nil {
+		return nil, err
+	}
+	f.From, f.To = from, to
+	items, err := s.repo.ListAreaSearches(ctx, f)
+	if err != nil {
+		logx.Std.Errorf("list area searches failed: %s", err)
+		return nil, err
+	}
+	type key struct {
+		location int64
+		sector   string
+	}
+	type acc struct {
+		stats   dto.AreaSearchSectorStats
+		ttfSum  int
+		ttfSeen int
+	}
+	groups := map[key]*acc{}
+	var order []key
+	for _, it := range items {
+		var k key
+		if it.LocationID != nil {
+			k.location = *it.LocationID
+		}
+		if it.SectorName != nil {
+			k.sector = *it.SectorName
+		}
+		a, ok := groups[k]
+		if !ok {
+			a = &acc{stats: dto.AreaSearchSectorStats{SectorName: it.SectorName, LocationID: it.LocationID}}
+			groups[k] = a
+			order = append(order, k)
+		}
+		a.stats.Rounds++
+		a.stats.HiddenPersons += len(it.Persons)
+		a.stats.Found += it.Found()
+		a.stats.FalseAlerts += it.FalseAlerts
+		for _, p := range it.Persons {
+			if p.TimeToFindS != nil {
+				a.ttfSum += *p.TimeToFindS
+				a.ttfSeen++
+			}
+		}
+	}
+	sort.Slice(order, func(i, j int) bool {
+		if order[i].location != order[j].location {
+			return order[i].location < order[j].location
+		}
+		return order[i].sector < order[j].sector
+	})
+	out := make([]*dto.AreaSearchSectorStats, 0, len(order))
+	for _, k := range order {
+		a := groups[k]
+		if a.stats.HiddenPersons > 0 {
+			v := float64(a.stats.Found) / float64(a.stats.HiddenPersons)
+			a.stats.ProbabilityOfDetection = &v
+		}
+		if a.ttfSeen > 0 {
+			v := float64(a.ttfSum) / float64(a.ttfSeen)
+			a.stats.MeanTimeToFindS = &v
+		}
+		a.stats.FalseAlertsPerRound = float64(a.stats.FalseAlerts) / float64(a.stats.Rounds)
+		out = append(out, &a.stats)
+	}
+	return out, nil
+}
+
+// round loads a round and checks it belongs to the session.
+func (s *Service) round(ctx context.Context, sessionID, roundID int64) (*session.Round, error) {
+	r, err := s.repo.GetRound(ctx, roundID)
+	if err != nil {
+		return nil, err
+	}
+	if r.SessionID != sessionID {
+		return nil, common.ErrNotFound
+	}
+	return r, nil
+}
+
+func toAreaSearch(in AreaSearchInput) (*session.AreaSearch, error) {
+	a := &session.AreaSearch{SectorName: in.SectorName, FalseAlerts: in.FalseAlerts}
+	if in.Sector != nil {
+		if in.Sector.Type != "Polygon" || len(in.Sector.Coordinates) == 0 {
+			return nil, common.ErrValidation
+		}
+		ring, ok := location.RingFromPositions(in.Sector.Coordinates[0])
+		if !ok {
+			return nil, common.ErrValidation
+		}
+		a.Sector = ring
+	}
+	for _, p := range in.HiddenPersons {
+		a.Persons = append(a.Persons, session.HiddenPerson{
+			Label: p.Label, Position: location.Point{Lat: p.Latitude, Lon: p.Longitude},
+			Found: p.Found, TimeToFindS: p.TimeToFindS, Notes: p.Notes,
+		})
+	}
+	if !a.Valid() {
+		return nil, common.ErrValidation
+	}
+	return a, nil
+}
+
+func toAreaSearchDTO(a *session.AreaSearch) *dto.AreaSearch {
+	if a == nil {
+		return nil
+	}
+	out := &dto.AreaSearch{
+		SectorName: a.SectorName, FalseAlerts: a.FalseAlerts, HiddenPersons: []dto.HiddenPerson{},
+		Found: a.Found(), ProbabilityOfDetection: a.ProbabilityOfDetection(), MeanTimeToFindS: a.MeanTimeToFindS(),
+	}
+	if len(a.Sector) > 0 {
+		out.Sector = &dto.GeoPolygon{Type: "Polygon", Coordinates: [][][2]float64{location.Positions(a.Sector)}}
+	}
+	for _, p := range a.Persons {
+		out.HiddenPersons = append(out.HiddenPersons, dto.HiddenPerson{
+			ID: p.ID, Label: p.Label, Latitude: p.Position.Lat, Longitude: p.Position.Lon,
+			Found: p.Found, TimeToFindS: p.TimeToFindS, Notes: p.Notes,
+		})
+	}
+	return out
+}
@@ -0,0 +1,87 @@
+package sessions
+
+import (
+	"sort"
+
+	"github.com/tnosaj/sar-training/backend/internal/application/dto"
+	"github.com/tnosaj/sar-training/backend/internal/domain/session"
+)
+
+// outcomeBand accumulates the rounds falling into one band of a factor.
+type outcomeBand struct {
+	dto.ConditionBand
+	reached  int
+	scoreSum float64
+	scored   int
+}
+
+// outcomeBands groups round outcomes by band label for the condition,
+// reinforcement and trailing analytics.
+type outcomeBands map[string]*outcomeBand
+
+// add counts a round in the band and returns the band for further counts.
+func (b outcomeBands) add(label, outcome string, score *int) *outcomeBand {
+	a, ok := b[label]
+	if !ok {
+		a = &outcomeBand{ConditionBand: dto.ConditionBand{Band: label}}
+		b[label] = a
+	}
+	a.Rounds++
+	switch outcome {
+	case "success":
+		a.Successes++
+	case "partial":
+		a.Partials++
+	case "fail":
+		a.Fails++
+	}
+	if score != nil {
+		a.scoreSum += float64(*score)
+		a.scored++
+	}
+	return a
+}
+
+// seen lists the labels that occurred, sorted, for factors without a fixed
+// set of bands.
+func (b outcomeBands) seen() []string {
+	var out []string
+	for label := range b {
+		if label != session.UnknownBand {
+			out = append(out, label)
+		}
+	}
+	sort.Strings(out)
+	return out
+}
+
+// ordered returns the bands in the given order followed by the unknown
+// band, with rates and mean scores filled in. Bands of the order without
+// rounds are kept empty; an empty unknown band is left out.
+func (b outcomeBands) ordered(order []string) []*outcomeBand {
+	out := make([]*outcomeBand, 0, len(order)+1)
+	for _, label := range append(append([]string{}, order...), session.UnknownBand) {
+		a, ok := b[label]
+		if !ok {
+			if label != session.UnknownBand {
+				out = append(out, &outcomeBand{ConditionBand: dto.ConditionBand{Band: label}})
+			}
+			continue
+		}
+		a.SuccessRate = float64(a.Successes) / float64(a.Rounds)
+		if a.scored > 0 {
+			m := a.scoreSum / float64(a.scored)
+			a.MeanScore = &m
+		}
+		out = append(out, a)
+	}
+	return out
+}
+
+func toConditionBands(bands []*outcomeBand) []dto.ConditionBand {
+	out := make([]dto.ConditionBand, 0, len(bands))
+	for _, a := range bands {
+		out = append(out, a.ConditionBand)
+	}
+	return out
+}
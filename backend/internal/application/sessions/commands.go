@@ -1,17 +1,47 @@
 package sessions
 
+import "github.com/tnosaj/sar-training/backend/internal/application/dto"
+
+// Conditions are the weather and terrain fields shared by sessions and
+// rounds.
+type Conditions struct {
+	TemperatureC     *float64 `json:"temperature_c,omitempty"`
+	HumidityPct      *float64 `json:"humidity_pct,omitempty"`
+	WindSpeedKmh     *float64 `json:"wind_speed_kmh,omitempty"`
+	WindDirectionDeg *int     `json:"wind_direction_deg,omitempty"`
+	Precipitation    *string  `json:"precipitation,omitempty"`
+	Terrain          *string  `json:"terrain,omitempty"`
+}
+
+type CriterionScoreInput struct {
+	CriterionID int64 `json:"criterion_id"`
+	Points      int   `json:"points"`
+}
+
+// ReinforcementInput holds the reward and distraction fields of a round.
+type ReinforcementInput struct {
+	Reward           *string  `json:"reward,omitempty"`
+	Schedule         *string  `json:"reinforcement_schedule,omitempty"`
+	Distractions     []string `json:"distractions,omitempty"`
+	DistractionLevel *string  `json:"distraction_level,omitempty"`
+}
+
 type CreateSessionCommand struct {
-	Location  *string `json:"location,omitempty"`
-	Notes     *string `json:"notes,omitempty"`
-	StartedAt *string `json:"started_at,omitempty"`
+	Location   *string `json:"location,omitempty"`
+	LocationID *int64  `json:"location_id,omitempty"`
+	Notes      *string `json:"notes,omitempty"`
+	StartedAt  *string `json:"started_at,omitempty"`
+	Conditions
 }
 
 type UpdateSessionCommand struct {
-	SessionID int64   `json:"-"`
-	Location  *string `json:"location,omitempty"`
-	Notes     *string `json:"notes,omitempty"`
-	StartedAt *string `json:"started_at,omitempty"`
-	EndedAt   *string `json:"ended_at,omitempty"`
+	SessionID  int64   `json:"-"`
+	Location   *string `json:"location,omitempty"`
+	LocationID *int64  `json:"location_id,omitempty"`
+	Notes      *string `json:"notes,omitempty"`
+	StartedAt  *string `json:"started_at,omitempty"`
+	EndedAt    *string `json:"ended_at,omitempty"`
+	Conditions
 }
 
 type CloseSessionCommand struct {
@@ -36,4 +66,134 @@ type CreateRoundCommand struct {
 	Notes               *string `json:"notes,omitempty"`
 	StartedAt           *string `json:"started_at,omitempty"`
 	EndedAt             *string `json:"ended_at,omitempty"`
+	TimeToFirstAlertS   *int    `json:"time_to_first_alert_s,omitempty"`
+	TimeToIndicationS   *int    `json:"time_to_indication_s,omitempty"`
+	HandlerID           *int64  `json:"handler_id,omitempty"`
+	IndicationResult    *string `json:"indication_result,omitempty"`
+	Conditions
+	ReinforcementInput
+	// CriterionScores scores the round on its exercise's rubric; the total
+	// score is computed from them.
+	CriterionScores []CriterionScoreInput `json:"criterion_scores,omitempty"`
+	AreaSearch      *AreaSearchInput      `json:"area_search,omitempty"`
+	Trailing        *TrailingInput        `json:"trailing,omitempty"`
+	Rubble          *RubbleInput          `json:"rubble,omitempty"`
+	// DifficultyLevel picks a level of the exercise; its preset parameter
+	// values apply unless overridden in Parameters.
+	DifficultyLevel *int               `json:"difficulty_level,omitempty"`
+	Parameters      map[string]float64 `json:"parameters,omitempty"`
+	// UserID is the user logging the round.
+	UserID int64 `json:"-"`
+}
+
+type ConditionStatsQuery struct {
+	Factor     string
+	Cuts       []float64
+	DogID      *int64
+	BehaviorID *int64
+	From       *string
+	To         *string
+}
+
+type HiddenPersonInput struct {
+	Label       *string `json:"label,omitempty"`
+	Latitude    float64 `json:"latitude"`
+	Longitude   float64 `json:"longitude"`
+	Found       bool    `json:"found"`
+	TimeToFindS *int    `json:"time_to_find_s,omitempty"`
+	Notes       *string `json:"notes,omitempty"`
+}
+
+type AreaSearchInput struct {
+	SectorName    *string             `json:"sector_name,omitempty"`
+	Sector        *dto.GeoPolygon     `json:"sector,omitempty"`
+	FalseAlerts   int                 `json:"false_alerts"`
+	HiddenPersons []HiddenPersonInput `json:"hidden_persons"`
+}
+
+type SaveAreaSearchCommand struct {
+	SessionID int64 `json:"-"`
+	RoundID   int64 `json:"-"`
+	AreaSearchInput
+	UserID int64 `json:"-"`
+}
+
+type AreaSearchStatsQuery struct {
+	DogID      *int64
+	LocationID *int64
+	From       *string
+	To         *string
+}
+
+type IndicationStatsQuery struct {
+	DogID *int64
+	From  *string
+	To    *string
+}
+
+type ReinforcementStatsQuery struct {
+	Factor     string
+	DogID      *int64
+	BehaviorID *int64
+	From       *string
+	To         *string
+}
+
+type DurationStatsQuery struct {
+	// GroupBy is behavior or exercise.
+	GroupBy    string
+	DogID      *int64
+	BehaviorID *int64
+	ExerciseID *int64
+	From       *string
+	To         *string
+}
+
+type DifficultyProgressQuery struct {
+	DogID      int64
+	ExerciseID *int64
+	// Bucket is week or month.
+	Bucket string
+	From   *string
+	To     *string
+}
+
+type TrailingInput struct {
+	TrailAgeMin    int      `json:"trail_age_min"`
+	TrailLengthM   float64  `json:"trail_length_m"`
+	ScentArticle   *string  `json:"scent_article,omitempty"`
+	StartType      *string  `json:"start_type,omitempty"`
+	Surfaces       []string `json:"surfaces,omitempty"`
+	Contamination  *string  `json:"contamination,omitempty"`
+	ReachedSubject bool     `json:"reached_subject"`
+}
+
+type SaveTrailingCommand struct {
+	SessionID int64 `json:"-"`
+	RoundID   int64 `json:"-"`
+	TrailingInput
+	UserID int64 `json:"-"`
+}
+
+type RubbleInput struct {
+	StructureType string   `json:"structure_type"`
+	Victims       int      `json:"victims"`
+	VictimsFound  int      `json:"victims_found"`
+	MaxDepthM     *float64 `json:"max_depth_m,omitempty"`
+	FalseAlerts   int      `json:"false_alerts"`
+}
+
+type SaveRubbleCommand struct {
+	SessionID int64 `json:"-"`
+	RoundID   int64 `json:"-"`
+	RubbleInput
+	UserID int64 `json:"-"`
+}
+
+type TrailingStatsQuery struct {
+	Factor string
+	Cuts   []float64
+	DogID  *int64
+	From   *string
+	To     *string
 }
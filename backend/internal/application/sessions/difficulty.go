@@ -0,0 +1,148 @@
+package sessions
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/tnosaj/sar-training/backend/internal/application/dto"
+	"github.com/tnosaj/sar-training/backend/internal/domain/common"
+	"github.com/tnosaj/sar-training/backend/internal/domain/exercise"
+	"github.com/tnosaj/sar-training/backend/internal/domain/session"
+	logx "github.com/tnosaj/sar-training/backend/internal/infra/log"
+)
+
+// applyDifficulty records the level and parameter values the round was
+// trained at, checked against the exercise's difficulty settings.
+func (s *Service) applyDifficulty(ctx context.Context, r *session.Round, level *int, params map[string]float64) error {
+	if level == nil && len(params) == 0 {
+		return nil
+	}
+	d, err := s.exercises.Difficulty(ctx, exercise.ExerciseID(r.ExerciseID))
+	if err != nil {
+		logx.Std.Errorf("get difficulty failed: %s", err)
+		return err
+	}
+	values := make(map[exercise.ParameterKind]float64, len(params))
+	for k, v := range params {
+		values[exercise.ParameterKind(k)] = v
+	}
+	resolved, ok := d.Resolve(level, values)
+	if !ok {
+		return common.ErrValidation
+	}
+	r.DifficultyLevel = level
+	if len(resolved) > 0 {
+		r.Parameters = make(map[string]float64, len(resolved))
+		for k, v := range resolved {
+			r.Parameters[string(k)] = v
+		}
+	}
+	return nil
+}
+
+// DifficultyProgress shows per exercise how the difficulty a dog trains at
+// developed, by week or by month.
+func (s *Service) DifficultyProgress(ctx context.Context, q DifficultyProgressQuery) ([]*dto.DifficultyProgress, error) {
+	logx.Std.Tracef("difficulty progress %v", q)
+	if q.Bucket == "" {
+		q.Bucket = "week"
+	}
+	if q.DogID <= 0 || (q.Bucket != "week" && q.Bucket != "month") {
+		return nil, common.ErrValidation
+	}
+	f := session.DifficultyFilter{DogID: q.DogID, ExerciseID: q.ExerciseID}
+	from, to, err := common.ParseRange(q.From, q.To)
+	if err != nil {
+		return nil, err
+	}
+	f.From, f.To = from, to
+	items, err := s.repo.ListDifficultyRounds(ctx, f)
+	if err != nil {
+		logx.Std.Errorf("list difficulty rounds failed: %s", err)
+		return nil, err
+	}
+	type period struct {
+		out       dto.DifficultyPeriod
+		successes int
+		levels    []int
+		params    map[string][]float64
+	}
+	type acc struct {
+		progress dto.DifficultyProgress
+		periods  []*period
+	}
+	groups := map[int64]*acc{}
+	var order []int64
+	for _, it := range items {
+		a, ok := groups[it.ExerciseID]
+		if !ok {
+			a = &acc{progress: dto.DifficultyProgress{DogID: it.DogID, ExerciseID: it.ExerciseID, ExerciseName: it.ExerciseName}}
+			groups[it.ExerciseID] = a
+			order = append(order, it.ExerciseID)
+		}
+		a.progress.Rounds++
+		if it.DifficultyLevel != nil {
+			if a.progress.FirstLevel == nil {
+				a.progress.FirstLevel = it.DifficultyLevel
+			}
+			a.progress.LatestLevel = it.DifficultyLevel
+		}
+		key := periodOf(it.At, q.Bucket)
+		if n := len(a.periods); n == 0 || a.periods[n-1].out.Period != key {
+			a.periods = append(a.periods, &period{out: dto.DifficultyPeriod{Period: key}, params: map[string][]float64{}})
+		}
+		p := a.periods[len(a.periods)-1]
+		p.out.Rounds++
+		if it.Outcome == "success" {
+			p.successes++
+		}
+		if it.DifficultyLevel != nil {
+			p.levels = append(p.levels, *it.DifficultyLevel)
+		}
+		for k, v := range it.Parameters {
+			p.params[k] = append(p.params[k], v)
+		}
+	}
+	sort.Slice(order, func(i, j int) bool { return order[i] < order[j] })
+	out := make([]*dto.DifficultyProgress, 0, len(order))
+	for _, id := range order {
+		a := groups[id]
+		a.progress.Periods = make([]dto.DifficultyPeriod, 0, len(a.periods))
+		for _, p := range a.periods {
+			p.out.SuccessRate = float64(p.successes) / float64(p.out.Rounds)
+			if len(p.levels) > 0 {
+				sort.Ints(p.levels)
+				p.out.MeanLevel, p.out.MaxLevel = meanOf(p.levels), &p.levels[len(p.levels)-1]
+			}
+			if len(p.params) > 0 {
+				p.out.Parameters = make(map[string]dto.ParameterProgress, len(p.params))
+				for k, vs := range p.params {
+					var pp dto.ParameterProgress
+					for i, v := range vs {
+						pp.Mean += v
+						if i == 0 || v > pp.Max {
+							pp.Max = v
+						}
+					}
+					pp.Mean /= float64(len(vs))
+					p.out.Parameters[k] = pp
+				}
+			}
+			a.progress.Periods = append(a.progress.Periods, p.out)
+		}
+		out = append(out, &a.progress)
+	}
+	return out, nil
+}
+
+// periodOf labels the ISO week (2026-W07) or month (2026-02) t falls in.
+func periodOf(t time.Time, bucket string) string {
+	t = t.UTC()
+	if bucket == "month" {
+		return t.Format("2006-01")
+	}
+	y, w := t.ISOWeek()
+	return fmt.Sprintf("%d-W%02d", y, w)
+}
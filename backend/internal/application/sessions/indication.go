@@ -0,0 +1,56 @@
+package sessions
+
+import (
+	"context"
+
+	"github.com/tnosaj/sar-training/backend/internal/application/dto"
+	"github.com/tnosaj/sar-training/backend/internal/domain/common"
+	"github.com/tnosaj/sar-training/backend/internal/domain/session"
+	logx "github.com/tnosaj/sar-training/backend/internal/infra/log"
+)
+
+// IndicationStats reports how reliably each dog gives its trained
+// indication. Rates are shares of the rounds with a recorded result; the
+// false-alert rate counts indications where there was no subject.
+func (s *Service) IndicationStats(ctx context.Context, q IndicationStatsQuery) ([]*dto.IndicationStats, error) {
+	logx.Std.Tracef("indication stats %v", q)
+	f := session.IndicationFilter{DogID: q.DogID}
+	from, to, err := common.ParseRange(q.From, q.To)
+	if err != nil {
+		return nil, err
+	}
+	f.From, f.To = from, to
+	items, err := s.repo.ListIndicationCounts(ctx, f)
+	if err != nil {
+		logx.Std.Errorf("list indication counts failed: %s", err)
+		return nil, err
+	}
+	byDog := map[int64]*dto.IndicationStats{}
+	out := []*dto.IndicationStats{}
+	for _, it := range items {
+		st, ok := byDog[it.DogID]
+		if !ok {
+			st = &dto.IndicationStats{DogID: it.DogID, DogName: it.DogName, Indication: it.Indication}
+			byDog[it.DogID] = st
+			out = append(out, st)
+		}
+		st.Rounds += it.Rounds
+		switch it.Result {
+		case session.IndicationCorrect:
+			st.Correct += it.Rounds
+		case session.IndicationLate:
+			st.Late += it.Rounds
+		case session.IndicationFalse:
+			st.False += it.Rounds
+		case session.IndicationMissing:
+			st.Missing += it.Rounds
+		}
+	}
+	for _, st := range out {
+		n := float64(st.Rounds)
+		st.CorrectRate = float64(st.Correct) / n
+		st.FalseAlertRate = float64(st.False) / n
+		st.MissRate = float64(st.Missing) / n
+	}
+	return out, nil
+}
@@ -0,0 +1,109 @@
+package sessions
+
+import (
+	"context"
+
+	"github.com/tnosaj/sar-training/backend/internal/application/dto"
+	"github.com/tnosaj/sar-training/backend/internal/domain/common"
+	"github.com/tnosaj/sar-training/backend/internal/domain/session"
+	logx "github.com/tnosaj/sar-training/backend/internal/infra/log"
+)
+
+func toReinforcement(in ReinforcementInput, c *session.ReinforcementCatalog) (session.Reinforcement, error) {
+	var r session.Reinforcement
+	if in.Reward != nil {
+		v := session.Reward(*in.Reward)
+		r.Reward = &v
+	}
+	if in.Schedule != nil {
+		v := session.Schedule(*in.Schedule)
+		r.Schedule = &v
+	}
+	if in.DistractionLevel != nil {
+		v := session.DistractionLevel(*in.DistractionLevel)
+		r.DistractionLevel = &v
+	}
+	for _, d := range in.Distractions {
+		r.Distractions = append(r.Distractions, session.Distraction(d))
+	}
+	if !r.Valid() || !c.Allows(&r) {
+		return r, common.ErrValidation
+	}
+	return r, nil
+}
+
+func toReinforcementDTO(r session.Reinforcement) dto.Reinforcement {
+	var out dto.Reinforcement
+	if r.Reward != nil {
+		v := string(*r.Reward)
+		out.Reward = &v
+	}
+	if r.Schedule != nil {
+		v := string(*r.Schedule)
+		out.Schedule = &v
+	}
+	if r.DistractionLevel != nil {
+		v := string(*r.DistractionLevel)
+		out.DistractionLevel = &v
+	}
+	for _, d := range r.Distractions {
+		out.Distractions = append(out.Distractions, string(d))
+	}
+	return out
+}
+
+// ReinforcementStats groups round outcomes by reward, reinforcement
+// schedule, distraction kind or distraction level. Bands follow the catalog
+// order; rounds without the detail fall into the unknown band.
+func (s *Service) ReinforcementStats(ctx context.Context, q ReinforcementStatsQuery) (*dto.ConditionStats, error) {
+	logx.Std.Tracef("reinforcement stats %v", q)
+	factor := session.ReinforcementFactor(q.Factor)
+	if !factor.Valid() {
+		return nil, common.ErrValidation
+	}
+	f := session.ReinforcementFilter{DogID: q.DogID, BehaviorID: q.BehaviorID}
+	from, to, err := common.ParseRange(q.From, q.To)
+	if err != nil {
+		return nil, err
+	}
+	f.From, f.To = from, to
+	items, err := s.repo.ListReinforcementRounds(ctx, f)
+	if err != nil {
+		logx.Std.Errorf("list reinforcement rounds failed: %s", err)
+		return nil, err
+	}
+
+	catalog, err := s.repo.ReinforcementCatalog(ctx)
+	if err != nil {
+		logx.Std.Errorf("get reinforcement catalog failed: %s", err)
+		return nil, err
+	}
+
+	bands := outcomeBands{}
+	for _, it := range items {
+		for _, label := range it.BandsOf(factor) {
+			bands.add(label, it.Outcome, it.Score)
+		}
+	}
+	return &dto.ConditionStats{Factor: string(factor), Bands: toConditionBands(bands.ordered(catalog.Labels(factor)))}, nil
+}
+
+// ReinforcementCatalog lists the rewards, schedules, distractions and
+// distraction levels rounds may record.
+func (s *Service) ReinforcementCatalog(ctx context.Context) (*dto.ReinforcementCatalog, error) {
+	c, err := s.repo.ReinforcementCatalog(ctx)
+	if err != nil {
+		logx.Std.Errorf("get reinforcement catalog failed: %s", err)
+		return nil, err
+	}
+	return &dto.ReinforcementCatalog{Rewards: toCatalogDTO(c.Rewards), Schedules: toCatalogDTO(c.Schedules),
+		Distractions: toCatalogDTO(c.Distractions), DistractionLevels: toCatalogDTO(c.DistractionLevels)}, nil
+}
+
+func toCatalogDTO(entries []session.CatalogEntry) []dto.CatalogEntry {
+	out := make([]dto.CatalogEntry, 0, len(entries))
+	for _, e := range entries {
+		out = append(out, dto.CatalogEntry{Code: e.Code, Name: e.Name})
+	}
+	return out
+}
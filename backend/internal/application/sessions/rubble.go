@@ -0,0 +1,81 @@
+package sessions
+
+import (
+	"context"
+
+	"github.com/tnosaj/sar-training/backend/internal/application/dto"
+	"github.com/tnosaj/sar-training/backend/internal/domain/common"
+	"github.com/tnosaj/sar-training/backend/internal/domain/discipline"
+	"github.com/tnosaj/sar-training/backend/internal/domain/session"
+	logx "github.com/tnosaj/sar-training/backend/internal/infra/log"
+)
+
+func (s *Service) GetRubble(ctx context.Context, sessionID, roundID int64) (*dto.Rubble, error) {
+	logx.Std.Tracef("get rubble details of round %d", roundID)
+	if _, err := s.round(ctx, sessionID, roundID); err != nil {
+		return nil, err
+	}
+	rb, err := s.repo.GetRubble(ctx, roundID)
+	if err != nil {
+		return nil, err
+	}
+	return toRubbleDTO(rb), nil
+}
+
+// SaveRubble replaces the rubble details of a round. Only users allowed to
+// log rounds for the dog may change them.
+func (s *Service) SaveRubble(ctx context.Context, cmd SaveRubbleCommand) (*dto.Rubble, error) {
+	logx.Std.Tracef("save rubble details %v", cmd)
+	r, err := s.round(ctx, cmd.SessionID, cmd.RoundID)
+	if err != nil {
+		return nil, err
+	}
+	rb, err := toRubble(cmd.RubbleInput)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.allowExtension(ctx, r, discipline.ExtensionRubble); err != nil {
+		return nil, err
+	}
+	if _, err := s.authorize(ctx, r, cmd.UserID); err != nil {
+		return nil, err
+	}
+	if err := s.repo.SaveRubble(ctx, r.ID, rb); err != nil {
+		logx.Std.Errorf("save rubble details failed: %s", err)
+		return nil, err
+	}
+	return toRubbleDTO(rb), nil
+}
+
+func (s *Service) DeleteRubble(ctx context.Context, sessionID, roundID, userID int64) error {
+	logx.Std.Tracef("delete rubble details of round %d", roundID)
+	r, err := s.round(ctx, sessionID, roundID)
+	if err != nil {
+		return err
+	}
+	if _, err := s.authorize(ctx, r, userID); err != nil {
+		return err
+	}
+	err = s.repo.DeleteRubble(ctx, roundID)
+	if err != nil && err != common.ErrNotFound {
+		logx.Std.Errorf("delete rubble details failed: %s", err)
+	}
+	return err
+}
+
+func toRubble(in RubbleInput) (*session.Rubble, error) {
+	rb := &session.Rubble{StructureType: session.StructureType(in.StructureType), Victims: in.Victims,
+		VictimsFound: in.VictimsFound, MaxDepthM: in.MaxDepthM, FalseAlerts: in.FalseAlerts}
+	if !rb.Valid() {
+		return nil, common.ErrValidation
+	}
+	return rb, nil
+}
+
+func toRubbleDTO(rb *session.Rubble) *dto.Rubble {
+	if rb == nil {
+		return nil
+	}
+	return &dto.Rubble{StructureType: string(rb.StructureType), Victims: rb.Victims, VictimsFound: rb.VictimsFound,
+		MaxDepthM: rb.MaxDepthM, FalseAlerts: rb.FalseAlerts}
+}
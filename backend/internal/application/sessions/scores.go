@@ -0,0 +1,60 @@
+package sessions
+
+import (
+	"context"
+	"math"
+
+	"github.com/tnosaj/sar-training/backend/internal/application/dto"
+	"github.com/tnosaj/sar-training/backend/internal/domain/common"
+	"github.com/tnosaj/sar-training/backend/internal/domain/exercise"
+	"github.com/tnosaj/sar-training/backend/internal/domain/session"
+	logx "github.com/tnosaj/sar-training/backend/internal/infra/log"
+)
+
+// scoreRound computes the round score from the points per criterion of the
+// exercise's rubric. Exercises with a rubric must be scored by criterion; a
+// score given directly is only accepted for exercises without one.
+func (s *Service) scoreRound(ctx context.Context, r *session.Round, in []CriterionScoreInput) error {
+	rb, err := s.exercises.Rubric(ctx, exercise.ExerciseID(r.ExerciseID))
+	if err != nil {
+		logx.Std.Errorf("get rubric failed: %s", err)
+		return err
+	}
+	if len(rb) == 0 && len(in) == 0 {
+		return nil
+	}
+	if len(rb) == 0 || len(in) == 0 || r.Score != nil {
+		return common.ErrValidation
+	}
+	points := map[exercise.CriterionID]int{}
+	for _, cs := range in {
+		id := exercise.CriterionID(cs.CriterionID)
+		if _, dup := points[id]; dup {
+			return common.ErrValidation
+		}
+		points[id] = cs.Points
+	}
+	total, ok := rb.Score(points)
+	if !ok {
+		return common.ErrValidation
+	}
+	score := int(math.Round(total))
+	r.Score, r.WeightedScore = &score, &total
+	for _, c := range rb {
+		r.CriterionScores = append(r.CriterionScores, session.CriterionScore{
+			CriterionID: int64(c.ID), Name: c.Name, Points: points[c.ID], MaxPoints: c.MaxPoints, Weight: c.Weight,
+		})
+	}
+	return nil
+}
+
+func toCriterionScoresDTO(in []session.CriterionScore) []dto.CriterionScore {
+	if len(in) == 0 {
+		return nil
+	}
+	out := make([]dto.CriterionScore, 0, len(in))
+	for _, cs := range in {
+		out = append(out, dto.CriterionScore{CriterionID: cs.CriterionID, Name: cs.Name, Points: cs.Points, MaxPoints: cs.MaxPoints, Weight: cs.Weight})
+	}
+	return out
+}
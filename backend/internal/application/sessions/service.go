@@ -2,19 +2,69 @@ package sessions
 
 import (
 	"context"
+	"database/sql"
+	"errors"
+	"sort"
+	"strings"
 	"time"
 
 	"github.com/tnosaj/sar-training/backend/internal/application/dto"
 	"github.com/tnosaj/sar-training/backend/internal/domain/common"
+	"github.com/tnosaj/sar-training/backend/internal/domain/discipline"
+	"github.com/tnosaj/sar-training/backend/internal/domain/exercise"
+	"github.com/tnosaj/sar-training/backend/internal/domain/health"
+	"github.com/tnosaj/sar-training/backend/internal/domain/location"
+	"github.com/tnosaj/sar-training/backend/internal/domain/pairing"
 	"github.com/tnosaj/sar-training/backend/internal/domain/session"
+	"github.com/tnosaj/sar-training/backend/internal/domain/user"
 	logx "github.com/tnosaj/sar-training/backend/internal/infra/log"
 )
 
-type Service struct{ repo session.Repository }
+type Service struct {
+	repo        session.Repository
+	pairings    pairing.Repository
+	users       user.Repository
+	health      health.Repository
+	locations   location.Repository
+	disciplines discipline.Repository
+	exercises   exercise.Repository
+}
 
-func NewService(r session.Repository) *Service {
+func NewService(r session.Repository, p pairing.Repository, u user.Repository, h health.Repository, l location.Repository, d discipline.Repository, e exercise.Repository) *Service {
 	logx.Std.Trace("starting sessions service")
-	return &Service{repo: r}
+	return &Service{repo: r, pairings: p, users: u, health: h, locations: l, disciplines: d, exercises: e}
+}
+
+// resolveLocation links the session to the catalogue. A location_id sets
+// the location name and, unless given, the terrain; a bare name is linked
+// when the catalogue knows it.
+func (s *Service) resolveLocation(ctx context.Context, ses *session.Session) error {
+	var l *location.Location
+	var err error
+	switch {
+	case ses.LocationID != nil:
+		l, err = s.locations.Get(ctx, location.LocationID(*ses.LocationID))
+		if err == common.ErrNotFound {
+			return common.ErrValidation
+		}
+	case ses.Location != nil && strings.TrimSpace(*ses.Location) != "":
+		l, err = s.locations.FindByName(ctx, *ses.Location)
+		if err == common.ErrNotFound {
+			return nil
+		}
+	default:
+		return nil
+	}
+	if err != nil {
+		logx.Std.Errorf("resolve location failed: %s", err)
+		return err
+	}
+	id := int64(l.ID)
+	ses.LocationID, ses.Location = &id, &l.Name
+	if ses.Conditions.Terrain == nil {
+		ses.Conditions.Terrain = l.Terrain
+	}
+	return nil
 }
 
 func (s *Service) Create(ctx context.Context, cmd CreateSessionCommand) (*dto.Session, error) {
@@ -24,7 +74,14 @@ func (s *Service) Create(ctx context.Context, cmd CreateSessionCommand) (*dto.Se
 		v := time.Now().UTC().Format(time.RFC3339)
 		started = &v
 	}
-	ent := &session.Session{StartedAt: *started, EndedAt: nil, Location: cmd.Location, Notes: cmd.Notes}
+	cond, err := toConditions(cmd.Conditions)
+	if err != nil {
+		return nil, err
+	}
+	ent := &session.Session{StartedAt: *started, EndedAt: nil, Location: cmd.Location, LocationID: cmd.LocationID, Notes: cmd.Notes, Conditions: cond}
+	if err := s.resolveLocation(ctx, ent); err != nil {
+		return nil, err
+	}
 	if err := s.repo.CreateSession(ctx, ent); err != nil {
 		logx.Std.Errorf("create session failed: %s", err)
 		return nil, err
@@ -39,7 +96,14 @@ func (s *Service) Update(ctx context.Context, cmd UpdateSessionCommand) (*dto.Se
 		v := time.Now().UTC().Format(time.RFC3339)
 		started = &v
 	}
-	ent := &session.Session{ID: session.SessionID(cmd.SessionID), StartedAt: *started, EndedAt: cmd.EndedAt, Location: cmd.Location, Notes: cmd.Notes}
+	cond, err := toConditions(cmd.Conditions)
+	if err != nil {
+		return nil, err
+	}
+	ent := &session.Session{ID: session.SessionID(cmd.SessionID), StartedAt: *started, EndedAt: cmd.EndedAt, Location: cmd.Location, LocationID: cmd.LocationID, Notes: cmd.Notes, Conditions: cond}
+	if err := s.resolveLocation(ctx, ent); err != nil {
+		return nil, err
+	}
 	if err := s.repo.UpdateSession(ctx, ent); err != nil {
 		logx.Std.Errorf("update session failed: %s", err)
 		return nil, err
@@ -76,11 +140,30 @@ func (s *Service) List(ctx context.Context) ([]*dto.Session, error) {
 	return out, nil
 }
 
+// AddDog refuses dogs whose health log flags them as not fit for training
+// on the day of the session.
 func (s *Service) AddDog(ctx context.Context, cmd AddDogCommand) error {
 	if cmd.SessionID <= 0 || cmd.DogID <= 0 {
 		return common.ErrValidation
 	}
-	err := s.repo.AddDog(ctx, cmd.SessionID, cmd.DogID)
+	ses, err := s.repo.GetSession(ctx, cmd.SessionID)
+	if err != nil {
+		return err
+	}
+	day, err := common.ParseDate(ses.StartedAt)
+	if err != nil {
+		day = time.Now().UTC()
+	}
+	unfit, err := s.health.NotFit(ctx, cmd.DogID, day)
+	if err != nil {
+		logx.Std.Errorf("fitness check failed: %s", err)
+		return err
+	}
+	if len(unfit) > 0 {
+		logx.Std.Infof("dog %d not fit for training, not adding to session %d", cmd.DogID, cmd.SessionID)
+		return common.ErrConflict
+	}
+	err = s.repo.AddDog(ctx, cmd.SessionID, cmd.DogID)
 	if err != nil {
 		logx.Std.Errorf("add dog failed: %s", err)
 	}
@@ -123,11 +206,71 @@ func (s *Service) CreateRound(ctx context.Context, cmd CreateRoundCommand) (*dto
 	if cmd.Outcome != "success" && cmd.Outcome != "partial" && cmd.Outcome != "fail" {
 		return nil, common.ErrValidation
 	}
+	cond, err := toConditions(cmd.Conditions)
+	if err != nil {
+		return nil, err
+	}
+	timing, err := toTiming(cmd)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.checkWindow(ctx, cmd.SessionID, timing); err != nil {
+		return nil, err
+	}
+	catalog, err := s.repo.ReinforcementCatalog(ctx)
+	if err != nil {
+		logx.Std.Errorf("get reinforcement catalog failed: %s", err)
+		return nil, err
+	}
+	reinforcement, err := toReinforcement(cmd.ReinforcementInput, catalog)
+	if err != nil {
+		return nil, err
+	}
 	r := &session.Round{
-		SessionID: cmd.SessionID, DogID: cmd.DogID, ExerciseID: cmd.ExerciseID,
+		Conditions: cond, SessionID: cmd.SessionID, DogID: cmd.DogID, ExerciseID: cmd.ExerciseID,
 		PlannedBehaviorID: cmd.PlannedBehaviorID, ExhibitedBehaviorID: cmd.ExhibitedBehaviorID,
 		ExhibitedFreeText: cmd.ExhibitedFreeText, Outcome: cmd.Outcome, Score: cmd.Score,
-		Notes: cmd.Notes, StartedAt: cmd.StartedAt, EndedAt: cmd.EndedAt,
+		Notes: cmd.Notes, Timing: timing, Reinforcement: reinforcement,
+	}
+	if err := s.scoreRound(ctx, r, cmd.CriterionScores); err != nil {
+		return nil, err
+	}
+	if err := s.applyDifficulty(ctx, r, cmd.DifficultyLevel, cmd.Parameters); err != nil {
+		return nil, err
+	}
+	if cmd.IndicationResult != nil {
+		ir := session.IndicationResult(*cmd.IndicationResult)
+		if !ir.Valid() {
+			return nil, common.ErrValidation
+		}
+		r.IndicationResult = &ir
+	}
+	if cmd.AreaSearch != nil {
+		if r.AreaSearch, err = toAreaSearch(*cmd.AreaSearch); err != nil {
+			return nil, err
+		}
+		if err := s.allowExtension(ctx, r, discipline.ExtensionAreaSearch); err != nil {
+			return nil, err
+		}
+	}
+	if cmd.Trailing != nil {
+		if r.Trailing, err = toTrailing(*cmd.Trailing); err != nil {
+			return nil, err
+		}
+		if err := s.allowExtension(ctx, r, discipline.ExtensionTrailing); err != nil {
+			return nil, err
+		}
+	}
+	if cmd.Rubble != nil {
+		if r.Rubble, err = toRubble(*cmd.Rubble); err != nil {
+			return nil, err
+		}
+		if err := s.allowExtension(ctx, r, discipline.ExtensionRubble); err != nil {
+			return nil, err
+		}
+	}
+	if err := s.attribute(ctx, r, cmd.UserID, cmd.HandlerID); err != nil {
+		return nil, err
 	}
 	if err := s.repo.CreateRound(ctx, r); err != nil {
 		logx.Std.Errorf("create round failed: %s", err)
@@ -136,12 +279,169 @@ func (s *Service) CreateRound(ctx context.Context, cmd CreateRoundCommand) (*dto
 	return toRoundDTO(r), nil
 }
 
+// allowExtension rejects round details that the discipline of the planned
+// behavior's skill does not use. Rounds outside any discipline accept all.
+func (s *Service) allowExtension(ctx context.Context, r *session.Round, e discipline.Extension) error {
+	d, err := s.disciplines.ForBehavior(ctx, r.PlannedBehaviorID)
+	if err == common.ErrNotFound {
+		return nil
+	}
+	if err != nil {
+		logx.Std.Errorf("get discipline failed: %s", err)
+		return err
+	}
+	if !d.Allows(e) {
+		return common.ErrValidation
+	}
+	return nil
+}
+
+// attribute checks that userID may log rounds for the dog and sets the
+// round's handler. Once a dog has handlers paired on the round's day only
+// those handlers and admins may log for it. Without an explicit handler the
+// round goes to the logging user if they handle the dog, else to the
+// primary handler, else to the logging user. An explicit handler must be an
+// existing user.
+func (s *Service) attribute(ctx context.Context, r *session.Round, userID int64, handlerID *int64) error {
+	active, err := s.authorize(ctx, r, userID)
+	if err != nil {
+		return err
+	}
+	switch {
+	case handlerID != nil:
+		if len(active) > 0 && !pairing.Handles(active, *handlerID) {
+			return common.ErrValidation
+		}
+		if _, err := s.users.GetUserByID(ctx, *handlerID); err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return common.ErrValidation
+			}
+			return err
+		}
+		r.HandlerID = handlerID
+	case len(active) > 0 && !pairing.Handles(active, userID):
+		if pr := pairing.Primary(active); pr != nil {
+			r.HandlerID = &pr.UserID
+		}
+	case userID > 0:
+		r.HandlerID = &userID
+	}
+	return nil
+}
+
+// authorize checks that userID may log for the round's dog and returns the
+// pairings active on the round's day.
+func (s *Service) authorize(ctx context.Context, r *session.Round, userID int64) ([]*pairing.Pairing, error) {
+	day := time.Now().UTC()
+	if r.StartedAt != nil {
+		day = *r.StartedAt
+	}
+	active, err := s.pairings.List(ctx, pairing.Filter{DogID: &r.DogID, ActiveOn: &day})
+	if err != nil {
+		logx.Std.Errorf("list pairings failed: %s", err)
+		return nil, err
+	}
+	if len(active) > 0 && !pairing.Handles(active, userID) {
+		u, err := s.users.GetUserByID(ctx, userID)
+		if err != nil || !u.IsAdmin {
+			return nil, common.ErrForbidden
+		}
+	}
+	return active, nil
+}
+
+// ConditionStats groups round outcomes by bands of one weather or terrain
+// factor. Numeric factors use q.Cuts as band boundaries, or
+// session.DefaultCuts when none are given; precipitation and terrain group
+// by value.
+func (s *Service) ConditionStats(ctx context.Context, q ConditionStatsQuery) (*dto.ConditionStats, error) {
+	logx.Std.Tracef("condition stats %v", q)
+	factor := session.Factor(q.Factor)
+	if !factor.Valid() {
+		return nil, common.ErrValidation
+	}
+	cuts := q.Cuts
+	if defaults, numeric := session.DefaultCuts[factor]; numeric && len(cuts) == 0 {
+		cuts = defaults
+	}
+	if !sort.Float64sAreSorted(cuts) {
+		return nil, common.ErrValidation
+	}
+	f := session.ConditionFilter{DogID: q.DogID, BehaviorID: q.BehaviorID}
+	from, to, err := common.ParseRange(q.From, q.To)
+	if err != nil {
+		return nil, err
+	}
+	f.From, f.To = from, to
+	items, err := s.repo.ListConditionRounds(ctx, f)
+	if err != nil {
+		logx.Std.Errorf("list condition rounds failed: %s", err)
+		return nil, err
+	}
+
+	bands := outcomeBands{}
+	for _, it := range items {
+		bands.add(it.Conditions.BandOf(factor, cuts), it.Outcome, it.Score)
+	}
+	order := bands.seen()
+	if _, numeric := session.DefaultCuts[factor]; numeric {
+		order = session.BandLabels(cuts)
+	}
+	return &dto.ConditionStats{Factor: string(factor), Bands: toConditionBands(bands.ordered(order))}, nil
+}
+
+func toConditions(c Conditions) (session.Conditions, error) {
+	out := session.Conditions{
+		TemperatureC: c.TemperatureC, HumidityPct: c.HumidityPct, WindSpeedKmh: c.WindSpeedKmh,
+		WindDirectionDeg: c.WindDirectionDeg, Terrain: c.Terrain,
+	}
+	if c.Precipitation != nil {
+		p := session.Precipitation(*c.Precipitation)
+		out.Precipitation = &p
+	}
+	if !out.Valid() {
+		return out, common.ErrValidation
+	}
+	return out, nil
+}
+
+func toConditionsDTO(c session.Conditions) dto.Conditions {
+	out := dto.Conditions{
+		TemperatureC: c.TemperatureC, HumidityPct: c.HumidityPct, WindSpeedKmh: c.WindSpeedKmh,
+		WindDirectionDeg: c.WindDirectionDeg, Terrain: c.Terrain,
+	}
+	if c.Precipitation != nil {
+		p := string(*c.Precipitation)
+		out.Precipitation = &p
+	}
+	return out
+}
+
 func toSessionDTO(ses *session.Session) *dto.Session {
-	return &dto.Session{ID: int64(ses.ID), StartedAt: ses.StartedAt, EndedAt: ses.EndedAt, Location: ses.Location, Notes: ses.Notes}
+	return &dto.Session{ID: int64(ses.ID), StartedAt: ses.StartedAt, EndedAt: ses.EndedAt, Location: ses.Location, LocationID: ses.LocationID, Notes: ses.Notes, Kind: string(ses.Kind), Conditions: toConditionsDTO(ses.Conditions)}
 }
 
 func toRoundDTO(r *session.Round) *dto.Round {
-	return &dto.Round{ID: r.ID, SessionID: r.SessionID, RoundNumber: r.RoundNumber, DogID: r.DogID, ExerciseID: r.ExerciseID, PlannedBehaviorID: r.PlannedBehaviorID, ExhibitedBehaviorID: r.ExhibitedBehaviorID, ExhibitedFreeText: r.ExhibitedFreeText, Outcome: r.Outcome, Score: r.Score, Notes: r.Notes, StartedAt: r.StartedAt, EndedAt: r.EndedAt}
+	var ir *string
+	if r.IndicationResult != nil {
+		v := string(*r.IndicationResult)
+		ir = &v
+	}
+	var started, ended *string
+	var duration *int
+	if r.StartedAt != nil {
+		v := r.StartedAt.Format(time.RFC3339)
+		started = &v
+	}
+	if r.EndedAt != nil {
+		v := r.EndedAt.Format(time.RFC3339)
+		ended = &v
+	}
+	if d, ok := r.Duration(); ok {
+		v := int(d / time.Second)
+		duration = &v
+	}
+	return &dto.Round{ID: r.ID, SessionID: r.SessionID, RoundNumber: r.RoundNumber, DogID: r.DogID, ExerciseID: r.ExerciseID, PlannedBehaviorID: r.PlannedBehaviorID, ExhibitedBehaviorID: r.ExhibitedBehaviorID, ExhibitedFreeText: r.ExhibitedFreeText, Outcome: r.Outcome, Score: r.Score, Notes: r.Notes, StartedAt: started, EndedAt: ended, DurationS: duration, TimeToFirstAlertS: r.TimeToFirstAlertS, TimeToIndicationS: r.TimeToIndicationS, HandlerID: r.HandlerID, IndicationResult: ir, Conditions: toConditionsDTO(r.Conditions), Reinforcement: toReinforcementDTO(r.Reinforcement), WeightedScore: r.WeightedScore, CriterionScores: toCriterionScoresDTO(r.CriterionScores), AreaSearch: toAreaSearchDTO(r.AreaSearch), Trailing: toTrailingDTO(r.Trailing), Rubble: toRubbleDTO(r.Rubble), DifficultyLevel: r.DifficultyLevel, Parameters: r.Parameters}
 }
 
 func (s *Service) ListRoundsByDog(ctx context.Context, dogID int64) ([]*dto.Round, error) {
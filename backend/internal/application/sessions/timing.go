@@ -0,0 +1,157 @@
+package sessions
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/tnosaj/sar-training/backend/internal/application/dto"
+	"github.com/tnosaj/sar-training/backend/internal/domain/common"
+	"github.com/tnosaj/sar-training/backend/internal/domain/session"
+	logx "github.com/tnosaj/sar-training/backend/internal/infra/log"
+)
+
+// toTiming parses the round times and checks that the round does not end
+// before it starts and that the latencies are in order and fit the round.
+func toTiming(cmd CreateRoundCommand) (session.Timing, error) {
+	t := session.Timing{TimeToFirstAlertS: cmd.TimeToFirstAlertS, TimeToIndicationS: cmd.TimeToIndicationS}
+	from, to, err := common.ParseRange(cmd.StartedAt, cmd.EndedAt)
+	if err != nil {
+		return t, err
+	}
+	t.StartedAt, t.EndedAt = from, to
+	if t.EndedAt != nil && (t.StartedAt == nil || t.EndedAt.Before(*t.StartedAt)) {
+		return t, common.ErrValidation
+	}
+	for _, l := range []*int{t.TimeToFirstAlertS, t.TimeToIndicationS} {
+		if l == nil {
+			continue
+		}
+		if *l < 0 {
+			return t, common.ErrValidation
+		}
+		if d, ok := t.Duration(); ok && time.Duration(*l)*time.Second > d {
+			return t, common.ErrValidation
+		}
+	}
+	if t.TimeToFirstAlertS != nil && t.TimeToIndicationS != nil && *t.TimeToIndicationS < *t.TimeToFirstAlertS {
+		return t, common.ErrValidation
+	}
+	return t, nil
+}
+
+// checkWindow rejects round times outside the session. Sessions whose start
+// is not a timestamp are not checked.
+func (s *Service) checkWindow(ctx context.Context, sessionID int64, t session.Timing) error {
+	if t.StartedAt == nil {
+		return nil
+	}
+	ses, err := s.repo.GetSession(ctx, sessionID)
+	if err != nil {
+		return err
+	}
+	start, err := time.Parse(time.RFC3339, ses.StartedAt)
+	if err != nil {
+		return nil
+	}
+	w := session.Window{Start: start}
+	if ses.EndedAt != nil {
+		if end, err := time.Parse(time.RFC3339, *ses.EndedAt); err == nil {
+			w.End = &end
+		}
+	}
+	if !w.Contains(*t.StartedAt) || (t.EndedAt != nil && !w.Contains(*t.EndedAt)) {
+		return common.ErrValidation
+	}
+	return nil
+}
+
+// DurationStats reports round durations and latencies grouped by planned
+// behavior or by exercise.
+func (s *Service) DurationStats(ctx context.Context, q DurationStatsQuery) ([]*dto.DurationStats, error) {
+	logx.Std.Tracef("duration stats %v", q)
+	if q.GroupBy == "" {
+		q.GroupBy = "behavior"
+	}
+	if q.GroupBy != "behavior" && q.GroupBy != "exercise" {
+		return nil, common.ErrValidation
+	}
+	f := session.TimingFilter{DogID: q.DogID, BehaviorID: q.BehaviorID, ExerciseID: q.ExerciseID}
+	from, to, err := common.ParseRange(q.From, q.To)
+	if err != nil {
+		return nil, err
+	}
+	f.From, f.To = from, to
+	items, err := s.repo.ListRoundTimings(ctx, f)
+	if err != nil {
+		logx.Std.Errorf("list round timings failed: %s", err)
+		return nil, err
+	}
+	type acc struct {
+		stats     dto.DurationStats
+		durations []int
+		alerts    []int
+		indicates []int
+	}
+	groups := map[int64]*acc{}
+	var order []int64
+	for _, it := range items {
+		k := it.BehaviorID
+		if q.GroupBy == "exercise" {
+			k = it.ExerciseID
+		}
+		a, ok := groups[k]
+		if !ok {
+			a = &acc{}
+			id := k
+			if q.GroupBy == "exercise" {
+				a.stats.ExerciseID = &id
+			} else {
+				a.stats.BehaviorID = &id
+			}
+			groups[k] = a
+			order = append(order, k)
+		}
+		a.stats.Rounds++
+		if d, ok := it.Duration(); ok {
+			a.durations = append(a.durations, int(d/time.Second))
+		}
+		if it.TimeToFirstAlertS != nil {
+			a.alerts = append(a.alerts, *it.TimeToFirstAlertS)
+		}
+		if it.TimeToIndicationS != nil {
+			a.indicates = append(a.indicates, *it.TimeToIndicationS)
+		}
+	}
+	sort.Slice(order, func(i, j int) bool { return order[i] < order[j] })
+	out := make([]*dto.DurationStats, 0, len(order))
+	for _, k := range order {
+		a := groups[k]
+		a.stats.TimedRounds = len(a.durations)
+		if n := len(a.durations); n > 0 {
+			sort.Ints(a.durations)
+			mean, median := meanOf(a.durations), float64(a.durations[n/2])
+			if n%2 == 0 {
+				median = float64(a.durations[n/2-1]+a.durations[n/2]) / 2
+			}
+			a.stats.MeanDurationS, a.stats.MedianDurationS = mean, &median
+			a.stats.MinDurationS, a.stats.MaxDurationS = &a.durations[0], &a.durations[n-1]
+		}
+		a.stats.MeanTimeToFirstAlertS = meanOf(a.alerts)
+		a.stats.MeanTimeToIndicationS = meanOf(a.indicates)
+		out = append(out, &a.stats)
+	}
+	return out, nil
+}
+
+func meanOf(v []int) *float64 {
+	if len(v) == 0 {
+		return nil
+	}
+	sum := 0
+	for _, x := range v {
+		sum += x
+	}
+	m := float64(sum) / float64(len(v))
+	return &m
+}
@@ -0,0 +1,166 @@
+package sessions
+
+import (
+	"context"
+	"sort"
+
+	"github.com/tnosaj/sar-training/backend/internal/application/dto"
+	"github.com/tnosaj/sar-training/backend/internal/domain/common"
+	"github.com/tnosaj/sar-training/backend/internal/domain/discipline"
+	"github.com/tnosaj/sar-training/backend/internal/domain/session"
+	logx "github.com/tnosaj/sar-training/backend/internal/infra/log"
+)
+
+func (s *Service) GetTrailing(ctx context.Context, sessionID, roundID int64) (*dto.Trailing, error) {
+	logx.Std.Tracef("get trailing details of round %d", roundID)
+	if _, err := s.round(ctx, sessionID, roundID); err != nil {
+		return nil, err
+	}
+	t, err := s.repo.GetTrailing(ctx, roundID)
+	if err != nil {
+		return nil, err
+	}
+	return toTrailingDTO(t), nil
+}
+
+// SaveTrailing replaces the mantrailing details of a round. Only users
+// allowed to log rounds for the dog may change them.
+func (s *Service) SaveTrailing(ctx context.Context, cmd SaveTrailingCommand) (*dto.Trailing, error) {
+	logx.Std.Tracef("save trailing details %v", cmd)
+	r, err := s.round(ctx, cmd.SessionID, cmd.RoundID)
+	if err != nil {
+		return nil, err
+	}
+	t, err := toTrailing(cmd.TrailingInput)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.allowExtension(ctx, r, discipline.ExtensionTrailing); err != nil {
+		return nil, err
+	}
+	if _, err := s.authorize(ctx, r, cmd.UserID); err != nil {
+		return nil, err
+	}
+	if err := s.repo.SaveTrailing(ctx, r.ID, t); err != nil {
+		logx.Std.Errorf("save trailing details failed: %s", err)
+		return nil, err
+	}
+	return toTrailingDTO(t), nil
+}
+
+func (s *Service) DeleteTrailing(ctx context.Context, sessionID, roundID, userID int64) error {
+	logx.Std.Tracef("delete trailing details of round %d", roundID)
+	r, err := s.round(ctx, sessionID, roundID)
+	if err != nil {
+		return err
+	}
+	if _, err := s.authorize(ctx, r, userID); err != nil {
+		return err
+	}
+	err = s.repo.DeleteTrailing(ctx, roundID)
+	if err != nil && err != common.ErrNotFound {
+		logx.Std.Errorf("delete trailing details failed: %s", err)
+	}
+	return err
+}
+
+// TrailingStats groups trailing rounds by one trailing factor and reports
+// how often the dog reached the subject. Numeric factors use q.Cuts as band
+// boundaries, or session.TrailingDefaultCuts when none are given.
+func (s *Service) TrailingStats(ctx context.Context, q TrailingStatsQuery) (*dto.TrailingStats, error) {
+	logx.Std.Tracef("trailing stats %v", q)
+	factor := session.TrailingFactor(q.Factor)
+	if q.Factor == "" {
+		factor = session.TrailingFactorAge
+	}
+	if !factor.Valid() {
+		return nil, common.ErrValidation
+	}
+	cuts := q.Cuts
+	defaults, numeric := session.TrailingDefaultCuts[factor]
+	if numeric && len(cuts) == 0 {
+		cuts = defaults
+	}
+	if !sort.Float64sAreSorted(cuts) {
+		return nil, common.ErrValidation
+	}
+	f := session.TrailingFilter{DogID: q.DogID}
+	from, to, err := common.ParseRange(q.From, q.To)
+	if err != nil {
+		return nil, err
+	}
+	f.From, f.To = from, to
+	items, err := s.repo.ListTrailing(ctx, f)
+	if err != nil {
+		logx.Std.Errorf("list trailing rounds failed: %s", err)
+		return nil, err
+	}
+
+	bands := outcomeBands{}
+	for _, it := range items {
+		for _, label := range it.BandsOf(factor, cuts) {
+			if b := bands.add(label, it.Outcome, nil); it.ReachedSubject {
+				b.reached++
+			}
+		}
+	}
+	order := bands.seen()
+	if numeric {
+		order = session.BandLabels(cuts)
+	}
+	out := &dto.TrailingStats{Factor: string(factor), Bands: []dto.TrailingBand{}}
+	for _, b := range bands.ordered(order) {
+		tb := dto.TrailingBand{Band: b.Band, Rounds: b.Rounds, Reached: b.reached, Successes: b.Successes, SuccessRate: b.SuccessRate}
+		if b.Rounds > 0 {
+			tb.ReachRate = float64(b.reached) / float64(b.Rounds)
+		}
+		out.Bands = append(out.Bands, tb)
+	}
+	return out, nil
+}
+
+func toTrailing(in TrailingInput) (*session.Trailing, error) {
+	t := &session.Trailing{TrailAgeMin: in.TrailAgeMin, TrailLengthM: in.TrailLengthM, ReachedSubject: in.ReachedSubject}
+	if in.ScentArticle != nil {
+		v := session.ScentArticle(*in.ScentArticle)
+		t.ScentArticle = &v
+	}
+	if in.StartType != nil {
+		v := session.StartType(*in.StartType)
+		t.StartType = &v
+	}
+	if in.Contamination != nil {
+		v := session.Contamination(*in.Contamination)
+		t.Contamination = &v
+	}
+	for _, sf := range in.Surfaces {
+		t.Surfaces = append(t.Surfaces, session.Surface(sf))
+	}
+	if !t.Valid() {
+		return nil, common.ErrValidation
+	}
+	return t, nil
+}
+
+func toTrailingDTO(t *session.Trailing) *dto.Trailing {
+	if t == nil {
+		return nil
+	}
+	out := &dto.Trailing{TrailAgeMin: t.TrailAgeMin, TrailLengthM: t.TrailLengthM, ReachedSubject: t.ReachedSubject}
+	if t.ScentArticle != nil {
+		v := string(*t.ScentArticle)
+		out.ScentArticle = &v
+	}
+	if t.StartType != nil {
+		v := string(*t.StartType)
+		out.StartType = &v
+	}
+	if t.Contamination != nil {
+		v := string(*t.Contamination)
+		out.Contamination = &v
+	}
+	for _, sf := range t.Surfaces {
+		out.Surfaces = append(out.Surfaces, string(sf))
+	}
+	return out
+}
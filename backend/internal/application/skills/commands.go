@@ -1,14 +1,16 @@
 package skills
 
 type CreateSkillCommand struct {
-	Name        string  `json:"name"`
-	Description *string `json:"description,omitempty"`
+	Name         string  `json:"name"`
+	Description  *string `json:"description,omitempty"`
+	DisciplineID *int64  `json:"discipline_id,omitempty"`
 }
 
 type UpdateSkillCommand struct {
-	ID          int64   `json:"id"`
-	Name        string  `json:"name"`
-	Description *string `json:"description,omitempty"`
+	ID           int64   `json:"id"`
+	Name         string  `json:"name"`
+	Description  *string `json:"description,omitempty"`
+	DisciplineID *int64  `json:"discipline_id,omitempty"`
 }
 
 type DeleteSkillCommand struct {
@@ -17,4 +19,6 @@ type DeleteSkillCommand struct {
 	Description *string `json:"description,omitempty"`
 }
 
-type ListSkillsQuery struct{}
+type ListSkillsQuery struct {
+	DisciplineID *int64
+}
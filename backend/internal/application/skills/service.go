@@ -6,15 +6,19 @@ import (
 
 	"github.com/tnosaj/sar-training/backend/internal/application/dto"
 	"github.com/tnosaj/sar-training/backend/internal/domain/common"
+	"github.com/tnosaj/sar-training/backend/internal/domain/discipline"
 	"github.com/tnosaj/sar-training/backend/internal/domain/skill"
 	logx "github.com/tnosaj/sar-training/backend/internal/infra/log"
 )
 
-type Service struct{ repo skill.Repository }
+type Service struct {
+	repo        skill.Repository
+	disciplines discipline.Repository
+}
 
-func NewService(r skill.Repository) *Service {
+func NewService(r skill.Repository, d discipline.Repository) *Service {
 	logx.Std.Trace("starting skills service")
-	return &Service{repo: r}
+	return &Service{repo: r, disciplines: d}
 }
 
 func (s *Service) Create(ctx context.Context, cmd CreateSkillCommand) (*dto.Skill, error) {
@@ -30,8 +34,11 @@ func (s *Service) Create(ctx context.Context, cmd CreateSkillCommand) (*dto.Skil
 	if exists {
 		return nil, common.ErrConflict
 	}
+	if err := s.checkDiscipline(ctx, cmd.DisciplineID); err != nil {
+		return nil, err
+	}
 	now := time.Now().UTC()
-	ent := &skill.Skill{Name: cmd.Name, Description: cmd.Description, CreatedAt: now, UpdatedAt: now}
+	ent := &skill.Skill{Name: cmd.Name, Description: cmd.Description, DisciplineID: cmd.DisciplineID, CreatedAt: now, UpdatedAt: now}
 	if err := s.repo.Create(ctx, ent); err != nil {
 		logx.Std.Errorf("create skill failed: %s", err)
 		return nil, err
@@ -39,7 +46,7 @@ func (s *Service) Create(ctx context.Context, cmd CreateSkillCommand) (*dto.Skil
 	return toDTO(ent), nil
 }
 
-func (s *Service) List(ctx context.Context, _ ListSkillsQuery) ([]*dto.Skill, error) {
+func (s *Service) List(ctx context.Context, q ListSkillsQuery) ([]*dto.Skill, error) {
 	logx.Std.Trace("list skills")
 	items, err := s.repo.List(ctx)
 	if err != nil {
@@ -48,6 +55,9 @@ func (s *Service) List(ctx context.Context, _ ListSkillsQuery) ([]*dto.Skill, er
 	}
 	out := make([]*dto.Skill, 0, len(items))
 	for _, it := range items {
+		if q.DisciplineID != nil && (it.DisciplineID == nil || *it.DisciplineID != *q.DisciplineID) {
+			continue
+		}
 		out = append(out, toDTO(it))
 	}
 	return out, nil
@@ -63,8 +73,12 @@ func (s *Service) Update(ctx context.Context, cmd UpdateSkillCommand) (*dto.Skil
 		logx.Std.Errorf("get skill failed: %s", err)
 		return nil, err
 	}
+	if err := s.checkDiscipline(ctx, cmd.DisciplineID); err != nil {
+		return nil, err
+	}
 	ent.Name = cmd.Name
 	ent.Description = cmd.Description
+	ent.DisciplineID = cmd.DisciplineID
 	ent.UpdatedAt = time.Now().UTC()
 	if err := s.repo.Update(ctx, ent); err != nil {
 		logx.Std.Errorf("update skill failed: %s", err)
@@ -82,11 +96,27 @@ func (s *Service) Delete(ctx context.Context, id int64) error {
 	return err
 }
 
+// checkDiscipline rejects references to disciplines that do not exist.
+func (s *Service) checkDiscipline(ctx context.Context, id *int64) error {
+	if id == nil {
+		return nil
+	}
+	if _, err := s.disciplines.Get(ctx, discipline.DisciplineID(*id)); err != nil {
+		if err == common.ErrNotFound {
+			return common.ErrValidation
+		}
+		logx.Std.Errorf("get discipline failed: %s", err)
+		return err
+	}
+	return nil
+}
+
 func toDTO(skl *skill.Skill) *dto.Skill {
 	return &dto.Skill{
 		ID: int64(skl.ID), Name: skl.Name,
-		Description: skl.Description,
-		CreatedAt:   skl.CreatedAt.Format(time.RFC3339),
-		UpdatedAt:   skl.UpdatedAt.Format(time.RFC3339),
+		Description:  skl.Description,
+		DisciplineID: skl.DisciplineID,
+		CreatedAt:    skl.CreatedAt.Format(time.RFC3339),
+		UpdatedAt:    skl.UpdatedAt.Format(time.RFC3339),
 	}
 }
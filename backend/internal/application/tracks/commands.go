@@ -0,0 +1,22 @@
+package tracks
+
+import "io"
+
+type UploadTracksCommand struct {
+	SessionID int64
+	RoundID   *int64
+	Kind      string
+	Data      io.Reader
+}
+
+type ListTracksQuery struct {
+	SessionID   int64
+	RoundID     *int64
+	SweepWidthM float64
+}
+
+type GetTrackQuery struct {
+	SessionID   int64
+	TrackID     int64
+	SweepWidthM float64
+}
@@ -0,0 +1,8 @@
+package tracks
+
+// ParseError reports a GPX file that could not be read.
+type ParseError struct{ Err error }
+
+func (e *ParseError) Error() string { return "invalid gpx file: " + e.Err.Error() }
+
+func (e *ParseError) Unwrap() error { return e.Err }
@@ -0,0 +1,320 @@
+package tracks
+
+import (
+	"context"
+	"math"
+	"time"
+
+	"github.com/tnosaj/sar-training/backend/internal/application/dto"
+	"github.com/tnosaj/sar-training/backend/internal/domain/common"
+	"github.com/tnosaj/sar-training/backend/internal/domain/location"
+	"github.com/tnosaj/sar-training/backend/internal/domain/session"
+	"github.com/tnosaj/sar-training/backend/internal/domain/track"
+	logx "github.com/tnosaj/sar-training/backend/internal/infra/log"
+)
+
+// DefaultSweepWidthM is the width of ground a team is assumed to clear
+// along its path when no sweep width is given.
+const DefaultSweepWidthM = 20
+
+// MaxSweepWidthM bounds the sweep width; wider strips make every track
+// cover its whole sector.
+const MaxSweepWidthM = 500
+
+type Service struct {
+	repo      track.Repository
+	sessions  session.Repository
+	locations location.Repository
+}
+
+func NewService(r track.Repository, s session.Repository, l location.Repository) *Service {
+	logx.Std.Trace("starting tracks service")
+	return &Service{repo: r, sessions: s, locations: l}
+}
+
+// Upload stores every track of a GPX file on the session, and on the round
+// when one is given.
+func (s *Service) Upload(ctx context.Context, cmd UploadTracksCommand) ([]*dto.Track, error) {
+	logx.Std.Tracef("upload tracks to session %d", cmd.SessionID)
+	kind := track.Kind(cmd.Kind)
+	if kind == "" {
+		kind = track.KindHandler
+	}
+	if !kind.Valid() {
+		return nil, common.ErrValidation
+	}
+	sess, err := s.session(ctx, cmd.SessionID, cmd.RoundID)
+	if err != nil {
+		return nil, err
+	}
+	parsed, err := track.ParseGPX(cmd.Data)
+	if err != nil {
+		return nil, &ParseError{Err: err}
+	}
+	sector, err := s.sector(ctx, sess, cmd.RoundID)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now().UTC()
+	out := make([]*dto.Track, 0, len(parsed))
+	for _, t := range parsed {
+		t.SessionID, t.RoundID, t.Kind, t.CreatedAt = cmd.SessionID, cmd.RoundID, kind, now
+		if err := s.repo.Create(ctx, t); err != nil {
+			if err != common.ErrNotFound {
+				logx.Std.Errorf("create track failed: %s", err)
+			}
+			return nil, err
+		}
+		out = append(out, toDTO(t, sector, DefaultSweepWidthM))
+	}
+	logx.Std.Infof("stored %d tracks for session %d", len(out), cmd.SessionID)
+	return out, nil
+}
+
+func (s *Service) List(ctx context.Context, q ListTracksQuery) ([]*dto.Track, error) {
+	logx.Std.Tracef("list tracks %v", q)
+	sess, items, err := s.load(ctx, &q)
+	if err != nil {
+		return nil, err
+	}
+	sectors := map[int64][]location.Point{}
+	out := make([]*dto.Track, 0, len(items))
+	for _, t := range items {
+		sector, err := s.trackSector(ctx, sess, t, sectors)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, toDTO(t, sector, q.SweepWidthM))
+	}
+	return out, nil
+}
+
+// Map returns the tracks and the search sector as GeoJSON, with the
+// coverage of all tracks together.
+func (s *Service) Map(ctx context.Context, q ListTracksQuery) (*dto.GeoFeatureCollection, error) {
+	logx.Std.Tracef("map tracks %v", q)
+	sess, items, err := s.load(ctx, &q)
+	if err != nil {
+		return nil, err
+	}
+	sector, err := s.sector(ctx, sess, q.RoundID)
+	if err != nil {
+		return nil, err
+	}
+	sectors := map[int64][]location.Point{}
+	out := &dto.GeoFeatureCollection{Type: "FeatureCollection", Features: []dto.GeoFeature{}}
+	if len(sector) > 0 {
+		out.Features = append(out.Features, sectorFeature(sector))
+	}
+	var all [][]track.Point
+	for _, t := range items {
+		own, err := s.trackSector(ctx, sess, t, sectors)
+		if err != nil {
+			return nil, err
+		}
+		out.Features = append(out.Features, toFeature(t, own, q.SweepWidthM))
+		all = append(all, t.Segments...)
+	}
+	if len(sector) > 0 && len(all) > 0 {
+		out.Coverage = toCoverageDTO(track.CoverageOf(all, sector, q.SweepWidthM))
+	}
+	return out, nil
+}
+
+// Get returns one track as a GeoJSON feature.
+func (s *Service) Get(ctx context.Context, q GetTrackQuery) (*dto.GeoFeature, error) {
+	logx.Std.Tracef("get track %v", q)
+	w, err := sweepWidth(q.SweepWidthM)
+	if err != nil {
+		return nil, err
+	}
+	q.SweepWidthM = w
+	t, err := s.repo.Get(ctx, track.TrackID(q.TrackID))
+	if err != nil {
+		return nil, err
+	}
+	if t.SessionID != q.SessionID {
+		return nil, common.ErrNotFound
+	}
+	sess, err := s.sessions.GetSession(ctx, q.SessionID)
+	if err != nil {
+		return nil, err
+	}
+	sector, err := s.sector(ctx, sess, t.RoundID)
+	if err != nil {
+		return nil, err
+	}
+	f := toFeature(t, sector, q.SweepWidthM)
+	return &f, nil
+}
+
+func (s *Service) Delete(ctx context.Context, sessionID, trackID int64) error {
+	logx.Std.Tracef("delete track %d of session %d", trackID, sessionID)
+	t, err := s.repo.Get(ctx, track.TrackID(trackID))
+	if err != nil {
+		return err
+	}
+	if t.SessionID != sessionID {
+		return common.ErrNotFound
+	}
+	if err := s.repo.Delete(ctx, t.ID); err != nil {
+		logx.Std.Errorf("delete track failed: %s", err)
+		return err
+	}
+	return nil
+}
+
+// sweepWidth checks a requested sweep width, defaulting it when unset.
+func sweepWidth(w float64) (float64, error) {
+	if w == 0 {
+		return DefaultSweepWidthM, nil
+	}
+	if math.IsNaN(w) || math.IsInf(w, 0) || w < 0 || w > MaxSweepWidthM {
+		return 0, common.ErrValidation
+	}
+	return w, nil
+}
+
+// load fetches the session and tracks for q, defaulting its sweep width.
+func (s *Service) load(ctx context.Context, q *ListTracksQuery) (*session.Session, []*track.Track, error) {
+	w, err := sweepWidth(q.SweepWidthM)
+	if err != nil {
+		return nil, nil, err
+	}
+	q.SweepWidthM = w
+	sess, err := s.session(ctx, q.SessionID, q.RoundID)
+	if err != nil {
+		return nil, nil, err
+	}
+	items, err := s.repo.List(ctx, track.Filter{SessionID: &q.SessionID, RoundID: q.RoundID})
+	if err != nil {
+		logx.Std.Errorf("list tracks failed: %s", err)
+		return nil, nil, err
+	}
+	return sess, items, nil
+}
+
+// session loads the session and checks that the round, if any, belongs to
+// it.
+func (s *Service) session(ctx context.Context, sessionID int64, roundID *int64) (*session.Session, error) {
+	sess, err := s.sessions.GetSession(ctx, sessionID)
+	if err != nil {
+		return nil, err
+	}
+	if roundID != nil {
+		ro, err := s.sessions.GetRound(ctx, *roundID)
+		if err == common.ErrNotFound || (err == nil && ro.SessionID != sessionID) {
+			return nil, common.ErrValidation
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	return sess, nil
+}
+
+// trackSector returns the sector of the track's round, caching sectors by
+// round; tracks without a round use round 0, the session's sector.
+func (s *Service) trackSector(ctx context.Context, sess *session.Session, t *track.Track, cache map[int64][]location.Point) ([]location.Point, error) {
+	var key int64
+	if t.RoundID != nil {
+		key = *t.RoundID
+	}
+	if sector, ok := cache[key]; ok {
+		return sector, nil
+	}
+	sector, err := s.sector(ctx, sess, t.RoundID)
+	if err != nil {
+		return nil, err
+	}
+	cache[key] = sector
+	return sector, nil
+}
+
+// sector returns the assigned search area: the sector recorded on the
+// round's area search, else the boundary of the session's location.
+func (s *Service) sector(ctx context.Context, sess *session.Session, roundID *int64) ([]location.Point, error) {
+	if roundID != nil {
+		a, err := s.sessions.GetAreaSearch(ctx, *roundID)
+		if err != nil && err != common.ErrNotFound {
+			return nil, err
+		}
+		if a != nil && len(a.Sector) > 0 {
+			return a.Sector, nil
+		}
+	}
+	if sess.LocationID == nil {
+		return nil, nil
+	}
+	l, err := s.locations.Get(ctx, location.LocationID(*sess.LocationID))
+	if err == common.ErrNotFound {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return l.Boundary, nil
+}
+
+func toDTO(t *track.Track, sector []location.Point, sweepWidthM float64) *dto.Track {
+	sum := track.Summarize(t.Segments)
+	out := &dto.Track{
+		ID: int64(t.ID), SessionID: t.SessionID, RoundID: t.RoundID, Kind: string(t.Kind), Name: t.Name,
+		Points: sum.Points, DistanceM: sum.DistanceM, AvgSpeedKmh: sum.AvgSpeedKmh(), CreatedAt: t.CreatedAt.Format(time.RFC3339),
+	}
+	if sum.StartedAt != nil {
+		v, e := sum.StartedAt.Format(time.RFC3339), sum.EndedAt.Format(time.RFC3339)
+		d := sum.Duration().Seconds()
+		out.StartedAt, out.EndedAt, out.DurationS = &v, &e, &d
+	}
+	if len(sector) > 0 {
+		out.Coverage = toCoverageDTO(track.CoverageOf(t.Segments, sector, sweepWidthM))
+	}
+	return out
+}
+
+func toCoverageDTO(c track.Coverage) *dto.TrackCoverage {
+	return &dto.TrackCoverage{SectorAreaM2: c.SectorAreaM2, SweepWidthM: c.SweepWidthM, CoverageRate: c.CoverageRate, InSectorRate: c.InSectorRate}
+}
+
+// trackProperties are the GeoJSON properties of a track feature. Point
+// times follow the coordTimes convention of common GPX converters.
+type trackProperties struct {
+	*dto.Track
+	CoordTimes [][]*string `json:"coordTimes,omitempty"`
+}
+
+func toFeature(t *track.Track, sector []location.Point, sweepWidthM float64) dto.GeoFeature {
+	coords := make([][][]float64, len(t.Segments))
+	var times [][]*string
+	hasTimes := false
+	for i, seg := range t.Segments {
+		coords[i] = make([][]float64, len(seg))
+		ts := make([]*string, len(seg))
+		for j, p := range seg {
+			c := []float64{p.Lon, p.Lat}
+			if p.Ele != nil {
+				c = append(c, *p.Ele)
+			}
+			coords[i][j] = c
+			if p.Time != nil {
+				v := p.Time.Format(time.RFC3339)
+				ts[j], hasTimes = &v, true
+			}
+		}
+		times = append(times, ts)
+	}
+	props := trackProperties{Track: toDTO(t, sector, sweepWidthM)}
+	if hasTimes {
+		props.CoordTimes = times
+	}
+	return dto.GeoFeature{Type: "Feature", Geometry: dto.GeoGeometry{Type: "MultiLineString", Coordinates: coords}, Properties: props}
+}
+
+func sectorFeature(sector []location.Point) dto.GeoFeature {
+	return dto.GeoFeature{
+		Type:       "Feature",
+		Geometry:   dto.GeoGeometry{Type: "Polygon", Coordinates: [][][2]float64{location.Positions(sector)}},
+		Properties: map[string]any{"kind": "sector"},
+	}
+}
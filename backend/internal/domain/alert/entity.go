@@ -0,0 +1,72 @@
+package alert
+
+import "time"
+
+type AlertID int64
+
+type Kind string
+
+const (
+	KindRegression Kind = "regression"
+	KindPlateau    Kind = "plateau"
+)
+
+type Metric string
+
+const (
+	MetricSuccessRate Metric = "success_rate"
+	MetricScore       Metric = "score"
+)
+
+type Status string
+
+const (
+	StatusOpen         Status = "open"
+	StatusAcknowledged Status = "acknowledged"
+	StatusDismissed    Status = "dismissed"
+)
+
+type Alert struct {
+	ID             AlertID
+	DogID          int64
+	BehaviorID     int64
+	Kind           Kind
+	Metric         Metric
+	Status         Status
+	Baseline       *float64
+	Recent         float64
+	Slope          float64
+	PValue         *float64
+	Samples        int
+	Message        string
+	WindowStart    time.Time
+	WindowEnd      time.Time
+	CreatedAt      time.Time
+	UpdatedAt      time.Time
+	AcknowledgedBy *int64
+	AcknowledgedAt *time.Time
+}
+
+// Supersedes reports whether a is a new occurrence rather than the same
+// trend prev already reported: its window starts after prev's ended. The
+// status of prev does not matter.
+func (a *Alert) Supersedes(prev *Alert) bool {
+	return prev == nil || a.WindowStart.After(prev.WindowEnd)
+}
+
+// Observation is a single round reduced to what the trend analysis needs.
+// Rounds are attributed to their planned behavior, since that is what the
+// outcome and score grade.
+type Observation struct {
+	DogID      int64
+	BehaviorID int64
+	At         time.Time
+	Outcome    string
+	Score      *int
+}
+
+type Filter struct {
+	DogID      *int64
+	BehaviorID *int64
+	Status     *Status
+}
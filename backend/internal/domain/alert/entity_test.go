@@ -0,0 +1,31 @@
+package alert
+
+import (
+	"testing"
+	"time"
+)
+
+func TestSupersedes(t *testing.T) {
+	day := func(d int) time.Time { return time.Date(2026, 3, d, 0, 0, 0, 0, time.UTC) }
+	prev := &Alert{Status: StatusDismissed, WindowStart: day(1), WindowEnd: day(10)}
+	tests := []struct {
+		name       string
+		start, end time.Time
+		prev       *Alert
+		want       bool
+	}{
+		{"no previous alert", day(5), day(12), nil, true},
+		{"same window", day(1), day(10), prev, false},
+		{"overlapping window", day(5), day(14), prev, false},
+		{"starts when previous ended", day(10), day(20), prev, false},
+		{"starts after previous ended", day(11), day(20), prev, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a := &Alert{WindowStart: tt.start, WindowEnd: tt.end}
+			if got := a.Supersedes(tt.prev); got != tt.want {
+				t.Errorf("Supersedes = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
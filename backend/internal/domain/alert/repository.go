@@ -0,0 +1,17 @@
+package alert
+
+import (
+	"context"
+	"time"
+)
+
+type Repository interface {
+	Create(ctx context.Context, a *Alert) error
+	Get(ctx context.Context, id AlertID) (*Alert, error)
+	List(ctx context.Context, f Filter) ([]*Alert, error)
+	UpdateStatus(ctx context.Context, a *Alert) error
+	// Latest returns the most recent alert of the given kind and metric for a
+	// dog and behavior, or common.ErrNotFound.
+	Latest(ctx context.Context, dogID, behaviorID int64, kind Kind, metric Metric) (*Alert, error)
+	ListObservations(ctx context.Context, since time.Time) ([]Observation, error)
+}
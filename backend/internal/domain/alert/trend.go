@@ -0,0 +1,187 @@
+package alert
+
+import (
+	"fmt"
+	"math"
+	"sort"
+)
+
+// Thresholds tune when a trend is considered meaningful.
+type Thresholds struct {
+	// MinSamples is the number of rounds a dog needs on a behavior before
+	// any trend is reported.
+	MinSamples int
+	// RecentWindow is the number of latest rounds compared to everything
+	// before them.
+	RecentWindow int
+	// MinDrop is the minimum drop in success rate (0..1) for a regression.
+	MinDrop float64
+	// MinScoreDrop is the minimum drop in mean score (0..10) for a regression.
+	MinScoreDrop float64
+	// Significance is the one-sided p-value below which a drop is reported.
+	Significance float64
+	// PlateauWindow is the number of latest rounds fitted for plateau detection.
+	PlateauWindow int
+	// PlateauChange is the largest change in success rate across the plateau
+	// window, as implied by the fitted slope, that still counts as flat.
+	PlateauChange float64
+	// MasteryRate is the success rate at which a flat trend is no longer a
+	// plateau but simply a mastered behavior.
+	MasteryRate float64
+}
+
+func DefaultThresholds() Thresholds {
+	return Thresholds{
+		MinSamples:    12,
+		RecentWindow:  6,
+		MinDrop:       0.2,
+		MinScoreDrop:  1.5,
+		Significance:  0.05,
+		PlateauWindow: 12,
+		PlateauChange: 0.1,
+		MasteryRate:   0.8,
+	}
+}
+
+// OutcomeValue maps a round outcome onto 0..1 so that partial rounds count
+// half towards the success rate.
+func OutcomeValue(outcome string) float64 {
+	switch outcome {
+	case "success":
+		return 1
+	case "partial":
+		return 0.5
+	}
+	return 0
+}
+
+// Analyze inspects the observations of a single dog and behavior and returns
+// the alerts they warrant. The returned alerts are not persisted and carry no
+// status or timestamps besides their window.
+func Analyze(obs []Observation, t Thresholds) []*Alert {
+	if len(obs) < t.MinSamples || len(obs) <= t.RecentWindow {
+		return nil
+	}
+	sorted := make([]Observation, len(obs))
+	copy(sorted, obs)
+	sort.SliceStable(sorted, func(i, j int) bool { return sorted[i].At.Before(sorted[j].At) })
+
+	var out []*Alert
+	split := len(sorted) - t.RecentWindow
+	baseline, recent := sorted[:split], sorted[split:]
+
+	bv, rv := outcomeValues(baseline), outcomeValues(recent)
+	bm, rm := mean(bv), mean(rv)
+	if drop := bm - rm; drop >= t.MinDrop {
+		if p := welchPValue(bv, rv); p < t.Significance {
+			out = append(out, newAlert(recent, KindRegression, MetricSuccessRate, &bm, rm, slope(rv), &p,
+				fmt.Sprintf("success rate dropped from %.0f%% to %.0f%% over the last %d rounds", bm*100, rm*100, len(recent))))
+		}
+	}
+
+	bs, rs := scoreValues(baseline), scoreValues(recent)
+	if len(bs) >= 3 && len(rs) >= 3 {
+		bm, rm := mean(bs), mean(rs)
+		if drop := bm - rm; drop >= t.MinScoreDrop {
+			if p := welchPValue(bs, rs); p < t.Significance {
+				out = append(out, newAlert(recent, KindRegression, MetricScore, &bm, rm, slope(rs), &p,
+					fmt.Sprintf("mean score dropped from %.1f to %.1f over the last %d rounds", bm, rm, len(recent))))
+			}
+		}
+	}
+	if len(out) > 0 {
+		return out
+	}
+
+	if len(sorted) >= t.PlateauWindow {
+		window := sorted[len(sorted)-t.PlateauWindow:]
+		wv := outcomeValues(window)
+		wm, ws := mean(wv), slope(wv)
+		if wm < t.MasteryRate && math.Abs(ws)*float64(len(wv)-1) <= t.PlateauChange {
+			out = append(out, newAlert(window, KindPlateau, MetricSuccessRate, nil, wm, ws, nil,
+				fmt.Sprintf("success rate flat at %.0f%% over the last %d rounds", wm*100, len(window))))
+		}
+	}
+	return out
+}
+
+func newAlert(window []Observation, k Kind, m Metric, baseline *float64, recent, slope float64, p *float64, msg string) *Alert {
+	first, last := window[0], window[len(window)-1]
+	return &Alert{
+		DogID: first.DogID, BehaviorID: first.BehaviorID, Kind: k, Metric: m,
+		Baseline: baseline, Recent: recent, Slope: slope, PValue: p, Samples: len(window),
+		Message: msg, WindowStart: first.At, WindowEnd: last.At,
+	}
+}
+
+func outcomeValues(obs []Observation) []float64 {
+	out := make([]float64, 0, len(obs))
+	for _, o := range obs {
+		out = append(out, OutcomeValue(o.Outcome))
+	}
+	return out
+}
+
+func scoreValues(obs []Observation) []float64 {
+	out := make([]float64, 0, len(obs))
+	for _, o := range obs {
+		if o.Score != nil {
+			out = append(out, float64(*o.Score))
+		}
+	}
+	return out
+}
+
+func mean(v []float64) float64 {
+	if len(v) == 0 {
+		return 0
+	}
+	var sum float64
+	for _, x := range v {
+		sum += x
+	}
+	return sum / float64(len(v))
+}
+
+func variance(v []float64) float64 {
+	if len(v) < 2 {
+		return 0
+	}
+	m := mean(v)
+	var sum float64
+	for _, x := range v {
+		sum += (x - m) * (x - m)
+	}
+	return sum / float64(len(v)-1)
+}
+
+// slope is the least-squares slope of v against its index, i.e. the change
+// per round.
+func slope(v []float64) float64 {
+	n := float64(len(v))
+	if n < 2 {
+		return 0
+	}
+	mx, my := (n-1)/2, mean(v)
+	var num, den float64
+	for i, y := range v {
+		dx := float64(i) - mx
+		num += dx * (y - my)
+		den += dx * dx
+	}
+	return num / den
+}
+
+// welchPValue returns the one-sided p-value that b has a higher mean than r,
+// using Welch's t statistic with a normal approximation.
+func welchPValue(b, r []float64) float64 {
+	se := math.Sqrt(variance(b)/float64(len(b)) + variance(r)/float64(len(r)))
+	diff := mean(b) - mean(r)
+	if se == 0 {
+		if diff > 0 {
+			return 0
+		}
+		return 1
+	}
+	return 0.5 * math.Erfc(diff/se/math.Sqrt2)
+}
@@ -0,0 +1,88 @@
+package alert
+
+import (
+	"testing"
+	"time"
+)
+
+// observations builds one round per day from outcomes ('s', 'p' or 'f') and
+// the matching scores, where a negative score means none.
+func observations(outcomes string, scores ...int) []Observation {
+	start := time.Date(2026, 1, 1, 9, 0, 0, 0, time.UTC)
+	out := make([]Observation, 0, len(outcomes))
+	for i, c := range outcomes {
+		o := Observation{DogID: 1, BehaviorID: 2, At: start.AddDate(0, 0, i), Outcome: "fail"}
+		switch c {
+		case 's':
+			o.Outcome = "success"
+		case 'p':
+			o.Outcome = "partial"
+		}
+		if i < len(scores) && scores[i] >= 0 {
+			s := scores[i]
+			o.Score = &s
+		}
+		out = append(out, o)
+	}
+	return out
+}
+
+func reversed(obs []Observation) []Observation {
+	out := make([]Observation, len(obs))
+	for i, o := range obs {
+		out[len(obs)-1-i] = o
+	}
+	return out
+}
+
+func TestAnalyze(t *testing.T) {
+	type want struct {
+		kind    Kind
+		metric  Metric
+		samples int
+	}
+	scores := []int{8, 8, 8, 8, 8, 8, 8, 8, 8, 8, 8, 8, 4, 4, 4, 4, 4, 4}
+	tests := []struct {
+		name string
+		obs  []Observation
+		want []want
+	}{
+		{"too few rounds", observations("sssssssssff"), nil},
+		{"mastered", observations("ssssssssssssssssss"), nil},
+		{"small drop", observations("sssssssssssssssssf"), nil},
+		{"success rate regression", observations("ssssssssssssffffff"), []want{{KindRegression, MetricSuccessRate, 6}}},
+		{"unsorted input", reversed(observations("ssssssssssssffffff")), []want{{KindRegression, MetricSuccessRate, 6}}},
+		{"score regression", observations("ssssssssssssssssss", scores...), []want{{KindRegression, MetricScore, 6}}},
+		{"both regressions", observations("ssssssssssssffffff", scores...), []want{
+			{KindRegression, MetricSuccessRate, 6}, {KindRegression, MetricScore, 6},
+		}},
+		{"plateau", observations("pppppppppppp"), []want{{KindPlateau, MetricSuccessRate, 12}}},
+		{"improving", observations("ffffffffffffssssss"), nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Analyze(tt.obs, DefaultThresholds())
+			if len(got) != len(tt.want) {
+				t.Fatalf("Analyze returned %d alerts, want %d: %+v", len(got), len(tt.want), got)
+			}
+			last := tt.obs[0].At
+			for _, o := range tt.obs {
+				if o.At.After(last) {
+					last = o.At
+				}
+			}
+			for i, a := range got {
+				w := tt.want[i]
+				if a.Kind != w.kind || a.Metric != w.metric || a.Samples != w.samples {
+					t.Errorf("alert %d = %s/%s over %d rounds, want %s/%s over %d", i, a.Kind, a.Metric, a.Samples, w.kind, w.metric, w.samples)
+				}
+				if !a.WindowEnd.Equal(last) || !a.WindowStart.Equal(last.AddDate(0, 0, 1-w.samples)) {
+					t.Errorf("alert %d window = %s..%s, want the last %d rounds", i, a.WindowStart, a.WindowEnd, w.samples)
+				}
+				if a.DogID != 1 || a.BehaviorID != 2 {
+					t.Errorf("alert %d is for dog %d behavior %d", i, a.DogID, a.BehaviorID)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,14 @@
+package behavior
+
+// FreeText is an exhibited behavior logged as text on rounds that are not
+// linked to a behavior yet. Entries differing only in case or surrounding
+// spaces are grouped; FirstSeen and LastSeen are session start times.
+type FreeText struct {
+	Text      string
+	Rounds    int
+	Dogs      int
+	FirstSeen string
+	LastSeen  string
+	// MatchID is a behavior with the same name, the likely promotion target.
+	MatchID *BehaviorID
+}
@@ -2,8 +2,26 @@ package behavior
 
 import "context"
 
+// ListFilter narrows List to the behaviors of a skill and/or those linked to
+// an exercise.
+type ListFilter struct {
+	SkillID    *int64
+	ExerciseID *int64
+}
+
 type Repository interface {
 	Create(ctx context.Context, b *Behavior) error
-	List(ctx context.Context, skillID *int64) ([]*Behavior, error)
+	List(ctx context.Context, f ListFilter) ([]*Behavior, error)
 	Get(ctx context.Context, id BehaviorID) (*Behavior, error)
+	Update(ctx context.Context, b *Behavior) error
+	// Delete fails with common.ErrConflict while rounds or exam protocol
+	// tasks still refer to the behavior.
+	Delete(ctx context.Context, id BehaviorID) error
+
+	FreeTexts(ctx context.Context) ([]FreeText, error)
+	// Promote links the unlinked rounds whose free text matches one of texts
+	// (ignoring case and surrounding spaces) to b, creating b first when it
+	// has no ID, all in one transaction. It fails with common.ErrNotFound,
+	// creating nothing, when no round matches.
+	Promote(ctx context.Context, texts []string, b *Behavior) (int64, error)
 }
@@ -0,0 +1,38 @@
+package common
+
+import "time"
+
+// DateLayout is how calendar dates are written in the API and stored.
+const DateLayout = "2006-01-02"
+
+// ParseDate accepts a plain date or an RFC3339 timestamp and returns the
+// calendar date in UTC.
+func ParseDate(v string) (time.Time, error) {
+	if t, err := time.Parse(DateLayout, v); err == nil {
+		return t, nil
+	}
+	t, err := time.Parse(time.RFC3339, v)
+	if err != nil {
+		return time.Time{}, err
+	}
+	y, m, d := t.UTC().Date()
+	return time.Date(y, m, d, 0, 0, 0, 0, time.UTC), nil
+}
+
+// ParseRange parses optional RFC3339 bounds of a time range into UTC. Either
+// may be nil; a malformed bound is an ErrValidation.
+func ParseRange(from, to *string) (*time.Time, *time.Time, error) {
+	var out [2]*time.Time
+	for i, v := range []*string{from, to} {
+		if v == nil {
+			continue
+		}
+		t, err := time.Parse(time.RFC3339, *v)
+		if err != nil {
+			return nil, nil, ErrValidation
+		}
+		t = t.UTC()
+		out[i] = &t
+	}
+	return out[0], out[1], nil
+}
@@ -0,0 +1,39 @@
+package discipline
+
+import "time"
+
+type DisciplineID int64
+
+// Extension names a block of discipline-specific round details.
+type Extension string
+
+const (
+	ExtensionAreaSearch Extension = "area_search"
+	ExtensionTrailing   Extension = "trailing"
+	ExtensionRubble     Extension = "rubble"
+)
+
+func (e Extension) Valid() bool {
+	return e == ExtensionAreaSearch || e == ExtensionTrailing || e == ExtensionRubble
+}
+
+// Discipline groups the skills of one kind of search work, such as area
+// search or mantrailing, and names the round details that apply to it.
+type Discipline struct {
+	ID          DisciplineID
+	Name        string
+	Description *string
+	Extensions  []Extension
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+}
+
+// Allows reports whether rounds in the discipline may carry extension e.
+func (d *Discipline) Allows(e Extension) bool {
+	for _, x := range d.Extensions {
+		if x == e {
+			return true
+		}
+	}
+	return false
+}
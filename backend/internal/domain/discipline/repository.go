@@ -0,0 +1,14 @@
+package discipline
+
+import "context"
+
+type Repository interface {
+	Create(ctx context.Context, d *Discipline) error
+	Get(ctx context.Context, id DisciplineID) (*Discipline, error)
+	List(ctx context.Context) ([]*Discipline, error)
+	Update(ctx context.Context, d *Discipline) error
+	Delete(ctx context.Context, id DisciplineID) error
+	// ForBehavior returns the discipline of the behavior's skill, or
+	// common.ErrNotFound when the skill has none.
+	ForBehavior(ctx context.Context, behaviorID int64) (*Discipline, error)
+}
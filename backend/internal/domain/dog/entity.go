@@ -1,10 +1,96 @@
 package dog
 
+import "time"
+
 type DogID int64
 
+type Status string
+
+const (
+	StatusInTraining  Status = "in_training"
+	StatusOperational Status = "operational"
+	StatusRetired     Status = "retired"
+)
+
+func (s Status) Valid() bool {
+	return s == StatusInTraining || s == StatusOperational || s == StatusRetired
+}
+
+type Sex string
+
+const (
+	SexMale   Sex = "male"
+	SexFemale Sex = "female"
+)
+
+// Indication is the final response a dog is trained to give when it has
+// found the missing person.
+type Indication string
+
+const (
+	IndicationBark         Indication = "bark"
+	IndicationBringsel     Indication = "bringsel"
+	IndicationRecallRefind Indication = "recall_refind"
+	IndicationPassiveSit   Indication = "passive_sit"
+)
+
+func (i Indication) Valid() bool {
+	switch i {
+	case IndicationBark, IndicationBringsel, IndicationRecallRefind, IndicationPassiveSit:
+		return true
+	}
+	return false
+}
+
 type Dog struct {
-	ID       DogID
-	Name     string
-	Callname *string
-	Birthdate *string
+	ID                 DogID
+	Name               string
+	Callname           *string
+	Birthdate          *string
+	Breed              *string
+	Sex                *Sex
+	Microchip          *string
+	RegistrationNumber *string
+	Owner              *string
+	HandlerID          *int64
+	Status             Status
+	Indication         *Indication
+	Attributes         map[string]string
+	HasPhoto           bool
+	ArchivedAt         *time.Time
+}
+
+type Photo struct {
+	DogID       DogID
+	ContentType string
+	Data        []byte
+	UpdatedAt   time.Time
+}
+
+// PhotoTypes are the raster image types a photo may be stored and served
+// as.
+var PhotoTypes = map[string]bool{
+	"image/jpeg": true,
+	"image/png":  true,
+	"image/gif":  true,
+	"image/webp": true,
+}
+
+type Filter struct {
+	Status          *Status
+	IncludeArchived bool
+	// HandlerUserID restricts the result to dogs the user currently handles.
+	HandlerUserID *int64
+}
+
+// History counts the rows per table that reference a dog and would be
+// removed by a purge.
+type History map[string]int64
+
+func (h History) Total() int64 {
+	var n int64
+	for _, v := range h {
+		n += v
+	}
+	return n
 }
@@ -1,11 +1,31 @@
 package dog
 
-import "context"
+import (
+	"context"
+	"time"
+)
 
 type Repository interface {
 	Create(ctx context.Context, d *Dog) error
-	List(ctx context.Context) ([]*Dog, error)
+	List(ctx context.Context, f Filter) ([]*Dog, error)
 	Get(ctx context.Context, id DogID) (*Dog, error)
 	Update(ctx context.Context, d *Dog) error
+	// Delete removes a dog without history and returns common.ErrConflict
+	// while rounds still reference it.
 	Delete(ctx context.Context, id DogID) error
+	// SetArchived archives the dog at the given time, or restores it when
+	// at is nil.
+	SetArchived(ctx context.Context, id DogID, at *time.Time) error
+	// Archive retires the dog and archives it at the given time in a single
+	// write.
+	Archive(ctx context.Context, id DogID, at time.Time) error
+	History(ctx context.Context, id DogID) (History, error)
+	// Purge deletes the dog together with its whole history, including the
+	// sessions of its mock exams, in one transaction and returns what was
+	// removed.
+	Purge(ctx context.Context, id DogID) (History, error)
+
+	SetPhoto(ctx context.Context, p *Photo) error
+	GetPhoto(ctx context.Context, id DogID) (*Photo, error)
+	DeletePhoto(ctx context.Context, id DogID) error
 }
@@ -0,0 +1,47 @@
+package exam
+
+import "time"
+
+type ExamID int64
+
+// Exam describes what a dog has to show over a recent window of training
+// before it is considered ready for an operational exam.
+type Exam struct {
+	ID           ExamID
+	Name         string
+	Description  *string
+	WindowDays   int
+	Requirements []Requirement
+	CreatedAt    time.Time
+	UpdatedAt    time.Time
+}
+
+// Requirement targets either a single behavior, every behavior of a skill,
+// or with Missions set the dog's real deployments, where a find counts as
+// success. WindowDays overrides the exam window when set.
+type Requirement struct {
+	ID             int64
+	ExamID         ExamID
+	BehaviorID     *int64
+	SkillID        *int64
+	Missions       bool
+	MinSuccessRate float64
+	MinScore       *float64
+	MinRounds      int
+	WindowDays     *int
+	Weight         float64
+}
+
+// Window returns the look-back window of the requirement in days.
+func (r Requirement) Window(e *Exam) int {
+	if r.WindowDays != nil {
+		return *r.WindowDays
+	}
+	return e.WindowDays
+}
+
+// RoundResult is the part of a round that counts towards readiness.
+type RoundResult struct {
+	Outcome string
+	Score   *int
+}
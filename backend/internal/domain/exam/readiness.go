@@ -0,0 +1,104 @@
+package exam
+
+import "math"
+
+type RequirementState string
+
+const (
+	StateMet              RequirementState = "met"
+	StateNotMet           RequirementState = "not_met"
+	StateInsufficientData RequirementState = "insufficient_data"
+)
+
+// Evaluation is the outcome of checking one requirement against a dog's
+// rounds. Gaps are zero once the respective criterion is satisfied.
+type Evaluation struct {
+	Requirement    Requirement
+	State          RequirementState
+	Rounds         int
+	SuccessRate    float64
+	MeanScore      *float64
+	RoundsGap      int
+	SuccessRateGap float64
+	ScoreGap       float64
+	// Readiness is the fraction (0..1) of the requirement fulfilled.
+	Readiness float64
+}
+
+// Evaluate scores rounds against a requirement. Only "success" outcomes count
+// towards the success rate; an exam does not pass on partial results.
+func Evaluate(req Requirement, rounds []RoundResult) Evaluation {
+	ev := Evaluation{Requirement: req, Rounds: len(rounds)}
+	var successes, scored, scoreSum int
+	for _, r := range rounds {
+		if r.Outcome == "success" {
+			successes++
+		}
+		if r.Score != nil {
+			scored++
+			scoreSum += *r.Score
+		}
+	}
+	if len(rounds) > 0 {
+		ev.SuccessRate = float64(successes) / float64(len(rounds))
+	}
+	if scored > 0 {
+		m := float64(scoreSum) / float64(scored)
+		ev.MeanScore = &m
+	}
+
+	ev.RoundsGap = max(0, req.MinRounds-len(rounds))
+	ev.SuccessRateGap = math.Max(0, req.MinSuccessRate-ev.SuccessRate)
+
+	fractions := []float64{ratio(float64(len(rounds)), float64(req.MinRounds))}
+	if req.MinSuccessRate > 0 {
+		fractions = append(fractions, ratio(ev.SuccessRate, req.MinSuccessRate))
+	}
+	if req.MinScore != nil {
+		got := 0.0
+		if ev.MeanScore != nil {
+			got = *ev.MeanScore
+		}
+		ev.ScoreGap = math.Max(0, *req.MinScore-got)
+		fractions = append(fractions, ratio(got, *req.MinScore))
+	}
+	ev.Readiness = 1
+	for _, f := range fractions {
+		ev.Readiness = math.Min(ev.Readiness, f)
+	}
+
+	switch {
+	case ev.RoundsGap > 0:
+		ev.State = StateInsufficientData
+	case ev.SuccessRateGap > 0 || ev.ScoreGap > 0:
+		ev.State = StateNotMet
+	default:
+		ev.State = StateMet
+	}
+	return ev
+}
+
+// Overall returns the weighted readiness (0..1) of a set of evaluations and
+// whether every requirement is met.
+func Overall(evs []Evaluation) (float64, bool) {
+	var sum, weights float64
+	ready := true
+	for _, ev := range evs {
+		sum += ev.Readiness * ev.Requirement.Weight
+		weights += ev.Requirement.Weight
+		if ev.State != StateMet {
+			ready = false
+		}
+	}
+	if weights == 0 {
+		return 0, false
+	}
+	return sum / weights, ready
+}
+
+func ratio(got, want float64) float64 {
+	if want <= 0 {
+		return 1
+	}
+	return math.Min(1, got/want)
+}
@@ -0,0 +1,18 @@
+package exam
+
+import (
+	"context"
+	"time"
+)
+
+type Repository interface {
+	Create(ctx context.Context, e *Exam) error
+	Get(ctx context.Context, id ExamID) (*Exam, error)
+	List(ctx context.Context) ([]*Exam, error)
+	Update(ctx context.Context, e *Exam) error
+	Delete(ctx context.Context, id ExamID) error
+	// ListResults returns the dog's rounds since the given time whose planned
+	// behavior matches the requirement, or its deployments on missions that
+	// were not cancelled for a missions requirement.
+	ListResults(ctx context.Context, dogID int64, req Requirement, since time.Time) ([]RoundResult, error)
+}
@@ -0,0 +1,108 @@
+package exercise
+
+import "strings"
+
+// ParameterKind is a dimension an exercise is varied by.
+type ParameterKind string
+
+const (
+	ParamDistance ParameterKind = "distance_m"
+	ParamDuration ParameterKind = "duration_s"
+	ParamHides    ParameterKind = "hides"
+	ParamTrailAge ParameterKind = "trail_age_min"
+)
+
+// ParameterKinds lists the known kinds in display order.
+var ParameterKinds = []ParameterKind{ParamDistance, ParamDuration, ParamHides, ParamTrailAge}
+
+func (k ParameterKind) Valid() bool {
+	for _, v := range ParameterKinds {
+		if k == v {
+			return true
+		}
+	}
+	return false
+}
+
+// Parameter is a kind the exercise is varied by with its allowed range;
+// either bound may be open.
+type Parameter struct {
+	Kind ParameterKind
+	Min  *float64
+	Max  *float64
+}
+
+// Allows reports whether v is a valid value for the parameter.
+func (p Parameter) Allows(v float64) bool {
+	if v < 0 || (p.Kind == ParamHides && v != float64(int64(v))) {
+		return false
+	}
+	return (p.Min == nil || v >= *p.Min) && (p.Max == nil || v <= *p.Max)
+}
+
+// Level is a named difficulty step, 1 being the easiest, with preset values
+// for some or all of the exercise's parameters.
+type Level struct {
+	Level       int
+	Name        string
+	Description *string
+	Values      map[ParameterKind]float64
+}
+
+// Difficulty is how an exercise can be varied: its parameters and levels.
+type Difficulty struct {
+	Parameters []Parameter
+	Levels     []Level
+}
+
+func (d Difficulty) Parameter(k ParameterKind) (Parameter, bool) {
+	for _, p := range d.Parameters {
+		if p.Kind == k {
+			return p, true
+		}
+	}
+	return Parameter{}, false
+}
+
+func (d Difficulty) Level(n int) (Level, bool) {
+	for _, l := range d.Levels {
+		if l.Level == n {
+			return l, true
+		}
+	}
+	return Level{}, false
+}
+
+// Keeps reports whether d still has level l under the same number and name.
+// Rounds record only the level number, so a level rounds were trained at
+// must keep both.
+func (d Difficulty) Keeps(l Level) bool {
+	n, ok := d.Level(l.Level)
+	return ok && strings.EqualFold(n.Name, l.Name)
+}
+
+// Resolve checks the level and parameter values chosen for a round and
+// fills in the level's presets for parameters not given. Every value must
+// be for a configured parameter and within its range.
+func (d Difficulty) Resolve(level *int, values map[ParameterKind]float64) (map[ParameterKind]float64, bool) {
+	out := map[ParameterKind]float64{}
+	if level != nil {
+		l, ok := d.Level(*level)
+		if !ok {
+			return nil, false
+		}
+		for k, v := range l.Values {
+			out[k] = v
+		}
+	}
+	for k, v := range values {
+		out[k] = v
+	}
+	for k, v := range out {
+		p, ok := d.Parameter(k)
+		if !ok || !p.Allows(v) {
+			return nil, false
+		}
+	}
+	return out, true
+}
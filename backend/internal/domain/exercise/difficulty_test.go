@@ -0,0 +1,96 @@
+package exercise
+
+import (
+	"reflect"
+	"testing"
+)
+
+func ptr[T any](v T) *T { return &v }
+
+func TestParameterAllows(t *testing.T) {
+	tests := []struct {
+		name  string
+		param Parameter
+		v     float64
+		want  bool
+	}{
+		{"open range", Parameter{Kind: ParamDistance}, 1234.5, true},
+		{"negative", Parameter{Kind: ParamDistance}, -1, false},
+		{"at min", Parameter{Kind: ParamDistance, Min: ptr(10.0)}, 10, true},
+		{"below min", Parameter{Kind: ParamDistance, Min: ptr(10.0)}, 9.9, false},
+		{"at max", Parameter{Kind: ParamDuration, Max: ptr(60.0)}, 60, true},
+		{"above max", Parameter{Kind: ParamDuration, Max: ptr(60.0)}, 60.5, false},
+		{"whole hides", Parameter{Kind: ParamHides}, 3, true},
+		{"fractional hides", Parameter{Kind: ParamHides}, 2.5, false},
+		{"fractional trail age", Parameter{Kind: ParamTrailAge}, 2.5, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.param.Allows(tt.v); got != tt.want {
+				t.Errorf("Allows(%v) = %v, want %v", tt.v, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDifficultyResolve(t *testing.T) {
+	d := Difficulty{
+		Parameters: []Parameter{
+			{Kind: ParamDistance, Min: ptr(0.0), Max: ptr(500.0)},
+			{Kind: ParamHides},
+		},
+		Levels: []Level{
+			{Level: 1, Name: "Easy", Values: map[ParameterKind]float64{ParamDistance: 50, ParamHides: 1}},
+			{Level: 2, Name: "Hard", Values: map[ParameterKind]float64{ParamDistance: 400}},
+		},
+	}
+	tests := []struct {
+		name   string
+		level  *int
+		values map[ParameterKind]float64
+		want   map[ParameterKind]float64
+		ok     bool
+	}{
+		{"nothing chosen", nil, nil, map[ParameterKind]float64{}, true},
+		{"level presets", ptr(1), nil, map[ParameterKind]float64{ParamDistance: 50, ParamHides: 1}, true},
+		{"override a preset", ptr(1), map[ParameterKind]float64{ParamDistance: 80}, map[ParameterKind]float64{ParamDistance: 80, ParamHides: 1}, true},
+		{"add to presets", ptr(2), map[ParameterKind]float64{ParamHides: 3}, map[ParameterKind]float64{ParamDistance: 400, ParamHides: 3}, true},
+		{"values without level", nil, map[ParameterKind]float64{ParamHides: 2}, map[ParameterKind]float64{ParamHides: 2}, true},
+		{"unknown level", ptr(3), nil, nil, false},
+		{"out of range", nil, map[ParameterKind]float64{ParamDistance: 600}, nil, false},
+		{"unconfigured parameter", nil, map[ParameterKind]float64{ParamDuration: 30}, nil, false},
+		{"invalid override", ptr(1), map[ParameterKind]float64{ParamHides: 1.5}, nil, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := d.Resolve(tt.level, tt.values)
+			if ok != tt.ok {
+				t.Fatalf("ok = %v, want %v", ok, tt.ok)
+			}
+			if ok && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("values = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDifficultyKeeps(t *testing.T) {
+	d := Difficulty{Levels: []Level{{Level: 1, Name: "Easy"}, {Level: 2, Name: "Hard"}}}
+	tests := []struct {
+		name string
+		l    Level
+		want bool
+	}{
+		{"same number and name", Level{Level: 2, Name: "Hard"}, true},
+		{"name differs in case", Level{Level: 1, Name: "easy"}, true},
+		{"renumbered", Level{Level: 1, Name: "Hard"}, false},
+		{"removed", Level{Level: 3, Name: "Expert"}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := d.Keeps(tt.l); got != tt.want {
+				t.Errorf("Keeps(%v) = %v, want %v", tt.l, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,16 @@
+package exercise
+
+// Link ties a behavior to an exercise that trains it. Strength (1-5) is how
+// directly the exercise trains the behavior.
+type Link struct {
+	BehaviorID   int64
+	BehaviorName string
+	ExerciseID   ExerciseID
+	ExerciseName string
+	Strength     int
+}
+
+type LinkFilter struct {
+	BehaviorID *int64
+	ExerciseID *int64
+}
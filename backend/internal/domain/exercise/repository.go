@@ -2,9 +2,35 @@ package exercise
 
 import "context"
 
+// ListFilter narrows List to the exercises linked to a behavior.
+type ListFilter struct {
+	BehaviorID *int64
+}
+
 type Repository interface {
 	Create(ctx context.Context, e *Exercise) error
-	List(ctx context.Context) ([]*Exercise, error)
+	List(ctx context.Context, f ListFilter) ([]*Exercise, error)
 	Get(ctx context.Context, id ExerciseID) (*Exercise, error)
+	Update(ctx context.Context, e *Exercise) error
+	// Delete fails with common.ErrConflict while rounds or exam protocol
+	// tasks still refer to the exercise.
+	Delete(ctx context.Context, id ExerciseID) error
+
 	LinkBehavior(ctx context.Context, behaviorID int64, exerciseID int64, strength int) error
+	UnlinkBehavior(ctx context.Context, behaviorID int64, exerciseID int64) error
+	Links(ctx context.Context, f LinkFilter) ([]Link, error)
+
+	Rubric(ctx context.Context, id ExerciseID) (Rubric, error)
+	// SetRubric replaces the criteria of the exercise. Criteria with an ID
+	// are updated, new ones created and missing ones removed; removing a
+	// scored criterion or changing its MaxPoints fails with
+	// common.ErrConflict.
+	SetRubric(ctx context.Context, id ExerciseID, rb Rubric) error
+
+	Difficulty(ctx context.Context, id ExerciseID) (Difficulty, error)
+	// SetDifficulty replaces the parameters and levels of the exercise.
+	// Rounds keep the values they were recorded with; it fails with
+	// common.ErrConflict when a level rounds were trained at would be
+	// renumbered, renamed or removed.
+	SetDifficulty(ctx context.Context, id ExerciseID, d Difficulty) error
 }
@@ -0,0 +1,41 @@
+package exercise
+
+type CriterionID int64
+
+// Criterion is one scored aspect of an exercise, such as search pattern or
+// handler work. Points range from 0 to MaxPoints.
+type Criterion struct {
+	ID          CriterionID
+	ExerciseID  ExerciseID
+	Name        string
+	Description *string
+	Weight      float64
+	MaxPoints   int
+	Position    int
+}
+
+// Rubric is the ordered set of criteria rounds of an exercise are scored
+// against. An exercise without criteria has no rubric.
+type Rubric []*Criterion
+
+// MaxScore is the upper end of the round score scale.
+const MaxScore = 10
+
+// Score weighs the points per criterion into a total on the 0 to MaxScore
+// scale. Every criterion must be scored within its range and no other
+// criterion may be given.
+func (rb Rubric) Score(points map[CriterionID]int) (float64, bool) {
+	if len(rb) == 0 || len(points) != len(rb) {
+		return 0, false
+	}
+	var sum, weights float64
+	for _, c := range rb {
+		p, ok := points[c.ID]
+		if !ok || p < 0 || p > c.MaxPoints {
+			return 0, false
+		}
+		sum += c.Weight * float64(p) / float64(c.MaxPoints)
+		weights += c.Weight
+	}
+	return MaxScore * sum / weights, true
+}
@@ -0,0 +1,42 @@
+package exercise
+
+import (
+	"math"
+	"testing"
+)
+
+func TestRubricScore(t *testing.T) {
+	rb := Rubric{
+		{ID: 1, Name: "Search pattern", Weight: 2, MaxPoints: 10},
+		{ID: 2, Name: "Indication", Weight: 1, MaxPoints: 5},
+	}
+	tests := []struct {
+		name   string
+		rubric Rubric
+		points map[CriterionID]int
+		want   float64
+		ok     bool
+	}{
+		{"full marks", rb, map[CriterionID]int{1: 10, 2: 5}, 10, true},
+		{"no points", rb, map[CriterionID]int{1: 0, 2: 0}, 0, true},
+		{"weighted", rb, map[CriterionID]int{1: 8, 2: 5}, 10 * (2*0.8 + 1) / 3, true},
+		{"heavier criterion dominates", rb, map[CriterionID]int{1: 10, 2: 0}, 10 * 2.0 / 3, true},
+		{"missing criterion", rb, map[CriterionID]int{1: 10}, 0, false},
+		{"unknown criterion", rb, map[CriterionID]int{1: 10, 3: 5}, 0, false},
+		{"extra criterion", rb, map[CriterionID]int{1: 10, 2: 5, 3: 1}, 0, false},
+		{"above max", rb, map[CriterionID]int{1: 11, 2: 5}, 0, false},
+		{"negative", rb, map[CriterionID]int{1: 5, 2: -1}, 0, false},
+		{"empty rubric", nil, map[CriterionID]int{}, 0, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := tt.rubric.Score(tt.points)
+			if ok != tt.ok {
+				t.Fatalf("ok = %v, want %v", ok, tt.ok)
+			}
+			if math.Abs(got-tt.want) > 1e-9 {
+				t.Errorf("score = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,62 @@
+package figurant
+
+import "time"
+
+type FigurantID int64
+
+// Figurant is a volunteer who acts as hidden person or trail layer.
+// ScentConsent records that they agreed to hand out scent articles, which
+// mantrailing rounds require.
+type Figurant struct {
+	ID           FigurantID
+	Name         string
+	Phone        *string
+	Email        *string
+	ScentConsent bool
+	ConsentDate  *time.Time
+	Active       bool
+	Notes        *string
+	CreatedAt    time.Time
+	UpdatedAt    time.Time
+}
+
+// Availability is a date range, both ends inclusive, in which a figurant
+// can be scheduled.
+type Availability struct {
+	ID         int64
+	FigurantID FigurantID
+	StartDate  time.Time
+	EndDate    time.Time
+	Notes      *string
+}
+
+// Covers reports whether the range includes the calendar day of t.
+func (a *Availability) Covers(t time.Time) bool {
+	y, m, d := t.UTC().Date()
+	day := time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
+	return !day.Before(a.StartDate) && !day.After(a.EndDate)
+}
+
+type Filter struct {
+	ActiveOnly bool
+	// AvailableOn restricts the result to figurants with an availability
+	// range covering that day.
+	AvailableOn *time.Time
+}
+
+// Appearance is one round a figurant acted in.
+type Appearance struct {
+	FigurantID  FigurantID
+	RoundID     int64
+	SessionID   int64
+	RoundNumber int64
+	DogID       int64
+	DogName     string
+	// Date is the round's start, falling back to its session's.
+	Date string
+}
+
+type HistoryFilter struct {
+	FigurantID *FigurantID
+	DogID      *int64
+}
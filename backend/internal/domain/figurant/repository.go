@@ -0,0 +1,32 @@
+package figurant
+
+import (
+	"context"
+	"time"
+)
+
+type Repository interface {
+	Create(ctx context.Context, f *Figurant) error
+	Get(ctx context.Context, id FigurantID) (*Figurant, error)
+	List(ctx context.Context, f Filter) ([]*Figurant, error)
+	Update(ctx context.Context, f *Figurant) error
+	// Delete fails with common.ErrConflict once the figurant acted in a
+	// round.
+	Delete(ctx context.Context, id FigurantID) error
+
+	AddAvailability(ctx context.Context, a *Availability) error
+	ListAvailability(ctx context.Context, id FigurantID) ([]*Availability, error)
+	DeleteAvailability(ctx context.Context, id FigurantID, availabilityID int64) error
+	// AvailableOn returns the ids of figurants available on the day of t.
+	AvailableOn(ctx context.Context, t time.Time) (map[FigurantID]bool, error)
+
+	AssignSession(ctx context.Context, sessionID int64, id FigurantID) error
+	UnassignSession(ctx context.Context, sessionID int64, id FigurantID) error
+	ListBySession(ctx context.Context, sessionID int64) ([]*Figurant, error)
+	// SetRoundFigurants replaces the figurants of a round and adds them to
+	// the round's session.
+	SetRoundFigurants(ctx context.Context, roundID int64, ids []FigurantID) error
+	ListByRound(ctx context.Context, roundID int64) ([]*Figurant, error)
+
+	History(ctx context.Context, f HistoryFilter) ([]*Appearance, error)
+}
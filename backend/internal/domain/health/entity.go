@@ -0,0 +1,66 @@
+package health
+
+import "time"
+
+type EntryID int64
+
+type Kind string
+
+const (
+	KindVetVisit    Kind = "vet_visit"
+	KindVaccination Kind = "vaccination"
+	KindInjury      Kind = "injury"
+	KindMedication  Kind = "medication"
+	KindRest        Kind = "rest"
+	KindHeat        Kind = "heat"
+	KindWeight      Kind = "weight"
+)
+
+func (k Kind) Valid() bool {
+	switch k {
+	case KindVetVisit, KindVaccination, KindInjury, KindMedication, KindRest, KindHeat, KindWeight:
+		return true
+	}
+	return false
+}
+
+// Entry is one record in a dog's health log. Date and EndDate span the
+// period the entry covers (an injury until it healed, a course of
+// medication); DueDate is when a vaccination must be repeated. NotFit marks
+// the dog as not fit for training for the whole period, open-ended while
+// EndDate is nil.
+type Entry struct {
+	ID        EntryID
+	DogID     int64
+	Kind      Kind
+	Date      time.Time
+	EndDate   *time.Time
+	DueDate   *time.Time
+	Title     *string
+	Vet       *string
+	WeightKg  *float64
+	NotFit    bool
+	Notes     *string
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+// Covers reports whether the entry's period includes the calendar day of t.
+func (e *Entry) Covers(t time.Time) bool {
+	y, m, d := t.UTC().Date()
+	day := time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
+	if day.Before(e.Date) {
+		return false
+	}
+	return e.EndDate == nil || !day.After(*e.EndDate)
+}
+
+type Filter struct {
+	DogID *int64
+	Kind  *Kind
+	From  *time.Time
+	To    *time.Time
+	// DueBefore restricts the result to entries with a due date on or
+	// before the given day.
+	DueBefore *time.Time
+}
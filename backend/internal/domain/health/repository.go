@@ -0,0 +1,17 @@
+package health
+
+import (
+	"context"
+	"time"
+)
+
+type Repository interface {
+	Create(ctx context.Context, e *Entry) error
+	Get(ctx context.Context, id EntryID) (*Entry, error)
+	List(ctx context.Context, f Filter) ([]*Entry, error)
+	Update(ctx context.Context, e *Entry) error
+	Delete(ctx context.Context, id EntryID) error
+	// NotFit returns the entries flagging the dog as not fit for training
+	// on the day of t.
+	NotFit(ctx context.Context, dogID int64, t time.Time) ([]*Entry, error)
+}
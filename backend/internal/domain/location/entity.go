@@ -0,0 +1,41 @@
+package location
+
+import "time"
+
+type LocationID int64
+
+// Point is a WGS84 coordinate.
+type Point struct {
+	Lat float64
+	Lon float64
+}
+
+func (p Point) Valid() bool {
+	return p.Lat >= -90 && p.Lat <= 90 && p.Lon >= -180 && p.Lon <= 180
+}
+
+type Location struct {
+	ID          LocationID
+	Name        string
+	Center      *Point
+	Terrain     *string
+	AccessNotes *string
+	// Boundary is the outer ring of the area, without repeating the first
+	// point at the end.
+	Boundary  []Point
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+// Stats summarises the training done at a location.
+type Stats struct {
+	Sessions     int
+	Rounds       int
+	Successes    int
+	Partials     int
+	Fails        int
+	Dogs         int
+	MeanScore    *float64
+	FirstSession *string
+	LastSession  *string
+}
@@ -0,0 +1,92 @@
+package location
+
+import "math"
+
+const earthRadiusKm = 6371.0
+
+// DistanceKm returns the great-circle distance between a and b.
+func DistanceKm(a, b Point) float64 {
+	lat1, lat2 := a.Lat*math.Pi/180, b.Lat*math.Pi/180
+	dLat := lat2 - lat1
+	dLon := (b.Lon - a.Lon) * math.Pi / 180
+	h := math.Sin(dLat/2)*math.Sin(dLat/2) + math.Cos(lat1)*math.Cos(lat2)*math.Sin(dLon/2)*math.Sin(dLon/2)
+	return 2 * earthRadiusKm * math.Asin(math.Min(1, math.Sqrt(h)))
+}
+
+// Contains reports whether p lies inside the ring, using ray casting on
+// plain coordinates. That is accurate enough for training areas of a few
+// kilometres away from the poles and the antimeridian.
+func Contains(ring []Point, p Point) bool {
+	in := false
+	for i, j := 0, len(ring)-1; i < len(ring); j, i = i, i+1 {
+		a, b := ring[i], ring[j]
+		if (a.Lat > p.Lat) != (b.Lat > p.Lat) &&
+			p.Lon < (b.Lon-a.Lon)*(p.Lat-a.Lat)/(b.Lat-a.Lat)+a.Lon {
+			in = !in
+		}
+	}
+	return in
+}
+
+// Centroid returns the area centroid of the ring, or the mean of its points
+// when the ring is degenerate.
+func Centroid(ring []Point) Point {
+	if len(ring) == 0 {
+		return Point{}
+	}
+	// Work relative to the first vertex; absolute coordinates lose
+	// precision in the cross products.
+	o := ring[0]
+	var area, cx, cy float64
+	for i, j := 0, len(ring)-1; i < len(ring); j, i = i, i+1 {
+		x0, y0 := ring[j].Lon-o.Lon, ring[j].Lat-o.Lat
+		x1, y1 := ring[i].Lon-o.Lon, ring[i].Lat-o.Lat
+		cross := x0*y1 - x1*y0
+		area += cross
+		cx += (x0 + x1) * cross
+		cy += (y0 + y1) * cross
+	}
+	if area == 0 {
+		var p Point
+		for _, r := range ring {
+			p.Lat += r.Lat / float64(len(ring))
+			p.Lon += r.Lon / float64(len(ring))
+		}
+		return p
+	}
+	return Point{Lat: o.Lat + cy/(3*area), Lon: o.Lon + cx/(3*area)}
+}
+
+// RingFromPositions turns GeoJSON [longitude, latitude] positions into a
+// ring, dropping the closing position. It returns false when the ring has
+// fewer than three points or an invalid coordinate.
+func RingFromPositions(positions [][2]float64) ([]Point, bool) {
+	if n := len(positions); n > 1 && positions[0] == positions[n-1] {
+		positions = positions[:n-1]
+	}
+	if len(positions) < 3 {
+		return nil, false
+	}
+	ring := make([]Point, 0, len(positions))
+	for _, c := range positions {
+		p := Point{Lon: c[0], Lat: c[1]}
+		if !p.Valid() {
+			return nil, false
+		}
+		ring = append(ring, p)
+	}
+	return ring, true
+}
+
+// Positions returns the ring as closed GeoJSON [longitude, latitude]
+// positions.
+func Positions(ring []Point) [][2]float64 {
+	out := make([][2]float64, 0, len(ring)+1)
+	for _, p := range ring {
+		out = append(out, [2]float64{p.Lon, p.Lat})
+	}
+	if len(ring) > 0 {
+		out = append(out, out[0])
+	}
+	return out
+}
@@ -0,0 +1,17 @@
+package location
+
+import "context"
+
+type Repository interface {
+	Create(ctx context.Context, l *Location) error
+	Get(ctx context.Context, id LocationID) (*Location, error)
+	// FindByName matches names case-insensitively.
+	FindByName(ctx context.Context, name string) (*Location, error)
+	List(ctx context.Context) ([]*Location, error)
+	Update(ctx context.Context, l *Location) error
+	Delete(ctx context.Context, id LocationID) error
+	// Merge moves every session from the source locations to target and
+	// deletes the sources.
+	Merge(ctx context.Context, target LocationID, sources []LocationID) (int64, error)
+	Stats(ctx context.Context, id LocationID) (*Stats, error)
+}
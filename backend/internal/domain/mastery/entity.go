@@ -0,0 +1,40 @@
+package mastery
+
+import "time"
+
+// Prerequisite says BehaviorID is trained once RequiresID is mastered.
+type Prerequisite struct {
+	BehaviorID int64
+	RequiresID int64
+}
+
+// Criteria decide when a dog has mastered a behavior: at least MinRounds
+// rounds planned for it within the last WindowDays, with the success rate
+// and, when set, the mean score reaching the minimums.
+type Criteria struct {
+	MinRounds      int
+	MinSuccessRate float64
+	MinScore       *float64
+	WindowDays     int
+}
+
+// DefaultCriteria apply to behaviors without criteria of their own.
+var DefaultCriteria = Criteria{MinRounds: 10, MinSuccessRate: 0.8, WindowDays: 30}
+
+// RoundResult is a dog's round for a behavior and when it started.
+type RoundResult struct {
+	BehaviorID int64
+	Outcome    string
+	Score      *int
+	At         time.Time
+}
+
+type State string
+
+const (
+	// StateLocked means a prerequisite is not mastered yet.
+	StateLocked     State = "locked"
+	StateUnlocked   State = "unlocked"
+	StateInProgress State = "in_progress"
+	StateMastered   State = "mastered"
+)
@@ -0,0 +1,52 @@
+package mastery
+
+// Assessment is how a dog's rounds for a behavior measure up to its
+// criteria.
+type Assessment struct {
+	Rounds      int
+	SuccessRate float64
+	MeanScore   *float64
+	Mastered    bool
+}
+
+// Assess evaluates rounds, already limited to the criteria window.
+func (c Criteria) Assess(rounds []RoundResult) Assessment {
+	a := Assessment{Rounds: len(rounds)}
+	var successes, scored, scoreSum int
+	for _, r := range rounds {
+		if r.Outcome == "success" {
+			successes++
+		}
+		if r.Score != nil {
+			scored++
+			scoreSum += *r.Score
+		}
+	}
+	if len(rounds) > 0 {
+		a.SuccessRate = float64(successes) / float64(len(rounds))
+	}
+	if scored > 0 {
+		m := float64(scoreSum) / float64(scored)
+		a.MeanScore = &m
+	}
+	a.Mastered = a.Rounds >= c.MinRounds && a.SuccessRate >= c.MinSuccessRate
+	if c.MinScore != nil && (a.MeanScore == nil || *a.MeanScore < *c.MinScore) {
+		a.Mastered = false
+	}
+	return a
+}
+
+// StateOf places a behavior in the curriculum. Mastery counts whatever the
+// prerequisites; otherwise a behavior with an unmastered prerequisite is
+// locked.
+func StateOf(a Assessment, prerequisitesMastered bool) State {
+	switch {
+	case a.Mastered:
+		return StateMastered
+	case !prerequisitesMastered:
+		return StateLocked
+	case a.Rounds > 0:
+		return StateInProgress
+	}
+	return StateUnlocked
+}
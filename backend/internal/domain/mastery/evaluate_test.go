@@ -0,0 +1,78 @@
+package mastery
+
+import "testing"
+
+func rounds(outcomes string, score *int) []RoundResult {
+	out := make([]RoundResult, 0, len(outcomes))
+	for _, c := range outcomes {
+		r := RoundResult{BehaviorID: 1, Score: score}
+		switch c {
+		case 's':
+			r.Outcome = "success"
+		case 'p':
+			r.Outcome = "partial"
+		default:
+			r.Outcome = "fail"
+		}
+		out = append(out, r)
+	}
+	return out
+}
+
+func TestAssess(t *testing.T) {
+	six, eight := 6, 8
+	minScore := 7.0
+	base := Criteria{MinRounds: 4, MinSuccessRate: 0.75, WindowDays: 30}
+	scored := base
+	scored.MinScore = &minScore
+	tests := []struct {
+		name      string
+		criteria  Criteria
+		rounds    []RoundResult
+		wantRate  float64
+		wantScore *float64
+		mastered  bool
+	}{
+		{"no rounds", base, nil, 0, nil, false},
+		{"too few rounds", base, rounds("sss", nil), 1, nil, false},
+		{"rate reached", base, rounds("sssf", nil), 0.75, nil, true},
+		{"rate missed", base, rounds("sspf", nil), 0.5, nil, false},
+		{"score required but missing", scored, rounds("ssss", nil), 1, nil, false},
+		{"score too low", scored, rounds("ssss", &six), 1, ptr(6.0), false},
+		{"score reached", scored, rounds("ssss", &eight), 1, ptr(8.0), true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a := tt.criteria.Assess(tt.rounds)
+			if a.Rounds != len(tt.rounds) || a.SuccessRate != tt.wantRate || a.Mastered != tt.mastered {
+				t.Errorf("Assess = %+v, want %d rounds, rate %v, mastered %v", a, len(tt.rounds), tt.wantRate, tt.mastered)
+			}
+			if (a.MeanScore == nil) != (tt.wantScore == nil) || (a.MeanScore != nil && *a.MeanScore != *tt.wantScore) {
+				t.Errorf("mean score = %v, want %v", a.MeanScore, tt.wantScore)
+			}
+		})
+	}
+}
+
+func TestStateOf(t *testing.T) {
+	tests := []struct {
+		name       string
+		a          Assessment
+		prereqsMet bool
+		want       State
+	}{
+		{"mastered despite prerequisites", Assessment{Rounds: 10, Mastered: true}, false, StateMastered},
+		{"locked", Assessment{Rounds: 3}, false, StateLocked},
+		{"in progress", Assessment{Rounds: 3}, true, StateInProgress},
+		{"unlocked", Assessment{}, true, StateUnlocked},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := StateOf(tt.a, tt.prereqsMet); got != tt.want {
+				t.Errorf("StateOf = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func ptr[T any](v T) *T { return &v }
@@ -0,0 +1,77 @@
+package mastery
+
+// Reaches reports whether to can be reached from from by following
+// prerequisite edges, i.e. whether from depends on to.
+func Reaches(edges []Prerequisite, from, to int64) bool {
+	next := map[int64][]int64{}
+	for _, e := range edges {
+		next[e.BehaviorID] = append(next[e.BehaviorID], e.RequiresID)
+	}
+	seen := map[int64]bool{from: true}
+	stack := []int64{from}
+	for len(stack) > 0 {
+		id := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		if id == to {
+			return true
+		}
+		for _, n := range next[id] {
+			if !seen[n] {
+				seen[n] = true
+				stack = append(stack, n)
+			}
+		}
+	}
+	return false
+}
+
+// CreatesCycle reports whether adding p to edges would close a cycle.
+func CreatesCycle(edges []Prerequisite, p Prerequisite) bool {
+	return p.BehaviorID == p.RequiresID || Reaches(edges, p.RequiresID, p.BehaviorID)
+}
+
+// Order sorts ids so that every behavior comes after its prerequisites,
+// keeping the given order among independent ones.
+func Order(ids []int64, edges []Prerequisite) []int64 {
+	pos := map[int64]int{}
+	for i, id := range ids {
+		pos[id] = i
+	}
+	pending := map[int64]int{}
+	dependents := map[int64][]int64{}
+	for _, e := range edges {
+		if _, ok := pos[e.BehaviorID]; !ok {
+			continue
+		}
+		if _, ok := pos[e.RequiresID]; !ok {
+			continue
+		}
+		pending[e.BehaviorID]++
+		dependents[e.RequiresID] = append(dependents[e.RequiresID], e.BehaviorID)
+	}
+	out := make([]int64, 0, len(ids))
+	done := map[int64]bool{}
+	for len(out) < len(ids) {
+		progressed := false
+		for _, id := range ids {
+			if done[id] || pending[id] > 0 {
+				continue
+			}
+			done[id], progressed = true, true
+			out = append(out, id)
+			for _, d := range dependents[id] {
+				pending[d]--
+			}
+		}
+		if !progressed {
+			// a cycle slipped in; append the rest as given
+			for _, id := range ids {
+				if !done[id] {
+					out = append(out, id)
+				}
+			}
+			break
+		}
+	}
+	return out
+}
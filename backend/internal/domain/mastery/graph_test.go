@@ -0,0 +1,55 @@
+package mastery
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestCreatesCycle(t *testing.T) {
+	// 3 requires 2, 2 requires 1
+	chain := []Prerequisite{{BehaviorID: 3, RequiresID: 2}, {BehaviorID: 2, RequiresID: 1}}
+	tests := []struct {
+		name  string
+		edges []Prerequisite
+		p     Prerequisite
+		want  bool
+	}{
+		{"self", nil, Prerequisite{BehaviorID: 1, RequiresID: 1}, true},
+		{"first edge", nil, Prerequisite{BehaviorID: 2, RequiresID: 1}, false},
+		{"direct back edge", chain, Prerequisite{BehaviorID: 1, RequiresID: 2}, true},
+		{"transitive back edge", chain, Prerequisite{BehaviorID: 1, RequiresID: 3}, true},
+		{"shortcut", chain, Prerequisite{BehaviorID: 3, RequiresID: 1}, false},
+		{"existing edge", chain, Prerequisite{BehaviorID: 3, RequiresID: 2}, false},
+		{"unrelated behaviors", chain, Prerequisite{BehaviorID: 5, RequiresID: 4}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := CreatesCycle(tt.edges, tt.p); got != tt.want {
+				t.Errorf("CreatesCycle(%v) = %v, want %v", tt.p, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestOrder(t *testing.T) {
+	tests := []struct {
+		name  string
+		ids   []int64
+		edges []Prerequisite
+		want  []int64
+	}{
+		{"no edges keeps order", []int64{3, 1, 2}, nil, []int64{3, 1, 2}},
+		{"prerequisite first", []int64{2, 1}, []Prerequisite{{BehaviorID: 2, RequiresID: 1}}, []int64{1, 2}},
+		{"chain", []int64{3, 2, 1}, []Prerequisite{{BehaviorID: 3, RequiresID: 2}, {BehaviorID: 2, RequiresID: 1}}, []int64{1, 2, 3}},
+		{"independent stay in place", []int64{4, 2, 1}, []Prerequisite{{BehaviorID: 2, RequiresID: 1}}, []int64{4, 1, 2}},
+		{"edges to unlisted behaviors ignored", []int64{2, 3}, []Prerequisite{{BehaviorID: 2, RequiresID: 9}}, []int64{2, 3}},
+		{"cycle keeps the rest", []int64{1, 2, 3}, []Prerequisite{{BehaviorID: 1, RequiresID: 2}, {BehaviorID: 2, RequiresID: 1}}, []int64{3, 1, 2}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Order(tt.ids, tt.edges); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Order = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
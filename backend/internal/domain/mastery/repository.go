@@ -0,0 +1,23 @@
+package mastery
+
+import (
+	"context"
+	"time"
+)
+
+type Repository interface {
+	Prerequisites(ctx context.Context) ([]Prerequisite, error)
+	// AddPrerequisite fails with common.ErrConflict when p would close a
+	// cycle.
+	AddPrerequisite(ctx context.Context, p Prerequisite) error
+	RemovePrerequisite(ctx context.Context, p Prerequisite) error
+
+	// Criteria returns the behaviors' own criteria by behavior ID.
+	Criteria(ctx context.Context) (map[int64]Criteria, error)
+	SetCriteria(ctx context.Context, behaviorID int64, c Criteria) error
+	DeleteCriteria(ctx context.Context, behaviorID int64) error
+
+	// Rounds lists the dog's rounds started at or after since by planned
+	// behavior.
+	Rounds(ctx context.Context, dogID int64, since time.Time) ([]RoundResult, error)
+}
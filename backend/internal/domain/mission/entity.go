@@ -0,0 +1,71 @@
+package mission
+
+import (
+	"time"
+
+	"github.com/tnosaj/sar-training/backend/internal/domain/location"
+)
+
+type MissionID int64
+
+type Outcome string
+
+const (
+	OutcomeFind      Outcome = "find"
+	OutcomeNoFind    Outcome = "no_find"
+	OutcomeCancelled Outcome = "cancelled"
+)
+
+func (o Outcome) Valid() bool {
+	return o == OutcomeFind || o == OutcomeNoFind || o == OutcomeCancelled
+}
+
+// Mission is a real callout, as opposed to a training session.
+type Mission struct {
+	ID             MissionID
+	StartedAt      time.Time
+	EndedAt        *time.Time
+	AlertingAgency string
+	// Reference is the agency's incident number.
+	Reference  *string
+	Location   *string
+	LocationID *int64
+	SearchArea []location.Point
+	Outcome    Outcome
+	Debrief    *string
+	Teams      []Team
+	CreatedAt  time.Time
+	UpdatedAt  time.Time
+}
+
+// Team is a dog deployed on a mission with its handler. Found is set for
+// the teams that located the missing person.
+type Team struct {
+	ID            int64
+	DogID         int64
+	HandlerID     *int64
+	DisciplineID  *int64
+	Found         bool
+	SearchMinutes *int
+	Notes         *string
+}
+
+type Filter struct {
+	DogID     *int64
+	HandlerID *int64
+	Outcome   *Outcome
+	From      *time.Time
+	To        *time.Time
+}
+
+// DogRecord compares a dog's deployments with its training over the same
+// period.
+type DogRecord struct {
+	DogID          int64
+	DogName        string
+	Missions       int
+	Finds          int
+	SearchMinutes  int
+	Rounds         int
+	RoundSuccesses int
+}
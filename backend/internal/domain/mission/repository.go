@@ -0,0 +1,16 @@
+package mission
+
+import "context"
+
+type Repository interface {
+	// Create and Update store the mission with its teams and fail with
+	// common.ErrValidation when a team references an unknown dog or handler.
+	Create(ctx context.Context, m *Mission) error
+	Get(ctx context.Context, id MissionID) (*Mission, error)
+	List(ctx context.Context, f Filter) ([]*Mission, error)
+	Update(ctx context.Context, m *Mission) error
+	Delete(ctx context.Context, id MissionID) error
+	// DogRecords counts deployments and training rounds per dog. Cancelled
+	// missions are left out.
+	DogRecords(ctx context.Context, f Filter) ([]*DogRecord, error)
+}
@@ -0,0 +1,74 @@
+package pairing
+
+import "time"
+
+type PairingID int64
+
+type Role string
+
+const (
+	RolePrimary   Role = "primary"
+	RoleSecondary Role = "secondary"
+)
+
+// Pairing links a handler (user) to a dog for a date range. EndDate is
+// inclusive; an open end means the pairing is ongoing.
+type Pairing struct {
+	ID        PairingID
+	DogID     int64
+	UserID    int64
+	Role      Role
+	StartDate time.Time
+	EndDate   *time.Time
+	Notes     *string
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+// ActiveOn reports whether the pairing covers the calendar day of t.
+func (p *Pairing) ActiveOn(t time.Time) bool {
+	y, m, d := t.UTC().Date()
+	day := time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
+	if day.Before(p.StartDate) {
+		return false
+	}
+	return p.EndDate == nil || !day.After(*p.EndDate)
+}
+
+// Overlaps reports whether both pairings share at least one day.
+func (p *Pairing) Overlaps(o *Pairing) bool {
+	if p.EndDate != nil && p.EndDate.Before(o.StartDate) {
+		return false
+	}
+	if o.EndDate != nil && o.EndDate.Before(p.StartDate) {
+		return false
+	}
+	return true
+}
+
+type Filter struct {
+	DogID  *int64
+	UserID *int64
+	// ActiveOn restricts the result to pairings covering that day.
+	ActiveOn *time.Time
+}
+
+// Handles reports whether userID is one of the handlers in pairings.
+func Handles(pairings []*Pairing, userID int64) bool {
+	for _, p := range pairings {
+		if p.UserID == userID {
+			return true
+		}
+	}
+	return false
+}
+
+// Primary returns the primary pairing among pairings, or nil.
+func Primary(pairings []*Pairing) *Pairing {
+	for _, p := range pairings {
+		if p.Role == RolePrimary {
+			return p
+		}
+	}
+	return nil
+}
@@ -0,0 +1,11 @@
+package pairing
+
+import "context"
+
+type Repository interface {
+	Create(ctx context.Context, p *Pairing) error
+	Get(ctx context.Context, id PairingID) (*Pairing, error)
+	List(ctx context.Context, f Filter) ([]*Pairing, error)
+	Update(ctx context.Context, p *Pairing) error
+	Delete(ctx context.Context, id PairingID) error
+}
@@ -0,0 +1,116 @@
+package protocol
+
+import "time"
+
+type ProtocolID int64
+
+type TaskID int64
+
+type RunID int64
+
+// DefaultPassRatio is the share of the protocol's points a run needs when
+// no pass ratio is set.
+const DefaultPassRatio = 0.7
+
+// Protocol is the sheet a mock exam is judged by: ordered tasks and the
+// share of their points needed to pass.
+type Protocol struct {
+	ID          ProtocolID
+	Name        string
+	Description *string
+	PassRatio   float64
+	Tasks       []Task
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+}
+
+// Task is one exercise of the exam. A round for the task must match its
+// behavior and exercise when set. A required task is a knockout: failing it
+// fails the exam whatever the points.
+type Task struct {
+	ID          TaskID
+	ProtocolID  ProtocolID
+	Position    int
+	Name        string
+	Description *string
+	BehaviorID  *int64
+	ExerciseID  *int64
+	TimeLimitS  *int
+	MaxPoints   int
+	MinPoints   int
+	Required    bool
+}
+
+// Task returns the protocol's task with the given ID, or nil.
+func (p *Protocol) Task(id TaskID) *Task {
+	for i := range p.Tasks {
+		if p.Tasks[i].ID == id {
+			return &p.Tasks[i]
+		}
+	}
+	return nil
+}
+
+// SameScoring reports whether q judges runs like p: the same pass ratio and
+// the same set of tasks with the same rounds, limits and points. Names,
+// descriptions and the order of tasks may differ.
+func (p *Protocol) SameScoring(q *Protocol) bool {
+	if p.PassRatio != q.PassRatio || len(p.Tasks) != len(q.Tasks) {
+		return false
+	}
+	byID := make(map[TaskID]Task, len(p.Tasks))
+	for _, t := range p.Tasks {
+		byID[t.ID] = t
+	}
+	for _, t := range q.Tasks {
+		c, ok := byID[t.ID]
+		if !ok || !sameID(c.BehaviorID, t.BehaviorID) || !sameID(c.ExerciseID, t.ExerciseID) ||
+			!sameLimit(c.TimeLimitS, t.TimeLimitS) || c.MaxPoints != t.MaxPoints || c.MinPoints != t.MinPoints ||
+			c.Required != t.Required {
+			return false
+		}
+		// each task matches once, so duplicates in q cannot stand in for
+		// one of p's tasks
+		delete(byID, t.ID)
+	}
+	return true
+}
+
+func sameID(a, b *int64) bool {
+	return (a == nil && b == nil) || (a != nil && b != nil && *a == *b)
+}
+
+func sameLimit(a, b *int) bool {
+	return (a == nil && b == nil) || (a != nil && b != nil && *a == *b)
+}
+
+// Run is a dog taking a protocol in a mock-exam session. Passed and
+// TotalPoints are set when the run is completed.
+type Run struct {
+	ID          RunID
+	ProtocolID  ProtocolID
+	SessionID   int64
+	DogID       int64
+	DogName     string
+	Judge       *string
+	StartedAt   time.Time
+	CreatedAt   time.Time
+	CompletedAt *time.Time
+	Passed      *bool
+	TotalPoints *float64
+}
+
+type RunFilter struct {
+	DogID      *int64
+	ProtocolID *ProtocolID
+}
+
+// TaskRound is the round judged for a task of a run.
+type TaskRound struct {
+	TaskID    TaskID
+	RoundID   int64
+	Outcome   string
+	Score     *int
+	StartedAt *time.Time
+	EndedAt   *time.Time
+}
@@ -0,0 +1,42 @@
+package protocol
+
+import "testing"
+
+func TestSameScoring(t *testing.T) {
+	behavior, other := int64(1), int64(2)
+	limit := 60
+	base := func() *Protocol {
+		return &Protocol{Name: "BH", PassRatio: 0.7, Tasks: []Task{
+			{ID: 1, Name: "Search", BehaviorID: &behavior, TimeLimitS: &limit, MaxPoints: 30, MinPoints: 20, Required: true},
+			{ID: 2, Name: "Obedience", MaxPoints: 10},
+		}}
+	}
+	tests := []struct {
+		name   string
+		change func(*Protocol)
+		want   bool
+	}{
+		{"unchanged", func(*Protocol) {}, true},
+		{"renamed", func(q *Protocol) { q.Name = "IPO"; q.Tasks[0].Name = "Area search" }, true},
+		{"reordered", func(q *Protocol) { q.Tasks[0], q.Tasks[1] = q.Tasks[1], q.Tasks[0] }, true},
+		{"pass ratio", func(q *Protocol) { q.PassRatio = 0.8 }, false},
+		{"task removed", func(q *Protocol) { q.Tasks = q.Tasks[:1] }, false},
+		{"task replaced", func(q *Protocol) { q.Tasks[1].ID = 3 }, false},
+		{"task duplicated", func(q *Protocol) { q.Tasks[1] = q.Tasks[0] }, false},
+		{"behavior changed", func(q *Protocol) { q.Tasks[0].BehaviorID = &other }, false},
+		{"exercise set", func(q *Protocol) { q.Tasks[1].ExerciseID = &other }, false},
+		{"limit dropped", func(q *Protocol) { q.Tasks[0].TimeLimitS = nil }, false},
+		{"max points", func(q *Protocol) { q.Tasks[0].MaxPoints = 40 }, false},
+		{"min points", func(q *Protocol) { q.Tasks[1].MinPoints = 5 }, false},
+		{"required", func(q *Protocol) { q.Tasks[1].Required = true }, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			q := base()
+			tt.change(q)
+			if got := base().SameScoring(q); got != tt.want {
+				t.Errorf("SameScoring = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
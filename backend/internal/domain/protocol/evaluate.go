@@ -0,0 +1,126 @@
+package protocol
+
+import "time"
+
+type TaskState string
+
+const (
+	TaskPending TaskState = "pending"
+	TaskPassed  TaskState = "passed"
+	TaskFailed  TaskState = "failed"
+)
+
+// TaskResult is how a run did on one task.
+type TaskResult struct {
+	Task      Task
+	RoundID   *int64
+	State     TaskState
+	Points    float64
+	DurationS *int
+	Overtime  bool
+}
+
+// Result is the evaluation of a run against its protocol.
+type Result struct {
+	Tasks     []TaskResult
+	Points    float64
+	MaxPoints float64
+	// Ratio is Points over MaxPoints.
+	Ratio    float64
+	Complete bool
+	Passed   bool
+}
+
+// Evaluate scores the rounds of a run against the protocol. A task earns
+// the round score (0-10) scaled to its points, or without a score all, half
+// or none of them for a success, partial or fail. A failed round or one over
+// the time limit earns nothing. A task passes with at least its minimum
+// points; the run passes when every task was attempted, every required task
+// passed and the points reach the pass ratio.
+func Evaluate(p *Protocol, rounds []TaskRound) Result {
+	byTask := map[TaskID]TaskRound{}
+	for _, r := range rounds {
+		byTask[r.TaskID] = r
+	}
+	res := Result{Complete: true, Passed: true}
+	for _, t := range p.Tasks {
+		tr := TaskResult{Task: t, State: TaskPending}
+		res.MaxPoints += float64(t.MaxPoints)
+		r, ok := byTask[t.ID]
+		if !ok {
+			res.Complete, res.Passed = false, false
+			res.Tasks = append(res.Tasks, tr)
+			continue
+		}
+		id := r.RoundID
+		tr.RoundID = &id
+		if r.StartedAt != nil && r.EndedAt != nil {
+			d := int(r.EndedAt.Sub(*r.StartedAt) / time.Second)
+			tr.DurationS = &d
+			tr.Overtime = t.TimeLimitS != nil && d > *t.TimeLimitS
+		}
+		if r.Outcome != "fail" && !tr.Overtime {
+			tr.Points = points(t, r)
+		}
+		tr.State = TaskFailed
+		if r.Outcome != "fail" && !tr.Overtime && tr.Points >= float64(t.MinPoints) {
+			tr.State = TaskPassed
+		}
+		if t.Required && tr.State != TaskPassed {
+			res.Passed = false
+		}
+		res.Points += tr.Points
+		res.Tasks = append(res.Tasks, tr)
+	}
+	if res.MaxPoints > 0 {
+		res.Ratio = res.Points / res.MaxPoints
+	}
+	if res.Ratio < p.PassRatio {
+		res.Passed = false
+	}
+	return res
+}
+
+func points(t Task, r TaskRound) float64 {
+	full := float64(t.MaxPoints)
+	if r.Score != nil {
+		return full * float64(*r.Score) / 10
+	}
+	if r.Outcome == "partial" {
+		return full / 2
+	}
+	return full
+}
+
+// Stored rebuilds the result of a completed run from the task results stored
+// at completion, so later changes to the rounds do not alter it. Points and
+// verdict are the run's own.
+func Stored(p *Protocol, run *Run, tasks []TaskResult) Result {
+	byTask := map[TaskID]TaskResult{}
+	for _, tr := range tasks {
+		byTask[tr.Task.ID] = tr
+	}
+	res := Result{Complete: true}
+	for _, t := range p.Tasks {
+		tr, ok := byTask[t.ID]
+		if !ok {
+			tr = TaskResult{State: TaskPending}
+		}
+		tr.Task = t
+		if tr.State == TaskPending {
+			res.Complete = false
+		}
+		res.MaxPoints += float64(t.MaxPoints)
+		res.Tasks = append(res.Tasks, tr)
+	}
+	if run.TotalPoints != nil {
+		res.Points = *run.TotalPoints
+	}
+	if run.Passed != nil {
+		res.Passed = *run.Passed
+	}
+	if res.MaxPoints > 0 {
+		res.Ratio = res.Points / res.MaxPoints
+	}
+	return res
+}
@@ -0,0 +1,128 @@
+package protocol
+
+import (
+	"testing"
+	"time"
+)
+
+func TestEvaluate(t *testing.T) {
+	limit := 60
+	p := &Protocol{PassRatio: 0.7, Tasks: []Task{
+		{ID: 1, MaxPoints: 10, MinPoints: 5, Required: true, TimeLimitS: &limit},
+		{ID: 2, MaxPoints: 10, MinPoints: 0},
+	}}
+	start := time.Date(2026, 5, 1, 10, 0, 0, 0, time.UTC)
+	after := func(s int) *time.Time { e := start.Add(time.Duration(s) * time.Second); return &e }
+	score := func(s int) *int { return &s }
+	tests := []struct {
+		name     string
+		rounds   []TaskRound
+		points   float64
+		states   []TaskState
+		complete bool
+		passed   bool
+	}{
+		{
+			name:   "nothing run",
+			states: []TaskState{TaskPending, TaskPending},
+		},
+		{
+			name:   "incomplete fails",
+			rounds: []TaskRound{{TaskID: 1, Outcome: "success"}},
+			points: 10, states: []TaskState{TaskPassed, TaskPending},
+		},
+		{
+			name:   "all success",
+			rounds: []TaskRound{{TaskID: 1, Outcome: "success"}, {TaskID: 2, Outcome: "success"}},
+			points: 20, states: []TaskState{TaskPassed, TaskPassed}, complete: true, passed: true,
+		},
+		{
+			name:   "partial earns half",
+			rounds: []TaskRound{{TaskID: 1, Outcome: "success"}, {TaskID: 2, Outcome: "partial"}},
+			points: 15, states: []TaskState{TaskPassed, TaskPassed}, complete: true, passed: true,
+		},
+		{
+			name:   "score scales points",
+			rounds: []TaskRound{{TaskID: 1, Outcome: "success", Score: score(8)}, {TaskID: 2, Outcome: "partial", Score: score(4)}},
+			points: 12, states: []TaskState{TaskPassed, TaskPassed}, complete: true,
+		},
+		{
+			name:   "required task under minimum",
+			rounds: []TaskRound{{TaskID: 1, Outcome: "success", Score: score(4)}, {TaskID: 2, Outcome: "success"}},
+			points: 14, states: []TaskState{TaskFailed, TaskPassed}, complete: true,
+		},
+		{
+			name:   "fail earns nothing",
+			rounds: []TaskRound{{TaskID: 1, Outcome: "success"}, {TaskID: 2, Outcome: "fail", Score: score(9)}},
+			points: 10, states: []TaskState{TaskPassed, TaskFailed}, complete: true,
+		},
+		{
+			name:   "overtime earns nothing",
+			rounds: []TaskRound{{TaskID: 1, Outcome: "success", StartedAt: &start, EndedAt: after(61)}, {TaskID: 2, Outcome: "success"}},
+			points: 10, states: []TaskState{TaskFailed, TaskPassed}, complete: true,
+		},
+		{
+			name:   "within the limit",
+			rounds: []TaskRound{{TaskID: 1, Outcome: "success", StartedAt: &start, EndedAt: after(60)}, {TaskID: 2, Outcome: "success"}},
+			points: 20, states: []TaskState{TaskPassed, TaskPassed}, complete: true, passed: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			res := Evaluate(p, tt.rounds)
+			if res.Points != tt.points || res.MaxPoints != 20 || res.Complete != tt.complete || res.Passed != tt.passed {
+				t.Errorf("Evaluate = %v/%v complete %v passed %v, want %v/20 complete %v passed %v",
+					res.Points, res.MaxPoints, res.Complete, res.Passed, tt.points, tt.complete, tt.passed)
+			}
+			if res.Ratio != tt.points/20 {
+				t.Errorf("ratio = %v, want %v", res.Ratio, tt.points/20)
+			}
+			for i, tr := range res.Tasks {
+				if tr.State != tt.states[i] {
+					t.Errorf("task %d state = %s, want %s", tr.Task.ID, tr.State, tt.states[i])
+				}
+			}
+		})
+	}
+}
+
+func TestStored(t *testing.T) {
+	p := &Protocol{PassRatio: 0.5, Tasks: []Task{
+		{ID: 1, Name: "Search", MaxPoints: 10},
+		{ID: 2, Name: "Obedience", MaxPoints: 10},
+	}}
+	round := int64(7)
+	points, passed := 8.0, false
+	run := &Run{TotalPoints: &points, Passed: &passed}
+	tests := []struct {
+		name     string
+		tasks    []TaskResult
+		states   []TaskState
+		complete bool
+	}{
+		{
+			name:   "all tasks stored",
+			tasks:  []TaskResult{{Task: Task{ID: 2}, State: TaskFailed}, {Task: Task{ID: 1}, RoundID: &round, State: TaskPassed, Points: 8}},
+			states: []TaskState{TaskPassed, TaskFailed}, complete: true,
+		},
+		{
+			name:   "missing task is pending",
+			tasks:  []TaskResult{{Task: Task{ID: 1}, RoundID: &round, State: TaskPassed, Points: 8}},
+			states: []TaskState{TaskPassed, TaskPending},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			res := Stored(p, run, tt.tasks)
+			if res.Points != 8 || res.MaxPoints != 20 || res.Ratio != 0.4 || res.Passed || res.Complete != tt.complete {
+				t.Errorf("Stored = %v/%v ratio %v passed %v complete %v, want 8/20 ratio 0.4 failed complete %v",
+					res.Points, res.MaxPoints, res.Ratio, res.Passed, res.Complete, tt.complete)
+			}
+			for i, tr := range res.Tasks {
+				if tr.Task.ID != p.Tasks[i].ID || tr.Task.Name != p.Tasks[i].Name || tr.State != tt.states[i] {
+					t.Errorf("task %d = %d %q %s, want %d %q %s", i, tr.Task.ID, tr.Task.Name, tr.State, p.Tasks[i].ID, p.Tasks[i].Name, tt.states[i])
+				}
+			}
+		})
+	}
+}
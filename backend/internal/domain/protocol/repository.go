@@ -0,0 +1,29 @@
+package protocol
+
+import "context"
+
+type Repository interface {
+	Create(ctx context.Context, p *Protocol) error
+	Get(ctx context.Context, id ProtocolID) (*Protocol, error)
+	List(ctx context.Context) ([]*Protocol, error)
+	// Update replaces the protocol and its tasks; once the protocol has
+	// runs it fails with common.ErrConflict unless the scoring is unchanged.
+	Update(ctx context.Context, p *Protocol) error
+	Delete(ctx context.Context, id ProtocolID) error
+
+	// StartRun opens a mock-exam session with the dog and records the run.
+	StartRun(ctx context.Context, run *Run) error
+	GetRun(ctx context.Context, id RunID) (*Run, error)
+	ListRuns(ctx context.Context, f RunFilter) ([]*Run, error)
+	// SetTaskRound records the round judged for a task, replacing any
+	// earlier attempt.
+	SetTaskRound(ctx context.Context, runID RunID, taskID TaskID, roundID int64) error
+	DeleteTaskRound(ctx context.Context, runID RunID, taskID TaskID) error
+	TaskRounds(ctx context.Context, runID RunID) ([]TaskRound, error)
+	// CompleteRun stores the result of the run with its task results and
+	// closes its session.
+	CompleteRun(ctx context.Context, run *Run, tasks []TaskResult) error
+	// TaskResults returns the task results stored when the run was
+	// completed; only the ID of their Task is set.
+	TaskResults(ctx context.Context, runID RunID) ([]TaskResult, error)
+}
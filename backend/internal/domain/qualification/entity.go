@@ -0,0 +1,102 @@
+package qualification
+
+import "time"
+
+type QualificationID int64
+
+type Result string
+
+const (
+	ResultPassed Result = "passed"
+	ResultFailed Result = "failed"
+)
+
+type Status string
+
+const (
+	StatusValid    Status = "valid"
+	StatusExpiring Status = "expiring"
+	StatusExpired  Status = "expired"
+	StatusFailed   Status = "failed"
+)
+
+// Qualification is an exam record for a dog, a handler, or a dog–handler
+// team when both are set.
+type Qualification struct {
+	ID                QualificationID
+	DogID             *int64
+	HandlerID         *int64
+	ExamType          string
+	ExamID            *int64
+	ExamDate          time.Time
+	Examiner          *string
+	Result            Result
+	CertificateNumber *string
+	ValidUntil        *time.Time
+	RemindDaysBefore  int
+	// DisciplineID is the discipline the qualification certifies, if any.
+	DisciplineID *int64
+	Notes        *string
+	CreatedAt    time.Time
+	UpdatedAt    time.Time
+}
+
+// Status reports whether the qualification is in force at the given time.
+// A passed qualification without expiry date never expires.
+func (q *Qualification) Status(now time.Time) Status {
+	if q.Result != ResultPassed {
+		return StatusFailed
+	}
+	if q.ValidUntil == nil {
+		return StatusValid
+	}
+	end := q.ValidUntil.AddDate(0, 0, 1) // valid through the whole last day
+	switch {
+	case !now.Before(end):
+		return StatusExpired
+	case !now.Before(end.AddDate(0, 0, -q.RemindDaysBefore)):
+		return StatusExpiring
+	}
+	return StatusValid
+}
+
+// IsTeam reports whether the record certifies a dog–handler team.
+func (q *Qualification) IsTeam() bool {
+	return q.DogID != nil && q.HandlerID != nil
+}
+
+type Attachment struct {
+	ID              int64
+	QualificationID QualificationID
+	Filename        string
+	ContentType     string
+	Size            int64
+	Data            []byte
+	UploadedAt      time.Time
+}
+
+// AttachmentTypes are the content types certificate scans may be stored
+// and served as.
+var AttachmentTypes = map[string]bool{
+	"application/pdf": true,
+	"image/jpeg":      true,
+	"image/png":       true,
+	"image/gif":       true,
+	"image/webp":      true,
+}
+
+type Filter struct {
+	DogID        *int64
+	HandlerID    *int64
+	ExamType     *string
+	DisciplineID *int64
+	TeamsOnly    bool
+}
+
+// Team is a dog–handler pair that appears on at least one team record.
+type Team struct {
+	DogID        int64
+	DogName      string
+	HandlerID    int64
+	HandlerEmail string
+}
@@ -0,0 +1,18 @@
+package qualification
+
+import "context"
+
+type Repository interface {
+	Create(ctx context.Context, q *Qualification) error
+	Get(ctx context.Context, id QualificationID) (*Qualification, error)
+	List(ctx context.Context, f Filter) ([]*Qualification, error)
+	Update(ctx context.Context, q *Qualification) error
+	Delete(ctx context.Context, id QualificationID) error
+	ListTeams(ctx context.Context) ([]Team, error)
+
+	AddAttachment(ctx context.Context, a *Attachment) error
+	// ListAttachments returns attachment metadata without the file contents.
+	ListAttachments(ctx context.Context, id QualificationID) ([]*Attachment, error)
+	GetAttachment(ctx context.Context, id QualificationID, attachmentID int64) (*Attachment, error)
+	DeleteAttachment(ctx context.Context, id QualificationID, attachmentID int64) error
+}
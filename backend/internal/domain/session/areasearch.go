@@ -0,0 +1,104 @@
+package session
+
+import (
+	"time"
+
+	"github.com/tnosaj/sar-training/backend/internal/domain/location"
+)
+
+// AreaSearch holds the details of an area-search round: the assigned
+// sector, the hidden persons placed in it and the false alerts the dog
+// gave.
+type AreaSearch struct {
+	SectorName *string
+	// Sector is the outer ring of the search area, without repeating the
+	// first point. It may be empty when only a name was recorded.
+	Sector      []location.Point
+	FalseAlerts int
+	Persons     []HiddenPerson
+}
+
+// HiddenPerson is a figurant placed in the sector. TimeToFindS counts from
+// the start of the round.
+type HiddenPerson struct {
+	ID          int64
+	Label       *string
+	Position    location.Point
+	Found       bool
+	TimeToFindS *int
+	Notes       *string
+}
+
+func (a *AreaSearch) Valid() bool {
+	if a.FalseAlerts < 0 || (len(a.Sector) > 0 && len(a.Sector) < 3) {
+		return false
+	}
+	for _, p := range a.Sector {
+		if !p.Valid() {
+			return false
+		}
+	}
+	for _, p := range a.Persons {
+		if !p.Position.Valid() {
+			return false
+		}
+		if p.TimeToFindS != nil && (!p.Found || *p.TimeToFindS < 0) {
+			return false
+		}
+	}
+	return true
+}
+
+func (a *AreaSearch) Found() int {
+	n := 0
+	for _, p := range a.Persons {
+		if p.Found {
+			n++
+		}
+	}
+	return n
+}
+
+// ProbabilityOfDetection is the share of hidden persons found, or nil when
+// none were placed.
+func (a *AreaSearch) ProbabilityOfDetection() *float64 {
+	if len(a.Persons) == 0 {
+		return nil
+	}
+	v := float64(a.Found()) / float64(len(a.Persons))
+	return &v
+}
+
+// MeanTimeToFindS averages the times of the persons found with a time.
+func (a *AreaSearch) MeanTimeToFindS() *float64 {
+	var sum, n int
+	for _, p := range a.Persons {
+		if p.TimeToFindS != nil {
+			sum += *p.TimeToFindS
+			n++
+		}
+	}
+	if n == 0 {
+		return nil
+	}
+	v := float64(sum) / float64(n)
+	return &v
+}
+
+// AreaSearchRound is an area search with the round it belongs to, for
+// analytics across rounds.
+type AreaSearchRound struct {
+	RoundID    int64
+	SessionID  int64
+	DogID      int64
+	LocationID *int64
+	StartedAt  string
+	AreaSearch
+}
+
+type AreaSearchFilter struct {
+	DogID      *int64
+	LocationID *int64
+	From       *time.Time
+	To         *time.Time
+}
@@ -0,0 +1,144 @@
+package session
+
+import (
+	"fmt"
+	"time"
+)
+
+type Precipitation string
+
+const (
+	PrecipitationNone     Precipitation = "none"
+	PrecipitationLight    Precipitation = "light"
+	PrecipitationModerate Precipitation = "moderate"
+	PrecipitationHeavy    Precipitation = "heavy"
+)
+
+func (p Precipitation) Valid() bool {
+	switch p {
+	case PrecipitationNone, PrecipitationLight, PrecipitationModerate, PrecipitationHeavy:
+		return true
+	}
+	return false
+}
+
+// Conditions describe weather and terrain. On a round every field is
+// optional and falls back to the session's value.
+type Conditions struct {
+	TemperatureC     *float64
+	HumidityPct      *float64
+	WindSpeedKmh     *float64
+	WindDirectionDeg *int
+	Precipitation    *Precipitation
+	Terrain          *string
+}
+
+func (c Conditions) Valid() bool {
+	if c.HumidityPct != nil && (*c.HumidityPct < 0 || *c.HumidityPct > 100) {
+		return false
+	}
+	if c.WindSpeedKmh != nil && *c.WindSpeedKmh < 0 {
+		return false
+	}
+	if c.WindDirectionDeg != nil && (*c.WindDirectionDeg < 0 || *c.WindDirectionDeg > 359) {
+		return false
+	}
+	return c.Precipitation == nil || c.Precipitation.Valid()
+}
+
+// Factor is a condition outcomes can be grouped by.
+type Factor string
+
+const (
+	FactorTemperature   Factor = "temperature_c"
+	FactorHumidity      Factor = "humidity_pct"
+	FactorWindSpeed     Factor = "wind_speed_kmh"
+	FactorPrecipitation Factor = "precipitation"
+	FactorTerrain       Factor = "terrain"
+)
+
+// DefaultCuts are the band boundaries for numeric factors. Each band is
+// [lower cut, upper cut).
+var DefaultCuts = map[Factor][]float64{
+	FactorTemperature: {0, 10, 20, 30},
+	FactorHumidity:    {40, 60, 80},
+	FactorWindSpeed:   {5, 15, 30},
+}
+
+func (f Factor) Valid() bool {
+	_, numeric := DefaultCuts[f]
+	return numeric || f == FactorPrecipitation || f == FactorTerrain
+}
+
+// UnknownBand collects rounds without a value for the factor.
+const UnknownBand = "unknown"
+
+// BandOf returns the band label of the effective condition for factor f.
+func (c Conditions) BandOf(f Factor, cuts []float64) string {
+	var v *float64
+	switch f {
+	case FactorPrecipitation:
+		if c.Precipitation == nil {
+			return UnknownBand
+		}
+		return string(*c.Precipitation)
+	case FactorTerrain:
+		if c.Terrain == nil || *c.Terrain == "" {
+			return UnknownBand
+		}
+		return *c.Terrain
+	case FactorTemperature:
+		v = c.TemperatureC
+	case FactorHumidity:
+		v = c.HumidityPct
+	case FactorWindSpeed:
+		v = c.WindSpeedKmh
+	}
+	if v == nil {
+		return UnknownBand
+	}
+	return BandLabels(cuts)[bandIndex(cuts, *v)]
+}
+
+// BandLabels names the len(cuts)+1 bands defined by cuts, e.g. "<5",
+// "5-15", ">=30".
+func BandLabels(cuts []float64) []string {
+	out := make([]string, 0, len(cuts)+1)
+	for i := 0; i <= len(cuts); i++ {
+		switch {
+		case i == 0:
+			out = append(out, fmt.Sprintf("<%g", cuts[0]))
+		case i == len(cuts):
+			out = append(out, fmt.Sprintf(">=%g", cuts[i-1]))
+		default:
+			out = append(out, fmt.Sprintf("%g-%g", cuts[i-1], cuts[i]))
+		}
+	}
+	return out
+}
+
+func bandIndex(cuts []float64, v float64) int {
+	for i, c := range cuts {
+		if v < c {
+			return i
+		}
+	}
+	return len(cuts)
+}
+
+// ConditionRound is a round outcome with the conditions in effect, round
+// values taking precedence over the session's.
+type ConditionRound struct {
+	DogID      int64
+	BehaviorID int64
+	Outcome    string
+	Score      *int
+	Conditions Conditions
+}
+
+type ConditionFilter struct {
+	DogID      *int64
+	BehaviorID *int64
+	From       *time.Time
+	To         *time.Time
+}
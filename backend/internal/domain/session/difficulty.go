@@ -0,0 +1,34 @@
+package session
+
+import "time"
+
+// Difficulty is the level of its exercise a round was trained at and the
+// parameter values used, keyed by parameter kind.
+type Difficulty struct {
+	DifficultyLevel *int
+	Parameters      map[string]float64
+}
+
+// Recorded reports whether the round has any difficulty recorded.
+func (d Difficulty) Recorded() bool {
+	return d.DifficultyLevel != nil || len(d.Parameters) > 0
+}
+
+// DifficultyRound is the difficulty of a round with when it ran and how it
+// went.
+type DifficultyRound struct {
+	RoundID      int64
+	DogID        int64
+	ExerciseID   int64
+	ExerciseName string
+	Outcome      string
+	At           time.Time
+	Difficulty
+}
+
+type DifficultyFilter struct {
+	DogID      int64
+	ExerciseID *int64
+	From       *time.Time
+	To         *time.Time
+}
@@ -2,26 +2,47 @@ package session
 
 type SessionID int64
 
+// Kind tells regular training sessions from those opened for a mock exam.
+type Kind string
+
+const (
+	KindTraining Kind = "training"
+	KindMockExam Kind = "mock_exam"
+)
+
 type Session struct {
-	ID        SessionID
-	StartedAt string
-	EndedAt   *string
-	Location  *string
-	Notes     *string
+	ID         SessionID
+	StartedAt  string
+	EndedAt    *string
+	Location   *string
+	Notes      *string
+	LocationID *int64
+	Kind       Kind
+	Conditions Conditions
 }
 
 type Round struct {
-	ID                   int64
-	SessionID            int64
-	RoundNumber          int64
-	DogID                int64
-	ExerciseID           int64
-	PlannedBehaviorID    int64
-	ExhibitedBehaviorID  *int64
-	ExhibitedFreeText    *string
-	Outcome              string
-	Score                *int
-	Notes                *string
-	StartedAt            *string
-	EndedAt              *string
+	ID                  int64
+	SessionID           int64
+	RoundNumber         int64
+	DogID               int64
+	ExerciseID          int64
+	PlannedBehaviorID   int64
+	ExhibitedBehaviorID *int64
+	ExhibitedFreeText   *string
+	Outcome             string
+	Score               *int
+	Notes               *string
+	Timing
+	Reinforcement
+	// WeightedScore is the rubric total Score was rounded from.
+	WeightedScore    *float64
+	CriterionScores  []CriterionScore
+	HandlerID        *int64
+	IndicationResult *IndicationResult
+	Conditions       Conditions
+	AreaSearch       *AreaSearch
+	Trailing         *Trailing
+	Rubble           *Rubble
+	Difficulty
 }
@@ -0,0 +1,38 @@
+package session
+
+import "time"
+
+// IndicationResult records how the dog gave its trained final response on
+// a round: on time at the subject, late, at a spot without a subject, or
+// not at all.
+type IndicationResult string
+
+const (
+	IndicationCorrect IndicationResult = "correct"
+	IndicationLate    IndicationResult = "late"
+	IndicationFalse   IndicationResult = "false"
+	IndicationMissing IndicationResult = "missing"
+)
+
+func (r IndicationResult) Valid() bool {
+	switch r {
+	case IndicationCorrect, IndicationLate, IndicationFalse, IndicationMissing:
+		return true
+	}
+	return false
+}
+
+// IndicationCount is the number of rounds of a dog with a given result.
+type IndicationCount struct {
+	DogID      int64
+	DogName    string
+	Indication *string
+	Result     IndicationResult
+	Rounds     int
+}
+
+type IndicationFilter struct {
+	DogID *int64
+	From  *time.Time
+	To    *time.Time
+}
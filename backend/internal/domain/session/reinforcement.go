@@ -0,0 +1,207 @@
+package session
+
+import "time"
+
+// Reward is what the dog was paid with for the round.
+type Reward string
+
+const (
+	RewardFood   Reward = "food"
+	RewardToy    Reward = "toy"
+	RewardPlay   Reward = "play"
+	RewardPraise Reward = "praise"
+	RewardNone   Reward = "none"
+)
+
+// Schedule is how often correct responses were rewarded: every time, after
+// a fixed or varying number of responses, or with an exceptional jackpot.
+type Schedule string
+
+const (
+	ScheduleContinuous    Schedule = "continuous"
+	ScheduleFixedRatio    Schedule = "fixed_ratio"
+	ScheduleVariableRatio Schedule = "variable_ratio"
+	ScheduleJackpot       Schedule = "jackpot"
+)
+
+// Distraction is a kind of distraction present during a round.
+type Distraction string
+
+const (
+	DistractionOtherDogs Distraction = "other_dogs"
+	DistractionGameScent Distraction = "game_scent"
+	DistractionTraffic   Distraction = "traffic"
+	DistractionPeople    Distraction = "people"
+	DistractionNoise     Distraction = "noise"
+	DistractionFood      Distraction = "food"
+	DistractionLivestock Distraction = "livestock"
+)
+
+// DistractionLevel rates how strongly the round was distracted overall.
+type DistractionLevel string
+
+const (
+	DistractionNone   DistractionLevel = "none"
+	DistractionLow    DistractionLevel = "low"
+	DistractionMedium DistractionLevel = "medium"
+	DistractionHigh   DistractionLevel = "high"
+)
+
+// Reinforcement records the reward and distractions of a round. All fields
+// are optional; a round rated free of distractions cannot list any. The
+// values themselves are checked against the ReinforcementCatalog.
+type Reinforcement struct {
+	Reward           *Reward
+	Schedule         *Schedule
+	Distractions     []Distraction
+	DistractionLevel *DistractionLevel
+}
+
+func (r *Reinforcement) Valid() bool {
+	if r.Reward != nil && *r.Reward == RewardNone && r.Schedule != nil {
+		return false
+	}
+	if len(r.Distractions) > 0 && r.DistractionLevel != nil && *r.DistractionLevel == DistractionNone {
+		return false
+	}
+	seen := map[Distraction]bool{}
+	for _, d := range r.Distractions {
+		if seen[d] {
+			return false
+		}
+		seen[d] = true
+	}
+	return true
+}
+
+// ReinforcementFactor is a reinforcement detail outcomes can be grouped by.
+type ReinforcementFactor string
+
+const (
+	ReinforcementFactorReward           ReinforcementFactor = "reward"
+	ReinforcementFactorSchedule         ReinforcementFactor = "schedule"
+	ReinforcementFactorDistraction      ReinforcementFactor = "distraction"
+	ReinforcementFactorDistractionLevel ReinforcementFactor = "distraction_level"
+)
+
+func (f ReinforcementFactor) Valid() bool {
+	switch f {
+	case ReinforcementFactorReward, ReinforcementFactorSchedule, ReinforcementFactorDistraction, ReinforcementFactorDistractionLevel:
+		return true
+	}
+	return false
+}
+
+// BandsOf returns the band labels of the round for factor f. A round with
+// several distractions falls into each of their bands; one rated free of
+// distractions falls into "none".
+func (r *Reinforcement) BandsOf(f ReinforcementFactor) []string {
+	switch f {
+	case ReinforcementFactorReward:
+		if r.Reward != nil {
+			return []string{string(*r.Reward)}
+		}
+	case ReinforcementFactorSchedule:
+		if r.Schedule != nil {
+			return []string{string(*r.Schedule)}
+		}
+	case ReinforcementFactorDistractionLevel:
+		if r.DistractionLevel != nil {
+			return []string{string(*r.DistractionLevel)}
+		}
+	case ReinforcementFactorDistraction:
+		out := make([]string, 0, len(r.Distractions))
+		for _, d := range r.Distractions {
+			out = append(out, string(d))
+		}
+		if len(out) > 0 {
+			return out
+		}
+		if r.DistractionLevel != nil && *r.DistractionLevel == DistractionNone {
+			return []string{string(DistractionNone)}
+		}
+	}
+	return []string{UnknownBand}
+}
+
+// CatalogEntry is one value of a reinforcement catalog with the name shown
+// for it.
+type CatalogEntry struct {
+	Code string
+	Name string
+}
+
+// ReinforcementCatalog lists the rewards, schedules, distractions and
+// distraction levels rounds may record, each in display order.
+type ReinforcementCatalog struct {
+	Rewards           []CatalogEntry
+	Schedules         []CatalogEntry
+	Distractions      []CatalogEntry
+	DistractionLevels []CatalogEntry
+}
+
+// Allows reports whether every value r records is in the catalog.
+func (c *ReinforcementCatalog) Allows(r *Reinforcement) bool {
+	if (r.Reward != nil && !hasCode(c.Rewards, string(*r.Reward))) ||
+		(r.Schedule != nil && !hasCode(c.Schedules, string(*r.Schedule))) ||
+		(r.DistractionLevel != nil && !hasCode(c.DistractionLevels, string(*r.DistractionLevel))) {
+		return false
+	}
+	for _, d := range r.Distractions {
+		if !hasCode(c.Distractions, string(d)) {
+			return false
+		}
+	}
+	return true
+}
+
+// Labels returns the catalog values of factor f in display order. The
+// distraction factor ends with the band of rounds rated free of them.
+func (c *ReinforcementCatalog) Labels(f ReinforcementFactor) []string {
+	var entries []CatalogEntry
+	switch f {
+	case ReinforcementFactorReward:
+		entries = c.Rewards
+	case ReinforcementFactorSchedule:
+		entries = c.Schedules
+	case ReinforcementFactorDistraction:
+		entries = c.Distractions
+	case ReinforcementFactorDistractionLevel:
+		entries = c.DistractionLevels
+	}
+	out := make([]string, 0, len(entries)+1)
+	for _, e := range entries {
+		out = append(out, e.Code)
+	}
+	if f == ReinforcementFactorDistraction {
+		out = append(out, string(DistractionNone))
+	}
+	return out
+}
+
+func hasCode(entries []CatalogEntry, code string) bool {
+	for _, e := range entries {
+		if e.Code == code {
+			return true
+		}
+	}
+	return false
+}
+
+// ReinforcementRound is a round outcome with its reinforcement details, for
+// analytics across rounds.
+type ReinforcementRound struct {
+	RoundID    int64
+	DogID      int64
+	BehaviorID int64
+	Outcome    string
+	Score      *int
+	Reinforcement
+}
+
+type ReinforcementFilter struct {
+	DogID      *int64
+	BehaviorID *int64
+	From       *time.Time
+	To         *time.Time
+}
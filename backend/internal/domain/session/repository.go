@@ -7,6 +7,7 @@ type Repository interface {
 	UpdateSession(ctx context.Context, s *Session) error
 	CloseSession(ctx context.Context, s *Session) error
 	ListSessions(ctx context.Context) ([]*Session, error)
+	GetSession(ctx context.Context, id int64) (*Session, error)
 
 	AddDog(ctx context.Context, sessionID int64, dogID int64) error
 	ListDogs(ctx context.Context, sessionID int64) ([]struct {
@@ -17,4 +18,33 @@ type Repository interface {
 	CreateRound(ctx context.Context, r *Round) error
 	ListRounds(ctx context.Context, sessionID int64) ([]*Round, error)
 	ListRoundsByDog(ctx context.Context, dogID int64) ([]*Round, error)
+	GetRound(ctx context.Context, id int64) (*Round, error)
+
+	ListConditionRounds(ctx context.Context, f ConditionFilter) ([]*ConditionRound, error)
+	// ListIndicationCounts counts rounds per dog and indication result.
+	// Rounds without a recorded result are left out.
+	ListIndicationCounts(ctx context.Context, f IndicationFilter) ([]*IndicationCount, error)
+	ListReinforcementRounds(ctx context.Context, f ReinforcementFilter) ([]*ReinforcementRound, error)
+	ReinforcementCatalog(ctx context.Context) (*ReinforcementCatalog, error)
+	// ListRoundTimings returns rounds that have a duration or a latency.
+	ListRoundTimings(ctx context.Context, f TimingFilter) ([]*RoundTiming, error)
+	// ListDifficultyRounds returns rounds with a difficulty level or
+	// parameter values, oldest first.
+	ListDifficultyRounds(ctx context.Context, f DifficultyFilter) ([]*DifficultyRound, error)
+
+	// SaveAreaSearch replaces the area-search details of a round.
+	SaveAreaSearch(ctx context.Context, roundID int64, a *AreaSearch) error
+	GetAreaSearch(ctx context.Context, roundID int64) (*AreaSearch, error)
+	DeleteAreaSearch(ctx context.Context, roundID int64) error
+	ListAreaSearches(ctx context.Context, f AreaSearchFilter) ([]*AreaSearchRound, error)
+
+	// SaveTrailing replaces the mantrailing details of a round.
+	SaveTrailing(ctx context.Context, roundID int64, t *Trailing) error
+	GetTrailing(ctx context.Context, roundID int64) (*Trailing, error)
+	DeleteTrailing(ctx context.Context, roundID int64) error
+	ListTrailing(ctx context.Context, f TrailingFilter) ([]*TrailingRound, error)
+	// SaveRubble replaces the rubble details of a round.
+	SaveRubble(ctx context.Context, roundID int64, r *Rubble) error
+	GetRubble(ctx context.Context, roundID int64) (*Rubble, error)
+	DeleteRubble(ctx context.Context, roundID int64) error
 }
@@ -0,0 +1,39 @@
+package session
+
+// StructureType is the kind of collapsed structure searched in a rubble
+// round.
+type StructureType string
+
+const (
+	StructureBuilding   StructureType = "building"
+	StructureDebrisPile StructureType = "debris_pile"
+	StructureTunnel     StructureType = "tunnel"
+	StructureVehicle    StructureType = "vehicle"
+	StructureOther      StructureType = "other"
+)
+
+func (s StructureType) Valid() bool {
+	switch s {
+	case StructureBuilding, StructureDebrisPile, StructureTunnel, StructureVehicle, StructureOther:
+		return true
+	}
+	return false
+}
+
+// Rubble holds the details of a rubble round: the structure searched, how
+// many victims were buried and found, how deep the deepest lay and the
+// false alerts the dog gave.
+type Rubble struct {
+	StructureType StructureType
+	Victims       int
+	VictimsFound  int
+	MaxDepthM     *float64
+	FalseAlerts   int
+}
+
+func (r *Rubble) Valid() bool {
+	if !r.StructureType.Valid() || r.Victims < 0 || r.VictimsFound < 0 || r.VictimsFound > r.Victims {
+		return false
+	}
+	return r.FalseAlerts >= 0 && (r.MaxDepthM == nil || *r.MaxDepthM >= 0)
+}
@@ -0,0 +1,11 @@
+package session
+
+// CriterionScore is the points a round earned on one criterion of its
+// exercise's rubric. Name, MaxPoints and Weight are filled in on reads.
+type CriterionScore struct {
+	CriterionID int64
+	Name        string
+	Points      int
+	MaxPoints   int
+	Weight      float64
+}
@@ -0,0 +1,55 @@
+package session
+
+import "time"
+
+// Timing is when a round ran and how long the dog took to first show
+// interest in the scent and to give its indication, both in seconds from
+// the start of the round.
+type Timing struct {
+	StartedAt         *time.Time
+	EndedAt           *time.Time
+	TimeToFirstAlertS *int
+	TimeToIndicationS *int
+}
+
+// Duration is the time from the start to the end of the round, known only
+// when both are recorded.
+func (t Timing) Duration() (time.Duration, bool) {
+	if t.StartedAt == nil || t.EndedAt == nil {
+		return 0, false
+	}
+	return t.EndedAt.Sub(*t.StartedAt), true
+}
+
+// Window is the span of a session a round has to fall into. End is nil
+// while the session is open.
+type Window struct {
+	Start time.Time
+	End   *time.Time
+}
+
+// Contains reports whether t lies within the window.
+func (w Window) Contains(t time.Time) bool {
+	if t.Before(w.Start) {
+		return false
+	}
+	return w.End == nil || !t.After(*w.End)
+}
+
+// RoundTiming is the timing of a round with what it is grouped by.
+type RoundTiming struct {
+	RoundID    int64
+	DogID      int64
+	BehaviorID int64
+	ExerciseID int64
+	Outcome    string
+	Timing
+}
+
+type TimingFilter struct {
+	DogID      *int64
+	BehaviorID *int64
+	ExerciseID *int64
+	From       *time.Time
+	To         *time.Time
+}
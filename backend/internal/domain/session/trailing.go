@@ -0,0 +1,185 @@
+package session
+
+import "time"
+
+type ScentArticle string
+
+const (
+	ScentArticleClothing      ScentArticle = "clothing"
+	ScentArticleGauze         ScentArticle = "gauze"
+	ScentArticleTouchedObject ScentArticle = "touched_object"
+	ScentArticleFootwear      ScentArticle = "footwear"
+	ScentArticleOther         ScentArticle = "other"
+)
+
+func (a ScentArticle) Valid() bool {
+	switch a {
+	case ScentArticleClothing, ScentArticleGauze, ScentArticleTouchedObject, ScentArticleFootwear, ScentArticleOther:
+		return true
+	}
+	return false
+}
+
+// StartType describes how the dog is given the trail: pointed in the
+// known direction, left to find it at a marked spot, or scented in a pool
+// of possible directions or from a vehicle.
+type StartType string
+
+const (
+	StartDirectional    StartType = "directional"
+	StartNonDirectional StartType = "non_directional"
+	StartScentPool      StartType = "scent_pool"
+	StartVehicle        StartType = "vehicle"
+)
+
+func (s StartType) Valid() bool {
+	switch s {
+	case StartDirectional, StartNonDirectional, StartScentPool, StartVehicle:
+		return true
+	}
+	return false
+}
+
+type Surface string
+
+const (
+	SurfaceAsphalt Surface = "asphalt"
+	SurfaceGravel  Surface = "gravel"
+	SurfaceGrass   Surface = "grass"
+	SurfaceForest  Surface = "forest"
+	SurfaceField   Surface = "field"
+	SurfaceUrban   Surface = "urban"
+	SurfaceSand    Surface = "sand"
+	SurfaceSnow    Surface = "snow"
+)
+
+func (s Surface) Valid() bool {
+	switch s {
+	case SurfaceAsphalt, SurfaceGravel, SurfaceGrass, SurfaceForest, SurfaceField, SurfaceUrban, SurfaceSand, SurfaceSnow:
+		return true
+	}
+	return false
+}
+
+// Contamination rates how much foreign scent crossed the trail.
+type Contamination string
+
+const (
+	ContaminationNone   Contamination = "none"
+	ContaminationLow    Contamination = "low"
+	ContaminationMedium Contamination = "medium"
+	ContaminationHigh   Contamination = "high"
+)
+
+func (c Contamination) Valid() bool {
+	switch c {
+	case ContaminationNone, ContaminationLow, ContaminationMedium, ContaminationHigh:
+		return true
+	}
+	return false
+}
+
+// Trailing holds the details of a mantrailing round. Trail age and length
+// are required; the rest is optional but validated.
+type Trailing struct {
+	TrailAgeMin    int
+	TrailLengthM   float64
+	ScentArticle   *ScentArticle
+	StartType      *StartType
+	Surfaces       []Surface
+	Contamination  *Contamination
+	ReachedSubject bool
+}
+
+func (t *Trailing) Valid() bool {
+	if t.TrailAgeMin < 0 || t.TrailLengthM <= 0 {
+		return false
+	}
+	if (t.ScentArticle != nil && !t.ScentArticle.Valid()) || (t.StartType != nil && !t.StartType.Valid()) ||
+		(t.Contamination != nil && !t.Contamination.Valid()) {
+		return false
+	}
+	seen := map[Surface]bool{}
+	for _, s := range t.Surfaces {
+		if !s.Valid() || seen[s] {
+			return false
+		}
+		seen[s] = true
+	}
+	return true
+}
+
+// TrailingFactor is a trailing detail outcomes can be grouped by.
+type TrailingFactor string
+
+const (
+	TrailingFactorAge           TrailingFactor = "trail_age_min"
+	TrailingFactorLength        TrailingFactor = "trail_length_m"
+	TrailingFactorScentArticle  TrailingFactor = "scent_article"
+	TrailingFactorStartType     TrailingFactor = "start_type"
+	TrailingFactorSurface       TrailingFactor = "surface"
+	TrailingFactorContamination TrailingFactor = "contamination"
+)
+
+// TrailingDefaultCuts are the band boundaries for the numeric trailing
+// factors: trail age in minutes and length in metres.
+var TrailingDefaultCuts = map[TrailingFactor][]float64{
+	TrailingFactorAge:    {30, 60, 120, 240, 720, 1440},
+	TrailingFactorLength: {500, 1000, 2000, 4000},
+}
+
+func (f TrailingFactor) Valid() bool {
+	switch f {
+	case TrailingFactorAge, TrailingFactorLength, TrailingFactorScentArticle, TrailingFactorStartType,
+		TrailingFactorSurface, TrailingFactorContamination:
+		return true
+	}
+	return false
+}
+
+// BandsOf returns the band labels of the round for factor f. A trail over
+// several surfaces falls into each of their bands.
+func (t *Trailing) BandsOf(f TrailingFactor, cuts []float64) []string {
+	switch f {
+	case TrailingFactorAge:
+		return []string{BandLabels(cuts)[bandIndex(cuts, float64(t.TrailAgeMin))]}
+	case TrailingFactorLength:
+		return []string{BandLabels(cuts)[bandIndex(cuts, t.TrailLengthM)]}
+	case TrailingFactorScentArticle:
+		if t.ScentArticle != nil {
+			return []string{string(*t.ScentArticle)}
+		}
+	case TrailingFactorStartType:
+		if t.StartType != nil {
+			return []string{string(*t.StartType)}
+		}
+	case TrailingFactorContamination:
+		if t.Contamination != nil {
+			return []string{string(*t.Contamination)}
+		}
+	case TrailingFactorSurface:
+		out := make([]string, 0, len(t.Surfaces))
+		for _, s := range t.Surfaces {
+			out = append(out, string(s))
+		}
+		if len(out) > 0 {
+			return out
+		}
+	}
+	return []string{UnknownBand}
+}
+
+// TrailingRound is a trailing round's details with its outcome, for
+// analytics across rounds.
+type TrailingRound struct {
+	RoundID int64
+	DogID   int64
+	Outcome string
+	Trailing
+}
+
+type TrailingFilter struct {
+	DogID *int64
+	From  *time.Time
+	To    *time.Time
+}
@@ -8,6 +8,8 @@ type Skill struct {
 	ID          SkillID
 	Name        string
 	Description *string
-	CreatedAt   time.Time
-	UpdatedAt   time.Time
+	// DisciplineID groups the skill under a discipline, if any.
+	DisciplineID *int64
+	CreatedAt    time.Time
+	UpdatedAt    time.Time
 }
@@ -0,0 +1,197 @@
+package track
+
+import (
+	"math"
+	"time"
+
+	"github.com/tnosaj/sar-training/backend/internal/domain/location"
+)
+
+// maxCoverageCells bounds the raster used to measure sector coverage; large
+// sectors get coarser cells instead of unbounded memory.
+const maxCoverageCells = 500_000
+
+const earthRadiusM = 6371000.0
+
+// Summary holds the basic measures of a track. Distance only counts moves
+// within a segment, not the jumps between segments.
+type Summary struct {
+	Points    int
+	DistanceM float64
+	StartedAt *time.Time
+	EndedAt   *time.Time
+}
+
+// Duration is the time between the first and last timestamped fix.
+func (s Summary) Duration() time.Duration {
+	if s.StartedAt == nil || s.EndedAt == nil {
+		return 0
+	}
+	return s.EndedAt.Sub(*s.StartedAt)
+}
+
+// AvgSpeedKmh returns nil when the track has no usable timestamps.
+func (s Summary) AvgSpeedKmh() *float64 {
+	d := s.Duration()
+	if d <= 0 {
+		return nil
+	}
+	v := s.DistanceM / 1000 / d.Hours()
+	return &v
+}
+
+func Summarize(segments [][]Point) Summary {
+	var s Summary
+	for _, seg := range segments {
+		s.Points += len(seg)
+		for i, p := range seg {
+			if i > 0 {
+				s.DistanceM += location.DistanceKm(seg[i-1].Position(), p.Position()) * 1000
+			}
+			if p.Time == nil {
+				continue
+			}
+			if s.StartedAt == nil || p.Time.Before(*s.StartedAt) {
+				s.StartedAt = p.Time
+			}
+			if s.EndedAt == nil || p.Time.After(*s.EndedAt) {
+				s.EndedAt = p.Time
+			}
+		}
+	}
+	return s
+}
+
+// Coverage relates tracks to an assigned search sector.
+type Coverage struct {
+	SectorAreaM2 float64
+	SweepWidthM  float64
+	// CoverageRate is the share of the sector area within half the sweep
+	// width of any track.
+	CoverageRate float64
+	// InSectorRate is the share of the track distance walked inside the
+	// sector.
+	InSectorRate float64
+}
+
+// CoverageOf measures how well the segments cover the sector ring. The
+// sector is rasterised on a local plane; cells within half the sweep width
+// of the path count as covered.
+func CoverageOf(segments [][]Point, sector []location.Point, sweepWidthM float64) Coverage {
+	c := Coverage{SweepWidthM: sweepWidthM}
+	if len(sector) < 3 || sweepWidthM <= 0 {
+		return c
+	}
+	proj := newPlane(sector[0])
+	ring := make([][2]float64, len(sector))
+	minX, minY, maxX, maxY := math.Inf(1), math.Inf(1), math.Inf(-1), math.Inf(-1)
+	for i, p := range sector {
+		x, y := proj.xy(p)
+		ring[i] = [2]float64{x, y}
+		minX, minY, maxX, maxY = math.Min(minX, x), math.Min(minY, y), math.Max(maxX, x), math.Max(maxY, y)
+	}
+	c.SectorAreaM2 = math.Abs(shoelace(ring))
+
+	var total, inside float64
+	for _, seg := range segments {
+		for i := 1; i < len(seg); i++ {
+			d := location.DistanceKm(seg[i-1].Position(), seg[i].Position()) * 1000
+			total += d
+			mid := location.Point{Lat: (seg[i-1].Lat + seg[i].Lat) / 2, Lon: (seg[i-1].Lon + seg[i].Lon) / 2}
+			if location.Contains(sector, mid) {
+				inside += d
+			}
+		}
+	}
+	if total > 0 {
+		c.InSectorRate = inside / total
+	}
+
+	cell := sweepWidthM / 4
+	if w, h := maxX-minX, maxY-minY; w*h/(cell*cell) > maxCoverageCells {
+		cell = math.Sqrt(w * h / maxCoverageCells)
+	}
+	nx, ny := int((maxX-minX)/cell)+1, int((maxY-minY)/cell)+1
+	covered := make([]bool, nx*ny)
+	radius := sweepWidthM / 2
+	mark := func(x, y float64) {
+		i0, i1 := int((x-radius-minX)/cell), int((x+radius-minX)/cell)
+		j0, j1 := int((y-radius-minY)/cell), int((y+radius-minY)/cell)
+		for j := max(j0, 0); j <= min(j1, ny-1); j++ {
+			for i := max(i0, 0); i <= min(i1, nx-1); i++ {
+				cx, cy := minX+(float64(i)+0.5)*cell, minY+(float64(j)+0.5)*cell
+				if math.Hypot(cx-x, cy-y) <= radius {
+					covered[j*nx+i] = true
+				}
+			}
+		}
+	}
+	for _, seg := range segments {
+		for i, p := range seg {
+			x, y := proj.xy(p.Position())
+			mark(x, y)
+			if i == 0 {
+				continue
+			}
+			px, py := proj.xy(seg[i-1].Position())
+			steps := int(math.Hypot(x-px, y-py) / (cell / 2))
+			for k := 1; k < steps; k++ {
+				f := float64(k) / float64(steps)
+				mark(px+(x-px)*f, py+(y-py)*f)
+			}
+		}
+	}
+
+	var cells, hit int
+	for j := 0; j < ny; j++ {
+		for i := 0; i < nx; i++ {
+			cx, cy := minX+(float64(i)+0.5)*cell, minY+(float64(j)+0.5)*cell
+			if !containsXY(ring, cx, cy) {
+				continue
+			}
+			cells++
+			if covered[j*nx+i] {
+				hit++
+			}
+		}
+	}
+	if cells > 0 {
+		c.CoverageRate = float64(hit) / float64(cells)
+	}
+	return c
+}
+
+// plane is an equirectangular projection around an origin, in metres.
+type plane struct {
+	origin location.Point
+	cosLat float64
+}
+
+func newPlane(origin location.Point) plane {
+	return plane{origin: origin, cosLat: math.Cos(origin.Lat * math.Pi / 180)}
+}
+
+func (p plane) xy(q location.Point) (float64, float64) {
+	x := (q.Lon - p.origin.Lon) * math.Pi / 180 * earthRadiusM * p.cosLat
+	y := (q.Lat - p.origin.Lat) * math.Pi / 180 * earthRadiusM
+	return x, y
+}
+
+func shoelace(ring [][2]float64) float64 {
+	var a float64
+	for i, j := 0, len(ring)-1; i < len(ring); j, i = i, i+1 {
+		a += ring[j][0]*ring[i][1] - ring[i][0]*ring[j][1]
+	}
+	return a / 2
+}
+
+func containsXY(ring [][2]float64, x, y float64) bool {
+	in := false
+	for i, j := 0, len(ring)-1; i < len(ring); j, i = i, i+1 {
+		a, b := ring[i], ring[j]
+		if (a[1] > y) != (b[1] > y) && x < (b[0]-a[0])*(y-a[1])/(b[1]-a[1])+a[0] {
+			in = !in
+		}
+	}
+	return in
+}
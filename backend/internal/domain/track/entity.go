@@ -0,0 +1,46 @@
+package track
+
+import (
+	"time"
+
+	"github.com/tnosaj/sar-training/backend/internal/domain/location"
+)
+
+type TrackID int64
+
+// Kind tells whose movement a track records.
+type Kind string
+
+const (
+	KindHandler Kind = "handler"
+	KindDog     Kind = "dog"
+)
+
+func (k Kind) Valid() bool { return k == KindHandler || k == KindDog }
+
+// Point is a recorded GPS fix. Elevation and time are optional in GPX.
+type Point struct {
+	Lat  float64
+	Lon  float64
+	Ele  *float64
+	Time *time.Time
+}
+
+func (p Point) Position() location.Point { return location.Point{Lat: p.Lat, Lon: p.Lon} }
+
+// Track is one recorded path, split into segments where the receiver lost
+// its fix or recording was paused.
+type Track struct {
+	ID        TrackID
+	SessionID int64
+	RoundID   *int64
+	Kind      Kind
+	Name      *string
+	Segments  [][]Point
+	CreatedAt time.Time
+}
+
+type Filter struct {
+	SessionID *int64
+	RoundID   *int64
+}
@@ -0,0 +1,70 @@
+package track
+
+import (
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+type gpxFile struct {
+	Tracks []struct {
+		Name     string `xml:"name"`
+		Segments []struct {
+			Points []gpxPoint `xml:"trkpt"`
+		} `xml:"trkseg"`
+	} `xml:"trk"`
+}
+
+type gpxPoint struct {
+	Lat  float64  `xml:"lat,attr"`
+	Lon  float64  `xml:"lon,attr"`
+	Ele  *float64 `xml:"ele"`
+	Time string   `xml:"time"`
+}
+
+// ParseGPX reads the tracks of a GPX 1.0/1.1 file. Routes and waypoints are
+// ignored; empty segments and tracks are dropped.
+func ParseGPX(r io.Reader) ([]*Track, error) {
+	var f gpxFile
+	if err := xml.NewDecoder(r).Decode(&f); err != nil {
+		return nil, fmt.Errorf("invalid gpx: %w", err)
+	}
+	var out []*Track
+	for i, trk := range f.Tracks {
+		t := &Track{}
+		if name := strings.TrimSpace(trk.Name); name != "" {
+			t.Name = &name
+		}
+		for _, seg := range trk.Segments {
+			var pts []Point
+			for _, gp := range seg.Points {
+				p := Point{Lat: gp.Lat, Lon: gp.Lon, Ele: gp.Ele}
+				if !p.Position().Valid() {
+					return nil, fmt.Errorf("track %d: invalid coordinate %v,%v", i+1, gp.Lat, gp.Lon)
+				}
+				if v := strings.TrimSpace(gp.Time); v != "" {
+					ts, err := time.Parse(time.RFC3339, v)
+					if err != nil {
+						return nil, fmt.Errorf("track %d: invalid time %q", i+1, v)
+					}
+					ts = ts.UTC()
+					p.Time = &ts
+				}
+				pts = append(pts, p)
+			}
+			if len(pts) > 0 {
+				t.Segments = append(t.Segments, pts)
+			}
+		}
+		if len(t.Segments) > 0 {
+			out = append(out, t)
+		}
+	}
+	if len(out) == 0 {
+		return nil, errors.New("gpx contains no track points")
+	}
+	return out, nil
+}
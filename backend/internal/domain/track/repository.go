@@ -0,0 +1,10 @@
+package track
+
+import "context"
+
+type Repository interface {
+	Create(ctx context.Context, t *Track) error
+	Get(ctx context.Context, id TrackID) (*Track, error)
+	List(ctx context.Context, f Filter) ([]*Track, error)
+	Delete(ctx context.Context, id TrackID) error
+}
@@ -0,0 +1,156 @@
+package weather
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/tnosaj/sar-training/backend/internal/domain/session"
+)
+
+// csvColumns maps accepted header names onto the canonical column. Wind
+// speed may be given in km/h, m/s or knots and is converted to km/h;
+// precipitation as a category or as an amount in mm per hour.
+var csvColumns = map[string]string{
+	"time": "time", "timestamp": "time", "observed_at": "time", "datetime": "time", "date": "time",
+	"station": "station", "station_id": "station",
+	"temperature_c": "temperature", "temp_c": "temperature", "temperature": "temperature", "temp": "temperature",
+	"humidity_pct": "humidity", "humidity": "humidity", "rh": "humidity",
+	"wind_speed_kmh": "wind_kmh", "wind_kmh": "wind_kmh", "wind_speed": "wind_kmh",
+	"wind_speed_ms": "wind_ms", "wind_ms": "wind_ms",
+	"wind_speed_kt": "wind_kt", "wind_kt": "wind_kt",
+	"wind_direction_deg": "wind_dir", "wind_direction": "wind_dir", "wind_dir": "wind_dir",
+	"precipitation":    "precipitation",
+	"precipitation_mm": "precipitation_mm", "rain_mm": "precipitation_mm", "precip_mm": "precipitation_mm",
+}
+
+var csvTimeLayouts = []string{time.RFC3339, "2006-01-02T15:04", "2006-01-02 15:04:05", "2006-01-02 15:04"}
+
+// ParseCSV reads observations from a CSV file with a header row. Times
+// without a zone are taken as UTC. Empty cells are left unset.
+func ParseCSV(r io.Reader) ([]*Observation, error) {
+	cr := csv.NewReader(r)
+	cr.TrimLeadingSpace = true
+	cr.FieldsPerRecord = -1
+	header, err := cr.Read()
+	if err != nil {
+		return nil, fmt.Errorf("csv header: %w", err)
+	}
+	cols := map[string]int{}
+	for i, h := range header {
+		if c, ok := csvColumns[strings.ToLower(strings.TrimSpace(h))]; ok {
+			cols[c] = i
+		}
+	}
+	if _, ok := cols["time"]; !ok {
+		return nil, fmt.Errorf("csv header: no time column")
+	}
+	var out []*Observation
+	for line := 2; ; line++ {
+		rec, err := cr.Read()
+		if err == io.EOF {
+			return out, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		o, err := parseCSVRecord(rec, cols)
+		if err != nil {
+			return nil, fmt.Errorf("csv line %d: %w", line, err)
+		}
+		out = append(out, o)
+	}
+}
+
+func parseCSVRecord(rec []string, cols map[string]int) (*Observation, error) {
+	cell := func(name string) string {
+		i, ok := cols[name]
+		if !ok || i >= len(rec) {
+			return ""
+		}
+		return strings.TrimSpace(rec[i])
+	}
+	num := func(name string) (*float64, error) {
+		v := cell(name)
+		if v == "" {
+			return nil, nil
+		}
+		f, err := strconv.ParseFloat(v, 64)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", name, err)
+		}
+		return &f, nil
+	}
+
+	o := &Observation{Source: "csv"}
+	ts := cell("time")
+	var err error
+	for _, layout := range csvTimeLayouts {
+		if o.ObservedAt, err = time.Parse(layout, ts); err == nil {
+			break
+		}
+	}
+	if err != nil {
+		return nil, fmt.Errorf("time %q: %w", ts, err)
+	}
+	o.ObservedAt = o.ObservedAt.UTC()
+	if v := cell("station"); v != "" {
+		o.Station = &v
+	}
+	if o.TemperatureC, err = num("temperature"); err != nil {
+		return nil, err
+	}
+	if o.HumidityPct, err = num("humidity"); err != nil {
+		return nil, err
+	}
+	for _, w := range []struct {
+		col    string
+		factor float64
+	}{{"wind_kmh", 1}, {"wind_ms", 3.6}, {"wind_kt", 1.852}} {
+		v, err := num(w.col)
+		if err != nil {
+			return nil, err
+		}
+		if v != nil && o.WindSpeedKmh == nil {
+			kmh := roundTenth(*v * w.factor)
+			o.WindSpeedKmh = &kmh
+		}
+	}
+	dir, err := num("wind_dir")
+	if err != nil {
+		return nil, err
+	}
+	if dir != nil {
+		d := int(*dir) % 360
+		o.WindDirectionDeg = &d
+	}
+	if v := cell("precipitation"); v != "" {
+		p := session.Precipitation(strings.ToLower(v))
+		if !p.Valid() {
+			return nil, fmt.Errorf("precipitation %q", v)
+		}
+		o.Precipitation = &p
+	} else if mm, err := num("precipitation_mm"); err != nil {
+		return nil, err
+	} else if mm != nil {
+		p := PrecipitationFromRate(*mm)
+		o.Precipitation = &p
+	}
+	return o, nil
+}
+
+// PrecipitationFromRate classifies a precipitation rate in mm per hour.
+func PrecipitationFromRate(mm float64) session.Precipitation {
+	switch {
+	case mm <= 0:
+		return session.PrecipitationNone
+	case mm < 2.5:
+		return session.PrecipitationLight
+	case mm < 7.6:
+		return session.PrecipitationModerate
+	}
+	return session.PrecipitationHeavy
+}
@@ -0,0 +1,95 @@
+package weather
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseCSV(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		want    []string
+		wantErr string
+	}{
+		{
+			name: "canonical columns",
+			input: "observed_at,station,temperature_c,humidity_pct,wind_speed_kmh,wind_direction_deg,precipitation\n" +
+				"2026-03-14T12:50:00Z,EDDM,12.5,70,18,240,Light\n",
+			want: []string{"2026-03-14T12:50:00Z EDDM 12.5 70 18 240 light"},
+		},
+		{
+			name:  "aliases, zones and empty cells",
+			input: "Timestamp, Temp, RH, Wind_kt, Wind_dir\n2026-03-14 12:50,,81,10,360\n2026-03-14T14:50:00+02:00,3,,,\n",
+			want: []string{
+				"2026-03-14T12:50:00Z - - 81 18.5 0 -",
+				"2026-03-14T12:50:00Z - 3 - - - -",
+			},
+		},
+		{
+			name:  "metres per second",
+			input: "time,wind_ms\n2026-03-14 12:50:30,5\n",
+			want:  []string{"2026-03-14T12:50:30Z - - - 18 - -"},
+		},
+		{
+			name:  "km/h wins over other units",
+			input: "time,wind_kmh,wind_kt\n2026-03-14T12:50,20,10\n",
+			want:  []string{"2026-03-14T12:50:00Z - - - 20 - -"},
+		},
+		{
+			name:  "precipitation amounts",
+			input: "time,rain_mm\n2026-03-14T10:00,0\n2026-03-14T11:00,1\n2026-03-14T12:00,2.5\n2026-03-14T13:00,7.6\n",
+			want: []string{
+				"2026-03-14T10:00:00Z - - - - - none",
+				"2026-03-14T11:00:00Z - - - - - light",
+				"2026-03-14T12:00:00Z - - - - - moderate",
+				"2026-03-14T13:00:00Z - - - - - heavy",
+			},
+		},
+		{
+			name:    "no time column",
+			input:   "station,temp\nEDDM,3\n",
+			wantErr: "csv header: no time column",
+		},
+		{
+			name:    "bad time",
+			input:   "time,temp\n2026-03-14T10:00,3\n14.03.2026,3\n",
+			wantErr: "csv line 3: time",
+		},
+		{
+			name:    "bad number",
+			input:   "time,temp\n2026-03-14T10:00,warm\n",
+			wantErr: "csv line 2: temperature",
+		},
+		{
+			name:    "bad precipitation",
+			input:   "time,precipitation\n2026-03-14T10:00,drizzle\n",
+			wantErr: `csv line 2: precipitation "drizzle"`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			obs, err := ParseCSV(strings.NewReader(tt.input))
+			if tt.wantErr != "" {
+				if err == nil || !strings.HasPrefix(err.Error(), tt.wantErr) {
+					t.Fatalf("ParseCSV error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			var got []string
+			for _, o := range obs {
+				if o.Source != "csv" {
+					t.Errorf("source = %q", o.Source)
+				}
+				got = append(got, describe(o))
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseCSV =\n%q\nwant\n%q", got, tt.want)
+			}
+		})
+	}
+}
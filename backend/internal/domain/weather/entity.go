@@ -0,0 +1,53 @@
+package weather
+
+import (
+	"time"
+
+	"github.com/tnosaj/sar-training/backend/internal/domain/session"
+)
+
+type ObservationID int64
+
+// Observation is one weather reading. Location ties it to sessions held
+// there; Station is the reporting station's own id, if the file had one.
+type Observation struct {
+	ID               ObservationID
+	Location         string
+	Station          *string
+	ObservedAt       time.Time
+	TemperatureC     *float64
+	HumidityPct      *float64
+	WindSpeedKmh     *float64
+	WindDirectionDeg *int
+	Precipitation    *session.Precipitation
+	Source           string
+	ImportedAt       time.Time
+}
+
+// Fill copies the observed values into c. Fields already set in c are kept
+// unless overwrite is true. Terrain is never touched.
+func (o *Observation) Fill(c *session.Conditions, overwrite bool) bool {
+	changed := false
+	if o.TemperatureC != nil && (overwrite || c.TemperatureC == nil) {
+		c.TemperatureC, changed = o.TemperatureC, true
+	}
+	if o.HumidityPct != nil && (overwrite || c.HumidityPct == nil) {
+		c.HumidityPct, changed = o.HumidityPct, true
+	}
+	if o.WindSpeedKmh != nil && (overwrite || c.WindSpeedKmh == nil) {
+		c.WindSpeedKmh, changed = o.WindSpeedKmh, true
+	}
+	if o.WindDirectionDeg != nil && (overwrite || c.WindDirectionDeg == nil) {
+		c.WindDirectionDeg, changed = o.WindDirectionDeg, true
+	}
+	if o.Precipitation != nil && (overwrite || c.Precipitation == nil) {
+		c.Precipitation, changed = o.Precipitation, true
+	}
+	return changed
+}
+
+type Filter struct {
+	Location *string
+	From     *time.Time
+	To       *time.Time
+}
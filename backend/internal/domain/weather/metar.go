@@ -0,0 +1,185 @@
+package weather
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/tnosaj/sar-training/backend/internal/domain/session"
+)
+
+var (
+	metarStation = regexp.MustCompile(`^[A-Z][A-Z0-9]{3}$`)
+	metarTime    = regexp.MustCompile(`^(\d{2})(\d{2})(\d{2})Z$`)
+	metarWind    = regexp.MustCompile(`^(\d{3}|VRB)(\d{2,3})(?:G\d{2,3})?(KT|MPS|KMH)$`)
+	metarTemp    = regexp.MustCompile(`^(M?\d{2})/(M?\d{2})?$`)
+	metarWx      = regexp.MustCompile(`^([-+]|VC)?(?:MI|BC|PR|DR|BL|SH|TS|FZ)*((?:DZ|RA|SN|SG|PL|GR|GS|UP|IC)+)`)
+	// NOAA text dumps put the full observation time before the report,
+	// either on the same line or on the line above.
+	metarPrefix = regexp.MustCompile(`^(\d{4}/\d{2}/\d{2} \d{2}:\d{2})\s*(.*)$`)
+)
+
+// ParseMETAR reads one METAR report per line. Reports only carry day and
+// time, so the month comes from a "YYYY/MM/DD HH:MM" prefix when the dump
+// has one, else from ref: the report is placed in ref's month, or the month
+// before when that would put it after ref. Unparseable lines are skipped
+// and counted.
+func ParseMETAR(r io.Reader, ref time.Time) ([]*Observation, int, error) {
+	sc := bufio.NewScanner(r)
+	var out []*Observation
+	skipped := 0
+	var stamp *time.Time
+	for sc.Scan() {
+		line := strings.TrimSpace(sc.Text())
+		if line == "" {
+			continue
+		}
+		if m := metarPrefix.FindStringSubmatch(line); m != nil {
+			t, err := time.Parse("2006/01/02 15:04", m[1])
+			if err != nil {
+				skipped++
+				continue
+			}
+			stamp = &t
+			if line = strings.TrimSpace(m[2]); line == "" {
+				continue
+			}
+		}
+		o, err := parseMETARLine(line, stamp, ref)
+		stamp = nil
+		if err != nil {
+			skipped++
+			continue
+		}
+		out = append(out, o)
+	}
+	return out, skipped, sc.Err()
+}
+
+func parseMETARLine(line string, stamp *time.Time, ref time.Time) (*Observation, error) {
+	fields := strings.Fields(strings.TrimSuffix(line, "="))
+	o := &Observation{Source: "metar"}
+	i := 0
+	for i < len(fields) && (fields[i] == "METAR" || fields[i] == "SPECI") {
+		i++
+	}
+	if i >= len(fields) || !metarStation.MatchString(fields[i]) {
+		return nil, fmt.Errorf("no station")
+	}
+	station := fields[i]
+	o.Station = &station
+	i++
+	if i >= len(fields) {
+		return nil, fmt.Errorf("no time")
+	}
+	m := metarTime.FindStringSubmatch(fields[i])
+	if m == nil {
+		return nil, fmt.Errorf("no time")
+	}
+	day, _ := strconv.Atoi(m[1])
+	hour, _ := strconv.Atoi(m[2])
+	minute, _ := strconv.Atoi(m[3])
+	if stamp != nil {
+		o.ObservedAt = time.Date(stamp.Year(), stamp.Month(), day, hour, minute, 0, 0, time.UTC)
+	} else {
+		o.ObservedAt = resolveDay(ref, day, hour, minute)
+	}
+	i++
+
+	precip := session.PrecipitationNone
+	for _, f := range fields[i:] {
+		if f == "RMK" {
+			break
+		}
+		if f == "NIL" {
+			return nil, fmt.Errorf("missing report")
+		}
+		if w := metarWind.FindStringSubmatch(f); w != nil {
+			speed, _ := strconv.ParseFloat(w[2], 64)
+			switch w[3] {
+			case "KT":
+				speed *= 1.852
+			case "MPS":
+				speed *= 3.6
+			}
+			speed = roundTenth(speed)
+			o.WindSpeedKmh = &speed
+			if w[1] != "VRB" {
+				dir, _ := strconv.Atoi(w[1])
+				dir %= 360
+				o.WindDirectionDeg = &dir
+			}
+			continue
+		}
+		if t := metarTemp.FindStringSubmatch(f); t != nil {
+			temp := metarDegrees(t[1])
+			o.TemperatureC = &temp
+			if t[2] != "" {
+				rh := RelativeHumidity(temp, metarDegrees(t[2]))
+				o.HumidityPct = &rh
+			}
+			continue
+		}
+		if w := metarWx.FindStringSubmatch(f); w != nil && w[1] != "VC" {
+			p := session.PrecipitationModerate
+			switch w[1] {
+			case "-":
+				p = session.PrecipitationLight
+			case "+":
+				p = session.PrecipitationHeavy
+			}
+			if precipRank(p) > precipRank(precip) {
+				precip = p
+			}
+		}
+	}
+	o.Precipitation = &precip
+	return o, nil
+}
+
+func resolveDay(ref time.Time, day, hour, minute int) time.Time {
+	ref = ref.UTC()
+	t := time.Date(ref.Year(), ref.Month(), day, hour, minute, 0, 0, time.UTC)
+	if t.After(ref) {
+		t = time.Date(ref.Year(), ref.Month()-1, day, hour, minute, 0, 0, time.UTC)
+	}
+	return t
+}
+
+func metarDegrees(v string) float64 {
+	neg := strings.HasPrefix(v, "M")
+	n, _ := strconv.ParseFloat(strings.TrimPrefix(v, "M"), 64)
+	if neg {
+		return -n
+	}
+	return n
+}
+
+func precipRank(p session.Precipitation) int {
+	switch p {
+	case session.PrecipitationLight:
+		return 1
+	case session.PrecipitationModerate:
+		return 2
+	case session.PrecipitationHeavy:
+		return 3
+	}
+	return 0
+}
+
+// RelativeHumidity derives relative humidity in percent from temperature and
+// dew point using the Magnus formula, rounded to one decimal.
+func RelativeHumidity(tempC, dewC float64) float64 {
+	const a, b = 17.625, 243.04
+	rh := 100 * math.Exp(a*dewC/(b+dewC)) / math.Exp(a*tempC/(b+tempC))
+	return roundTenth(math.Min(rh, 100))
+}
+
+func roundTenth(v float64) float64 {
+	return math.Round(v*10) / 10
+}
@@ -0,0 +1,111 @@
+package weather
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+// describe renders the parsed fields of o on one line, with "-" for unset
+// values, so expectations read like the reports they come from.
+func describe(o *Observation) string {
+	opt := func(v any) string {
+		rv := reflect.ValueOf(v)
+		if rv.IsNil() {
+			return "-"
+		}
+		return fmt.Sprint(rv.Elem().Interface())
+	}
+	return strings.Join([]string{
+		o.ObservedAt.Format(time.RFC3339), opt(o.Station), opt(o.TemperatureC), opt(o.HumidityPct),
+		opt(o.WindSpeedKmh), opt(o.WindDirectionDeg), opt(o.Precipitation),
+	}, " ")
+}
+
+func TestParseMETAR(t *testing.T) {
+	ref := time.Date(2026, 3, 15, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name    string
+		input   string
+		want    []string
+		skipped int
+	}{
+		{
+			name:  "knots and light rain",
+			input: "EDDM 141250Z 24010KT 9999 -RA 12/08 Q1012",
+			want:  []string{"2026-03-14T12:50:00Z EDDM 12 76.5 18.5 240 light"},
+		},
+		{
+			name:  "day after ref is last month",
+			input: "METAR LOWW 200020Z VRB02MPS CAVOK M03/M05 Q1030=",
+			want:  []string{"2026-02-20T00:20:00Z LOWW -3 86.1 7.2 - none"},
+		},
+		{
+			name:  "gusts, heavy thunderstorm, vicinity and remarks ignored",
+			input: "SPECI EDDF 150900Z 36015G25KT +TSRA VCSH RMK RA",
+			want:  []string{"2026-03-15T09:00:00Z EDDF - - 27.8 0 heavy"},
+		},
+		{
+			name:  "strongest precipitation wins",
+			input: "EDDH 151100Z 27005KT -SN RA 01/",
+			want:  []string{"2026-03-15T11:00:00Z EDDH 1 - 9.3 270 moderate"},
+		},
+		{
+			name:  "prefix on the same line",
+			input: "2025/12/31 23:50 EDDM 312350Z 00000KT 01/M01",
+			want:  []string{"2025-12-31T23:50:00Z EDDM 1 86.5 0 0 none"},
+		},
+		{
+			name:  "prefix on the line above",
+			input: "2025/12/31 23:50\nEDDM 312350Z 00000KT 01/M01\n",
+			want:  []string{"2025-12-31T23:50:00Z EDDM 1 86.5 0 0 none"},
+		},
+		{
+			name:    "missing and unparseable reports skipped",
+			input:   "EDDM 151000Z NIL=\n\nnot a report\nEDDM 15100Z 00000KT\nEDDM 151020Z 00000KT\n",
+			want:    []string{"2026-03-15T10:20:00Z EDDM - - 0 0 none"},
+			skipped: 3,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			obs, skipped, err := ParseMETAR(strings.NewReader(tt.input), ref)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if skipped != tt.skipped {
+				t.Errorf("skipped = %d, want %d", skipped, tt.skipped)
+			}
+			var got []string
+			for _, o := range obs {
+				if o.Source != "metar" {
+					t.Errorf("source = %q", o.Source)
+				}
+				got = append(got, describe(o))
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseMETAR =\n%q\nwant\n%q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRelativeHumidity(t *testing.T) {
+	tests := []struct {
+		temp, dew, want float64
+	}{
+		{20, 20, 100},
+		{20, 25, 100},
+		{20, 10, 52.5},
+		{-3, -5, 86.1},
+	}
+	for _, tt := range tests {
+		t.Run(fmt.Sprintf("%v/%v", tt.temp, tt.dew), func(t *testing.T) {
+			if got := RelativeHumidity(tt.temp, tt.dew); got != tt.want {
+				t.Errorf("RelativeHumidity = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,16 @@
+package weather
+
+import (
+	"context"
+	"time"
+)
+
+type Repository interface {
+	// Save stores o unless an observation for the same location and time
+	// exists, and reports whether it was stored.
+	Save(ctx context.Context, o *Observation) (bool, error)
+	List(ctx context.Context, f Filter) ([]*Observation, error)
+	// Nearest returns the observation at location closest in time to t,
+	// at most maxGap away, or common.ErrNotFound.
+	Nearest(ctx context.Context, location string, t time.Time, maxGap time.Duration) (*Observation, error)
+}
@@ -3,6 +3,7 @@ package config
 import (
 	"log"
 	"os"
+	"time"
 )
 
 type Config struct {
@@ -10,6 +11,8 @@ type Config struct {
 	DBPath   string
 	LogLevel string
 	Secret   string
+	// AlertInterval is how often the learning-curve analysis runs.
+	AlertInterval time.Duration
 }
 
 func Load() Config {
@@ -17,10 +20,7 @@ func Load() Config {
 	if p == "" {
 		p = "8080"
 	}
-	db := os.Getenv("DB_PATH")
-	if db == "" {
-		db = "./dogtracker.db"
-	}
+	db := DBPath()
 	lglvl := os.Getenv("LOG_LEVEL")
 	if lglvl == "" {
 		lglvl = "warn"
@@ -29,5 +29,22 @@ func Load() Config {
 	if secret == "" {
 		log.Fatal("AUTH_SECRET is required")
 	}
-	return Config{Port: p, DBPath: db, LogLevel: lglvl, Secret: secret}
+	alertInterval := time.Hour
+	if v := os.Getenv("ALERT_INTERVAL"); v != "" {
+		d, err := time.ParseDuration(v)
+		if err != nil || d <= 0 {
+			log.Fatalf("invalid ALERT_INTERVAL %q", v)
+		}
+		alertInterval = d
+	}
+	return Config{Port: p, DBPath: db, LogLevel: lglvl, Secret: secret, AlertInterval: alertInterval}
+}
+
+// DBPath returns the database file from DB_PATH. Unlike Load it needs no
+// other settings, so offline commands can use it.
+func DBPath() string {
+	if db := os.Getenv("DB_PATH"); db != "" {
+		return db
+	}
+	return "./dogtracker.db"
 }